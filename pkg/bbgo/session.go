@@ -149,6 +149,10 @@ type ExchangeSessionConfig struct {
 	IsolatedMargin       bool   `json:"isolatedMargin,omitempty" yaml:"isolatedMargin,omitempty"`
 	IsolatedMarginSymbol string `json:"isolatedMarginSymbol,omitempty" yaml:"isolatedMarginSymbol,omitempty"`
 
+	// DryRun overrides the default dry-run mode of the exchange (e.g. POLYMARKET_DRY_RUN) for this session only,
+	// this option is exchange-specific, the exchange must implement types.DryRunExchange
+	DryRun *bool `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+
 	Futures               bool   `json:"futures,omitempty" yaml:"futures"`
 	IsolatedFutures       bool   `json:"isolatedFutures,omitempty" yaml:"isolatedFutures,omitempty"`
 	IsolatedFuturesSymbol string `json:"isolatedFuturesSymbol,omitempty" yaml:"isolatedFuturesSymbol,omitempty"`
@@ -1177,6 +1181,15 @@ func (session *ExchangeSession) InitExchange(name string, ex types.Exchange) err
 		}
 	}
 
+	if session.DryRun != nil {
+		dryRunExchange, ok := ex.(types.DryRunExchange)
+		if !ok {
+			return fmt.Errorf("exchange %s does not support dry-run", exchangeName)
+		}
+
+		dryRunExchange.SetDryRun(*session.DryRun)
+	}
+
 	session.Name = name
 	session.Exchange = ex
 	session.UserDataStream = ex.NewStream()
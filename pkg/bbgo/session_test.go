@@ -4,7 +4,10 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 func TestExchangeSession_LastPricesMutex_ConcurrentAccess(t *testing.T) {
@@ -46,3 +49,46 @@ func TestExchangeSession_LastPricesMutex_ConcurrentAccess(t *testing.T) {
 		t.Errorf("unexpected price %d, should be in [0, %d)", price.Int64(), writeCount)
 	}
 }
+
+// dryRunExchange is a minimal exchange that supports the per-instance dry-run mode.
+type dryRunExchange struct {
+	types.Exchange
+	dryRun bool
+}
+
+func (e *dryRunExchange) IsDryRun() bool { return e.dryRun }
+
+func (e *dryRunExchange) SetDryRun(dryRun bool) { e.dryRun = dryRun }
+
+func (e *dryRunExchange) NewStream() types.Stream {
+	stream := types.NewStandardStream()
+	return &stream
+}
+
+func TestExchangeSession_InitExchange_DryRun(t *testing.T) {
+	ex := &dryRunExchange{dryRun: true}
+
+	dryRun := false
+	session := &ExchangeSession{
+		ExchangeSessionConfig: ExchangeSessionConfig{
+			ExchangeName: types.ExchangePolymarket,
+			DryRun:       &dryRun,
+		},
+	}
+	assert.NoError(t, session.InitExchange("polymarket", ex))
+	assert.False(t, ex.IsDryRun())
+
+	// without the dryRun option, the exchange keeps its default mode
+	other := &dryRunExchange{dryRun: true}
+	session = &ExchangeSession{
+		ExchangeSessionConfig: ExchangeSessionConfig{ExchangeName: types.ExchangePolymarket},
+	}
+	assert.NoError(t, session.InitExchange("polymarket-dryrun", other))
+	assert.True(t, other.IsDryRun())
+
+	// the exchange must implement types.DryRunExchange
+	session = &ExchangeSession{
+		ExchangeSessionConfig: ExchangeSessionConfig{ExchangeName: types.ExchangePolymarket, DryRun: &dryRun},
+	}
+	assert.ErrorContains(t, session.InitExchange("polymarket", &struct{ types.Exchange }{}), "does not support dry-run")
+}
@@ -43,7 +43,11 @@ var factories = map[types.ExchangeName]Factory{
 	types.ExchangePolymarket: {
 		EnvLoader: DefaultEnvVarLoader,
 		Constructor: func(options Options) (types.Exchange, error) {
-			return polymarket.New(options[OptionKeyAPIKey], options[OptionKeyAPISecret], options[OptionKeyAPIPassphrase]), nil
+			ex, err := polymarket.NewWithPrivateKey(options[OptionKeyAPIKey], options[OptionKeyAPISecret], options[OptionKeyAPIPassphrase], options[OptionKeyAPIPrivateKey])
+			if err != nil {
+				return nil, err
+			}
+			return ex, nil
 		},
 	},
 }
@@ -0,0 +1,217 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// 真实下单前检查钱包的链上授权：CLOB 撮合时由 exchange 合约划转 USDC 和 outcome token，
+// 没有授权的话订单会在撮合时失败，报错也很难看懂。
+// - USDC：抵押 token（见 collateral.go）对 CTF Exchange / NegRisk CTF Exchange / NegRiskAdapter 的 ERC-20 allowance
+// - CTF（outcome token，ERC-1155）：对同样三个合约的 setApprovalForAll
+//
+// 环境变量：
+// - POLYMARKET_WALLET_ADDRESS：持有资金的钱包地址（proxy 钱包填 proxy 地址）
+// - POLYMARKET_RPC_URL：Polygon JSON-RPC 地址
+// - POLYMARKET_AUTO_APPROVE=true：缺少授权时用钱包私钥逐个发送授权交易并等待打包（见 transactions.go），
+//   ERC-20 授权额度为 2^256-1；钱包必须是私钥地址本身，proxy 钱包需要在 Polymarket 网页上授权
+
+const (
+	envWalletAddress = "POLYMARKET_WALLET_ADDRESS"
+	envRPCURL        = "POLYMARKET_RPC_URL"
+	envAutoApprove   = "POLYMARKET_AUTO_APPROVE"
+
+	defaultRPCURL = "https://polygon-rpc.com"
+)
+
+// Polygon 主网上的合约地址
+const (
+	ctfAddress            = "0x4D97DCd97eC945f40cF65F87097ACe5EA0476045"
+	negRiskAdapterAddress = "0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296"
+)
+
+// ABI 函数选择器
+const (
+	selectorAllowance         = "dd62ed3e" // allowance(address,address)
+	selectorIsApprovedForAll  = "e985e9c5" // isApprovedForAll(address,address)
+	selectorApprove           = "095ea7b3" // approve(address,uint256)
+	selectorSetApprovalForAll = "a22cb465" // setApprovalForAll(address,bool)
+)
+
+// approval 是一项下单需要的链上授权。
+type approval struct {
+	// Symbol 为 ERC-20 token 的名称，用于错误信息
+	Symbol  string
+	Token   string
+	Spender string
+	// ERC1155 为 true 时检查 isApprovedForAll，否则检查 ERC-20 allowance
+	ERC1155 bool
+}
+
+func (a approval) String() string {
+	if a.ERC1155 {
+		return fmt.Sprintf("CTF %s setApprovalForAll(%s)", a.Token, a.Spender)
+	}
+	return fmt.Sprintf("%s %s approve(%s)", a.Symbol, a.Token, a.Spender)
+}
+
+// data 返回完成授权的交易 calldata：ERC-20 approve(spender, 2^256-1)，ERC-1155 setApprovalForAll(spender, true)。
+func (a approval) data() []byte {
+	var call string
+	if a.ERC1155 {
+		call = encodeCall(selectorSetApprovalForAll, a.Spender) + strings.Repeat("0", 63) + "1"
+	} else {
+		call = encodeCall(selectorApprove, a.Spender) + strings.Repeat("f", 64)
+	}
+
+	data, _ := hex.DecodeString(strings.TrimPrefix(call, "0x"))
+	return data
+}
+
+func requiredApprovals(collateral CollateralToken) []approval {
+	var out []approval
+	for _, spender := range []string{ctfExchangeAddress, negRiskCTFExchangeAddress, negRiskAdapterAddress} {
+		out = append(out,
+			approval{Symbol: collateral.Symbol, Token: collateral.Address, Spender: spender},
+			approval{Token: ctfAddress, Spender: spender, ERC1155: true},
+		)
+	}
+	return out
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// rpcCall 调用 JSON-RPC 方法，结果解析到 result（结果为 null 时不修改 result）。
+func (c *restClient) rpcCall(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	req := rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params}
+
+	var resp rpcResponse
+	if err := c.do(ctx, c.limits.market, http.MethodPost, "", nil, req, &resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("polymarket: %s failed: %d %s", method, resp.Error.Code, resp.Error.Message)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("polymarket: invalid %s result %s: %w", method, string(resp.Result), err)
+	}
+	return nil
+}
+
+// rpcQuantity 调用返回十六进制整数的 JSON-RPC 方法。
+func (c *restClient) rpcQuantity(ctx context.Context, method string, params ...interface{}) (*big.Int, error) {
+	var result string
+	if err := c.rpcCall(ctx, method, &result, params...); err != nil {
+		return nil, err
+	}
+
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(result, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: invalid %s result %q", method, result)
+	}
+	return v, nil
+}
+
+// ethCall 调用合约的只读方法，返回 32 字节结果对应的整数。
+func (c *restClient) ethCall(ctx context.Context, to, data string) (*big.Int, error) {
+	return c.rpcQuantity(ctx, "eth_call", map[string]string{"to": to, "data": data}, "latest")
+}
+
+// encodeCall 按 ABI 编码只有 address 参数的调用。
+func encodeCall(selector string, addresses ...string) string {
+	var sb strings.Builder
+	sb.WriteString("0x")
+	sb.WriteString(selector)
+	for _, addr := range addresses {
+		addr = strings.ToLower(strings.TrimPrefix(addr, "0x"))
+		sb.WriteString(strings.Repeat("0", 64-len(addr)))
+		sb.WriteString(addr)
+	}
+	return sb.String()
+}
+
+// missingApprovals 返回 owner 还没有完成的授权。
+func (c *restClient) missingApprovals(ctx context.Context, collateral CollateralToken, owner string) ([]approval, error) {
+	var missing []approval
+	for _, a := range requiredApprovals(collateral) {
+		selector := selectorAllowance
+		if a.ERC1155 {
+			selector = selectorIsApprovedForAll
+		}
+
+		v, err := c.ethCall(ctx, a.Token, encodeCall(selector, owner, a.Spender))
+		if err != nil {
+			return nil, err
+		}
+		if v.Sign() == 0 {
+			missing = append(missing, a)
+		}
+	}
+	return missing, nil
+}
+
+// CheckAllowances 检查钱包是否已经授权 exchange 合约划转抵押 token 与 outcome token。
+// 第一次真实下单前会自动检查一次，检查通过后不再重复。
+func (e *Exchange) CheckAllowances(ctx context.Context) error {
+	e.mu.RLock()
+	checked := e.allowancesChecked
+	e.mu.RUnlock()
+	if checked {
+		return nil
+	}
+
+	owner := envString(envWalletAddress, "")
+	if owner == "" {
+		return fmt.Errorf("polymarket: %s is required to verify allowances before live trading", envWalletAddress)
+	}
+
+	missing, err := e.rpc.missingApprovals(ctx, e.collateral, owner)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, a := range missing {
+			names[i] = a.String()
+		}
+
+		if !envBool(envAutoApprove, false) {
+			return fmt.Errorf("polymarket: wallet %s is missing approvals, orders would fail at match time; approve them (e.g. via the Polymarket web app) or set %s=true: %s",
+				owner, envAutoApprove, strings.Join(names, "; "))
+		}
+
+		for _, a := range missing {
+			hash, err := e.sendTransaction(ctx, a.Token, a.data())
+			if err != nil {
+				return fmt.Errorf("polymarket: %s failed: %w", a, err)
+			}
+			log.Infof("polymarket wallet %s approval %s confirmed: %s", owner, a, hash)
+		}
+	}
+
+	e.mu.Lock()
+	e.allowancesChecked = true
+	e.mu.Unlock()
+
+	log.Infof("polymarket wallet %s allowances of %s verified", owner, e.collateral)
+	return nil
+}
@@ -0,0 +1,118 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestEncodeCall(t *testing.T) {
+	data := encodeCall(selectorAllowance, "0xAbC", ctfExchangeAddress)
+	assert.Equal(t, 2+8+64*2, len(data))
+	assert.True(t, strings.HasPrefix(data, "0xdd62ed3e"+strings.Repeat("0", 61)+"abc"))
+	assert.True(t, strings.HasSuffix(data, strings.ToLower(ctfExchangeAddress[2:])))
+}
+
+func TestExchange_CheckAllowances(t *testing.T) {
+	const owner = "0x1111111111111111111111111111111111111111"
+	t.Setenv(envWalletAddress, owner)
+
+	approved := map[string]bool{}
+	calls := 0
+	transport := &httptesting.MockTransport{}
+	transport.POST("", func(req *http.Request) (*http.Response, error) {
+		calls++
+		var rpcReq rpcRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+		assert.Equal(t, "eth_call", rpcReq.Method)
+
+		call := rpcReq.Params[0].(map[string]interface{})
+		result := "0x0"
+		if approved[call["to"].(string)] {
+			result = "0x1"
+		}
+		return httptesting.BuildResponseString(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`), nil
+	})
+
+	ex := New("", "", "")
+	ex.rpc = newTestRestClient(transport)
+
+	// 只授权了 USDC，缺少 CTF 的 setApprovalForAll
+	approved[usdceAddress] = true
+	err := ex.CheckAllowances(context.Background())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "setApprovalForAll")
+		assert.NotContains(t, err.Error(), "USDC")
+	}
+
+	// 自动授权需要私钥发送交易
+	t.Setenv(envAutoApprove, "true")
+	err = ex.CheckAllowances(context.Background())
+	assert.ErrorContains(t, err, "private key is required")
+
+	approved[ctfAddress] = true
+	assert.NoError(t, ex.CheckAllowances(context.Background()))
+
+	// 检查通过后不再请求 RPC
+	calls = 0
+	assert.NoError(t, ex.CheckAllowances(context.Background()))
+	assert.Equal(t, 0, calls)
+}
+
+func TestExchange_AutoApprove(t *testing.T) {
+	t.Setenv(envWalletAddress, testAddress)
+	t.Setenv(envAutoApprove, "true")
+	t.Setenv(envTxPollInterval, "1ms")
+
+	ex, err := NewWithPrivateKey("", "", "", testPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 只缺少 CTF 的 setApprovalForAll
+	rpc, client := newMockRPC(t, map[string]func(params []interface{}) interface{}{
+		"eth_call": func(params []interface{}) interface{} {
+			if params[0].(map[string]interface{})["to"] == ctfAddress {
+				return "0x0"
+			}
+			return "0x1"
+		},
+		"eth_getTransactionCount":   func(params []interface{}) interface{} { return "0x0" },
+		"eth_gasPrice":              func(params []interface{}) interface{} { return "0x1" },
+		"eth_estimateGas":           func(params []interface{}) interface{} { return "0x1" },
+		"eth_sendRawTransaction":    func(params []interface{}) interface{} { return "0xhash" },
+		"eth_getTransactionReceipt": func(params []interface{}) interface{} { return map[string]string{"status": "0x1"} },
+	})
+	ex.rpc = client
+
+	assert.NoError(t, ex.CheckAllowances(context.Background()))
+	assert.Len(t, rpc.params("eth_sendRawTransaction"), 3)
+
+	estimates := rpc.params("eth_estimateGas")
+	if assert.Len(t, estimates, 3) {
+		for i, spender := range []string{ctfExchangeAddress, negRiskCTFExchangeAddress, negRiskAdapterAddress} {
+			call := estimates[i][0].(map[string]interface{})
+			assert.Equal(t, ctfAddress, call["to"])
+			assert.Equal(t, encodeCall(selectorSetApprovalForAll, spender)+strings.Repeat("0", 63)+"1", call["data"])
+		}
+	}
+
+	// 检查通过后不再请求 RPC
+	assert.NoError(t, ex.CheckAllowances(context.Background()))
+	assert.Len(t, rpc.params("eth_call"), len(requiredApprovals(defaultCollateral())))
+}
+
+func TestApproval_Data(t *testing.T) {
+	a := approval{Symbol: "USDC", Token: usdceAddress, Spender: ctfExchangeAddress}
+	assert.Equal(t, encodeCall(selectorApprove, ctfExchangeAddress)[2:]+strings.Repeat("f", 64), hex.EncodeToString(a.data()))
+
+	a = approval{Token: ctfAddress, Spender: negRiskAdapterAddress, ERC1155: true}
+	assert.Equal(t, encodeCall(selectorSetApprovalForAll, negRiskAdapterAddress)[2:]+strings.Repeat("0", 63)+"1", hex.EncodeToString(a.data()))
+}
@@ -0,0 +1,151 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 改单（re-price / re-size）：
+// - dry-run 在内存中原子地修改订单的价格与数量（OrderID 不变），重新冻结余额后推送订单更新
+// - CLOB 没有改单接口，真实下单时用 cancel-replace：先提交新订单，成功后再撤掉原订单，
+//   避免出现没有挂单的空窗；新订单提交失败时原订单保持不变
+// newPrice / newQuantity 为零时保持原值，newQuantity 为订单的总数量（包含已成交部分）。
+
+// AmendOrder 修改 working 订单的价格与数量，返回修改后的订单（真实下单时为替换后的新订单）。
+func (e *Exchange) AmendOrder(ctx context.Context, order types.Order, newPrice, newQuantity fixedpoint.Value) (amended *types.Order, err error) {
+	if err := e.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	// cancel-replace 的下单与撤单请求本身会经过限流，不需要再包一层
+	if !e.IsDryRun() {
+		return e.cancelReplaceOrder(ctx, order, newPrice, newQuantity)
+	}
+
+	err = e.limits.do(ctx, e.limits.order, func() error {
+		amended, err = e.amendDryRunOrder(order, newPrice, newQuantity)
+		return err
+	})
+	return amended, err
+}
+
+// amendedSubmitOrder 返回按新价格与数量修改后的下单参数。
+func (e *Exchange) amendedSubmitOrder(o types.Order, newPrice, newQuantity fixedpoint.Value) (types.SubmitOrder, error) {
+	amended := o.SubmitOrder
+	if newPrice.Sign() > 0 {
+		amended.Price = newPrice
+	}
+	if newQuantity.Sign() > 0 {
+		amended.Quantity = newQuantity
+	}
+
+	if amended.Quantity.Compare(o.ExecutedQuantity) <= 0 {
+		return amended, fmt.Errorf("polymarket: amended quantity %s of order %d must be greater than the executed quantity %s",
+			amended.Quantity.String(), o.OrderID, o.ExecutedQuantity.String())
+	}
+	return e.prepareOrder(amended)
+}
+
+func (e *Exchange) amendDryRunOrder(order types.Order, newPrice, newQuantity fixedpoint.Value) (*types.Order, error) {
+	if err := e.loadOrders(); err != nil {
+		return nil, err
+	}
+
+	current, ok := e.findOrder(order.OrderID, order.ClientOrderID)
+	if !ok || !current.IsWorking {
+		return nil, fmt.Errorf("polymarket: working order not found (id %d, client order id %q)", order.OrderID, order.ClientOrderID)
+	}
+
+	// roundOrderPrice 需要 e.mu，在锁外计算
+	amended, err := e.amendedSubmitOrder(current, newPrice, newQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	snapshot, err := e.amendOrderLocked(current, amended)
+	if err != nil {
+		e.mu.RUnlock()
+		return nil, err
+	}
+	e.saveOrdersLocked()
+	balances := e.orderBalancesLocked(snapshot)
+	e.mu.RUnlock()
+
+	log.WithFields(snapshot.LogFields()).Infof("polymarket(dry-run) order amended: %s", snapshot.String())
+	e.logDryRunEvent(DryRunEventAmended, snapshot)
+	e.emitOrderUpdate(snapshot)
+	e.emitBalanceUpdate(balances)
+	return &snapshot, nil
+}
+
+// amendOrderLocked 在订单所在的分片上把 working 订单改为 amended，返回改单后的快照，需要持有 e.mu 的读锁。
+func (e *Exchange) amendOrderLocked(current types.Order, amended types.SubmitOrder) (types.Order, error) {
+	sh, ok := e.shardOf(current.OrderID, "")
+	if !ok {
+		return types.Order{}, fmt.Errorf("polymarket: working order not found (id %d, client order id %q)", current.OrderID, current.ClientOrderID)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	// 计算期间订单可能已经成交、被撤掉或被清理
+	existing, ok := sh.findLocked(current.OrderID, "")
+	if !ok {
+		return types.Order{}, fmt.Errorf("polymarket: order %d was pruned while amending", current.OrderID)
+	}
+	if !existing.IsWorking || amended.Quantity.Compare(existing.ExecutedQuantity) <= 0 {
+		return types.Order{}, fmt.Errorf("polymarket: order %d changed while amending, status %s, executed %s",
+			existing.OrderID, existing.Status, existing.ExecutedQuantity.String())
+	}
+
+	if err := e.checkMarketOpenLocked(amended.Symbol, time.Now()); err != nil {
+		return types.Order{}, err
+	}
+
+	if err := e.checkPostOnlyLocked(amended); err != nil {
+		return types.Order{}, err
+	}
+
+	// 先解冻原订单的剩余部分，再按新的价格与数量冻结，余额不足时恢复原订单
+	e.unlockBalanceLocked(existing)
+	previous := existing.SubmitOrder
+	existing.SubmitOrder = amended
+	if err := e.lockRemainingLocked(sh, existing); err != nil {
+		existing.SubmitOrder = previous
+		e.relockRestoredLocked(existing)
+		return types.Order{}, err
+	}
+
+	existing.UpdateTime = types.Time(time.Now())
+	return *existing, nil
+}
+
+// cancelReplaceOrder 提交剩余数量的新订单，成功后撤掉原订单。
+func (e *Exchange) cancelReplaceOrder(ctx context.Context, order types.Order, newPrice, newQuantity fixedpoint.Value) (*types.Order, error) {
+	if order.UUID == "" {
+		return nil, fmt.Errorf("polymarket: order %d has no CLOB order hash (UUID)", order.OrderID)
+	}
+
+	amended, err := e.amendedSubmitOrder(order, newPrice, newQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := amended
+	replacement.Quantity = amended.Quantity.Sub(order.ExecutedQuantity)
+	created, err := e.submitOrder(ctx, replacement)
+	e.recordOrderSubmission(replacement, err)
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: submit the replacement of order %s failed, the original order is kept: %w", order.UUID, err)
+	}
+
+	if err := e.cancelLiveOrders(ctx, []types.Order{order}); err != nil {
+		return created, fmt.Errorf("polymarket: cancel the original order %s after replacing it with %s failed: %w", order.UUID, created.UUID, err)
+	}
+	return created, nil
+}
@@ -0,0 +1,130 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_AmendOrder(t *testing.T) {
+	t.Setenv(envBalanceUSDC, "10")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	stream := ex.NewStream()
+
+	var updates []types.Order
+	stream.OnOrderUpdate(func(o types.Order) {
+		updates = append(updates, o)
+	})
+
+	ctx := context.Background()
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	amended, err := ex.AmendOrder(ctx, *order, fixedpoint.NewFromFloat(0.45), fixedpoint.NewFromFloat(20))
+	if assert.NoError(t, err) {
+		assert.Equal(t, order.OrderID, amended.OrderID)
+		assert.Equal(t, "0.45", amended.Price.String())
+		assert.Equal(t, "20", amended.Quantity.String())
+		assert.Equal(t, types.OrderStatusNew, amended.Status)
+	}
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, balances["USDC"].Available.Float64(), 1e-6)
+	assert.InDelta(t, 9, balances["USDC"].Locked.Float64(), 1e-6)
+
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, "0.45", updates[1].Price.String())
+	}
+
+	// 余额不足时原订单保持不变
+	_, err = ex.AmendOrder(ctx, *order, fixedpoint.Zero, fixedpoint.NewFromFloat(30))
+	assert.True(t, errors.Is(err, errInsufficientBalance), "%v", err)
+
+	openOrders, err := ex.QueryOpenOrders(ctx, "PM_BTC_15M_UP_YES_USDC")
+	assert.NoError(t, err)
+	if assert.Len(t, openOrders, 1) {
+		assert.Equal(t, "20", openOrders[0].Quantity.String())
+		assert.Equal(t, "0.45", openOrders[0].Price.String())
+	}
+	balances, err = ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.InDelta(t, 9, balances["USDC"].Locked.Float64(), 1e-6)
+
+	// 只改价格
+	amended, err = ex.AmendOrder(ctx, *order, fixedpoint.NewFromFloat(0.4), fixedpoint.Zero)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "20", amended.Quantity.String())
+		assert.Equal(t, "0.4", amended.Price.String())
+	}
+
+	assert.NoError(t, ex.CancelOrders(ctx, *order))
+	_, err = ex.AmendOrder(ctx, *order, fixedpoint.NewFromFloat(0.3), fixedpoint.Zero)
+	assert.ErrorContains(t, err, "working order not found")
+}
+
+func TestExchange_AmendPartiallyFilledOrder(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunFillChunk, "4")
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.45))
+
+	_, err = ex.AmendOrder(ctx, *order, fixedpoint.Zero, fixedpoint.NewFromFloat(4))
+	assert.ErrorContains(t, err, "must be greater than the executed quantity")
+
+	amended, err := ex.AmendOrder(ctx, *order, fixedpoint.Zero, fixedpoint.NewFromFloat(6))
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderStatusPartiallyFilled, amended.Status)
+		assert.Equal(t, "4", amended.ExecutedQuantity.String())
+		assert.Equal(t, "6", amended.Quantity.String())
+	}
+}
+
+func TestExchange_AmendOrderLive(t *testing.T) {
+	t.Setenv(envDryRun, "false")
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	_, err := ex.AmendOrder(context.Background(), types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "PM_BTC_15M_UP_YES_USDC",
+			Side:     types.SideTypeBuy,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		},
+		OrderID: 1,
+	}, fixedpoint.NewFromFloat(0.4), fixedpoint.Zero)
+	assert.ErrorContains(t, err, "has no CLOB order hash")
+}
@@ -0,0 +1,64 @@
+package polymarket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+)
+
+// CLOB 的私有接口（L2 鉴权）需要在请求头里带上 API key 与 HMAC 签名：
+// POLY_SIGNATURE = base64url(HMAC-SHA256(base64url_decode(secret), timestamp + method + path + body))。
+// path 不包含 query string。POLY_ADDRESS 为创建 API key 的签名钱包地址，
+// 取 POLYMARKET_SIGNER_ADDRESS，未设置时回退到 POLYMARKET_WALLET_ADDRESS。
+
+const envSignerAddress = "POLYMARKET_SIGNER_ADDRESS"
+
+type apiCredentials struct {
+	address    string
+	key        string
+	secret     string
+	passphrase string
+}
+
+// newAPICredentials 在没有配置 API key 时返回 nil，此时只能访问公开接口。
+func newAPICredentials(key, secret, passphrase string) *apiCredentials {
+	if key == "" || secret == "" {
+		return nil
+	}
+
+	return &apiCredentials{
+		address:    envString(envSignerAddress, envString(envWalletAddress, "")),
+		key:        key,
+		secret:     secret,
+		passphrase: passphrase,
+	}
+}
+
+func (c *apiCredentials) signature(timestamp int64, method, path string, body []byte) (string, error) {
+	secret, err := base64.URLEncoding.DecodeString(c.secret)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + method + path))
+	mac.Write(body)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// sign 给请求加上 L2 鉴权请求头。
+func (c *apiCredentials) sign(req *http.Request, timestamp int64, path string, body []byte) error {
+	sig, err := c.signature(timestamp, req.Method, path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("POLY_ADDRESS", c.address)
+	req.Header.Set("POLY_SIGNATURE", sig)
+	req.Header.Set("POLY_TIMESTAMP", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("POLY_API_KEY", c.key)
+	req.Header.Set("POLY_PASSPHRASE", c.passphrase)
+	return nil
+}
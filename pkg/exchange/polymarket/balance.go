@@ -0,0 +1,333 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 余额：
+// - POLYMARKET_BALANCE_USDC 为起始 USDC 余额；未设置时不检查余额（保持之前“无限余额”的行为）
+// - 买单创建时冻结 price*quantity + 手续费，可用余额不足时拒单
+// - 买单成交时扣除冻结金额，撤单时解冻未成交部分；卖单成交时把成交金额扣除手续费后记入可用余额
+// - 从持久化恢复的 working 买单会重新冻结
+// - 模拟成交同时更新 outcome token 持仓（以 market 的 BaseCurrency 为币种），不受 POLYMARKET_BALANCE_USDC 影响
+// - up/down 市场结算后赢的 token 持仓按 1 USDC 兑付记入可用余额，输的持仓清零（见 redeem.go）
+// - working 卖单未成交部分的 token 计入 Locked，Available 为持仓减去冻结部分（按当前订单计算，不单独记账）
+// - 设置了起始余额时卖单数量不能超过可用（未被其它卖单冻结）的 token 持仓，否则拒单，避免卖出没有的 token 凭空得到 USDC
+// - 下单、改单、撤单与成交后通过 user data stream 推送 USDC 与相关 token 的余额（types.BalanceUpdate），
+//   session 的 Account 因此能看到冻结金额的变化
+// 余额与持仓由所有 symbol 的订单共用，由 e.balanceMu 保护（见 order_shards.go），冻结时的检查与扣减在同一次加锁内完成；
+// 卖单冻结的 token 只统计该 symbol 分片里的 working 卖单，检查时需要持有该分片的锁。
+
+var (
+	errInsufficientBalance  = errors.New("polymarket(dry-run): insufficient USDC balance")
+	errInsufficientPosition = errors.New("polymarket(dry-run): insufficient token position")
+)
+
+type dryRunBalance struct {
+	enabled bool
+
+	available fixedpoint.Value
+	locked    fixedpoint.Value
+
+	// positions 为 outcome token 持仓，key 为 market 的 BaseCurrency
+	positions map[string]fixedpoint.Value
+}
+
+func newDryRunBalanceFromEnv() *dryRunBalance {
+	b := &dryRunBalance{positions: make(map[string]fixedpoint.Value)}
+
+	v := envString(envBalanceUSDC, "")
+	if v == "" {
+		return b
+	}
+
+	balance, err := fixedpoint.NewFromString(v)
+	if err != nil || balance.Sign() < 0 {
+		log.Warnf("invalid %s %q, dry-run balance is not enforced", envBalanceUSDC, v)
+		return b
+	}
+
+	b.enabled, b.available = true, balance
+	return b
+}
+
+func (b *dryRunBalance) toGlobalBalance() types.Balance {
+	return types.Balance{Currency: collateralCurrency, Available: b.available, Locked: b.locked}
+}
+
+// orderCostLocked 返回买单未成交部分需要冻结的金额（含手续费），需要持有 e.mu 的读锁。
+func (e *Exchange) orderCostLocked(o types.SubmitOrder, quantity fixedpoint.Value) fixedpoint.Value {
+	feeRateBps := fixedpoint.NewFromInt(int64(e.fees.feeRateBps(o.Symbol)))
+	return o.Price.Mul(quantity).Add(tradeFee(o.Price, quantity, feeRateBps))
+}
+
+// checkBalanceLocked 检查可用余额是否足够新买单冻结（卖单检查可用的 token 持仓），返回需要冻结的金额，
+// 需要持有 e.mu 的读锁与订单 symbol 的 sh.mu。
+func (e *Exchange) checkBalanceLocked(sh *orderShard, o types.SubmitOrder) (fixedpoint.Value, error) {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+	return e.checkBalanceHeldLocked(sh, o)
+}
+
+// checkBalanceHeldLocked 同 checkBalanceLocked，调用方已经持有 e.balanceMu。
+func (e *Exchange) checkBalanceHeldLocked(sh *orderShard, o types.SubmitOrder) (fixedpoint.Value, error) {
+	if !e.balance.enabled {
+		return fixedpoint.Zero, nil
+	}
+	if o.Side == types.SideTypeSell {
+		return fixedpoint.Zero, e.checkPositionLocked(sh, o.Quantity, fixedpoint.Zero)
+	}
+	if o.Side != types.SideTypeBuy {
+		return fixedpoint.Zero, nil
+	}
+
+	cost := e.orderCostLocked(o, o.Quantity)
+	if cost.Compare(e.balance.available) > 0 {
+		return cost, fmt.Errorf("%w: %s order cost %s USDC, available %s USDC",
+			errInsufficientBalance, o.Symbol, cost.String(), e.balance.available.String())
+	}
+	return cost, nil
+}
+
+// checkPositionLocked 检查在 sh 的 symbol 上卖出 quantity 是否超过可用的 token 持仓，需要持有 e.mu 的读锁、sh.mu 与 e.balanceMu。
+// 可用持仓为持仓减去 working 卖单冻结的部分，released 为其中属于正在检查的订单本身、不需要扣除的数量（改单时）。
+func (e *Exchange) checkPositionLocked(sh *orderShard, quantity, released fixedpoint.Value) error {
+	currency := e.positionCurrencyLocked(sh.symbol)
+	locked := sh.lockedPositionLocked().Sub(released)
+	available := e.balance.positions[currency].Sub(locked)
+	if quantity.Compare(available) > 0 {
+		return fmt.Errorf("%w: %s sell quantity %s %s, available %s",
+			errInsufficientPosition, sh.symbol, quantity.String(), currency, fixedpoint.Max(available, fixedpoint.Zero).String())
+	}
+	return nil
+}
+
+// lockBalanceLocked 为新买单冻结余额，余额不足（卖单为 token 持仓不足）时返回错误，需要持有 e.mu 的读锁与 sh.mu。
+// 卖单冻结的 token 按 working 订单计算，不需要单独记账。
+func (e *Exchange) lockBalanceLocked(sh *orderShard, o types.SubmitOrder) error {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	if !e.balance.enabled {
+		return nil
+	}
+
+	cost, err := e.checkBalanceHeldLocked(sh, o)
+	if err != nil || o.Side != types.SideTypeBuy {
+		return err
+	}
+
+	e.balance.available = e.balance.available.Sub(cost)
+	e.balance.locked = e.balance.locked.Add(cost)
+	return nil
+}
+
+// lockRemainingLocked 为改单后的 working 买单冻结未成交部分，余额不足（卖单为 token 持仓不足）时返回错误，
+// 需要持有 e.mu 的读锁与订单所在的 sh.mu。
+func (e *Exchange) lockRemainingLocked(sh *orderShard, o *types.Order) error {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	if !e.balance.enabled {
+		return nil
+	}
+	if o.Side == types.SideTypeSell {
+		// 改单后的订单已经计入冻结的 token
+		remaining := o.Quantity.Sub(o.ExecutedQuantity)
+		return e.checkPositionLocked(sh, remaining, remaining)
+	}
+	if o.Side != types.SideTypeBuy {
+		return nil
+	}
+
+	cost := e.orderCostLocked(o.SubmitOrder, o.Quantity.Sub(o.ExecutedQuantity))
+	if cost.Compare(e.balance.available) > 0 {
+		return fmt.Errorf("%w: %s order cost %s USDC, available %s USDC",
+			errInsufficientBalance, o.Symbol, cost.String(), e.balance.available.String())
+	}
+
+	e.balance.available = e.balance.available.Sub(cost)
+	e.balance.locked = e.balance.locked.Add(cost)
+	return nil
+}
+
+// relockRestoredLocked 重新冻结 working 买单的未成交部分（从持久化恢复时，或改单失败后恢复原订单），
+// 需要持有 e.mu（至少读锁），改单时还需要持有订单所在的 sh.mu。
+func (e *Exchange) relockRestoredLocked(o *types.Order) {
+	if !o.IsWorking || o.Side != types.SideTypeBuy {
+		return
+	}
+
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	if !e.balance.enabled {
+		return
+	}
+
+	cost := e.orderCostLocked(o.SubmitOrder, o.Quantity.Sub(o.ExecutedQuantity))
+	e.balance.available = e.balance.available.Sub(cost)
+	e.balance.locked = e.balance.locked.Add(cost)
+	if e.balance.available.Sign() < 0 {
+		log.Warnf("restored dry-run orders lock more than %s, available balance is %s USDC", envBalanceUSDC, e.balance.available.String())
+	}
+}
+
+// positionCurrencyLocked 返回 symbol 的 outcome token 币种（market 的 BaseCurrency），需要持有 e.mu 的读锁。
+func (e *Exchange) positionCurrencyLocked(symbol string) string {
+	if m, ok := e.markets[symbol]; ok && m.BaseCurrency != "" {
+		return m.BaseCurrency
+	}
+	return symbol
+}
+
+// lockedPositionLocked 返回 shard 中 working 卖单未成交部分的 token 数量，需要持有 sh.mu。
+func (sh *orderShard) lockedPositionLocked() fixedpoint.Value {
+	locked := fixedpoint.Zero
+	for _, o := range sh.orders {
+		if o.IsWorking && o.Side == types.SideTypeSell {
+			locked = locked.Add(o.Quantity.Sub(o.ExecutedQuantity))
+		}
+	}
+	return locked
+}
+
+// lockedPositionsLocked 逐个 shard 统计 symbols（为空时为全部）working 卖单冻结的 token 数量，key 为 token 币种，
+// 需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) lockedPositionsLocked(symbols ...string) map[string]fixedpoint.Value {
+	var shards []*orderShard
+	if len(symbols) == 0 {
+		shards = e.shardList()
+	}
+	for _, symbol := range symbols {
+		if sh := e.lookupShard(symbol); sh != nil {
+			shards = append(shards, sh)
+		}
+	}
+
+	locked := make(map[string]fixedpoint.Value)
+	for _, sh := range shards {
+		sh.mu.Lock()
+		quantity := sh.lockedPositionLocked()
+		sh.mu.Unlock()
+
+		currency := e.positionCurrencyLocked(sh.symbol)
+		locked[currency] = locked[currency].Add(quantity)
+	}
+	return locked
+}
+
+// positionBalance 返回 token 持仓的余额：卖单冻结的部分计入 Locked（不超过持仓）。
+func positionBalance(currency string, position, locked fixedpoint.Value) types.Balance {
+	locked = fixedpoint.Min(locked, position)
+	return types.Balance{Currency: currency, Available: position.Sub(locked), Locked: locked}
+}
+
+// balancesLocked 返回 USDC（设置了起始余额时）与 symbols 对应 token 持仓的余额，需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) balancesLocked(symbols ...string) types.BalanceMap {
+	var locked map[string]fixedpoint.Value
+	if len(symbols) > 0 {
+		locked = e.lockedPositionsLocked(symbols...)
+	}
+
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	balances := make(types.BalanceMap)
+	if e.balance.enabled {
+		balances[collateralCurrency] = e.balance.toGlobalBalance()
+	}
+	for _, symbol := range symbols {
+		currency := e.positionCurrencyLocked(symbol)
+		balances[currency] = positionBalance(currency, e.balance.positions[currency], locked[currency])
+	}
+	return balances
+}
+
+// orderBalancesLocked 返回下单、改单或撤单后冻结金额会变化的余额：USDC（设置了起始余额时）与卖单的 token，
+// 需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) orderBalancesLocked(orders ...types.Order) types.BalanceMap {
+	var symbols []string
+	for _, o := range orders {
+		if o.Side == types.SideTypeSell {
+			symbols = append(symbols, o.Symbol)
+		}
+	}
+	return e.balancesLocked(symbols...)
+}
+
+// dryRunBalancesLocked 返回模拟盘的全部余额：USDC（设置了起始余额时）与所有非零的 token 持仓，
+// 需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) dryRunBalancesLocked() types.BalanceMap {
+	locked := e.lockedPositionsLocked()
+
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	balances := make(types.BalanceMap)
+	if e.balance.enabled {
+		balances[collateralCurrency] = e.balance.toGlobalBalance()
+	}
+	for currency, quantity := range e.balance.positions {
+		if quantity.Sign() > 0 {
+			balances[currency] = positionBalance(currency, quantity, locked[currency])
+		}
+	}
+	return balances
+}
+
+// settleFillLocked 在订单以 price 成交 quantity 后结算余额与持仓，需要持有 e.mu 的读锁与订单所在的 sh.mu。
+// 买单按限价冻结，成交价更差（滑点）时差额从可用余额扣除。
+func (e *Exchange) settleFillLocked(o *types.Order, quantity, price fixedpoint.Value) {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	currency := e.positionCurrencyLocked(o.Symbol)
+	switch o.Side {
+	case types.SideTypeBuy:
+		e.balance.positions[currency] = e.balance.positions[currency].Add(quantity)
+	case types.SideTypeSell:
+		e.balance.positions[currency] = fixedpoint.Max(e.balance.positions[currency].Sub(quantity), fixedpoint.Zero)
+	}
+
+	if !e.balance.enabled {
+		return
+	}
+
+	switch o.Side {
+	case types.SideTypeBuy:
+		locked := e.orderCostLocked(o.SubmitOrder, quantity)
+		e.balance.locked = fixedpoint.Max(e.balance.locked.Sub(locked), fixedpoint.Zero)
+
+		filled := o.SubmitOrder
+		filled.Price = price
+		e.balance.available = e.balance.available.Add(locked).Sub(e.orderCostLocked(filled, quantity))
+
+	case types.SideTypeSell:
+		feeRateBps := fixedpoint.NewFromInt(int64(e.fees.feeRateBps(o.Symbol)))
+		proceeds := price.Mul(quantity).Sub(tradeFee(price, quantity, feeRateBps))
+		e.balance.available = e.balance.available.Add(proceeds)
+	}
+}
+
+// unlockBalanceLocked 在撤单（或改单前）解冻买单未成交部分，需要持有 e.mu 的读锁与订单所在的 sh.mu。
+func (e *Exchange) unlockBalanceLocked(o *types.Order) {
+	if o.Side != types.SideTypeBuy {
+		return
+	}
+
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	if !e.balance.enabled {
+		return
+	}
+
+	cost := fixedpoint.Min(e.orderCostLocked(o.SubmitOrder, o.Quantity.Sub(o.ExecutedQuantity)), e.balance.locked)
+	e.balance.locked = e.balance.locked.Sub(cost)
+	e.balance.available = e.balance.available.Add(cost)
+}
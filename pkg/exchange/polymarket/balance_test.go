@@ -0,0 +1,253 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DryRunBalance(t *testing.T) {
+	t.Setenv(envBalanceUSDC, "10")
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+
+	newOrder := func(side types.SideType, price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		}
+	}
+
+	assertBalance := func(available, locked string) {
+		balances, err := ex.QueryAccountBalances(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, available, balances["USDC"].Available.String())
+		assert.Equal(t, locked, balances["USDC"].Locked.String())
+	}
+
+	// 买单冻结 price * quantity
+	order, err := ex.SubmitOrder(ctx, newOrder(types.SideTypeBuy, 0.5, 10))
+	assert.NoError(t, err)
+	assertBalance("5", "5")
+
+	// 超过可用余额的买单被拒绝，不创建订单
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeBuy, 0.5, 12))
+	assert.ErrorIs(t, err, errInsufficientBalance)
+	assertBalance("5", "5")
+
+	// 撤单解冻
+	assert.NoError(t, ex.CancelOrders(ctx, *order))
+	assertBalance("10", "0")
+
+	// 批量下单中余额不足的订单单独失败
+	created, err := ex.SubmitOrders(ctx, newOrder(types.SideTypeBuy, 0.4, 10), newOrder(types.SideTypeBuy, 0.4, 20))
+	var batchErr *BatchOrderError
+	if assert.ErrorAs(t, err, &batchErr) {
+		assert.NoError(t, batchErr.Errors[0])
+		assert.ErrorIs(t, batchErr.Errors[1], errInsufficientBalance)
+	}
+	assert.NotZero(t, created[0].OrderID)
+	assert.Zero(t, created[1].OrderID)
+	assertBalance("6", "4")
+
+	// 买单成交后扣除冻结金额，卖单成交后记入可用余额
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.4))
+	assertBalance("6", "0")
+
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeSell, 0.6, 10))
+	assert.NoError(t, err)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.6))
+	assertBalance("12", "0")
+}
+
+func TestExchange_BalanceUpdateAfterFill(t *testing.T) {
+	t.Setenv(envBalanceUSDC, "10")
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	stream := ex.NewStream()
+	var updates []types.BalanceMap
+	stream.OnBalanceUpdate(func(balances types.BalanceMap) {
+		updates = append(updates, balances)
+	})
+
+	_, err = ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+	// 下单后推送冻结的 USDC
+	if assert.Len(t, updates, 1) {
+		assert.Equal(t, "5", updates[0]["USDC"].Available.String())
+		assert.Equal(t, "5", updates[0]["USDC"].Locked.String())
+	}
+
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, "5", updates[1]["USDC"].Available.String())
+		assert.Equal(t, "0", updates[1]["USDC"].Locked.String())
+		assert.Equal(t, "10", updates[1]["PM_BTC_15M_UP_YES"].Available.String())
+	}
+
+	// 账户余额包含 token 持仓
+	balances, err := ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", balances["PM_BTC_15M_UP_YES"].Available.String())
+}
+
+func TestExchange_DryRunLockedPosition(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	currency := "PM_BTC_15M_UP_YES"
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	stream := ex.NewStream()
+	var updates []types.BalanceMap
+	stream.OnBalanceUpdate(func(balances types.BalanceMap) {
+		updates = append(updates, balances)
+	})
+
+	newOrder := func(side types.SideType, price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		}
+	}
+
+	assertPosition := func(available, locked string) {
+		balances, err := ex.QueryAccountBalances(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, available, balances[currency].Available.String())
+		assert.Equal(t, locked, balances[currency].Locked.String())
+	}
+
+	// 没有设置起始余额时买单不推送余额
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeBuy, 0.5, 10))
+	assert.NoError(t, err)
+	assert.Empty(t, updates)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	assertPosition("10", "0")
+
+	// 卖单冻结未成交部分的 token
+	sell, err := ex.SubmitOrder(ctx, newOrder(types.SideTypeSell, 0.7, 4))
+	assert.NoError(t, err)
+	assertPosition("6", "4")
+	if assert.NotEmpty(t, updates) {
+		last := updates[len(updates)-1]
+		assert.Equal(t, "4", last[currency].Locked.String())
+		assert.NotContains(t, last, "USDC")
+	}
+
+	// 撤单解冻
+	assert.NoError(t, ex.CancelOrders(ctx, *sell))
+	assertPosition("10", "0")
+	assert.Equal(t, "0", updates[len(updates)-1][currency].Locked.String())
+
+	// 成交后持仓与冻结同时减少
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeSell, 0.6, 4))
+	assert.NoError(t, err)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.6))
+	assertPosition("6", "0")
+}
+
+func TestExchange_DryRunSellPosition(t *testing.T) {
+	t.Setenv(envBalanceUSDC, "100")
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	newOrder := func(side types.SideType, price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		}
+	}
+
+	// 没有持仓时不能卖出，也不会凭空得到 USDC
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeSell, 0.5, 10))
+	assert.ErrorIs(t, err, errInsufficientPosition)
+	_, err = ex.SimulateOrder(ctx, newOrder(types.SideTypeSell, 0.5, 10))
+	assert.ErrorIs(t, err, errInsufficientPosition)
+
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeBuy, 0.5, 10))
+	assert.NoError(t, err)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+
+	// working 卖单冻结的 token 不能再卖出
+	sell, err := ex.SubmitOrder(ctx, newOrder(types.SideTypeSell, 0.7, 6))
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = ex.SubmitOrder(ctx, newOrder(types.SideTypeSell, 0.7, 5))
+	assert.ErrorIs(t, err, errInsufficientPosition)
+
+	orders, err := ex.SubmitOrders(ctx, newOrder(types.SideTypeSell, 0.7, 4), newOrder(types.SideTypeSell, 0.7, 1))
+	var batchErr *BatchOrderError
+	if assert.ErrorAs(t, err, &batchErr) && assert.Len(t, orders, 2) {
+		assert.NoError(t, batchErr.Errors[0])
+		assert.ErrorIs(t, batchErr.Errors[1], errInsufficientPosition)
+		assert.NoError(t, ex.CancelOrders(ctx, orders[0]))
+	}
+
+	// 改单时原订单冻结的部分可以继续使用
+	_, err = ex.AmendOrder(ctx, *sell, fixedpoint.Zero, fixedpoint.NewFromFloat(10))
+	assert.NoError(t, err)
+	_, err = ex.AmendOrder(ctx, *sell, fixedpoint.Zero, fixedpoint.NewFromFloat(11))
+	assert.ErrorIs(t, err, errInsufficientPosition)
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "95", balances["USDC"].Available.String())
+		assert.Equal(t, "10", balances["PM_BTC_15M_UP_YES"].Locked.String())
+	}
+}
+
+func TestPositionBalance(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	b := positionBalance("PM_YES", f(10), f(4))
+	assert.Equal(t, "6", b.Available.String())
+	assert.Equal(t, "4", b.Locked.String())
+
+	// 卖出数量超过持仓时冻结全部持仓
+	b = positionBalance("PM_YES", f(3), f(4))
+	assert.Equal(t, "0", b.Available.String())
+	assert.Equal(t, "3", b.Locked.String())
+}
@@ -0,0 +1,187 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// CLOB 的 POST /orders 一次最多接受 15 个订单，超过的按批拆分提交。
+const maxBatchOrders = 15
+
+// BatchOrderError 记录批量下单中失败的订单，Errors 与提交的订单一一对应，成功的订单为 nil。
+type BatchOrderError struct {
+	Errors []error
+}
+
+func (e *BatchOrderError) Error() string {
+	failed := 0
+	var first error
+	for _, err := range e.Errors {
+		if err != nil {
+			failed++
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return fmt.Sprintf("polymarket: %d of %d orders failed: %v", failed, len(e.Errors), first)
+}
+
+// SubmitOrders 批量下单。返回的订单与 orders 一一对应，失败的订单为零值（OrderID 为 0），
+// 有订单失败时返回 *BatchOrderError，可以从中取得每个订单的错误。
+// dry-run 下所有订单在同一次加锁中创建，只持久化一次；client order id 已存在的订单返回原订单。
+func (e *Exchange) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (_ []types.Order, err error) {
+	errs := make([]error, len(orders))
+	defer func() {
+		for i, order := range orders {
+			// 整批失败时每个订单都记为 rejected
+			orderErr := errs[i]
+			if orderErr == nil {
+				if _, ok := err.(*BatchOrderError); !ok {
+					orderErr = err
+				}
+			}
+			e.recordOrderSubmission(order, orderErr)
+		}
+	}()
+
+	if err := e.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	// orders 可能是调用方的 slice，调整价格时复制一份；价格越界的订单不提交，见 checkOrderPrice
+	rounded := make([]types.SubmitOrder, len(orders))
+	for i, order := range orders {
+		rounded[i], errs[i] = e.prepareOrder(order)
+	}
+
+	created := make([]types.Order, len(orders))
+
+	for start := 0; start < len(orders); start += maxBatchOrders {
+		end := start + maxBatchOrders
+		if end > len(orders) {
+			end = len(orders)
+		}
+
+		submit := func() error {
+			return e.submitOrders(ctx, rounded[start:end], created[start:end], errs[start:end])
+		}
+
+		// 真实下单的 HTTP 请求本身会经过 order 限流，只有 dry-run 在这里限流
+		var err error
+		if e.IsDryRun() {
+			err = e.limits.do(ctx, e.limits.order, submit)
+		} else {
+			err = submit()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return created, &BatchOrderError{Errors: errs}
+		}
+	}
+	return created, nil
+}
+
+// submitOrders 提交一批订单，结果写入 created / errs 的对应位置；返回的 error 表示整批失败。
+func (e *Exchange) submitOrders(ctx context.Context, orders []types.SubmitOrder, created []types.Order, errs []error) error {
+	if !e.IsDryRun() {
+		now := time.Now()
+		clobOrders := make([]*CLOBOrder, len(orders))
+		for i, order := range orders {
+			if errs[i] != nil {
+				continue
+			}
+			if errs[i] = e.checkMarketOpen(order.Symbol, now); errs[i] != nil {
+				continue
+			}
+			clobOrders[i], errs[i] = e.buildOrder(order)
+		}
+
+		if err := e.CheckAllowances(ctx); err != nil {
+			return err
+		}
+
+		if err := e.assignNonces(ctx, clobOrders...); err != nil {
+			return err
+		}
+
+		var signed []*CLOBOrder
+		var indexes []int
+		for i, o := range clobOrders {
+			if o == nil {
+				continue
+			}
+			if errs[i] = e.signOrder(o); errs[i] != nil {
+				continue
+			}
+			signed = append(signed, o)
+			indexes = append(indexes, i)
+		}
+		if len(signed) == 0 {
+			return nil
+		}
+
+		resps, err := e.clobAPI().PlaceOrders(ctx, signed)
+		if err != nil {
+			return err
+		}
+
+		now = time.Now()
+		for j, i := range indexes {
+			resp := &resps[j]
+			if !resp.Success && resp.ErrorMsg != "" {
+				errs[i] = fmt.Errorf("polymarket: place order rejected: %s", resp.ErrorMsg)
+				continue
+			}
+			created[i] = toGlobalPlacedOrder(orders[i], resp, now)
+			log.WithFields(created[i].LogFields()).Infof("polymarket order placed: %s", created[i].String())
+		}
+		return nil
+	}
+
+	if err := e.loadOrders(); err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+
+	now := time.Now()
+	existed := make([]bool, len(orders))
+	for i, order := range orders {
+		if errs[i] != nil {
+			continue
+		}
+		created[i], _, existed[i], errs[i] = e.placeOrderLocked(order, now, false)
+	}
+	e.saveOrdersLocked()
+	e.startMatcher()
+	e.startJanitor()
+
+	var newOrders []types.Order
+	for i, o := range created {
+		if o.OrderID != 0 && !existed[i] {
+			newOrders = append(newOrders, o)
+		}
+	}
+	var balances types.BalanceMap
+	if len(newOrders) > 0 {
+		balances = e.orderBalancesLocked(newOrders...)
+	}
+	e.mu.RUnlock()
+
+	for _, o := range newOrders {
+		log.WithFields(o.LogFields()).Infof("polymarket(dry-run) order created: %s", o.String())
+		e.logDryRunEvent(DryRunEventCreated, o)
+		e.emitOrderUpdate(o)
+	}
+	e.emitBalanceUpdate(balances)
+	return nil
+}
@@ -0,0 +1,136 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_SubmitOrders(t *testing.T) {
+	newOrder := func(symbol string) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		}
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		ex := New("", "", "")
+		stream := ex.NewStream()
+
+		updates := 0
+		stream.OnOrderUpdate(func(o types.Order) {
+			updates++
+		})
+
+		var orders []types.SubmitOrder
+		for i := 0; i < maxBatchOrders+5; i++ {
+			orders = append(orders, newOrder("PM_BTC_15M_UP_YES_USDC"))
+		}
+
+		created, err := ex.SubmitOrders(context.Background(), orders...)
+		assert.NoError(t, err)
+		assert.Len(t, created, len(orders))
+		for i, o := range created {
+			assert.Equal(t, uint64(i+1), o.OrderID)
+			assert.Equal(t, types.OrderStatusNew, o.Status)
+		}
+		assert.Equal(t, len(orders), updates)
+
+		open, err := ex.QueryOpenOrders(context.Background(), "PM_BTC_15M_UP_YES_USDC")
+		assert.NoError(t, err)
+		assert.Len(t, open, len(orders))
+	})
+
+	t.Run("live per-order errors", func(t *testing.T) {
+		t.Setenv(envDryRun, "false")
+		t.Setenv(envOrderNonce, "0")
+
+		ex := New("", "", "")
+		ex.allowancesChecked = true
+		ex.markets = types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111"},
+		}
+
+		created, err := ex.SubmitOrders(context.Background(), newOrder("PM_UNKNOWN"), newOrder("PM_YES"))
+		assert.Len(t, created, 2)
+
+		var batchErr *BatchOrderError
+		if assert.True(t, errors.As(err, &batchErr)) {
+			assert.Contains(t, batchErr.Errors[0].Error(), "no CLOB token id")
+			assert.Contains(t, batchErr.Errors[1].Error(), "private key is required to sign orders")
+		}
+	})
+	t.Run("live POST /orders", func(t *testing.T) {
+		t.Setenv(envDryRun, "false")
+		t.Setenv(envOrderNonce, "7")
+		t.Setenv(envWalletAddress, testAddress)
+
+		server := newMockServer(t, map[string]func(w http.ResponseWriter, r *http.Request, body []byte){
+			"POST /rpc": func(w http.ResponseWriter, r *http.Request, body []byte) {
+				writeJSON(w, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": "0x1"})
+			},
+			"POST /orders": func(w http.ResponseWriter, r *http.Request, body []byte) {
+				writeJSON(w, []PlaceOrderResponse{
+					{Success: true, OrderID: "0xplaced", Status: "live"},
+					{Success: false, ErrorMsg: "not enough balance / allowance"},
+				})
+			},
+		})
+
+		ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", TickSize: fixedpoint.NewFromFloat(0.01), StepSize: fixedpoint.NewFromFloat(0.01)},
+			"PM_NO":  {Symbol: "PM_NO", LocalSymbol: "222222222222", QuoteCurrency: "USDC", TickSize: fixedpoint.NewFromFloat(0.01), StepSize: fixedpoint.NewFromFloat(0.01)},
+		}))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		created, err := ex.SubmitOrders(context.Background(), newOrder("PM_UNKNOWN"), newOrder("PM_YES"), newOrder("PM_NO"))
+		assert.Len(t, created, 3)
+		assert.Equal(t, "0xplaced", created[1].UUID)
+		assert.Equal(t, types.OrderStatusNew, created[1].Status)
+
+		var batchErr *BatchOrderError
+		if assert.True(t, errors.As(err, &batchErr)) {
+			assert.Contains(t, batchErr.Errors[0].Error(), "no CLOB token id")
+			assert.NoError(t, batchErr.Errors[1])
+			assert.ErrorContains(t, batchErr.Errors[2], "not enough balance / allowance")
+		}
+
+		// 有效的两个订单在一次请求中提交，并且都已签名
+		assert.Equal(t, 1, server.count(http.MethodPost, "/orders"))
+		req, body := server.last(http.MethodPost, "/orders")
+		if assert.NotNil(t, req) {
+			assert.Equal(t, "key", req.Header.Get("POLY_API_KEY"))
+			assert.NotEmpty(t, req.Header.Get("POLY_SIGNATURE"))
+
+			var placed []placeOrderRequest
+			assert.NoError(t, json.Unmarshal(body, &placed))
+			if assert.Len(t, placed, 2) {
+				key, _ := parsePrivateKey(testPrivateKey)
+				for i, tokenID := range []string{"111111111111", "222222222222"} {
+					o := placed[i].Order
+					assert.Equal(t, "key", placed[i].Owner)
+					assert.Equal(t, "GTC", placed[i].OrderType)
+					assert.Equal(t, tokenID, o.TokenID)
+					assert.Equal(t, "7", o.Nonce)
+
+					signature := o.Signature
+					assert.NoError(t, (&signer{key: key, address: testAddress}).signOrder(o, polygonChainID))
+					assert.Equal(t, signature, o.Signature)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 真实交易的撤单：CLOB DELETE /orders（L2 鉴权），请求体为订单 hash 数组；
+// 全部撤单用 DELETE /cancel-all，按 token 撤单用 DELETE /cancel-market-orders。
+// 订单的 hash 保存在 types.Order.UUID（见 convert.go），撤单结果由 user channel 推送，这里不再重复推送。
+
+// CancelOrdersResponse 是 CLOB 撤单接口的响应，NotCanceled 为订单 hash → 失败原因。
+type CancelOrdersResponse struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"`
+}
+
+func (e *Exchange) cancelLiveOrders(ctx context.Context, orders []types.Order) error {
+	if e.client.auth == nil {
+		return fmt.Errorf("polymarket: API key is required to cancel orders")
+	}
+
+	orderIDs := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if o.UUID == "" {
+			return fmt.Errorf("polymarket: order %d has no CLOB order hash (UUID)", o.OrderID)
+		}
+		orderIDs = append(orderIDs, o.UUID)
+	}
+	if len(orderIDs) == 0 {
+		return nil
+	}
+
+	resp, err := e.clobAPI().CancelOrders(ctx, orderIDs)
+	if err != nil {
+		return err
+	}
+
+	symbols := make(map[string]string, len(orders))
+	for _, o := range orders {
+		symbols[o.UUID] = o.Symbol
+	}
+	for _, id := range resp.Canceled {
+		e.recordOrderCancel(symbols[id], 1)
+	}
+	return resp.err()
+}
+
+func (c *restClient) cancelAllOrders(ctx context.Context) (*CancelOrdersResponse, error) {
+	var resp CancelOrdersResponse
+	if err := c.do(ctx, c.limits.cancel, http.MethodDelete, "/cancel-all", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *restClient) cancelMarketOrders(ctx context.Context, tokenID string) (*CancelOrdersResponse, error) {
+	var resp CancelOrdersResponse
+	body := map[string]string{"asset_id": tokenID}
+	if err := c.do(ctx, c.limits.cancel, http.MethodDelete, "/cancel-market-orders", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelAllOrders 撤销所有 working 订单，symbol 不为空时只撤该 symbol 的订单，部分订单撤单失败时返回汇总的错误。
+// 真实交易走 CLOB 的批量撤单接口，dry-run 撤销内存中的订单。
+func (e *Exchange) CancelAllOrders(ctx context.Context, symbol string) error {
+	if err := e.checkWritable(); err != nil {
+		return err
+	}
+
+	if e.IsDryRun() {
+		orders, err := e.workingOrders(symbol)
+		if err != nil {
+			return err
+		}
+		return e.CancelOrders(ctx, orders...)
+	}
+
+	if e.client.auth == nil {
+		return fmt.Errorf("polymarket: API key is required to cancel orders")
+	}
+
+	var resp *CancelOrdersResponse
+	var err error
+	if symbol == "" {
+		resp, err = e.client.cancelAllOrders(ctx)
+	} else {
+		token, ok := e.tokenOf(symbol)
+		if !ok {
+			return fmt.Errorf("polymarket: market %s has no CLOB token id", symbol)
+		}
+		resp, err = e.client.cancelMarketOrders(ctx, token.TokenID)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.recordOrderCancel(symbol, len(resp.Canceled))
+	return resp.err()
+}
+
+// workingOrders 返回 dry-run 中 symbol 的 working 订单，symbol 为空时返回全部。
+func (e *Exchange) workingOrders(symbol string) (orders []types.Order, err error) {
+	if err := e.loadOrders(); err != nil {
+		return nil, err
+	}
+
+	working := func(o *types.Order) bool { return o.IsWorking }
+	if symbol == "" {
+		return e.allOrders(working), nil
+	}
+	if sh := e.lookupShard(symbol); sh != nil {
+		return sh.snapshot(working), nil
+	}
+	return nil, nil
+}
+
+// err 汇总撤单失败的订单，全部成功时返回 nil。
+func (resp *CancelOrdersResponse) err() error {
+	if len(resp.NotCanceled) > 0 {
+		reasons := make([]string, 0, len(resp.NotCanceled))
+		for id, reason := range resp.NotCanceled {
+			reasons = append(reasons, id+": "+reason)
+		}
+		sort.Strings(reasons)
+		return fmt.Errorf("polymarket: %d orders not canceled: %s", len(reasons), strings.Join(reasons, "; "))
+	}
+	return nil
+}
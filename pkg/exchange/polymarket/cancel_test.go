@@ -0,0 +1,46 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DryRunCancelAllOrders(t *testing.T) {
+	ex := New("", "", "")
+	ctx := context.Background()
+
+	submit := func(symbol string) {
+		_, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		})
+		assert.NoError(t, err)
+	}
+
+	submit("PM_BTC_15M_UP_YES_USDC")
+	submit("PM_BTC_15M_UP_YES_USDC")
+	submit("PM_BTC_15M_UP_NO_USDC")
+
+	// 按 symbol 撤单
+	assert.NoError(t, ex.CancelAllOrders(ctx, "PM_BTC_15M_UP_YES_USDC"))
+	openOrders, err := ex.QueryOpenOrders(ctx, "PM_BTC_15M_UP_YES_USDC")
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+	openOrders, err = ex.QueryOpenOrders(ctx, "PM_BTC_15M_UP_NO_USDC")
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 1)
+
+	// symbol 为空时撤销全部
+	assert.NoError(t, ex.CancelAllOrders(ctx, ""))
+	openOrders, err = ex.QueryOpenOrders(ctx, "PM_BTC_15M_UP_NO_USDC")
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+}
@@ -0,0 +1,75 @@
+package polymarket
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// adapter 支持的功能：Polymarket 只有现货式的二元 outcome token（价格为 0~1 的概率），没有杠杆、合约与市价单，
+// 通用策略可以先查询这些方法再决定下单方式，而不是在运行时才遇到 "not implemented" 错误。
+// 返回值反映 adapter 当前的真实实现，例如 K 线由价格历史（QueryKLines）或 market channel（订阅）聚合而来，没有成交量。
+
+var _ types.CustomIntervalProvider = (*Exchange)(nil)
+
+// SupportedOrderTypes 返回支持的订单类型：限价单与 post-only（LIMIT_MAKER）；没有市价单与止损单。
+func (e *Exchange) SupportedOrderTypes() []types.OrderType {
+	return []types.OrderType{types.OrderTypeLimit, types.OrderTypeLimitMaker}
+}
+
+// SupportsOrderType 判断是否支持 orderType。
+func (e *Exchange) SupportsOrderType(orderType types.OrderType) bool {
+	for _, t := range e.SupportedOrderTypes() {
+		if t == orderType {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedTimeInForces 返回支持的 TimeInForce：GTC、IOC（CLOB 的 FAK）与 FOK，见 ioc.go。
+func (e *Exchange) SupportedTimeInForces() []types.TimeInForce {
+	return []types.TimeInForce{types.TimeInForceGTC, types.TimeInForceIOC, types.TimeInForceFOK}
+}
+
+// SupportsMarginTrading 返回 false：Polymarket 没有杠杆交易。
+func (e *Exchange) SupportsMarginTrading() bool {
+	return false
+}
+
+// SupportsFuturesTrading 返回 false：Polymarket 没有合约交易。
+func (e *Exchange) SupportsFuturesTrading() bool {
+	return false
+}
+
+// SupportsOrderAmend 返回 true：AmendOrder 以撤单重下的方式改单，见 amend.go。
+func (e *Exchange) SupportsOrderAmend() bool {
+	return true
+}
+
+// SupportsKLineQuery 返回 true：QueryKLines 用 CLOB 的价格历史聚合 K 线（1m 及以上的 interval），见 token_query.go。
+func (e *Exchange) SupportsKLineQuery() bool {
+	return true
+}
+
+// SupportsKLineSubscription 判断 public-only stream 能否推送 K 线：需要 POLYMARKET_WS_MARKET=true 开启 market channel。
+func (e *Exchange) SupportsKLineSubscription() bool {
+	return envBool(envWsMarket, false)
+}
+
+// SupportedInterval 返回 K 线订阅支持的 interval（聚合按秒级检查收盘，不支持毫秒级 interval），值为秒数。
+func (e *Exchange) SupportedInterval() map[types.Interval]int {
+	intervals := make(map[types.Interval]int)
+	for interval, seconds := range types.SupportedIntervals {
+		if interval.Duration() >= time.Second {
+			intervals[interval] = seconds
+		}
+	}
+	return intervals
+}
+
+// IsSupportedInterval 判断 interval 是否可以用于 K 线订阅。
+func (e *Exchange) IsSupportedInterval(interval types.Interval) bool {
+	_, ok := e.SupportedInterval()[interval]
+	return ok
+}
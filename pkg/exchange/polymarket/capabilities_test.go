@@ -0,0 +1,38 @@
+package polymarket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_Capabilities(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+
+	assert.True(t, ex.SupportsOrderType(types.OrderTypeLimit))
+	assert.True(t, ex.SupportsOrderType(types.OrderTypeLimitMaker))
+	assert.False(t, ex.SupportsOrderType(types.OrderTypeMarket))
+	assert.False(t, ex.SupportsOrderType(types.OrderTypeStopLimit))
+	assert.Contains(t, ex.SupportedTimeInForces(), types.TimeInForceIOC)
+
+	assert.False(t, ex.SupportsMarginTrading())
+	assert.False(t, ex.SupportsFuturesTrading())
+	assert.True(t, ex.SupportsOrderAmend())
+	assert.True(t, ex.SupportsKLineQuery())
+
+	assert.False(t, ex.SupportsKLineSubscription())
+	t.Setenv(envWsMarket, "true")
+	assert.True(t, ex.SupportsKLineSubscription())
+
+	assert.True(t, ex.IsSupportedInterval(types.Interval1m))
+	assert.True(t, ex.IsSupportedInterval(types.Interval15m))
+	assert.Equal(t, 60, ex.SupportedInterval()[types.Interval1m])
+	assert.False(t, ex.IsSupportedInterval(types.Interval("7m")))
+
+	var provider interface{} = ex
+	_, ok := provider.(types.CustomIntervalProvider)
+	assert.True(t, ok)
+}
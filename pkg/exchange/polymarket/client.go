@@ -0,0 +1,146 @@
+package polymarket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	envClobURL     = "POLYMARKET_CLOB_URL"
+	envGammaURL    = "POLYMARKET_GAMMA_URL"
+	envHTTPTimeout = "POLYMARKET_HTTP_TIMEOUT"
+
+	defaultClobURL     = "https://clob.polymarket.com"
+	defaultGammaURL    = "https://gamma-api.polymarket.com"
+	defaultHTTPTimeout = 15 * time.Second
+)
+
+// restClient 是 Polymarket REST API（CLOB / Gamma）的最小 HTTP 客户端。
+// 所有请求都经过 rateLimits 限流，429 会自动退避重试。
+// 请求绑定调用方的 ctx，ctx 取消时正在进行的请求、限流等待和退避都会立即返回；
+// 单个请求另有 POLYMARKET_HTTP_TIMEOUT 的超时（默认 15s），避免没有 deadline 的 ctx 被卡住。
+type restClient struct {
+	baseURL    string
+	httpClient *http.Client
+	limits     *rateLimits
+
+	// auth 不为 nil 时每个请求都带上 L2 鉴权请求头，见 auth.go
+	auth *apiCredentials
+
+	// clock 为服务器时间相对本机时间的偏差，鉴权时间戳按它修正，见 clock.go
+	clock clockOffset
+}
+
+func newRestClient(baseURL string, limits *rateLimits) *restClient {
+	return &restClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: envDuration(envHTTPTimeout, defaultHTTPTimeout)},
+		limits:     limits,
+	}
+}
+
+func newClobClient(limits *rateLimits) *restClient {
+	return newRestClient(envString(envClobURL, defaultClobURL), limits)
+}
+
+// newGammaClient 创建 Gamma（market 元数据）API 客户端。
+func newGammaClient(limits *rateLimits) *restClient {
+	return newRestClient(envString(envGammaURL, defaultGammaURL), limits)
+}
+
+// HTTPError 是 REST API 返回的非 2xx 响应（429 见 ErrTooManyRequests）。
+type HTTPError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("polymarket: %s %s failed: status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// do 发送请求并把 JSON 响应解码到 out（out 为 nil 时忽略响应体）。
+// GET 请求是幂等的，遇到网络错误或 5xx 时也会重试（见 ratelimit.go），其他请求只在 429 时重试。
+func (c *restClient) do(ctx context.Context, limiter *rate.Limiter, method, path string, query url.Values, body, out interface{}) error {
+	return c.doRoute(ctx, limiter, method, path, path, query, body, out)
+}
+
+// doRoute 与 do 相同，route 为 path 的路由模板（例如 "/data/order/{id}"），用作 prometheus 指标的标签，
+// 避免订单 hash 等参数造成标签基数无限增长。
+func (c *restClient) doRoute(ctx context.Context, limiter *rate.Limiter, method, path, route string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("polymarket: encode request body failed: %w", err)
+		}
+		payload = b
+	}
+
+	retryable := isRateLimited
+	if method == http.MethodGet {
+		retryable = isTransientError
+	}
+
+	return c.limits.doRetry(ctx, limiter, retryable, func() error {
+		u := c.baseURL + path
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.auth != nil {
+			if err := c.auth.sign(req, c.now().Unix(), path, payload); err != nil {
+				return fmt.Errorf("polymarket: sign request failed: %w", err)
+			}
+		}
+
+		start := time.Now()
+		defer func() {
+			recordRequestDuration(method, route, time.Since(start))
+		}()
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return ErrTooManyRequests
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return &HTTPError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: string(data)}
+		}
+
+		if out == nil {
+			return nil
+		}
+
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("polymarket: decode %s response failed: %w", path, err)
+		}
+		return nil
+	})
+}
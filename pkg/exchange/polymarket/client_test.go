@@ -0,0 +1,157 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func newTestRestClient(transport http.RoundTripper) *restClient {
+	limits := &rateLimits{
+		order:      newLimiter(0, 1),
+		cancel:     newLimiter(0, 1),
+		market:     newLimiter(0, 1),
+		maxRetries: 2,
+		backoff:    time.Millisecond,
+	}
+	c := newClobClient(limits)
+	c.baseURL = "https://clob.test"
+	c.httpClient.Transport = transport
+	return c
+}
+
+func TestClobClient_RetryOnTooManyRequests(t *testing.T) {
+	t.Run("retry until success", func(t *testing.T) {
+		calls := 0
+		transport := &httptesting.MockTransport{}
+		transport.GET("/midpoint", func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls < 3 {
+				return httptesting.BuildResponseString(http.StatusTooManyRequests, ""), nil
+			}
+			return httptesting.BuildResponseString(http.StatusOK, `{"mid":"0.5"}`), nil
+		})
+
+		c := newTestRestClient(transport)
+
+		var out struct {
+			Mid string `json:"mid"`
+		}
+		err := c.do(context.Background(), c.limits.market, http.MethodGet, "/midpoint", nil, nil, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.5", out.Mid)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("give up after max retries", func(t *testing.T) {
+		calls := 0
+		transport := &httptesting.MockTransport{}
+		transport.GET("/midpoint", func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httptesting.BuildResponseString(http.StatusTooManyRequests, ""), nil
+		})
+
+		c := newTestRestClient(transport)
+		err := c.do(context.Background(), c.limits.market, http.MethodGet, "/midpoint", nil, nil, nil)
+		assert.ErrorIs(t, err, ErrTooManyRequests)
+		assert.Equal(t, 3, calls)
+	})
+}
+
+func TestClobClient_RetryTransientErrors(t *testing.T) {
+	t.Run("retry GET on 5xx and network errors", func(t *testing.T) {
+		calls := 0
+		transport := &httptesting.MockTransport{}
+		transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+			calls++
+			switch calls {
+			case 1:
+				return nil, errors.New("connection reset by peer")
+			case 2:
+				return httptesting.BuildResponseString(http.StatusBadGateway, "bad gateway"), nil
+			}
+			return httptesting.BuildResponseString(http.StatusOK, `{"asset_id":"111111111111"}`), nil
+		})
+
+		c := newTestRestClient(transport)
+		_, err := c.queryOrderBook(context.Background(), "111111111111")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("do not retry 4xx", func(t *testing.T) {
+		calls := 0
+		transport := &httptesting.MockTransport{}
+		transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httptesting.BuildResponseString(http.StatusNotFound, "not found"), nil
+		})
+
+		c := newTestRestClient(transport)
+		_, err := c.queryOrderBook(context.Background(), "111111111111")
+		var httpErr *HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("do not retry non-GET on 5xx", func(t *testing.T) {
+		calls := 0
+		transport := &httptesting.MockTransport{}
+		transport.DELETE("/orders", func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httptesting.BuildResponseString(http.StatusInternalServerError, ""), nil
+		})
+
+		c := newTestRestClient(transport)
+		err := c.do(context.Background(), c.limits.cancel, http.MethodDelete, "/orders", nil, []string{"0x1"}, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestClobClient_ContextCancellation(t *testing.T) {
+	t.Run("cancel in-flight request", func(t *testing.T) {
+		transport := &httptesting.MockTransport{}
+		transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+			// 模拟卡住的请求，直到 ctx 被取消
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+
+		c := newTestRestClient(transport)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := c.queryOrderBook(ctx, "111111111111")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("cancel during retry backoff", func(t *testing.T) {
+		transport := &httptesting.MockTransport{}
+		transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+			return httptesting.BuildResponseString(http.StatusTooManyRequests, ""), nil
+		})
+
+		c := newTestRestClient(transport)
+		c.limits.backoff = time.Minute
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := c.queryOrderBook(ctx, "111111111111")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
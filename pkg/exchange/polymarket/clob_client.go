@@ -0,0 +1,307 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// CLOB 交易接口的抽象：Exchange 的真实交易路径（下单、撤单、查询挂单）只通过 clobClient 访问 CLOB，
+// 线上由 *restClient 实现，单元测试可以用内存 mock 替换（设置 Exchange.clob），不需要起 HTTP server。
+// Exchange.clob 为 nil 时使用 Exchange.client，这样替换 client（例如改 baseURL / transport）的代码不受影响。
+
+// clobClient 是 Exchange 依赖的 CLOB 接口。
+type clobClient interface {
+	// PlaceOrder 提交已签名的订单（POST /order）
+	PlaceOrder(ctx context.Context, order *CLOBOrder) (*PlaceOrderResponse, error)
+
+	// PlaceOrders 批量提交已签名的订单（POST /orders），响应与 orders 一一对应
+	PlaceOrders(ctx context.Context, orders []*CLOBOrder) ([]PlaceOrderResponse, error)
+
+	// CancelOrders 按订单 hash 撤单（DELETE /orders）
+	CancelOrders(ctx context.Context, orderIDs []string) (*CancelOrdersResponse, error)
+
+	// GetOrders 查询当前账户的挂单（GET /data/orders），params 支持 id / market / asset_id，自动翻页
+	GetOrders(ctx context.Context, params url.Values) ([]OpenOrder, error)
+
+	// GetOrder 按订单 hash 查询订单（GET /data/order/{id}），包括已经成交或撤销的订单
+	GetOrder(ctx context.Context, orderID string) (*OpenOrder, error)
+
+	// GetMarkets 查询一页 CLOB market（GET /markets），cursor 为空时从第一页开始
+	GetMarkets(ctx context.Context, cursor string) (*CLOBMarketsPage, error)
+
+	// GetPrice 查询 token 的最优价格（GET /price），买方向为最优卖价，卖方向为最优买价
+	GetPrice(ctx context.Context, tokenID string, side types.SideType) (fixedpoint.Value, error)
+}
+
+var _ clobClient = (*restClient)(nil)
+
+// PlaceOrderResponse 是 CLOB POST /order 的响应，OrderID 为订单 hash。
+type PlaceOrderResponse struct {
+	Success     bool     `json:"success"`
+	ErrorMsg    string   `json:"errorMsg"`
+	OrderID     string   `json:"orderID"`
+	Status      string   `json:"status"`
+	OrderHashes []string `json:"orderHashes"`
+
+	// MakingAmount / TakingAmount 为下单时立即成交的数量：买单付出 USDC、得到 outcome token，卖单相反
+	MakingAmount fixedpoint.Value `json:"makingAmount"`
+	TakingAmount fixedpoint.Value `json:"takingAmount"`
+}
+
+// OpenOrder 是 CLOB GET /data/orders 返回的挂单。
+type OpenOrder struct {
+	ID           string           `json:"id"`
+	Status       string           `json:"status"`
+	Market       string           `json:"market"`
+	AssetID      string           `json:"asset_id"`
+	Outcome      string           `json:"outcome"`
+	Side         string           `json:"side"`
+	Price        fixedpoint.Value `json:"price"`
+	OriginalSize fixedpoint.Value `json:"original_size"`
+	SizeMatched  fixedpoint.Value `json:"size_matched"`
+	OrderType    string           `json:"order_type"`
+	CreatedAt    int64            `json:"created_at"`
+}
+
+// CLOBToken 是 CLOB market 的一个 outcome token。
+type CLOBToken struct {
+	TokenID string           `json:"token_id"`
+	Outcome string           `json:"outcome"`
+	Price   fixedpoint.Value `json:"price"`
+}
+
+// CLOBMarket 是 CLOB GET /markets 返回的 market。
+type CLOBMarket struct {
+	ConditionID      string           `json:"condition_id"`
+	QuestionID       string           `json:"question_id"`
+	MarketSlug       string           `json:"market_slug"`
+	Tokens           []CLOBToken      `json:"tokens"`
+	MinimumTickSize  fixedpoint.Value `json:"minimum_tick_size"`
+	MinimumOrderSize fixedpoint.Value `json:"minimum_order_size"`
+	NegRisk          bool             `json:"neg_risk"`
+	Active           bool             `json:"active"`
+	Closed           bool             `json:"closed"`
+	AcceptingOrders  bool             `json:"accepting_orders"`
+}
+
+// CLOBMarketsPage 是 GET /markets 的一页结果，NextCursor 为 endCursor 时没有下一页。
+type CLOBMarketsPage struct {
+	Data       []CLOBMarket `json:"data"`
+	NextCursor string       `json:"next_cursor"`
+}
+
+type placeOrderRequest struct {
+	Order     *CLOBOrder `json:"order"`
+	Owner     string     `json:"owner"`
+	OrderType string     `json:"orderType"`
+	PostOnly  bool       `json:"postOnly,omitempty"`
+}
+
+// newPlaceOrderRequest 返回订单的请求体，未签名的订单不会提交。
+func (c *restClient) newPlaceOrderRequest(order *CLOBOrder) (*placeOrderRequest, error) {
+	if order.Signature == "" {
+		return nil, fmt.Errorf("polymarket: order for token %s is not signed", order.TokenID)
+	}
+	if c.auth == nil {
+		return nil, fmt.Errorf("polymarket: API key is required to place orders")
+	}
+
+	orderType := order.OrderType
+	if orderType == "" {
+		orderType = "GTC"
+	}
+	return &placeOrderRequest{Order: order, Owner: c.auth.key, OrderType: orderType, PostOnly: order.PostOnly}, nil
+}
+
+func (c *restClient) PlaceOrder(ctx context.Context, order *CLOBOrder) (*PlaceOrderResponse, error) {
+	req, err := c.newPlaceOrderRequest(order)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PlaceOrderResponse
+	if err := c.do(ctx, c.limits.order, http.MethodPost, "/order", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Success && resp.ErrorMsg != "" {
+		return nil, fmt.Errorf("polymarket: place order rejected: %s", resp.ErrorMsg)
+	}
+	return &resp, nil
+}
+
+// PlaceOrders 批量提交订单，单个订单被拒绝时对应响应的 Success 为 false、ErrorMsg 为原因，不影响其他订单。
+func (c *restClient) PlaceOrders(ctx context.Context, orders []*CLOBOrder) ([]PlaceOrderResponse, error) {
+	reqs := make([]*placeOrderRequest, len(orders))
+	for i, order := range orders {
+		req, err := c.newPlaceOrderRequest(order)
+		if err != nil {
+			return nil, err
+		}
+		reqs[i] = req
+	}
+
+	var resp []PlaceOrderResponse
+	if err := c.do(ctx, c.limits.order, http.MethodPost, "/orders", nil, reqs, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp) != len(orders) {
+		return nil, fmt.Errorf("polymarket: POST /orders returned %d results for %d orders", len(resp), len(orders))
+	}
+	return resp, nil
+}
+
+func (c *restClient) CancelOrders(ctx context.Context, orderIDs []string) (*CancelOrdersResponse, error) {
+	var resp CancelOrdersResponse
+	if err := c.do(ctx, c.limits.cancel, http.MethodDelete, "/orders", nil, orderIDs, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+type openOrdersPage struct {
+	Data       []OpenOrder `json:"data"`
+	NextCursor string      `json:"next_cursor"`
+}
+
+func (c *restClient) GetOrders(ctx context.Context, params url.Values) ([]OpenOrder, error) {
+	var orders []OpenOrder
+	for cursor := ""; cursor != endCursor; {
+		query := url.Values{}
+		for k, v := range params {
+			query[k] = v
+		}
+		if cursor != "" {
+			query.Set("next_cursor", cursor)
+		}
+
+		var page openOrdersPage
+		if err := c.do(ctx, c.limits.market, http.MethodGet, "/data/orders", query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, page.Data...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	return orders, nil
+}
+
+func (c *restClient) GetOrder(ctx context.Context, orderID string) (*OpenOrder, error) {
+	var order *OpenOrder
+	if err := c.doRoute(ctx, c.limits.market, http.MethodGet, "/data/order/"+url.PathEscape(orderID), "/data/order/{id}", nil, nil, &order); err != nil {
+		return nil, err
+	}
+	if order == nil || order.ID == "" {
+		return nil, fmt.Errorf("polymarket: order %s not found", orderID)
+	}
+	return order, nil
+}
+
+func (c *restClient) GetMarkets(ctx context.Context, cursor string) (*CLOBMarketsPage, error) {
+	var query url.Values
+	if cursor != "" {
+		query = url.Values{"next_cursor": {cursor}}
+	}
+
+	var page CLOBMarketsPage
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/markets", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *restClient) GetPrice(ctx context.Context, tokenID string, side types.SideType) (fixedpoint.Value, error) {
+	query := url.Values{"token_id": {tokenID}, "side": {strings.ToUpper(string(side))}}
+	var resp struct {
+		Price fixedpoint.Value `json:"price"`
+	}
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/price", query, nil, &resp); err != nil {
+		return fixedpoint.Zero, err
+	}
+	return resp.Price, nil
+}
+
+// clobAPI 返回真实交易使用的 CLOB 接口。
+func (e *Exchange) clobAPI() clobClient {
+	if e.clob != nil {
+		return e.clob
+	}
+	return e.client
+}
+
+// toGlobalOpenOrder 把 CLOB 的挂单转换成 bbgo 的 types.Order。
+func toGlobalOpenOrder(o OpenOrder, symbol string) types.Order {
+	order := toGlobalOrder(OrderEvent{
+		ID:           o.ID,
+		Market:       o.Market,
+		AssetID:      o.AssetID,
+		Outcome:      o.Outcome,
+		Side:         o.Side,
+		Price:        o.Price,
+		OriginalSize: o.OriginalSize,
+		SizeMatched:  o.SizeMatched,
+	}, symbol)
+	order.OriginalStatus = o.Status
+
+	// GetOrder 可以查到已经结束的订单：CANCELED（以及市场结算时的 CANCELED_MARKET_RESOLVED）为撤单，MATCHED 为全部成交
+	switch status := strings.ToUpper(o.Status); {
+	case strings.HasPrefix(status, "CANCELED"):
+		order.Status = types.OrderStatusCanceled
+		order.IsWorking = false
+	case status == "MATCHED":
+		order.Status = types.OrderStatusFilled
+		order.IsWorking = false
+	}
+
+	if o.CreatedAt > 0 {
+		order.CreationTime = types.Time(time.Unix(o.CreatedAt, 0))
+		order.UpdateTime = order.CreationTime
+	}
+	return order
+}
+
+// toGlobalPlacedOrder 由下单请求与 CLOB 的响应构造 bbgo 的 types.Order，后续状态由 user channel 推送。
+func toGlobalPlacedOrder(order types.SubmitOrder, resp *PlaceOrderResponse, at time.Time) types.Order {
+	o := types.Order{
+		SubmitOrder:      order,
+		Exchange:         types.ExchangePolymarket,
+		OrderID:          hashStringID(resp.OrderID),
+		UUID:             resp.OrderID,
+		Status:           types.OrderStatusNew,
+		OriginalStatus:   resp.Status,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     types.Time(at),
+		UpdateTime:       types.Time(at),
+	}
+
+	// FAK/FOK 订单在响应中已经确定结果（delayed 表示撮合延迟，结果稍后由 user channel 推送）：剩余部分已撤单
+	if !isImmediateOrCancel(order) || resp.Status == "delayed" {
+		return o
+	}
+
+	executed, quote := resp.TakingAmount, resp.MakingAmount
+	if order.Side == types.SideTypeSell {
+		executed, quote = resp.MakingAmount, resp.TakingAmount
+	}
+	if executed.Sign() > 0 {
+		o.AveragePrice = quote.Div(executed)
+	}
+	o.ExecutedQuantity = fixedpoint.Min(executed, order.Quantity)
+	o.IsWorking = false
+	if o.ExecutedQuantity.Compare(order.Quantity) >= 0 {
+		o.Status = types.OrderStatusFilled
+	} else {
+		o.Status = types.OrderStatusCanceled
+	}
+	return o
+}
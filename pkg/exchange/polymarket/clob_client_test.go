@@ -0,0 +1,221 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// mockClobClient 是内存中的 clobClient，记录收到的请求。
+type mockClobClient struct {
+	placed     []*CLOBOrder
+	canceled   [][]string
+	orderQuery []url.Values
+
+	placeResponse *PlaceOrderResponse
+	placeErr      error
+	openOrders    []OpenOrder
+	// orders 为 GetOrder 可以查到的订单（包括已结束的订单）
+	orders []OpenOrder
+}
+
+func (m *mockClobClient) PlaceOrder(ctx context.Context, order *CLOBOrder) (*PlaceOrderResponse, error) {
+	m.placed = append(m.placed, order)
+	if m.placeErr != nil {
+		return nil, m.placeErr
+	}
+	return m.placeResponse, nil
+}
+
+func (m *mockClobClient) PlaceOrders(ctx context.Context, orders []*CLOBOrder) ([]PlaceOrderResponse, error) {
+	m.placed = append(m.placed, orders...)
+	if m.placeErr != nil {
+		return nil, m.placeErr
+	}
+
+	resps := make([]PlaceOrderResponse, len(orders))
+	for i := range orders {
+		resps[i] = *m.placeResponse
+	}
+	return resps, nil
+}
+
+func (m *mockClobClient) CancelOrders(ctx context.Context, orderIDs []string) (*CancelOrdersResponse, error) {
+	m.canceled = append(m.canceled, orderIDs)
+	return &CancelOrdersResponse{Canceled: orderIDs}, nil
+}
+
+func (m *mockClobClient) GetOrders(ctx context.Context, params url.Values) ([]OpenOrder, error) {
+	m.orderQuery = append(m.orderQuery, params)
+	return m.openOrders, nil
+}
+
+func (m *mockClobClient) GetOrder(ctx context.Context, orderID string) (*OpenOrder, error) {
+	for _, o := range m.orders {
+		if o.ID == orderID {
+			return &o, nil
+		}
+	}
+	return nil, fmt.Errorf("polymarket: order %s not found", orderID)
+}
+
+func (m *mockClobClient) GetMarkets(ctx context.Context, cursor string) (*CLOBMarketsPage, error) {
+	return &CLOBMarketsPage{NextCursor: endCursor}, nil
+}
+
+func (m *mockClobClient) GetPrice(ctx context.Context, tokenID string, side types.SideType) (fixedpoint.Value, error) {
+	return fixedpoint.NewFromFloat(0.5), nil
+}
+
+func TestExchange_MockClobClient(t *testing.T) {
+	const tokenID = "111111111111"
+	t.Setenv(envOrderNonce, "0")
+
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(false),
+		WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC", TickSize: fixedpoint.NewFromFloat(0.01), StepSize: fixedpoint.NewFromFloat(0.01)},
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	mock := &mockClobClient{
+		placeResponse: &PlaceOrderResponse{Success: true, OrderID: "0xplaced", Status: "live"},
+		openOrders: []OpenOrder{
+			{ID: "0xopen", AssetID: tokenID, Side: "BUY", Price: fixedpoint.NewFromFloat(0.4),
+				OriginalSize: fixedpoint.NewFromFloat(10), SizeMatched: fixedpoint.NewFromFloat(4), Status: "LIVE", CreatedAt: 1760515200},
+			{ID: "0xunknown", AssetID: "999999999999", Side: "SELL"},
+		},
+	}
+	ex.clob = mock
+	ex.allowancesChecked = true
+	ctx := context.Background()
+
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "PM_YES",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0xplaced", order.UUID)
+		assert.Equal(t, types.OrderStatusNew, order.Status)
+		assert.False(t, order.IsDryRun)
+	}
+	if assert.Len(t, mock.placed, 1) {
+		assert.Equal(t, tokenID, mock.placed[0].TokenID)
+		assert.Equal(t, "BUY", mock.placed[0].Side)
+	}
+
+	orders, err := ex.QueryOpenOrders(ctx, "PM_YES")
+	assert.NoError(t, err)
+	assert.Equal(t, tokenID, mock.orderQuery[0].Get("asset_id"))
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "0xopen", orders[0].UUID)
+		assert.Equal(t, "PM_YES", orders[0].Symbol)
+		assert.Equal(t, types.OrderStatusPartiallyFilled, orders[0].Status)
+		assert.Equal(t, int64(1760515200), orders[0].CreationTime.Time().Unix())
+	}
+
+	assert.NoError(t, ex.CancelOrders(ctx, orders...))
+	assert.Equal(t, [][]string{{"0xopen"}}, mock.canceled)
+}
+
+func TestRestClient_ClobClient(t *testing.T) {
+	ctx := context.Background()
+	transport := &httptesting.MockTransport{}
+	transport.GET("/data/orders", func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("next_cursor") == "" {
+			return httptesting.BuildResponseString(http.StatusOK, `{"data":[{"id":"0x1","asset_id":"111111111111"}],"next_cursor":"MQ=="}`), nil
+		}
+		return httptesting.BuildResponseString(http.StatusOK, `{"data":[{"id":"0x2","asset_id":"111111111111"}],"next_cursor":"LTE="}`), nil
+	})
+	transport.GET("/price", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "BUY", req.URL.Query().Get("side"))
+		return httptesting.BuildResponseString(http.StatusOK, `{"price":"0.52"}`), nil
+	})
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK,
+			`{"data":[{"condition_id":"0xabc","neg_risk":true,"tokens":[{"token_id":"111111111111","outcome":"Yes"}]}],"next_cursor":"LTE="}`), nil
+	})
+	transport.POST("/order", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		var payload placeOrderRequest
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "GTC", payload.OrderType)
+		return httptesting.BuildResponseString(http.StatusOK, `{"success":true,"orderID":"0xplaced","status":"live"}`), nil
+	})
+
+	c := newTestRestClient(transport)
+	c.auth = newAPICredentials("key", "c2VjcmV0", "pass")
+
+	orders, err := c.GetOrders(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+
+	price, err := c.GetPrice(ctx, "111111111111", types.SideTypeBuy)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.52", price.String())
+
+	page, err := c.GetMarkets(ctx, "")
+	if assert.NoError(t, err) && assert.Len(t, page.Data, 1) {
+		assert.True(t, page.Data[0].NegRisk)
+		assert.Equal(t, "Yes", page.Data[0].Tokens[0].Outcome)
+	}
+
+	// 未签名的订单不会发出请求
+	_, err = c.PlaceOrder(ctx, &CLOBOrder{TokenID: "111111111111"})
+	assert.ErrorContains(t, err, "is not signed")
+
+	resp, err := c.PlaceOrder(ctx, &CLOBOrder{TokenID: "111111111111", Signature: "0xsig"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0xplaced", resp.OrderID)
+	}
+}
+
+func TestExchange_LiveOrderRateLimitedOnce(t *testing.T) {
+	const tokenID = "111111111111"
+	t.Setenv(envOrderNonce, "0")
+	t.Setenv(envMaxRetries, "2")
+	t.Setenv(envRetryBackoff, "1ms")
+
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(false),
+		WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC", TickSize: fixedpoint.NewFromFloat(0.01), StepSize: fixedpoint.NewFromFloat(0.01)},
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	// 限流与 429 重试由 REST client 负责，Exchange 层不会再重试，也不会消耗 order 限流的 token
+	mock := &mockClobClient{placeErr: ErrTooManyRequests}
+	ex.clob = mock
+	ex.allowancesChecked = true
+	ctx := context.Background()
+	tokens := ex.limits.order.Tokens()
+
+	order := types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+		Price: fixedpoint.NewFromFloat(0.5), Quantity: fixedpoint.NewFromFloat(10)}
+	_, err = ex.SubmitOrder(ctx, order)
+	assert.ErrorIs(t, err, ErrTooManyRequests)
+	assert.Len(t, mock.placed, 1)
+
+	_, err = ex.AmendOrder(ctx, types.Order{SubmitOrder: order, UUID: "0xopen", IsWorking: true}, fixedpoint.NewFromFloat(0.4), fixedpoint.Zero)
+	assert.ErrorIs(t, err, ErrTooManyRequests)
+	assert.Len(t, mock.placed, 2)
+
+	assert.InDelta(t, tokens, ex.limits.order.Tokens(), 0.5)
+}
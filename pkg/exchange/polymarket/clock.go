@@ -0,0 +1,111 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 服务器时间与时钟偏差：L2 鉴权请求头 POLY_TIMESTAMP 与带过期时间的签名订单都以 CLOB 服务器时间为准，
+// 本机时钟漂移过大时请求会被拒绝（鉴权失败或 "order expired"）。
+// - SyncServerTime 请求 CLOB GET /time，按请求往返的中点估算时钟偏差（服务器时间 - 本机时间）并缓存在 CLOB 客户端上，
+//   之后鉴权时间戳按该偏差修正；订单目前不设置过期时间（expiration 为 0），设置时同样需要以 restClient.now 为准
+// - 偏差绝对值超过 POLYMARKET_CLOCK_SKEW_WARN（默认 2s）时输出警告，提示校准本机时钟
+// - 真实交易在 session 初始化（Initialize）时同步一次，之后每隔 POLYMARKET_CLOCK_SYNC_INTERVAL（默认 30m，0 表示不再同步）重新同步
+// - 同步失败只输出警告，沿用上一次的偏差（初始为 0，即本机时间）；dry-run 不同步
+
+const (
+	envClockSkewWarn     = "POLYMARKET_CLOCK_SKEW_WARN"
+	envClockSyncInterval = "POLYMARKET_CLOCK_SYNC_INTERVAL"
+
+	defaultClockSkewWarn     = 2 * time.Second
+	defaultClockSyncInterval = 30 * time.Minute
+)
+
+// clockOffset 为服务器时间相对本机时间的偏差。
+type clockOffset struct {
+	mu   sync.Mutex
+	skew time.Duration
+}
+
+func (c *clockOffset) get() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skew
+}
+
+func (c *clockOffset) set(skew time.Duration) {
+	c.mu.Lock()
+	c.skew = skew
+	c.mu.Unlock()
+}
+
+// now 返回按时钟偏差修正后的当前时间（估算的服务器时间）。
+func (c *restClient) now() time.Time {
+	return time.Now().Add(c.clock.get())
+}
+
+// GetServerTime 查询 CLOB 服务器时间（GET /time，返回 unix 秒）。
+func (c *restClient) GetServerTime(ctx context.Context) (time.Time, error) {
+	var ts int64
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/time", nil, nil, &ts); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// SyncServerTime 查询 CLOB 服务器时间，更新缓存的时钟偏差并返回。
+// 服务器时间只精确到秒，偏差小于 1s 时视为 0。
+func (e *Exchange) SyncServerTime(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	serverTime, err := e.client.GetServerTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+	end := time.Now()
+
+	skew := serverTime.Sub(start.Add(end.Sub(start) / 2))
+	if skew > -time.Second && skew < time.Second {
+		skew = 0
+	}
+	e.client.clock.set(skew)
+
+	if warn := envDuration(envClockSkewWarn, defaultClockSkewWarn); warn > 0 && (skew > warn || skew < -warn) {
+		log.Warnf("local clock is off by %s from the CLOB server time (more than %s), signed requests use the server time; please sync the system clock", skew, warn)
+	}
+	return skew, nil
+}
+
+// ClockSkew 返回最近一次同步得到的时钟偏差（服务器时间 - 本机时间），没有同步过时为 0。
+func (e *Exchange) ClockSkew() time.Duration {
+	return e.client.clock.get()
+}
+
+// startClockSync 同步一次服务器时间，并在后台定期重新同步，直到 Exchange.Close。
+func (e *Exchange) startClockSync(ctx context.Context) {
+	if _, err := e.SyncServerTime(ctx); err != nil {
+		log.WithError(err).Warn("failed to sync the CLOB server time, use the local clock")
+	}
+
+	interval := envDuration(envClockSyncInterval, defaultClockSyncInterval)
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.background.Done():
+				return
+			case <-ticker.C:
+				if _, err := e.SyncServerTime(e.background); err != nil {
+					log.WithError(err).Warn("failed to sync the CLOB server time")
+				}
+			}
+		}
+	}()
+}
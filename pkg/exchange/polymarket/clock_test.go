@@ -0,0 +1,89 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestExchange_SyncServerTime(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+
+	offset := time.Minute
+	var timestamps []int64
+	transport := &httptesting.MockTransport{}
+	transport.GET("/time", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, strconv.FormatInt(time.Now().Add(offset).Unix(), 10)), nil
+	})
+	transport.GET("/data/orders", func(req *http.Request) (*http.Response, error) {
+		ts, err := strconv.ParseInt(req.Header.Get("POLY_TIMESTAMP"), 10, 64)
+		assert.NoError(t, err)
+		timestamps = append(timestamps, ts)
+		return httptesting.BuildResponseString(http.StatusOK, `{"data": [], "next_cursor": "LTE="}`), nil
+	})
+	ex.client = newTestRestClient(transport)
+	ex.client.auth = newAPICredentials("key", "c2VjcmV0", "pass")
+
+	ctx := context.Background()
+	assert.Zero(t, ex.ClockSkew())
+
+	// 服务器时间只精确到秒
+	skew, err := ex.SyncServerTime(ctx)
+	if assert.NoError(t, err) {
+		assert.InDelta(t, float64(time.Minute), float64(skew), float64(time.Second))
+		assert.Equal(t, skew, ex.ClockSkew())
+	}
+
+	// 鉴权时间戳使用服务器时间
+	_, err = ex.client.GetOrders(ctx, nil)
+	if assert.NoError(t, err) && assert.Len(t, timestamps, 1) {
+		assert.InDelta(t, time.Now().Add(time.Minute).Unix(), timestamps[0], 2)
+	}
+
+	// 小于 1s 的偏差视为 0
+	offset = 0
+	skew, err = ex.SyncServerTime(ctx)
+	if assert.NoError(t, err) {
+		assert.Zero(t, skew)
+	}
+
+	// 同步失败时沿用上一次的偏差
+	ex.client.clock.set(-time.Hour)
+	transport.GET("/time", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusBadRequest, `bad request`), nil
+	})
+	_, err = ex.SyncServerTime(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, -time.Hour, ex.ClockSkew())
+}
+
+func TestExchange_InitializeSyncsServerTime(t *testing.T) {
+	t.Setenv(envDryRun, "false")
+	t.Setenv(envWalletAddress, testAddress)
+	t.Setenv(envClockSyncInterval, "0")
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+
+	ex, err := NewWithPrivateKey("key", "c2VjcmV0", "pass", testPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/time", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, strconv.FormatInt(time.Now().Add(-10*time.Second).Unix(), 10)), nil
+	})
+	client := newTestRestClient(transport)
+	client.auth = ex.client.auth
+	ex.client = client
+
+	assert.NoError(t, ex.Initialize(context.Background()))
+	assert.InDelta(t, float64(-10*time.Second), float64(ex.ClockSkew()), float64(time.Second))
+}
@@ -0,0 +1,104 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// 抵押资产（collateral）：Polymarket 的 CTF 合约以桥接的 USDC.e 结算，Polygon 上同时存在原生 USDC，
+// 用错 token 地址时链上的授权检查与余额查询都会查到另一个合约，不会报错但结果是错的。
+// - POLYMARKET_COLLATERAL=usdce（默认，Polymarket 目前使用的 USDC.e）或 usdc（Circle 原生 USDC）
+// - POLYMARKET_COLLATERAL_ADDRESS 覆盖 token 合约地址（例如测试网）
+// - 授权检查（allowance.go）与 live 的余额查询（ERC-20 balanceOf）使用该 token
+// - 两种 token 在 bbgo 中都记为 USDC 资产（market 的 quoteCurrency 为 USDC），PlatformFeeCurrency 同样返回该资产
+// 配置无效时回退到默认的 USDC.e 并由 ValidateConfig 报告。
+
+const (
+	envCollateral        = "POLYMARKET_COLLATERAL"
+	envCollateralAddress = "POLYMARKET_COLLATERAL_ADDRESS"
+
+	CollateralUSDCe = "usdce"
+	CollateralUSDC  = "usdc"
+
+	// Polygon 主网上的 token 合约地址
+	usdceAddress      = "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"
+	nativeUSDCAddress = "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"
+
+	// collateralCurrency 为抵押资产在 bbgo 中的资产名
+	collateralCurrency = "USDC"
+	// collateralDecimals 为 USDC / USDC.e 的精度
+	collateralDecimals = 6
+
+	selectorBalanceOf = "70a08231" // balanceOf(address)
+)
+
+var addressRE = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// CollateralToken 为下单与结算使用的抵押 token
+type CollateralToken struct {
+	// Name 为 POLYMARKET_COLLATERAL 的取值：usdce / usdc
+	Name string
+	// Symbol 为 token 的链上名称，用于日志与错误信息
+	Symbol string
+	// Address 为 token 合约地址
+	Address string
+}
+
+func (c CollateralToken) String() string {
+	return fmt.Sprintf("%s(%s)", c.Symbol, c.Address)
+}
+
+func defaultCollateral() CollateralToken {
+	return CollateralToken{Name: CollateralUSDCe, Symbol: "USDC.e", Address: usdceAddress}
+}
+
+// loadCollateral 按环境变量选择抵押 token，取值无效时返回错误。
+func loadCollateral() (CollateralToken, error) {
+	var c CollateralToken
+	switch name := strings.ToLower(envString(envCollateral, CollateralUSDCe)); name {
+	case CollateralUSDCe, "usdc.e":
+		c = defaultCollateral()
+	case CollateralUSDC:
+		c = CollateralToken{Name: CollateralUSDC, Symbol: "USDC", Address: nativeUSDCAddress}
+	default:
+		return defaultCollateral(), fmt.Errorf("%s %q is invalid, should be one of %q, %q", envCollateral, name, CollateralUSDCe, CollateralUSDC)
+	}
+
+	if address := envString(envCollateralAddress, ""); address != "" {
+		if !addressRE.MatchString(address) {
+			return defaultCollateral(), fmt.Errorf("%s %q is not a valid contract address", envCollateralAddress, address)
+		}
+		c.Address = address
+	}
+	return c, nil
+}
+
+// newCollateralFromEnv 返回配置的抵押 token，配置无效时回退到 USDC.e。
+func newCollateralFromEnv() CollateralToken {
+	c, err := loadCollateral()
+	if err != nil {
+		log.WithError(err).Warnf("fallback to collateral %s", c)
+	}
+	return c
+}
+
+// Collateral 返回使用的抵押 token。
+func (e *Exchange) Collateral() CollateralToken {
+	return e.collateral
+}
+
+// queryCollateralBalance 查询 owner 持有的抵押 token 数量（ERC-20 balanceOf）。
+func (c *restClient) queryCollateralBalance(ctx context.Context, token CollateralToken, owner string) (fixedpoint.Value, error) {
+	v, err := c.ethCall(ctx, token.Address, encodeCall(selectorBalanceOf, owner))
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	amount := new(big.Rat).SetFrac(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(collateralDecimals), nil))
+	return fixedpoint.NewFromString(amount.FloatString(collateralDecimals))
+}
@@ -0,0 +1,90 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestLoadCollateral(t *testing.T) {
+	c, err := loadCollateral()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultCollateral(), c)
+
+	t.Setenv(envCollateral, "USDC")
+	c, err = loadCollateral()
+	assert.NoError(t, err)
+	assert.Equal(t, CollateralUSDC, c.Name)
+	assert.Equal(t, nativeUSDCAddress, c.Address)
+
+	const override = "0x9999999999999999999999999999999999999999"
+	t.Setenv(envCollateralAddress, override)
+	c, err = loadCollateral()
+	assert.NoError(t, err)
+	assert.Equal(t, override, c.Address)
+
+	t.Setenv(envCollateralAddress, "0x1234")
+	_, err = loadCollateral()
+	assert.ErrorContains(t, err, envCollateralAddress)
+
+	// 无效的配置回退到 USDC.e 并在 ValidateConfig 中报告
+	t.Setenv(envCollateralAddress, "")
+	t.Setenv(envCollateral, "dai")
+	c, err = loadCollateral()
+	assert.ErrorContains(t, err, envCollateral)
+	assert.Equal(t, defaultCollateral(), c)
+
+	ex := New("", "", "")
+	defer ex.Close()
+	assert.Equal(t, defaultCollateral(), ex.Collateral())
+	assert.ErrorContains(t, ex.ValidateConfig(), envCollateral)
+}
+
+func TestExchange_CollateralOnChainCalls(t *testing.T) {
+	const owner = "0x1111111111111111111111111111111111111111"
+	t.Setenv(envWalletAddress, owner)
+	t.Setenv(envCollateral, CollateralUSDC)
+
+	var targets []string
+	transport := &httptesting.MockTransport{}
+	transport.POST("", func(req *http.Request) (*http.Response, error) {
+		var rpcReq rpcRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+		call := rpcReq.Params[0].(map[string]interface{})
+		targets = append(targets, call["to"].(string))
+
+		result := "0x1"
+		if call["data"] == encodeCall(selectorBalanceOf, owner) {
+			// 12.5 USDC
+			result = "0xbebc20"
+		}
+		return httptesting.BuildResponseString(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`), nil
+	})
+
+	ex, err := NewWithOptions("", "", "", WithDryRun(false))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+	ex.rpc = newTestRestClient(transport)
+	ctx := context.Background()
+
+	assert.Equal(t, "USDC", ex.PlatformFeeCurrency())
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "12.5", balances[collateralCurrency].Available.String())
+	}
+	assert.Equal(t, []string{nativeUSDCAddress}, targets)
+
+	// 授权检查同样使用原生 USDC，不会查询 USDC.e
+	targets = nil
+	assert.NoError(t, ex.CheckAllowances(ctx))
+	assert.Contains(t, targets, nativeUSDCAddress)
+	assert.NotContains(t, targets, usdceAddress)
+}
@@ -0,0 +1,175 @@
+package polymarket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// 二元市场的互补定价：同一个市场的两个 outcome 到期时恰好一个兑付 1 USDC，所以 YES + NO ≈ 1。
+// - ImpliedComplement 用 market channel 的盘口缓存（见 ticker_cache.go）由一个 outcome 推出另一个的价格：1 - 中间价，
+//   按另一个 outcome 的 tick 取整并限制在 [0, 1]
+// - CheckComplement 同时读取两个 outcome 的盘口：两边 ask 之和低于 1 或 bid 之和高于 1 为套利机会，
+//   中间价之和偏离 1 超过 tolerance 通常说明其中一边的盘口过期
+// 另一个 outcome 的 symbol 依次从 Gamma 发现的 up/down 市场、markets 文件中相同 conditionId / slug 的 market、
+// 以及 symbol 中的 _YES_ / _NO_ 命名（例如默认示例 market）中查找。
+
+// ComplementCheck 为一组互补 outcome 的盘口一致性检查结果。
+type ComplementCheck struct {
+	Symbol     string
+	Complement string
+
+	Bid, Ask                     fixedpoint.Value
+	ComplementBid, ComplementAsk fixedpoint.Value
+
+	// AskSum / BidSum 为两边 best ask / best bid 之和
+	AskSum fixedpoint.Value
+	BidSum fixedpoint.Value
+
+	// Deviation 为两边中间价之和与 1 的偏差（绝对值）
+	Deviation fixedpoint.Value
+
+	// Arbitrage 为 true 时两边同时买入（AskSum < 1）或同时卖出（BidSum > 1）可以锁定收益
+	Arbitrage bool
+
+	// Consistent 为 true 时没有套利机会，且 Deviation 不超过 tolerance
+	Consistent bool
+}
+
+func (c ComplementCheck) String() string {
+	return fmt.Sprintf("%s/%s askSum=%s bidSum=%s deviation=%s arbitrage=%t consistent=%t",
+		c.Symbol, c.Complement, c.AskSum.String(), c.BidSum.String(), c.Deviation.String(), c.Arbitrage, c.Consistent)
+}
+
+// ComplementSymbol 返回二元市场中另一个 outcome 的 symbol。
+func (e *Exchange) ComplementSymbol(symbol string) (string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if complement, ok := e.complementSymbolLocked(symbol); ok {
+		return complement, nil
+	}
+	return "", fmt.Errorf("polymarket: complement outcome of %s not found", symbol)
+}
+
+func (e *Exchange) complementSymbolLocked(symbol string) (string, bool) {
+	for _, um := range e.upDownMarkets {
+		switch symbol {
+		case um.YesSymbol:
+			return um.NoSymbol, true
+		case um.NoSymbol:
+			return um.YesSymbol, true
+		}
+	}
+
+	if ref, ok := e.marketRefs[symbol]; ok && (ref.ConditionID != "" || ref.Slug != "") {
+		var found []string
+		for other, otherRef := range e.marketRefs {
+			if other == symbol {
+				continue
+			}
+			if (ref.ConditionID != "" && otherRef.ConditionID == ref.ConditionID) ||
+				(ref.ConditionID == "" && ref.Slug != "" && otherRef.Slug == ref.Slug) {
+				found = append(found, other)
+			}
+		}
+		// 多结果（neg-risk）市场的 outcome 没有唯一的互补
+		if len(found) == 1 {
+			if _, ok := e.markets[found[0]]; ok {
+				return found[0], true
+			}
+		}
+		return "", false
+	}
+
+	for _, pair := range [][2]string{{"_YES_", "_NO_"}, {"_NO_", "_YES_"}} {
+		if strings.Contains(symbol, pair[0]) {
+			complement := strings.Replace(symbol, pair[0], pair[1], 1)
+			if _, ok := e.markets[complement]; ok {
+				return complement, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ImpliedComplement 返回 symbol 的盘口缓存隐含的另一个 outcome 的价格（1 - 中间价），
+// 按另一个 outcome 的 tick 四舍五入并限制在 [0, 1]。盘口缓存没有该 symbol 或已过期时返回错误。
+func (e *Exchange) ImpliedComplement(symbol string) (fixedpoint.Value, error) {
+	e.mu.RLock()
+	complement, ok := e.complementSymbolLocked(symbol)
+	token, hasToken := e.tokenOfLocked(symbol)
+	tick := e.markets[complement].TickSize
+	e.mu.RUnlock()
+
+	if !ok {
+		return fixedpoint.Zero, fmt.Errorf("polymarket: complement outcome of %s not found", symbol)
+	}
+	if !hasToken {
+		return fixedpoint.Zero, fmt.Errorf("polymarket: market %s has no CLOB token id", symbol)
+	}
+
+	bid, ask, ok := e.tickers.bestBidAsk(token.TokenID, time.Now())
+	if !ok {
+		return fixedpoint.Zero, fmt.Errorf("polymarket: no fresh order book of %s in the market channel cache", symbol)
+	}
+	return impliedComplementPrice(bid.Add(ask).Div(fixedpoint.Two), tick), nil
+}
+
+// CheckComplement 检查 symbol 与另一个 outcome 的盘口是否一致，两边都需要有未过期的盘口缓存。
+func (e *Exchange) CheckComplement(symbol string, tolerance fixedpoint.Value) (*ComplementCheck, error) {
+	e.mu.RLock()
+	complement, ok := e.complementSymbolLocked(symbol)
+	token, hasToken := e.tokenOfLocked(symbol)
+	complementToken, hasComplementToken := e.tokenOfLocked(complement)
+	e.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("polymarket: complement outcome of %s not found", symbol)
+	}
+	if !hasToken || !hasComplementToken {
+		return nil, fmt.Errorf("polymarket: market %s or %s has no CLOB token id", symbol, complement)
+	}
+
+	now := time.Now()
+	bid, ask, ok := e.tickers.bestBidAsk(token.TokenID, now)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: no fresh order book of %s in the market channel cache", symbol)
+	}
+	complementBid, complementAsk, ok := e.tickers.bestBidAsk(complementToken.TokenID, now)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: no fresh order book of %s in the market channel cache", complement)
+	}
+
+	check := checkComplement(bid, ask, complementBid, complementAsk, tolerance)
+	check.Symbol, check.Complement = symbol, complement
+	return &check, nil
+}
+
+// impliedComplementPrice 返回 1 - price，按 tick 四舍五入（tick 为 0 时不取整）并限制在 [0, 1]。
+func impliedComplementPrice(price, tick fixedpoint.Value) fixedpoint.Value {
+	implied := fixedpoint.One.Sub(price)
+	if tick.Sign() > 0 {
+		implied = implied.Div(tick).Add(fixedpoint.NewFromFloat(0.5)).Add(snapTolerance).Floor().Mul(tick)
+	}
+	return fixedpoint.Min(fixedpoint.Max(implied, fixedpoint.Zero), fixedpoint.One)
+}
+
+func checkComplement(bid, ask, complementBid, complementAsk, tolerance fixedpoint.Value) ComplementCheck {
+	c := ComplementCheck{
+		Bid:           bid,
+		Ask:           ask,
+		ComplementBid: complementBid,
+		ComplementAsk: complementAsk,
+		AskSum:        ask.Add(complementAsk),
+		BidSum:        bid.Add(complementBid),
+	}
+
+	midSum := c.AskSum.Add(c.BidSum).Div(fixedpoint.Two)
+	c.Deviation = midSum.Sub(fixedpoint.One).Abs()
+	c.Arbitrage = c.AskSum.Compare(fixedpoint.One) < 0 || c.BidSum.Compare(fixedpoint.One) > 0
+	c.Consistent = !c.Arbitrage && c.Deviation.Compare(tolerance) <= 0
+	return c
+}
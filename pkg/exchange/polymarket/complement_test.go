@@ -0,0 +1,123 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestImpliedComplementPrice(t *testing.T) {
+	tick := fixedpoint.NewFromFloat(0.01)
+	for _, c := range []struct {
+		price, tick fixedpoint.Value
+		expected    string
+	}{
+		{fixedpoint.NewFromFloat(0.6), tick, "0.4"},
+		{fixedpoint.NewFromFloat(0.505), tick, "0.5"},
+		{fixedpoint.NewFromFloat(0.5049), tick, "0.5"},
+		{fixedpoint.NewFromFloat(0.5151), tick, "0.48"},
+		{fixedpoint.NewFromFloat(0.1234), fixedpoint.Zero, "0.8766"},
+		// 限制在 [0, 1]
+		{fixedpoint.NewFromFloat(1.02), tick, "0"},
+		{fixedpoint.NewFromFloat(-0.01), tick, "1"},
+		{fixedpoint.One, tick, "0"},
+		{fixedpoint.Zero, tick, "1"},
+	} {
+		assert.Equal(t, c.expected, impliedComplementPrice(c.price, c.tick).String(), "price %s", c.price.String())
+	}
+}
+
+func TestCheckComplement(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	tolerance := f(0.02)
+
+	c := checkComplement(f(0.55), f(0.56), f(0.44), f(0.46), tolerance)
+	assert.Equal(t, "1.02", c.AskSum.String())
+	assert.Equal(t, "0.99", c.BidSum.String())
+	assert.InDelta(t, 0.005, c.Deviation.Float64(), 1e-6)
+	assert.False(t, c.Arbitrage)
+	assert.True(t, c.Consistent)
+
+	// 两边 ask 之和低于 1：同时买入锁定收益
+	c = checkComplement(f(0.5), f(0.52), f(0.45), f(0.47), tolerance)
+	assert.True(t, c.Arbitrage)
+	assert.False(t, c.Consistent)
+
+	// 两边 bid 之和高于 1：同时卖出锁定收益
+	c = checkComplement(f(0.6), f(0.61), f(0.41), f(0.43), tolerance)
+	assert.True(t, c.Arbitrage)
+
+	// 没有套利，但中间价之和偏离 1 过多（盘口可能过期）
+	c = checkComplement(f(0.5), f(0.7), f(0.45), f(0.4), tolerance)
+	assert.False(t, c.Arbitrage)
+	assert.False(t, c.Consistent)
+}
+
+func TestExchange_ImpliedComplement(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[
+		{"symbol": "PM_RAIN_Y", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01",
+		 "conditionId": "0xabc", "outcome": "Yes"},
+		{"symbol": "PM_RAIN_N", "localSymbol": "222222222222", "quoteCurrency": "USDC", "pricePrecision": 3, "tickSize": "0.001", "stepSize": "0.01",
+		 "conditionId": "0xabc", "outcome": "No"},
+		{"symbol": "PM_OTHER", "localSymbol": "333333333333", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}
+	]`)
+
+	ex := New("", "", "")
+	defer ex.Close()
+	_, err := ex.QueryMarkets(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	complement, err := ex.ComplementSymbol("PM_RAIN_N")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_RAIN_Y", complement)
+	_, err = ex.ComplementSymbol("PM_OTHER")
+	assert.Error(t, err)
+
+	// 没有盘口缓存
+	_, err = ex.ImpliedComplement("PM_RAIN_Y")
+	assert.ErrorContains(t, err, "no fresh order book")
+
+	now := time.Now()
+	ex.tickers.updateBestBidAsk("111111111111", fixedpoint.NewFromFloat(0.61), fixedpoint.NewFromFloat(0.63), now)
+	price, err := ex.ImpliedComplement("PM_RAIN_Y")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.38", price.String())
+
+	_, err = ex.CheckComplement("PM_RAIN_Y", fixedpoint.NewFromFloat(0.02))
+	assert.ErrorContains(t, err, "PM_RAIN_N")
+
+	ex.tickers.updateBestBidAsk("222222222222", fixedpoint.NewFromFloat(0.37), fixedpoint.NewFromFloat(0.385), now)
+	check, err := ex.CheckComplement("PM_RAIN_Y", fixedpoint.NewFromFloat(0.02))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "PM_RAIN_N", check.Complement)
+		assert.Equal(t, "1.015", check.AskSum.String())
+		assert.True(t, check.Consistent)
+	}
+}
+
+func TestExchange_ComplementSymbol(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+	_, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	// 默认示例 market 按 _YES_ / _NO_ 命名配对
+	complement, err := ex.ComplementSymbol("PM_BTC_15M_UP_YES_USDC")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_BTC_15M_UP_NO_USDC", complement)
+
+	um := &UpDownMarket{Slug: "btc-updown-15m-1", YesSymbol: "PM_UP", NoSymbol: "PM_DOWN"}
+	ex.mu.Lock()
+	ex.upDownMarkets[um.Slug] = um
+	ex.mu.Unlock()
+
+	complement, err = ex.ComplementSymbol("PM_DOWN")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_UP", complement)
+}
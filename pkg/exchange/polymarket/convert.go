@@ -0,0 +1,80 @@
+package polymarket
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OrderMode 的返回值
+const (
+	OrderModeDryRun = "dry-run"
+	OrderModeLive   = "live"
+)
+
+// OrderMode 返回订单是 dry-run 模拟订单（types.Order.IsDryRun）还是 live 订单，
+// 迁移期间同时跑 dry-run 与 live 时，日志与盈亏报表用它区分两类订单。
+func OrderMode(o types.Order) string {
+	if o.IsDryRun {
+		return OrderModeDryRun
+	}
+	return OrderModeLive
+}
+
+// hashStringID 把 CLOB 的 hex 订单 hash 映射成 bbgo 需要的 uint64 OrderID，原始 hash 保存在 UUID。
+func hashStringID(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func toGlobalSide(side string) types.SideType {
+	switch strings.ToUpper(side) {
+	case "BUY":
+		return types.SideTypeBuy
+	case "SELL":
+		return types.SideTypeSell
+	}
+	return types.SideType(side)
+}
+
+func toGlobalOrderStatus(e OrderEvent) types.OrderStatus {
+	switch e.Type {
+	case OrderEventCancellation:
+		return types.OrderStatusCanceled
+	}
+
+	if e.SizeMatched.Sign() > 0 {
+		if e.SizeMatched.Compare(e.OriginalSize) >= 0 {
+			return types.OrderStatusFilled
+		}
+		return types.OrderStatusPartiallyFilled
+	}
+	return types.OrderStatusNew
+}
+
+// toGlobalOrder 把 user channel 的订单事件转换成 bbgo 的 types.Order。
+func toGlobalOrder(e OrderEvent, symbol string) types.Order {
+	status := toGlobalOrderStatus(e)
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:      symbol,
+			Side:        toGlobalSide(e.Side),
+			Type:        types.OrderTypeLimit,
+			Price:       e.Price,
+			Quantity:    e.OriginalSize,
+			TimeInForce: types.TimeInForceGTC,
+		},
+		Exchange:         types.ExchangePolymarket,
+		OrderID:          hashStringID(e.ID),
+		UUID:             e.ID,
+		Status:           status,
+		OriginalStatus:   e.Type,
+		ExecutedQuantity: e.SizeMatched,
+		IsWorking:        status == types.OrderStatusNew || status == types.OrderStatusPartiallyFilled,
+		CreationTime:     types.Time(e.Timestamp.Time()),
+		UpdateTime:       types.Time(e.Timestamp.Time()),
+		IsDryRun:         false,
+	}
+}
@@ -0,0 +1,102 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 盘口深度查询：下大单前查询限价以内可以成交的流动性，策略据此限制下注金额。
+// - QueryDepth 返回 symbol 的完整盘口（types.SliceOrderBook，bids 从高到低、asks 从低到高），
+//   优先使用 market channel 缓存的深度，过期或没有缓存时请求 REST /book（与 QueryTicker 相同）
+// - NotionalWithinPrice 汇总 side 方向、限价 limit 的订单可以吃到的对手方数量与金额（price × size）
+// - market 的 localSymbol 需要是 CLOB token id
+
+// QueryDepth 查询 symbol 的盘口深度。
+func (e *Exchange) QueryDepth(ctx context.Context, symbol string) (*types.SliceOrderBook, error) {
+	// 支持用 slug / condition id / token id 引用 market，见 symbols.go
+	if resolved, err := e.ResolveSymbol(symbol); err == nil {
+		symbol = resolved
+	}
+
+	token, ok := e.tokenOf(symbol)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: market %s has no CLOB token id (localSymbol)", symbol)
+	}
+
+	if bids, asks, at, ok := e.tickers.book(token.TokenID, time.Now()); ok {
+		return toSliceOrderBook(symbol, bids, asks, at), nil
+	}
+
+	book, err := e.client.queryOrderBook(ctx, token.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	return toSliceOrderBook(symbol, book.Bids, book.Asks, book.Timestamp.Time()), nil
+}
+
+// book 返回缓存的盘口深度（副本），盘口超过 maxAge 没有更新时 ok 为 false。
+func (c *tickerCache) book(assetID string, now time.Time) (bids, asks []PriceLevel, at time.Time, ok bool) {
+	t, found := c.lookup(assetID)
+	if !found {
+		return nil, nil, time.Time{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.bookUpdatedAt.IsZero() || now.Sub(t.bookUpdatedAt) > c.maxAge {
+		return nil, nil, time.Time{}, false
+	}
+
+	return append([]PriceLevel(nil), t.bids...), append([]PriceLevel(nil), t.asks...), t.bookUpdatedAt, true
+}
+
+// toSliceOrderBook 把 CLOB 的档位转换为 bbgo 的盘口，忽略数量为 0 的档位。
+func toSliceOrderBook(symbol string, bids, asks []PriceLevel, at time.Time) *types.SliceOrderBook {
+	book := types.NewSliceOrderBook(symbol)
+	book.Time = at
+	book.Bids = toPriceVolumes(bids)
+	book.Asks = toPriceVolumes(asks)
+	sort.Sort(sort.Reverse(book.Bids))
+	sort.Sort(book.Asks)
+	return book
+}
+
+func toPriceVolumes(levels []PriceLevel) types.PriceVolumeSlice {
+	pvs := make(types.PriceVolumeSlice, 0, len(levels))
+	for _, lv := range levels {
+		if lv.Price.Sign() > 0 && lv.Size.Sign() > 0 {
+			pvs = append(pvs, types.PriceVolume{Price: lv.Price, Volume: lv.Size})
+		}
+	}
+	return pvs
+}
+
+// NotionalWithinPrice 返回 side 方向、限价 limit 的订单在 book 中可以吃到的对手方总数量与总金额：
+// 买单汇总价格 <= limit 的卖单，卖单汇总价格 >= limit 的买单。
+func NotionalWithinPrice(book *types.SliceOrderBook, side types.SideType, limit fixedpoint.Value) (quantity, notional fixedpoint.Value) {
+	var levels types.PriceVolumeSlice
+	switch side {
+	case types.SideTypeBuy:
+		levels = book.Asks
+	case types.SideTypeSell:
+		levels = book.Bids
+	default:
+		return fixedpoint.Zero, fixedpoint.Zero
+	}
+
+	for _, pv := range levels {
+		if side == types.SideTypeBuy && pv.Price.Compare(limit) > 0 ||
+			side == types.SideTypeSell && pv.Price.Compare(limit) < 0 {
+			continue
+		}
+		quantity = quantity.Add(pv.Volume)
+		notional = notional.Add(pv.Price.Mul(pv.Volume))
+	}
+	return quantity, notional
+}
@@ -0,0 +1,100 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_QueryDepth(t *testing.T) {
+	const tokenID = "111111111111"
+	f := fixedpoint.NewFromFloat
+
+	requests := 0
+	transport := &httptesting.MockTransport{}
+	transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+		requests++
+		assert.Equal(t, tokenID, req.URL.Query().Get("token_id"))
+		return httptesting.BuildResponseString(http.StatusOK,
+			`{"asset_id": "`+tokenID+`", "bids": [{"price": "0.40", "size": "10"}, {"price": "0.42", "size": "2"}, {"price": "0.41", "size": "0"}],
+			"asks": [{"price": "0.46", "size": "5"}, {"price": "0.44", "size": "8"}], "timestamp": "1757908892351"}`), nil
+	})
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ex.client = newTestRestClient(transport)
+	ex.markets = types.MarketMap{
+		"PM_YES":   {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC"},
+		"PM_LOCAL": {Symbol: "PM_LOCAL", LocalSymbol: "PM_LOCAL", QuoteCurrency: "USDC"},
+	}
+	ctx := context.Background()
+
+	// 没有缓存时请求 REST /book，档位按价格从优到劣排序，忽略数量为 0 的档位
+	book, err := ex.QueryDepth(ctx, "PM_YES")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "PM_YES", book.Symbol)
+		if assert.Len(t, book.Bids, 2) {
+			assert.Equal(t, "0.42", book.Bids[0].Price.String())
+			assert.Equal(t, "0.4", book.Bids[1].Price.String())
+		}
+		if assert.Len(t, book.Asks, 2) {
+			assert.Equal(t, "0.44", book.Asks[0].Price.String())
+			assert.Equal(t, "0.46", book.Asks[1].Price.String())
+		}
+		assert.Equal(t, int64(1757908892351), book.Time.UnixMilli())
+	}
+	assert.Equal(t, 1, requests)
+
+	// market channel 的盘口缓存有效时不请求 REST
+	now := time.Now()
+	ex.tickers.updateBook(BookEvent{
+		AssetID: tokenID,
+		Bids:    []PriceLevel{{Price: f(0.45), Size: f(5)}},
+		Asks:    []PriceLevel{{Price: f(0.5), Size: f(3)}, {Price: f(0.48), Size: f(4)}},
+	}, now)
+	book, err = ex.QueryDepth(ctx, "PM_YES")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.48", book.Asks[0].Price.String())
+		assert.Equal(t, now, book.Time)
+	}
+	assert.Equal(t, 1, requests)
+
+	_, err = ex.QueryDepth(ctx, "PM_LOCAL")
+	assert.ErrorContains(t, err, "no CLOB token id")
+}
+
+func TestNotionalWithinPrice(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	book := toSliceOrderBook("PM_YES",
+		[]PriceLevel{{Price: f(0.4), Size: f(10)}, {Price: f(0.42), Size: f(2)}},
+		[]PriceLevel{{Price: f(0.46), Size: f(5)}, {Price: f(0.44), Size: f(8)}, {Price: f(0.5), Size: f(20)}},
+		time.Now())
+
+	tests := []struct {
+		name         string
+		side         types.SideType
+		limit        float64
+		wantQuantity string
+		wantNotional string
+	}{
+		{name: "buy within two levels", side: types.SideTypeBuy, limit: 0.46, wantQuantity: "13", wantNotional: "5.82"},
+		{name: "buy below the best ask", side: types.SideTypeBuy, limit: 0.43, wantQuantity: "0", wantNotional: "0"},
+		{name: "sell within one level", side: types.SideTypeSell, limit: 0.41, wantQuantity: "2", wantNotional: "0.84"},
+		{name: "sell all levels", side: types.SideTypeSell, limit: 0.01, wantQuantity: "12", wantNotional: "4.84"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quantity, notional := NotionalWithinPrice(book, tt.side, f(tt.limit))
+			assert.Equal(t, tt.wantQuantity, quantity.String())
+			assert.Equal(t, tt.wantNotional, notional.String())
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 订单事件的结构化日志：
+// - POLYMARKET_DRYRUN_LOG_FILE 配置后，dry-run 订单的创建、改单、（部分）成交、撤单事件以 JSON Lines 追加写入该文件，
+//   每行一个 DryRunEvent，可以直接用 pandas.read_json(path, lines=True) 等工具加载分析
+// - 文件在第一个事件时才创建（父目录不存在时自动创建），写入失败只打印一次警告，不影响下单
+// - symbol 属于 Gamma 发现的 up/down 市场时附带窗口的 slug 与起止时间
+
+const envDryRunLogFile = "POLYMARKET_DRYRUN_LOG_FILE"
+
+// DryRunEvent.Event 的取值
+const (
+	DryRunEventCreated         = "created"
+	DryRunEventAmended         = "amended"
+	DryRunEventPartiallyFilled = "partially_filled"
+	DryRunEventFilled          = "filled"
+	DryRunEventCanceled        = "canceled"
+)
+
+// DryRunEvent 为一条 dry-run 订单事件。
+type DryRunEvent struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+
+	OrderID       uint64            `json:"orderId"`
+	ClientOrderID string            `json:"clientOrderId,omitempty"`
+	Symbol        string            `json:"symbol"`
+	Side          types.SideType    `json:"side"`
+	Type          types.OrderType   `json:"type"`
+	Status        types.OrderStatus `json:"status"`
+	Tag           string            `json:"tag,omitempty"`
+
+	Price            fixedpoint.Value `json:"price"`
+	Quantity         fixedpoint.Value `json:"quantity"`
+	ExecutedQuantity fixedpoint.Value `json:"executedQuantity"`
+	AveragePrice     fixedpoint.Value `json:"averagePrice"`
+
+	// Window / WindowStart / WindowEnd 为 up/down 市场窗口的 slug 与起止时间，其它 market 为空
+	Window      string     `json:"window,omitempty"`
+	WindowStart *time.Time `json:"windowStart,omitempty"`
+	WindowEnd   *time.Time `json:"windowEnd,omitempty"`
+}
+
+type dryRunEventLog struct {
+	path string
+
+	mu     sync.Mutex
+	file   *os.File
+	failed bool
+}
+
+// newDryRunEventLogFromEnv 在没有配置 POLYMARKET_DRYRUN_LOG_FILE 时返回 nil（不记录）。
+func newDryRunEventLogFromEnv() *dryRunEventLog {
+	path := envString(envDryRunLogFile, "")
+	if path == "" {
+		return nil
+	}
+	return &dryRunEventLog{path: path}
+}
+
+func (l *dryRunEventLog) write(event DryRunEvent) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.failed {
+		return
+	}
+
+	line, err := json.Marshal(event)
+	if err == nil {
+		err = l.openLocked()
+	}
+	if err == nil {
+		_, err = l.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		l.failed = true
+		log.WithError(err).Warnf("failed to write dry-run event log %s, stop logging events", l.path)
+	}
+}
+
+func (l *dryRunEventLog) openLocked() error {
+	if l.file != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	l.file = f
+	return nil
+}
+
+func (l *dryRunEventLog) close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// logDryRunEvent 记录一条 dry-run 订单事件，调用时不能持有 e.mu。
+func (e *Exchange) logDryRunEvent(event string, o types.Order) {
+	if e.eventLog == nil {
+		return
+	}
+
+	ev := DryRunEvent{
+		Time:             o.UpdateTime.Time(),
+		Event:            event,
+		OrderID:          o.OrderID,
+		ClientOrderID:    o.ClientOrderID,
+		Symbol:           o.Symbol,
+		Side:             o.Side,
+		Type:             o.Type,
+		Status:           o.Status,
+		Tag:              o.Tag,
+		Price:            o.Price,
+		Quantity:         o.Quantity,
+		ExecutedQuantity: o.ExecutedQuantity,
+		AveragePrice:     o.AveragePrice,
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	e.mu.RLock()
+	for _, um := range e.upDownMarkets {
+		if um.YesSymbol == o.Symbol || um.NoSymbol == o.Symbol {
+			start, end := um.WindowStart, um.WindowEnd
+			ev.Window, ev.WindowStart, ev.WindowEnd = um.Slug, &start, &end
+			break
+		}
+	}
+	e.mu.RUnlock()
+
+	e.eventLog.write(ev)
+}
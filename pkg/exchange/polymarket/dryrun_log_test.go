@@ -0,0 +1,112 @@
+package polymarket
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func readDryRunEvents(t *testing.T, path string) (events []DryRunEvent) {
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev DryRunEvent
+		if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &ev), scanner.Text()) {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+func TestExchange_DryRunEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "dryrun.jsonl")
+	t.Setenv(envDryRunLogFile, path)
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunFillChunk, "4")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+
+	start := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+	um := &UpDownMarket{Slug: "btc-updown-15m-1760515200", YesSymbol: symbol, NoSymbol: "PM_BTC_15M_UP_NO_USDC",
+		WindowStart: start, WindowEnd: start.Add(15 * time.Minute)}
+	ex.mu.Lock()
+	ex.upDownMarkets[um.Slug] = um
+	ex.mu.Unlock()
+
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:        symbol,
+		Side:          types.SideTypeBuy,
+		Type:          types.OrderTypeLimit,
+		Price:         fixedpoint.NewFromFloat(0.5),
+		Quantity:      fixedpoint.NewFromFloat(10),
+		ClientOrderID: "c1",
+		Tag:           "test",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = ex.AmendOrder(ctx, *order, fixedpoint.NewFromFloat(0.48), fixedpoint.Zero)
+	assert.NoError(t, err)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.45))
+	assert.NoError(t, ex.CancelOrders(ctx, *order))
+	assert.NoError(t, ex.Close())
+
+	events := readDryRunEvents(t, path)
+	var names []string
+	for _, ev := range events {
+		names = append(names, ev.Event)
+	}
+	assert.Equal(t, []string{DryRunEventCreated, DryRunEventAmended, DryRunEventPartiallyFilled, DryRunEventCanceled}, names)
+
+	if len(events) == 4 {
+		created := events[0]
+		assert.Equal(t, order.OrderID, created.OrderID)
+		assert.Equal(t, "c1", created.ClientOrderID)
+		assert.Equal(t, symbol, created.Symbol)
+		assert.Equal(t, types.SideTypeBuy, created.Side)
+		assert.Equal(t, "test", created.Tag)
+		assert.Equal(t, "10", created.Quantity.String())
+		assert.False(t, created.Time.IsZero())
+		assert.Equal(t, um.Slug, created.Window)
+		if assert.NotNil(t, created.WindowStart) {
+			assert.True(t, start.Equal(*created.WindowStart))
+		}
+
+		assert.Equal(t, "0.48", events[1].Price.String())
+		assert.Equal(t, "4", events[2].ExecutedQuantity.String())
+		assert.Equal(t, types.OrderStatusCanceled, events[3].Status)
+	}
+}
+
+func TestExchange_DryRunEventLogDisabled(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+	assert.Nil(t, ex.eventLog)
+
+	_, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+}
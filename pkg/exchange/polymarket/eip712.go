@@ -0,0 +1,202 @@
+package polymarket
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// CLOB 订单的 EIP-712 签名（与 Polymarket 的 go-order-utils / py-order-utils 相同）：
+// - domain 为 EIP712Domain(name "Polymarket CTF Exchange", version "1", chainId 137, verifyingContract)，
+//   verifyingContract 为撮合订单的合约：普通市场为 CTF Exchange，neg-risk 市场为 NegRisk CTF Exchange（见 negrisk.go）
+// - 签名的结构为 Order(salt, maker, signer, taker, tokenId, makerAmount, takerAmount, expiration, nonce, feeRateBps, side, signatureType)，
+//   feeRateBps（见 fee.go）与 nonce（见 nonce.go）都在签名的结构中，CLOB 与合约按签名时的值校验
+// - signer 为私钥的地址，maker 为持有资金的钱包 POLYMARKET_WALLET_ADDRESS（未设置时与 signer 相同）
+// - POLYMARKET_SIGNATURE_TYPE 为钱包类型：0 EOA（默认，maker 必须等于 signer）、1 Polymarket proxy（邮箱 / Magic 钱包）、
+//   2 Gnosis Safe（浏览器钱包）
+// - 签名为 r || s || v（v 为 27/28），按 0x 开头的 hex 放在订单的 signature 字段
+
+const envSignatureType = "POLYMARKET_SIGNATURE_TYPE"
+
+// polygonChainID 为 Polygon 主网的 chain id
+const polygonChainID = 137
+
+// 订单的钱包类型（signatureType）
+const (
+	signatureTypeEOA        = 0
+	signatureTypePolyProxy  = 1
+	signatureTypeGnosisSafe = 2
+)
+
+const (
+	orderDomainName    = "Polymarket CTF Exchange"
+	orderDomainVersion = "1"
+)
+
+var (
+	eip712DomainTypeHash = keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+
+	orderTypeHash = keccak256([]byte("Order(uint256 salt,address maker,address signer,address taker,uint256 tokenId," +
+		"uint256 makerAmount,uint256 takerAmount,uint256 expiration,uint256 nonce,uint256 feeRateBps,uint8 side,uint8 signatureType)"))
+)
+
+// orderDomainSeparator 返回订单签名 domain 的 hash。
+func orderDomainSeparator(chainID int64, verifyingContract string) ([]byte, error) {
+	contract, err := abiAddress(verifyingContract)
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256(
+		eip712DomainTypeHash,
+		keccak256([]byte(orderDomainName)),
+		keccak256([]byte(orderDomainVersion)),
+		abiUint256(big.NewInt(chainID)),
+		contract,
+	), nil
+}
+
+// orderStructHash 返回订单结构的 EIP-712 hash。
+func orderStructHash(o *CLOBOrder) ([]byte, error) {
+	var side int64
+	switch o.Side {
+	case "BUY":
+		side = 0
+	case "SELL":
+		side = 1
+	default:
+		return nil, fmt.Errorf("polymarket: invalid order side %q", o.Side)
+	}
+
+	fields := [][]byte{orderTypeHash, abiUint256(big.NewInt(o.Salt))}
+	for _, addr := range []struct{ name, value string }{{"maker", o.Maker}, {"signer", o.Signer}, {"taker", o.Taker}} {
+		b, err := abiAddress(addr.value)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: invalid order %s: %w", addr.name, err)
+		}
+		fields = append(fields, b)
+	}
+	for _, n := range []struct{ name, value string }{
+		{"tokenId", o.TokenID},
+		{"makerAmount", o.MakerAmount},
+		{"takerAmount", o.TakerAmount},
+		{"expiration", o.Expiration},
+		{"nonce", o.Nonce},
+		{"feeRateBps", o.FeeRateBps},
+	} {
+		v, ok := new(big.Int).SetString(n.value, 10)
+		if !ok || v.Sign() < 0 || v.BitLen() > 256 {
+			return nil, fmt.Errorf("polymarket: invalid order %s %q", n.name, n.value)
+		}
+		fields = append(fields, abiUint256(v))
+	}
+	fields = append(fields, abiUint256(big.NewInt(side)), abiUint256(big.NewInt(int64(o.SignatureType))))
+	return keccak256(fields...), nil
+}
+
+// orderHash 返回订单在 chainID 上由 verifyingContract 撮合时的 EIP-712 签名 hash：keccak256(0x1901 || domainSeparator || structHash)。
+func orderHash(o *CLOBOrder, chainID int64, verifyingContract string) ([]byte, error) {
+	domain, err := orderDomainSeparator(chainID, verifyingContract)
+	if err != nil {
+		return nil, err
+	}
+
+	structHash, err := orderStructHash(o)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256([]byte{0x19, 0x01}, domain, structHash), nil
+}
+
+// signOrder 用私钥对订单签名并填充 Signature，verifyingContract 按订单的 NegRisk 选择。
+func (s *signer) signOrder(o *CLOBOrder, chainID int64) error {
+	hash, err := orderHash(o, chainID, o.ExchangeAddress())
+	if err != nil {
+		return err
+	}
+
+	sig, err := s.sign(hash)
+	if err != nil {
+		return err
+	}
+	sig[64] += 27
+	o.Signature = "0x" + hex.EncodeToString(sig)
+	return nil
+}
+
+// signOrder 填充订单的 maker / signer / signatureType 并签名。
+func (e *Exchange) signOrder(o *CLOBOrder) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.signer == nil {
+		return fmt.Errorf("polymarket: a wallet private key is required to sign orders: set %s or %s (or %s=true to use dry-run)",
+			envPrivateKey, envKeystoreFile, envDryRun)
+	}
+
+	signatureType := envInt(envSignatureType, signatureTypeEOA)
+	maker := envString(envWalletAddress, e.signer.address)
+	switch signatureType {
+	case signatureTypeEOA:
+		if !strings.EqualFold(maker, e.signer.address) {
+			return fmt.Errorf("polymarket: %s %s differs from the signer %s, set %s=%d (proxy) or %d (Gnosis Safe)",
+				envWalletAddress, maker, e.signer.address, envSignatureType, signatureTypePolyProxy, signatureTypeGnosisSafe)
+		}
+	case signatureTypePolyProxy, signatureTypeGnosisSafe:
+	default:
+		return fmt.Errorf("polymarket: invalid %s %d", envSignatureType, signatureType)
+	}
+
+	var err error
+	if o.Maker, err = checksumAddress(maker); err != nil {
+		return fmt.Errorf("polymarket: invalid %s: %w", envWalletAddress, err)
+	}
+	if o.Signer, err = checksumAddress(e.signer.address); err != nil {
+		return err
+	}
+	o.SignatureType = signatureType
+	return e.signer.signOrder(o, polygonChainID)
+}
+
+// abiUint256 按 ABI 编码 uint256（32 字节大端）。
+func abiUint256(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, 32))
+}
+
+// abiAddress 按 ABI 编码地址（左侧补 0 到 32 字节）。
+func abiAddress(s string) ([]byte, error) {
+	raw, err := parseAddress(s)
+	if err != nil {
+		return nil, err
+	}
+	return append(make([]byte, 12), raw...), nil
+}
+
+// parseAddress 解析 0x 开头的 20 字节地址。
+func parseAddress(s string) ([]byte, error) {
+	h := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	raw, err := hex.DecodeString(h)
+	if err != nil || len(raw) != 20 {
+		return nil, fmt.Errorf("invalid address %q", s)
+	}
+	return raw, nil
+}
+
+// checksumAddress 返回 EIP-55 大小写校验格式的地址。
+func checksumAddress(s string) (string, error) {
+	raw, err := parseAddress(s)
+	if err != nil {
+		return "", err
+	}
+
+	lower := hex.EncodeToString(raw)
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out), nil
+}
@@ -0,0 +1,112 @@
+package polymarket
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试向量来自 Polymarket go-order-utils（Amoy 测试网 chainId 80002，hardhat 默认账户 0 的私钥）
+const (
+	vectorPrivateKey  = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	vectorAddress     = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	vectorChainID     = 80002
+	vectorCTFExchange = "0xdFE02Eb6733538f8Ea35D585af8DE5958AD99E40"
+)
+
+func vectorOrder() *CLOBOrder {
+	return &CLOBOrder{
+		Salt:          479249096354,
+		Maker:         vectorAddress,
+		Signer:        vectorAddress,
+		Taker:         zeroAddress,
+		TokenID:       "1234",
+		MakerAmount:   "100000000",
+		TakerAmount:   "50000000",
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    "100",
+		Side:          "BUY",
+		SignatureType: signatureTypeEOA,
+	}
+}
+
+func TestOrderHash(t *testing.T) {
+	hash, err := orderHash(vectorOrder(), vectorChainID, vectorCTFExchange)
+	assert.NoError(t, err)
+	assert.Equal(t, "02ca1d1aa31103804173ad1acd70066cb6c1258a4be6dada055111f9a7ea4e55", hex.EncodeToString(hash))
+
+	o := vectorOrder()
+	o.NegRisk = true
+	hash, err = orderHash(o, vectorChainID, o.ExchangeAddress())
+	assert.NoError(t, err)
+	assert.Equal(t, "f15790d3edc4b5aed427b0b543a9206fcf4b1a13dfed016d33bfb313076263b8", hex.EncodeToString(hash))
+
+	o = vectorOrder()
+	o.Side = "HOLD"
+	_, err = orderHash(o, vectorChainID, vectorCTFExchange)
+	assert.ErrorContains(t, err, "invalid order side")
+
+	o = vectorOrder()
+	o.MakerAmount = "1.5"
+	_, err = orderHash(o, vectorChainID, vectorCTFExchange)
+	assert.ErrorContains(t, err, "invalid order makerAmount")
+}
+
+func TestSigner_SignOrder(t *testing.T) {
+	key, err := parsePrivateKey(vectorPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := &signer{key: key, address: addressOf(key.PubKey())}
+
+	// neg-risk 市场的订单由 NegRisk CTF Exchange 撮合，domain 的 verifyingContract 随之变化
+	o := vectorOrder()
+	o.NegRisk = true
+	assert.NoError(t, s.signOrder(o, vectorChainID))
+	assert.Equal(t, "0x1b3646ef347e5bd144c65bd3357ba19c12c12abaeedae733cf8579bc51a2752c0454c3bc6b236957e393637982c769b8dc0706c0f5c399983d933850afd1cbcd1c", o.Signature)
+}
+
+func TestExchange_SignOrder(t *testing.T) {
+	t.Setenv(envWalletAddress, "")
+	t.Setenv(envSignatureType, "")
+
+	assert.ErrorContains(t, New("", "", "").signOrder(vectorOrder()), "private key is required")
+
+	ex, err := NewWithPrivateKey("", "", "", vectorPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	o := vectorOrder()
+	o.Maker, o.Signer, o.SignatureType = "", "", -1
+	assert.NoError(t, ex.signOrder(o))
+	assert.Equal(t, vectorAddress, o.Maker)
+	assert.Equal(t, vectorAddress, o.Signer)
+	assert.Equal(t, signatureTypeEOA, o.SignatureType)
+	assert.Len(t, o.Signature, 2+65*2)
+
+	// EOA 的 maker 必须是私钥地址，proxy / Gnosis Safe 钱包的 maker 为资金钱包
+	t.Setenv(envWalletAddress, "0x1111111111111111111111111111111111111111")
+	assert.ErrorContains(t, ex.signOrder(vectorOrder()), "differs from the signer")
+
+	t.Setenv(envSignatureType, "2")
+	o = vectorOrder()
+	assert.NoError(t, ex.signOrder(o))
+	assert.Equal(t, "0x1111111111111111111111111111111111111111", o.Maker)
+	assert.Equal(t, vectorAddress, o.Signer)
+	assert.Equal(t, signatureTypeGnosisSafe, o.SignatureType)
+
+	t.Setenv(envSignatureType, "5")
+	assert.ErrorContains(t, ex.signOrder(vectorOrder()), "invalid "+envSignatureType)
+}
+
+func TestChecksumAddress(t *testing.T) {
+	address, err := checksumAddress("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266")
+	assert.NoError(t, err)
+	assert.Equal(t, vectorAddress, address)
+
+	_, err = checksumAddress("0x1234")
+	assert.Error(t, err)
+}
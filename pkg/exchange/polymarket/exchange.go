@@ -4,13 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/sirupsen/logrus"
+	"github.com/fsnotify/fsnotify"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
@@ -39,48 +41,318 @@ type Exchange struct {
 	secret     string
 	passphrase string
 
-	mu      sync.Mutex
+	// mu 保护 market 元数据与持久化 store。dry-run 订单操作只持有读锁读取 market，
+	// 订单按 symbol 分片加锁，共用的余额与成交记录由 balanceMu 保护，见 order_shards.go
+	mu      sync.RWMutex
 	markets types.MarketMap
-
-	nextOrderID uint64
-	orders      map[uint64]*types.Order
+	// marketsUpdatedAt 为 market 列表最近一次加载或更新的时间，见 health.go
+	marketsUpdatedAt time.Time
+	// tokenSymbols 为 token id → symbol 的反向索引，随 markets 更新，见 tokens.go
+	tokenSymbols map[string]string
+	// marketRefs 为 markets 文件中的 slug / condition id 等引用，symbolIndex 为 引用 → symbols 的索引，见 symbols.go
+	marketRefs  map[string]marketRef
+	symbolIndex map[string][]string
+	// marketStatus 为 Gamma 返回的 market 关闭状态，下单前检查，见 market_status.go
+	marketStatus map[string]marketStatus
+	// negRisk 标记 neg-risk 市场，key 为 symbol，见 negrisk.go
+	negRisk map[string]bool
+
+	limits *rateLimits
+	client *restClient
+	// clob 为真实交易使用的 CLOB 接口，nil 时使用 client，见 clob_client.go
+	clob    clobClient
+	gamma   *restClient
+	rpc     *restClient
+	matcher *dryRunMatcher
+	fees    *feeSchedule
+	// nonces 为签名订单的 nonce 缓存，见 nonce.go
+	nonces *nonceManager
+	// tickers 为 market channel 驱动的 ticker 缓存，见 ticker_cache.go
+	tickers *tickerCache
+
+	// upDownMarkets 缓存按窗口发现的 up/down 市场，key 为 slug
+	upDownMarkets map[string]*UpDownMarket
+
+	// markets 文件热加载，见 markets_reload.go
+	marketsWatcher           *fsnotify.Watcher
+	marketsReloadedCallbacks []func(markets types.MarketMap)
+
+	streamMu sync.Mutex
+	streams  []*Stream
+
+	// nextOrderID 为下一个 dry-run order id（从 1 开始，方便调试），恢复持久化订单时只会增大
+	nextOrderID atomic.Uint64
+
+	// ordersMu 保护 dry-run 订单的分片列表与 order id / client order id → symbol 的索引，见 order_shards.go
+	ordersMu           sync.RWMutex
+	shards             map[string]*orderShard
+	orderSymbols       map[uint64]string
+	clientOrderSymbols map[string]string
+
+	// balanceMu 保护 dry-run 各 market 共用的余额、成交记录与订单清理计数
+	balanceMu sync.Mutex
+
+	// trades 为 dry-run 模拟撮合的成交记录，见 trades.go
+	nextTradeID uint64
+	trades      []types.Trade
+
+	// balance 为 dry-run 的 USDC 余额，见 balance.go
+	balance *dryRunBalance
+
+	// janitor 定期清理已结束的 dry-run 订单，见 janitor.go
+	janitor *orderJanitor
+
+	// redeemer 在 up/down 市场结算后兑付 dry-run 持仓，见 redeem.go
+	redeemer *dryRunRedeemer
+
+	// saveMu 串行化 dry-run 订单的持久化写入，见 persistence.go
+	saveMu sync.Mutex
+
+	// background 为 dry-run 撮合、订单清理等后台循环的 context，Close 时取消
+	background     context.Context
+	stopBackground context.CancelFunc
+
+	// signer 为真实下单签名用的钱包私钥，见 signer.go
+	signer *signer
+
+	// readOnly 为只读模式（kill switch），见 readonly.go
+	readOnly bool
+
+	// dryRun 为当前的 dry-run 模式：创建时按 POLYMARKET_DRY_RUN，可以用 WithDryRun / SetDryRun 覆盖，见 options.go
+	dryRun atomic.Bool
+
+	// allowancesChecked 表示链上授权已经检查通过，见 allowance.go
+	allowancesChecked bool
+
+	// collateral 为抵押 token（USDC.e / USDC），见 collateral.go
+	collateral CollateralToken
+
+	// orderCap 为单笔订单的数量与金额上限，见 order_cap.go
+	orderCap orderCap
+
+	// eventLog 为 dry-run 订单事件的 JSON 日志，没有配置时为 nil，见 dryrun_log.go
+	eventLog *dryRunEventLog
+
+	// store 为 dry-run 订单的持久化存储，见 persistence.go
+	store       Store
+	storeLoaded bool
 }
 
-func New(key, secret, passphrase string) *Exchange {
-	return &Exchange{
+func New(key, secret, passphrase string, opts ...Option) *Exchange {
+	limits := newRateLimitsFromEnv()
+	client := newClobClient(limits)
+	client.auth = newAPICredentials(key, secret, passphrase)
+	background, stopBackground := context.WithCancel(context.Background())
+	registerMetrics()
+	e := &Exchange{
 		key:        key,
 		secret:     secret,
 		passphrase: passphrase,
 		markets:    nil,
-		orders:     make(map[uint64]*types.Order),
-		// order id 从 1 开始，方便调试
-		nextOrderID: 1,
+		negRisk:    parseNegRiskMarkets(envString(envNegRiskMarkets, "")),
+		limits:     limits,
+		client:     client,
+		gamma:      newGammaClient(limits),
+		rpc:        newRestClient(envString(envRPCURL, defaultRPCURL), limits),
+		matcher:    newDryRunMatcherFromEnv(),
+		fees:       newFeeScheduleFromEnv(),
+		nonces:     newNonceManagerFromEnv(),
+		tickers:    newTickerCacheFromEnv(),
+		balance:    newDryRunBalanceFromEnv(),
+		janitor:    newOrderJanitorFromEnv(),
+		redeemer:   newDryRunRedeemerFromEnv(),
+		eventLog:   newDryRunEventLogFromEnv(),
+		readOnly:   envBool(envReadOnly, false),
+		collateral: newCollateralFromEnv(),
+		orderCap:   newOrderCapFromEnv(),
+
+		shards:             make(map[string]*orderShard),
+		orderSymbols:       make(map[uint64]string),
+		clientOrderSymbols: make(map[string]string),
+
+		upDownMarkets: make(map[string]*UpDownMarket),
+
+		background:     background,
+		stopBackground: stopBackground,
+	}
+	e.nextOrderID.Store(1)
+	e.dryRun.Store(isDryRun())
+	e.applyOptions(newOptions(opts))
+	return e
+}
+
+// NewWithPrivateKey 创建 Exchange 并加载真实下单签名用的钱包私钥，privateKey 为空时从环境变量加载（见 signer.go）。
+func NewWithPrivateKey(key, secret, passphrase, privateKey string) (*Exchange, error) {
+	return NewWithOptions(key, secret, passphrase, WithPrivateKey(privateKey))
+}
+
+// NewWithOptions 按 opts 创建 Exchange 并加载钱包私钥（没有 WithPrivateKey 时从环境变量加载），
+// 没有指定的配置仍然从环境变量读取，见 options.go。
+func NewWithOptions(key, secret, passphrase string, opts ...Option) (*Exchange, error) {
+	s, err := loadSigner(newOptions(opts).privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ex := New(key, secret, passphrase, opts...)
+	if s == nil {
+		return ex, nil
+	}
+
+	if address := envString(envSignerAddress, ""); address != "" && !strings.EqualFold(address, s.address) {
+		s.zero()
+		_ = ex.Close()
+		return nil, fmt.Errorf("polymarket: %s %s does not match the private key address %s", envSignerAddress, address, s.address)
+	}
+	if ex.client.auth != nil && ex.client.auth.address == "" {
+		ex.client.auth.address = s.address
 	}
+
+	ex.signer = s
+	log.Infof("polymarket wallet %s loaded", s.address)
+	return ex, nil
+}
+
+// Close 停止 dry-run 撮合、订单清理与 markets 文件监听等后台循环，并清零内存中的钱包私钥。
+func (e *Exchange) Close() error {
+	e.stopBackground()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.signer.zero()
+	e.signer = nil
+
+	if err := e.eventLog.close(); err != nil {
+		log.WithError(err).Warn("failed to close the dry-run event log")
+	}
+
+	if e.marketsWatcher != nil {
+		err := e.marketsWatcher.Close()
+		e.marketsWatcher = nil
+		return err
+	}
+	return nil
 }
 
 func (e *Exchange) Name() types.ExchangeName { return types.ExchangePolymarket }
 
-// Polymarket 以 USDC 为主要结算资产（目前按常见实现设定）。
-func (e *Exchange) PlatformFeeCurrency() string { return "USDC" }
+// PlatformFeeCurrency 返回结算资产：抵押 token（USDC.e 或原生 USDC，见 collateral.go）在 bbgo 中都记为 USDC。
+func (e *Exchange) PlatformFeeCurrency() string { return collateralCurrency }
+
+func (e *Exchange) NewStream() types.Stream {
+	stream := NewStream(e.key, e.secret, e.passphrase, e.IsDryRun(), e.SymbolOfTokenID)
+	stream.assetIDsOf = e.assetIDsOf
+	stream.tickers = e.tickers
+	stream.queryBalances = e.QueryAccountBalances
+	stream.queryOpenOrders = e.QueryOpenOrders
+	stream.queryOrder = e.QueryOrder
+	stream.updateTickSize = e.updateTickSize
+	stream.now = func() time.Time { return e.client.now() }
 
-func (e *Exchange) NewStream() types.Stream { return NewStream() }
+	e.streamMu.Lock()
+	e.streams = append(e.streams, stream)
+	e.streamMu.Unlock()
+
+	return stream
+}
+
+// assetIDsOf 返回 symbols 对应的 CLOB token id，symbols 为空时返回所有 market 的 token id（按 symbol 排序）。
+func (e *Exchange) assetIDsOf(symbols []string) (assetIDs []string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(symbols) == 0 {
+		for symbol := range e.markets {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+	}
+
+	for _, symbol := range symbols {
+		if m, ok := e.markets[symbol]; ok && isTokenID(m.LocalSymbol) {
+			assetIDs = append(assetIDs, m.LocalSymbol)
+		}
+	}
+	return assetIDs
+}
+
+// emitOrderUpdate 把订单状态变化推送到所有 user data stream（public-only 的 market data stream 不推送）。
+func (e *Exchange) emitOrderUpdate(order types.Order) {
+	for _, stream := range e.userStreams() {
+		stream.EmitOrderUpdate(order)
+	}
+}
+
+// emitBalanceUpdate 把余额变化推送到 user data stream。
+func (e *Exchange) emitBalanceUpdate(balances types.BalanceMap) {
+	if len(balances) == 0 {
+		return
+	}
+
+	for _, stream := range e.userStreams() {
+		stream.EmitBalanceUpdate(balances)
+	}
+}
+
+// userStreams 返回非 public-only 的 stream
+func (e *Exchange) userStreams() (streams []*Stream) {
+	e.streamMu.Lock()
+	defer e.streamMu.Unlock()
+
+	for _, stream := range e.streams {
+		if !stream.GetPublicOnly() {
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
 
 func (e *Exchange) DefaultFeeRates() types.ExchangeFee {
-	// Polymarket 的费率取决于具体 API/市场；这里先给一个 0 的默认值，避免框架强制从 Account 取费率。
+	// Polymarket 的费率取决于具体市场；默认 0，可以通过 POLYMARKET_MAKER_FEE_BPS / POLYMARKET_TAKER_FEE_BPS 配置，见 fee.go。
 	return types.ExchangeFee{
-		MakerFeeRate: fixedpoint.Zero,
-		TakerFeeRate: fixedpoint.Zero,
+		MakerFeeRate: e.fees.makerFeeRate(),
+		TakerFeeRate: e.fees.takerFeeRate(),
 	}
 }
 
-func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+func (e *Exchange) QueryMarkets(ctx context.Context) (markets types.MarketMap, err error) {
+	err = e.limits.do(ctx, e.limits.market, func() error {
+		markets, err = e.queryMarkets(ctx)
+		return err
+	})
+	return markets, err
+}
+
+func (e *Exchange) queryMarkets(ctx context.Context) (types.MarketMap, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if err := e.loadOrdersLocked(); err != nil {
+		return nil, err
+	}
+
 	if e.markets != nil && len(e.markets) > 0 {
 		return e.markets, nil
 	}
 
+	// 加载失败或没有配置 market 时优先使用 last-known-good 缓存，见 markets_cache.go
+	markets, refs, fromCache, err := loadStartupMarkets()
+	if err != nil {
+		return nil, err
+	}
+
+	e.marketRefs = refs
+	e.setMarketsLocked(markets)
+	if !fromCache {
+		e.saveMarketsCacheLocked()
+	}
+	e.startMarketsWatcherLocked()
+	return e.markets, nil
+}
+
+// loadMarkets 从环境变量/文件加载 market 列表并填充 Exchange / Symbol。
+func loadMarkets() (types.MarketMap, error) {
 	markets, err := loadMarketsFromEnv()
 	if err != nil {
 		return nil, err
@@ -91,7 +363,12 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 		markets = defaultExampleMarkets()
 	}
 
-	// 填充 Exchange 字段
+	normalizeMarkets(markets)
+	return markets, nil
+}
+
+// normalizeMarkets 填充 Exchange / Symbol 字段。
+func normalizeMarkets(markets types.MarketMap) {
 	for symbol, m := range markets {
 		m.Exchange = types.ExchangePolymarket
 		if m.Symbol == "" {
@@ -99,12 +376,23 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 		}
 		markets[symbol] = m
 	}
-
-	e.markets = markets
-	return e.markets, nil
 }
 
 func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	// 支持用 slug / condition id / token id 引用 market，见 symbols.go
+	if resolved, err := e.ResolveSymbol(symbol); err == nil {
+		symbol = resolved
+	}
+
+	// market 的 LocalSymbol 是 CLOB token id 时，用 /book 的最优买卖价作为 ticker（公开接口，dry-run 也可用）。
+	if token, ok := e.tokenOf(symbol); ok {
+		return e.queryTokenTicker(ctx, token.TokenID)
+	}
+
+	if err := e.limits.market.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// 最小实现：不调用真实接口；返回一个可用但可能为 0 的 ticker。
 	// 如果你在 Polymarket session 里只持有 USDC，这里通常不会影响 bbgo 的初始化流程。
 	t := &types.Ticker{
@@ -125,25 +413,54 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbol ...string) (map[stri
 	return out, nil
 }
 
+// QueryKLines 用 CLOB 的价格历史聚合出 symbol 的概率 K 线（没有成交量），见 token_query.go。
 func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
-	return nil, fmt.Errorf("polymarket: QueryKLines is not implemented (use Binance session for kline source)")
+	if resolved, err := e.ResolveSymbol(symbol); err == nil {
+		symbol = resolved
+	}
+
+	token, ok := e.tokenOf(symbol)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: market %s has no CLOB token id (localSymbol)", symbol)
+	}
+	return e.queryTokenKLines(ctx, token.TokenID, symbol, interval, options)
 }
 
 func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	acct := types.NewAccount()
 
-	// 用 env 注入一个可用余额，便于 dry-run/测试策略时展示账户估值等信息
-	if v := strings.TrimSpace(os.Getenv(envBalanceUSDC)); v != "" {
+	// dry-run 返回模拟盘的 outcome token 持仓，设置了起始余额时 USDC 为模拟盘的实时余额（见 balance.go）；
+	// 否则用 env 注入一个可用余额，便于测试策略时展示账户估值等信息；
+	// 真实交易没有注入余额时查询钱包地址的链上抵押 token 余额
+	if e.IsDryRun() {
+		e.mu.RLock()
+		acct.UpdateBalances(e.dryRunBalancesLocked())
+		e.mu.RUnlock()
+	}
+	if v := strings.TrimSpace(os.Getenv(envBalanceUSDC)); v != "" && !(e.IsDryRun() && e.balance.enabled) {
 		if fp, err := fixedpoint.NewFromString(v); err == nil {
 			acct.UpdateBalances(types.BalanceMap{
-				"USDC": types.Balance{Currency: "USDC", Available: fp},
+				collateralCurrency: types.Balance{Currency: collateralCurrency, Available: fp},
 			})
 		}
+	} else if owner := envString(envWalletAddress, ""); !e.IsDryRun() && owner != "" {
+		// 真实交易时查询钱包持有的抵押 token（见 collateral.go）
+		available, err := e.rpc.queryCollateralBalance(ctx, e.collateral, owner)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: query %s balance of %s failed: %w", e.collateral, owner, err)
+		}
+		acct.UpdateBalances(types.BalanceMap{
+			collateralCurrency: types.Balance{Currency: collateralCurrency, Available: available},
+		})
 	}
 
 	acct.HasFeeRate = true
-	acct.MakerFeeRate = fixedpoint.Zero
-	acct.TakerFeeRate = fixedpoint.Zero
+	acct.MakerFeeRate = e.fees.makerFeeRate()
+	acct.TakerFeeRate = e.fees.takerFeeRate()
 	return acct, nil
 }
 
@@ -156,81 +473,327 @@ func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap,
 }
 
 func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (createdOrder *types.Order, err error) {
-	// 默认 dry-run：只在内存里创建订单，便于先把策略跑通。
-	dryRun := true
-	if v := strings.TrimSpace(os.Getenv(envDryRun)); v != "" {
-		// 支持 0/1, true/false
-		if b, err2 := strconv.ParseBool(v); err2 == nil {
-			dryRun = b
+	defer func() {
+		e.recordOrderSubmission(order, err)
+	}()
+
+	if err := e.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	order, err = e.prepareOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	// 真实下单的 HTTP 请求本身会经过 order 限流（429 时由 REST client 重试），不需要再包一层
+	if !e.IsDryRun() {
+		return e.submitOrder(ctx, order)
+	}
+
+	err = e.limits.do(ctx, e.limits.order, func() error {
+		createdOrder, err = e.submitOrder(ctx, order)
+		return err
+	})
+	return createdOrder, err
+}
+
+func (e *Exchange) submitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if !e.IsDryRun() {
+		if err := e.checkMarketOpen(order.Symbol, time.Now()); err != nil {
+			return nil, err
 		}
+
+		clobOrder, err := e.buildOrder(order)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := e.CheckAllowances(ctx); err != nil {
+			return nil, err
+		}
+
+		if err := e.assignNonces(ctx, clobOrder); err != nil {
+			return nil, err
+		}
+
+		if err := e.signOrder(clobOrder); err != nil {
+			return nil, err
+		}
+
+		resp, err := e.clobAPI().PlaceOrder(ctx, clobOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		created := toGlobalPlacedOrder(order, resp, time.Now())
+		log.WithFields(created.LogFields()).Infof("polymarket order placed: %s", created.String())
+		return &created, nil
 	}
 
-	if !dryRun {
-		// TODO: 在这里实现真实的 Polymarket 下单。
-		// 需要明确：CLOB endpoint、鉴权方式（API key/签名）、market token id 的映射（LocalSymbol）等。
-		return nil, fmt.Errorf("polymarket: real trading is not implemented yet; set %s=true to use dry-run", envDryRun)
+	if err := e.loadOrders(); err != nil {
+		return nil, err
 	}
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	e.mu.RLock()
 
-	now := types.Time(time.Now())
-	oid := e.nextOrderID
-	e.nextOrderID++
+	// IOC/FOK 订单立即按盘口深度撮合，剩余部分撤单
+	snapshot, updates, existed, err := e.placeOrderLocked(order, time.Now(), isImmediateOrCancel(order))
+	if err != nil {
+		e.mu.RUnlock()
+		return nil, err
+	}
+	if existed {
+		e.mu.RUnlock()
+		return &snapshot, nil
+	}
 
-	created := &types.Order{
-		SubmitOrder:       order,
-		Exchange:          types.ExchangePolymarket,
-		OrderID:           oid,
-		Status:            types.OrderStatusNew,
-		ExecutedQuantity:  fixedpoint.Zero,
-		IsWorking:         true,
-		CreationTime:      now,
-		UpdateTime:        now,
-		OriginalStatus:    "NEW",
-		IsFutures:         false,
-		IsMargin:          false,
-		IsIsolated:        false,
+	e.saveOrdersLocked()
+	e.startMatcher()
+	e.startJanitor()
+	balances := e.orderBalancesLocked(snapshot)
+	e.mu.RUnlock()
+
+	log.WithFields(snapshot.LogFields()).Infof("polymarket(dry-run) order created: %s", snapshot.String())
+	e.logDryRunEvent(DryRunEventCreated, snapshot)
+
+	// dry-run 没有 user websocket，由 exchange 直接把订单状态推送到 user data stream，
+	// 这样 bbgo 的 order store / active order book 能跟踪到订单。
+	e.emitOrderUpdate(snapshot)
+	for _, o := range updates {
+		switch o.Status {
+		case types.OrderStatusFilled:
+			log.WithFields(o.LogFields()).Infof("polymarket(dry-run) order filled: %s", o.String())
+			e.logDryRunEvent(DryRunEventFilled, o)
+		case types.OrderStatusPartiallyFilled:
+			log.WithFields(o.LogFields()).Infof("polymarket(dry-run) order partially filled: %s", o.String())
+			e.logDryRunEvent(DryRunEventPartiallyFilled, o)
+		default:
+			log.WithFields(o.LogFields()).Infof("polymarket(dry-run) order canceled: %s", o.String())
+			e.logDryRunEvent(DryRunEventCanceled, o)
+		}
+		e.emitOrderUpdate(o)
+		snapshot = o
+	}
+	e.emitBalanceUpdate(balances)
+	return &snapshot, nil
+}
+
+// newOrderID 分配一个新的 dry-run order id。
+func (e *Exchange) newOrderID() uint64 {
+	return e.nextOrderID.Add(1) - 1
+}
+
+// advanceOrderID 保证之后分配的 order id 不小于 next，不会回退。
+func (e *Exchange) advanceOrderID(next uint64) {
+	for {
+		current := e.nextOrderID.Load()
+		if current >= next || e.nextOrderID.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// placeOrderLocked 在 symbol 的分片上检查并创建 dry-run 订单，返回创建时的订单快照，immediate 为 true 时立即撮合
+// 并在 updates 中返回之后的状态变化。相同 client order id 的重复提交返回已有订单，existed 为 true。
+// 需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) placeOrderLocked(order types.SubmitOrder, now time.Time, immediate bool) (created types.Order, updates []types.Order, existed bool, err error) {
+	// 相同 client order id 的重复提交直接返回已有订单
+	if existing, ok := e.findOrder(0, order.ClientOrderID); ok {
+		return existing, nil, true, nil
+	}
+
+	if err := e.checkMarketOpenLocked(order.Symbol, now); err != nil {
+		return created, nil, false, err
+	}
+
+	if err := e.checkPostOnlyLocked(order); err != nil {
+		return created, nil, false, err
+	}
+
+	sh := e.shard(order.Symbol)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	// 查找之后、加锁之前同一个 symbol 上可能已经用相同的 client order id 下了单
+	if existing, ok := sh.findLocked(0, order.ClientOrderID); ok {
+		return *existing, nil, true, nil
+	}
+
+	if err := e.reserveClientOrderID(order.ClientOrderID, order.Symbol); err != nil {
+		return created, nil, false, err
+	}
+
+	if err := e.lockBalanceLocked(sh, order); err != nil {
+		e.releaseClientOrderID(order.ClientOrderID)
+		return created, nil, false, err
 	}
 
-	e.orders[oid] = created
+	o := e.createOrderLocked(sh, order, now)
+	created = *o
+	if immediate {
+		updates = e.fillImmediateLocked(o, now)
+	}
+	sh.updateMetricsLocked()
+	return created, updates, false, nil
+}
 
-	logrus.WithFields(created.LogFields()).Infof("polymarket(dry-run) order created: %s", created.String())
-	return created, nil
+// createOrderLocked 创建一个 dry-run 订单并加入 symbol 的分片，需要持有 sh.mu。
+func (e *Exchange) createOrderLocked(sh *orderShard, order types.SubmitOrder, at time.Time) *types.Order {
+	now := types.Time(at)
+	oid := e.newOrderID()
+
+	created := &types.Order{
+		SubmitOrder:      order,
+		Exchange:         types.ExchangePolymarket,
+		OrderID:          oid,
+		Status:           types.OrderStatusNew,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     now,
+		UpdateTime:       now,
+		OriginalStatus:   "NEW",
+		IsFutures:        false,
+		IsMargin:         false,
+		IsIsolated:       false,
+		IsDryRun:         true,
+	}
+
+	e.indexOrderLocked(sh, created)
+	return created
 }
 
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	if !e.IsDryRun() {
+		return e.queryLiveOpenOrders(ctx, symbol)
+	}
 
-	for _, o := range e.orders {
-		if !o.IsWorking {
-			continue
+	orders, err = e.workingOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+	sortOrders(orders)
+	return orders, nil
+}
+
+// sortOrders 按 CreationTime、OrderID 排序，保证 QueryOpenOrders 的返回顺序稳定。
+func sortOrders(orders []types.Order) {
+	sort.Slice(orders, func(i, j int) bool {
+		ti, tj := orders[i].CreationTime.Time(), orders[j].CreationTime.Time()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
 		}
-		if symbol != "" && o.Symbol != symbol {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+}
+
+// queryLiveOpenOrders 从 CLOB 查询挂单，symbol 为空时返回所有已知 market 的挂单。
+func (e *Exchange) queryLiveOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if e.client.auth == nil {
+		return nil, fmt.Errorf("polymarket: API key is required to query open orders")
+	}
+
+	params := url.Values{}
+	if symbol != "" {
+		token, ok := e.tokenOf(symbol)
+		if !ok {
+			return nil, fmt.Errorf("polymarket: market %s has no CLOB token id (localSymbol)", symbol)
+		}
+		params.Set("asset_id", token.TokenID)
+	}
+
+	openOrders, err := e.clobAPI().GetOrders(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []types.Order
+	for _, o := range openOrders {
+		orderSymbol, ok := e.SymbolOfTokenID(o.AssetID)
+		if !ok {
 			continue
 		}
-		orders = append(orders, *o)
+		orders = append(orders, toGlobalOpenOrder(o, orderSymbol))
 	}
+	sortOrders(orders)
 	return orders, nil
 }
 
 func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	if err := e.checkWritable(); err != nil {
+		return err
+	}
 
+	// 真实撤单的 HTTP 请求本身会经过 cancel 限流，不需要再包一层
+	if !e.IsDryRun() {
+		return e.cancelLiveOrders(ctx, orders)
+	}
+
+	return e.limits.do(ctx, e.limits.cancel, func() error {
+		return e.cancelOrders(ctx, orders...)
+	})
+}
+
+func (e *Exchange) cancelOrders(ctx context.Context, orders ...types.Order) error {
+	if err := e.loadOrders(); err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+
+	var canceled []types.Order
 	now := types.Time(time.Now())
 	for _, o := range orders {
-		if existing, ok := e.orders[o.OrderID]; ok {
-			existing.IsWorking = false
-			existing.Status = types.OrderStatusCanceled
-			existing.OriginalStatus = "CANCELED"
-			existing.UpdateTime = now
+		if c, ok := e.cancelOrderLocked(o, now); ok {
+			canceled = append(canceled, c)
 		}
 	}
+
+	var balances types.BalanceMap
+	if len(canceled) > 0 {
+		e.saveOrdersLocked()
+		balances = e.orderBalancesLocked(canceled...)
+	}
+	e.mu.RUnlock()
+
+	for _, o := range canceled {
+		e.logDryRunEvent(DryRunEventCanceled, o)
+		e.emitOrderUpdate(o)
+	}
+	e.emitBalanceUpdate(balances)
 	return nil
 }
 
+// cancelOrderLocked 在订单所在的分片上撤销 working 订单，返回撤单后的快照，需要持有 e.mu 的读锁。
+func (e *Exchange) cancelOrderLocked(o types.Order, now types.Time) (types.Order, bool) {
+	sh, ok := e.shardOf(o.OrderID, o.ClientOrderID)
+	if !ok {
+		return types.Order{}, false
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	existing, ok := sh.findLocked(o.OrderID, o.ClientOrderID)
+	if !ok || !existing.IsWorking {
+		return types.Order{}, false
+	}
+
+	e.unlockBalanceLocked(existing)
+	existing.IsWorking = false
+	existing.Status = types.OrderStatusCanceled
+	existing.OriginalStatus = "CANCELED"
+	existing.UpdateTime = now
+	sh.updateMetricsLocked()
+	e.recordOrderCancel(existing.Symbol, 1)
+	return *existing, true
+}
+
+// isDryRun 默认 dry-run：只在内存里创建订单，便于先把策略跑通。
+func isDryRun() bool {
+	return envBool(envDryRun, true)
+}
+
 func loadMarketsFromEnv() (types.MarketMap, error) {
 	if path := strings.TrimSpace(os.Getenv(envMarketsFile)); path != "" {
 		b, err := os.ReadFile(path)
@@ -240,6 +803,12 @@ func loadMarketsFromEnv() (types.MarketMap, error) {
 		return decodeMarketsJSON(b)
 	}
 
+	// 按文件拆分的 markets 目录，见 markets_dir.go
+	if dir := strings.TrimSpace(os.Getenv(envMarketsDir)); dir != "" {
+		markets, _, err := loadMarketsDir(dir)
+		return markets, err
+	}
+
 	if raw := strings.TrimSpace(os.Getenv(envMarketsJSON)); raw != "" {
 		return decodeMarketsJSON([]byte(raw))
 	}
@@ -251,8 +820,18 @@ func decodeMarketsJSON(b []byte) (types.MarketMap, error) {
 	// 支持两种格式：
 	// 1) MarketMap: {"SYMBOL": {...}, ...}
 	// 2) []Market: [{...}, {...}]（会用 Market.Symbol 做 key）
+	// 先按字段检查，拼错的字段、类型错误与精度问题一次性报告，见 markets_schema.go
+	if err := ValidateMarketsJSON(b); err != nil {
+		return nil, err
+	}
+
 	var mm types.MarketMap
 	if err := json.Unmarshal(b, &mm); err == nil && len(mm) > 0 {
+		for symbol, m := range mm {
+			if err := validateMarket(symbol, m); err != nil {
+				return nil, err
+			}
+		}
 		return mm, nil
 	}
 
@@ -262,15 +841,36 @@ func decodeMarketsJSON(b []byte) (types.MarketMap, error) {
 	}
 
 	out := make(types.MarketMap, len(arr))
-	for _, m := range arr {
+	for i, m := range arr {
 		if m.Symbol == "" {
-			return nil, fmt.Errorf("polymarket: market symbol is empty in json")
+			return nil, fmt.Errorf("polymarket: market symbol is empty in json (index %d)", i)
+		}
+		if _, ok := out[m.Symbol]; ok {
+			return nil, fmt.Errorf("polymarket: duplicate market symbol %s in json (index %d)", m.Symbol, i)
+		}
+		if err := validateMarket(m.Symbol, m); err != nil {
+			return nil, err
 		}
 		out[m.Symbol] = m
 	}
 	return out, nil
 }
 
+// validateMarket 检查下单时会用到的精度字段，避免后续出现除零或被 CLOB 拒单。
+func validateMarket(symbol string, m types.Market) error {
+	switch {
+	case m.TickSize.Sign() <= 0:
+		return fmt.Errorf("polymarket: market %s: tickSize must be positive", symbol)
+	case m.StepSize.Sign() <= 0:
+		return fmt.Errorf("polymarket: market %s: stepSize must be positive", symbol)
+	case m.PricePrecision <= 0:
+		return fmt.Errorf("polymarket: market %s: pricePrecision must be positive", symbol)
+	case m.QuoteCurrency == "":
+		return fmt.Errorf("polymarket: market %s: quoteCurrency is required", symbol)
+	}
+	return nil
+}
+
 func defaultExampleMarkets() types.MarketMap {
 	// 这是用于示例策略（BTC 15m up/down）跑通框架的默认 market。
 	// LocalSymbol 目前预留给“Polymarket tokenId/marketId”等内部映射。
@@ -279,13 +879,13 @@ func defaultExampleMarkets() types.MarketMap {
 			Symbol:          "PM_BTC_15M_UP_YES_USDC",
 			LocalSymbol:     "PM_BTC_15M_UP_YES_USDC",
 			BaseCurrency:    "PM_BTC_15M_UP_YES",
-			QuoteCurrency:   "USDC",
+			QuoteCurrency:   collateralCurrency,
 			PricePrecision:  4,
 			VolumePrecision: 2,
 			QuotePrecision:  2,
 			// 概率价格（0~1）常用 0.0001 tick；这里只是示例
-			TickSize:   fixedpoint.NewFromFloat(0.0001),
-			StepSize:   fixedpoint.NewFromFloat(0.01),
+			TickSize:    fixedpoint.NewFromFloat(0.0001),
+			StepSize:    fixedpoint.NewFromFloat(0.01),
 			MinNotional: fixedpoint.NewFromFloat(1),
 			MinQuantity: fixedpoint.NewFromFloat(1),
 		},
@@ -293,7 +893,7 @@ func defaultExampleMarkets() types.MarketMap {
 			Symbol:          "PM_BTC_15M_UP_NO_USDC",
 			LocalSymbol:     "PM_BTC_15M_UP_NO_USDC",
 			BaseCurrency:    "PM_BTC_15M_UP_NO",
-			QuoteCurrency:   "USDC",
+			QuoteCurrency:   collateralCurrency,
 			PricePrecision:  4,
 			VolumePrecision: 2,
 			QuotePrecision:  2,
@@ -304,4 +904,3 @@ func defaultExampleMarkets() types.MarketMap {
 		},
 	}
 }
-
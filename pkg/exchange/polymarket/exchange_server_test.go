@@ -0,0 +1,260 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// mockServer 用 httptest.Server 模拟 CLOB / Gamma / Polygon RPC，记录收到的请求，
+// 覆盖真实交易路径上请求的构造与响应的解析。
+type mockServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   map[*http.Request][]byte
+}
+
+func newMockServer(t *testing.T, handlers map[string]func(w http.ResponseWriter, r *http.Request, body []byte)) *mockServer {
+	s := &mockServer{bodies: make(map[*http.Request][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.bodies[r] = body
+		s.mu.Unlock()
+
+		h, ok := handlers[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		h(w, r, body)
+	}))
+	t.Cleanup(s.Close)
+
+	t.Setenv(envClobURL, s.URL)
+	t.Setenv(envGammaURL, s.URL+"/gamma")
+	t.Setenv(envRPCURL, s.URL+"/rpc")
+	return s
+}
+
+// count 返回 method + path 的请求次数。
+func (s *mockServer) count(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, r := range s.requests {
+		if r.Method == method && r.URL.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *mockServer) last(method, path string) (*http.Request, []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if r := s.requests[i]; r.Method == method && r.URL.Path == path {
+			return r, s.bodies[r]
+		}
+	}
+	return nil, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestExchange_MockServer(t *testing.T) {
+	const (
+		yesTokenID = "111111111111"
+		noTokenID  = "222222222222"
+	)
+
+	t.Setenv(envDryRun, "false")
+	t.Setenv(envWalletAddress, testAddress)
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "`+yesTokenID+`", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+
+	tickSize := 0.01
+	server := newMockServer(t, map[string]func(w http.ResponseWriter, r *http.Request, body []byte){
+		"GET /gamma/markets": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, []map[string]interface{}{{
+				"slug":                  r.URL.Query().Get("slug"),
+				"outcomes":              `["Up", "Down"]`,
+				"clobTokenIds":          `["` + yesTokenID + `", "` + noTokenID + `"]`,
+				"orderPriceMinTickSize": tickSize,
+			}})
+		},
+		"GET /book": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, map[string]interface{}{
+				"asset_id": r.URL.Query().Get("token_id"),
+				"bids":     []map[string]string{{"price": "0.48", "size": "100"}},
+				"asks":     []map[string]string{{"price": "0.52", "size": "100"}},
+			})
+		},
+		"POST /rpc": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": "0x1"})
+		},
+		"POST /order": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, PlaceOrderResponse{Success: true, OrderID: "0xplaced", Status: "live"})
+		},
+		"DELETE /orders": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			var ids []string
+			_ = json.Unmarshal(body, &ids)
+
+			resp := CancelOrdersResponse{NotCanceled: map[string]string{}}
+			for _, id := range ids {
+				if id == "0xfilled" {
+					resp.NotCanceled[id] = "order already matched"
+				} else {
+					resp.Canceled = append(resp.Canceled, id)
+				}
+			}
+			writeJSON(w, resp)
+		},
+		"DELETE /cancel-all": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, CancelOrdersResponse{Canceled: []string{"0xopen"}})
+		},
+		"DELETE /cancel-market-orders": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, CancelOrdersResponse{NotCanceled: map[string]string{"0xfilled": "order already matched"}})
+		},
+	})
+
+	ctx := context.Background()
+	ex, err := NewWithPrivateKey("key", base64.URLEncoding.EncodeToString([]byte("secret")), "pass", testPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("QueryMarkets", func(t *testing.T) {
+		markets, err := ex.QueryMarkets(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, yesTokenID, markets["PM_YES"].LocalSymbol)
+
+		um, err := ex.DiscoverUpDownMarket(ctx, "btc", types.Interval15m, time.Unix(1760515200, 0))
+		assert.NoError(t, err)
+		req, _ := server.last(http.MethodGet, "/gamma/markets")
+		if assert.NotNil(t, req) {
+			assert.Equal(t, "btc-updown-15m-1760515200", req.URL.Query().Get("slug"))
+		}
+
+		tickSize = 0.001
+		markets, err = ex.RefreshMarkets(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, server.count(http.MethodGet, "/gamma/markets"))
+		assert.Equal(t, fixedpoint.NewFromFloat(0.001), markets[um.NoSymbol].TickSize)
+		assert.Equal(t, noTokenID, markets[um.NoSymbol].LocalSymbol)
+	})
+
+	t.Run("QueryTicker", func(t *testing.T) {
+		ticker, err := ex.QueryTicker(ctx, "PM_YES")
+		assert.NoError(t, err)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.48), ticker.Buy)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.52), ticker.Sell)
+
+		req, _ := server.last(http.MethodGet, "/book")
+		if assert.NotNil(t, req) {
+			assert.Equal(t, yesTokenID, req.URL.Query().Get("token_id"))
+		}
+	})
+
+	t.Run("SubmitOrder", func(t *testing.T) {
+		_, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   "PM_YES",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		})
+
+		// 授权检查通过、查询了链上 nonce，签名后 POST /order
+		assert.NoError(t, err)
+		assert.Equal(t, len(requiredApprovals(defaultCollateral()))+1, server.count(http.MethodPost, "/rpc"))
+
+		_, body := server.last(http.MethodPost, "/rpc")
+		var rpcReq rpcRequest
+		assert.NoError(t, json.Unmarshal(body, &rpcReq))
+		assert.Equal(t, "eth_call", rpcReq.Method)
+		if call, ok := rpcReq.Params[0].(map[string]interface{}); assert.True(t, ok) {
+			assert.Equal(t, ctfExchangeAddress, call["to"])
+			assert.Equal(t, encodeCall(selectorNonces, testAddress), call["data"])
+		}
+
+		req, body := server.last(http.MethodPost, "/order")
+		if assert.NotNil(t, req) {
+			var placed placeOrderRequest
+			assert.NoError(t, json.Unmarshal(body, &placed))
+			assert.Equal(t, "key", placed.Owner)
+			assert.Equal(t, "GTC", placed.OrderType)
+
+			o := placed.Order
+			address, _ := checksumAddress(testAddress)
+			assert.Equal(t, address, o.Maker)
+			assert.Equal(t, address, o.Signer)
+			assert.Equal(t, yesTokenID, o.TokenID)
+			assert.Equal(t, "1", o.Nonce)
+			assert.Equal(t, signatureTypeEOA, o.SignatureType)
+
+			// 签名是确定性的：用同一个私钥对收到的订单重新签名应得到相同的 signature
+			key, _ := parsePrivateKey(testPrivateKey)
+			signature := o.Signature
+			assert.NoError(t, (&signer{key: key, address: testAddress}).signOrder(o, polygonChainID))
+			assert.Equal(t, signature, o.Signature)
+		}
+	})
+
+	t.Run("CancelOrders", func(t *testing.T) {
+		err := ex.CancelOrders(ctx, types.Order{UUID: "0xopen"})
+		assert.NoError(t, err)
+
+		req, body := server.last(http.MethodDelete, "/orders")
+		if assert.NotNil(t, req) {
+			assert.JSONEq(t, `["0xopen"]`, string(body))
+			assert.Equal(t, "key", req.Header.Get("POLY_API_KEY"))
+			assert.Equal(t, "pass", req.Header.Get("POLY_PASSPHRASE"))
+			assert.NotEmpty(t, req.Header.Get("POLY_SIGNATURE"))
+		}
+
+		err = ex.CancelOrders(ctx, types.Order{UUID: "0xopen"}, types.Order{UUID: "0xfilled"})
+		assert.ErrorContains(t, err, "0xfilled: order already matched")
+
+		err = ex.CancelOrders(ctx, types.Order{OrderID: 1})
+		assert.ErrorContains(t, err, "no CLOB order hash")
+		assert.Equal(t, 2, server.count(http.MethodDelete, "/orders"))
+	})
+
+	t.Run("CancelAllOrders", func(t *testing.T) {
+		assert.NoError(t, ex.CancelAllOrders(ctx, ""))
+		assert.Equal(t, 1, server.count(http.MethodDelete, "/cancel-all"))
+
+		err := ex.CancelAllOrders(ctx, "PM_YES")
+		assert.ErrorContains(t, err, "0xfilled: order already matched")
+
+		req, body := server.last(http.MethodDelete, "/cancel-market-orders")
+		if assert.NotNil(t, req) {
+			assert.JSONEq(t, `{"asset_id": "`+yesTokenID+`"}`, string(body))
+			assert.Equal(t, "key", req.Header.Get("POLY_API_KEY"))
+		}
+
+		assert.ErrorContains(t, ex.CancelAllOrders(ctx, "PM_UNKNOWN"), "no CLOB token id")
+	})
+}
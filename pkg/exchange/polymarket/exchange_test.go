@@ -0,0 +1,254 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_EmitOrderUpdates(t *testing.T) {
+	ex := New("", "", "")
+
+	userStream := ex.NewStream()
+	marketStream := ex.NewStream()
+	marketStream.SetPublicOnly()
+
+	var updates []types.Order
+	userStream.OnOrderUpdate(func(o types.Order) {
+		updates = append(updates, o)
+	})
+	marketStream.OnOrderUpdate(func(o types.Order) {
+		t.Errorf("public stream should not receive order updates: %s", o.String())
+	})
+
+	ctx := context.Background()
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ex.CancelOrders(ctx, *order))
+	// 重复撤单不应再推送
+	assert.NoError(t, ex.CancelOrders(ctx, *order))
+
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, types.OrderStatusNew, updates[0].Status)
+		assert.Equal(t, types.OrderStatusCanceled, updates[1].Status)
+		assert.Equal(t, order.OrderID, updates[1].OrderID)
+	}
+}
+
+func TestExchange_QueryTicker(t *testing.T) {
+	const tokenID = "52114319501245915516055106046884209969926127482827954674443846427813813222426"
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, tokenID, req.URL.Query().Get("token_id"))
+		return httptesting.BuildResponseString(http.StatusOK, `{
+			"asset_id": "`+tokenID+`",
+			"bids": [{"price": "0.40", "size": "10"}, {"price": "0.45", "size": "5"}],
+			"asks": [{"price": "0.55", "size": "8"}, {"price": "0.50", "size": "3"}],
+			"timestamp": "1700000000000"
+		}`), nil
+	})
+
+	ex := New("", "", "")
+	ex.client = newTestRestClient(transport)
+	ex.markets = types.MarketMap{
+		"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID},
+	}
+
+	ticker, err := ex.QueryTicker(context.Background(), "PM_YES")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.45), ticker.Buy)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.50), ticker.Sell)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.475), ticker.Last)
+}
+
+func TestExchange_CanceledContext(t *testing.T) {
+	ex := New("", "", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ex.QueryMarkets(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = ex.QueryAccount(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.ErrorIs(t, ex.CancelOrders(ctx, types.Order{OrderID: 1}), context.Canceled)
+}
+
+func TestOrderMode(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+
+	var updates []types.Order
+	ex.NewStream().OnOrderUpdate(func(o types.Order) {
+		updates = append(updates, o)
+	})
+
+	ctx := context.Background()
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, order.IsDryRun)
+	assert.Equal(t, OrderModeDryRun, OrderMode(*order))
+	assert.Equal(t, true, order.LogFields()["dry_run"])
+
+	openOrders, err := ex.QueryOpenOrders(ctx, order.Symbol)
+	if assert.NoError(t, err) && assert.Len(t, openOrders, 1) {
+		assert.True(t, openOrders[0].IsDryRun)
+	}
+	if assert.Len(t, updates, 1) {
+		assert.True(t, updates[0].IsDryRun)
+	}
+
+	// live 订单来自 user channel 的订单事件
+	live := toGlobalOrder(OrderEvent{ID: "0xabc", Side: "BUY", Type: OrderEventPlacement}, order.Symbol)
+	assert.False(t, live.IsDryRun)
+	assert.Equal(t, OrderModeLive, OrderMode(live))
+	assert.NotContains(t, live.LogFields(), "dry_run")
+}
+
+func TestExchange_QueryOpenOrdersOrdering(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	for i := 0; i < 20; i++ {
+		_, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(1),
+		})
+		assert.NoError(t, err)
+	}
+
+	// 创建时间相同的订单按 OrderID 排序
+	t0 := types.Time(time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC))
+	for _, sh := range ex.shardList() {
+		sh.mu.Lock()
+		for id, o := range sh.orders {
+			o.CreationTime = t0
+			if id%2 == 0 {
+				o.CreationTime = types.Time(t0.Time().Add(-time.Minute))
+			}
+		}
+		sh.mu.Unlock()
+	}
+
+	first, err := ex.QueryOpenOrders(ctx, symbol)
+	if !assert.NoError(t, err) || !assert.Len(t, first, 20) {
+		return
+	}
+	for i := 1; i < len(first); i++ {
+		prev, cur := first[i-1], first[i]
+		if prev.CreationTime.Time().Equal(cur.CreationTime.Time()) {
+			assert.Less(t, prev.OrderID, cur.OrderID)
+		} else {
+			assert.True(t, prev.CreationTime.Before(cur.CreationTime.Time()))
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		again, err := ex.QueryOpenOrders(ctx, symbol)
+		assert.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+}
+
+func TestExchange_ConcurrentMarkets(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	t.Setenv("POLYMARKET_DRYRUN_AUTOFILL", "true")
+	t.Setenv("POLYMARKET_DRYRUN_FILL_INTERVAL", "1h")
+	t.Setenv(envRateOrder, "0")
+	t.Setenv(envRateCancel, "0")
+
+	markets := types.MarketMap{}
+	for i := 1; i <= 4; i++ {
+		symbol := "PM_" + strconv.Itoa(i)
+		markets[symbol] = types.Market{Symbol: symbol, LocalSymbol: strings.Repeat(strconv.Itoa(i), 12), QuoteCurrency: "USDC",
+			TickSize: f(0.01), StepSize: f(0.01)}
+	}
+	ex := New("", "", "", WithMarkets(markets))
+	defer ex.Close()
+
+	// 不同 market 并发下单、查询、撤单与更新参考价（配合 go test -race 检查）
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for symbol := range markets {
+		symbol := symbol
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				created, err := ex.SubmitOrder(ctx, types.SubmitOrder{Symbol: symbol, Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+					Price: f(0.3), Quantity: f(10)})
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, err = ex.QueryOrder(ctx, types.OrderQuery{Symbol: symbol, OrderID: strconv.FormatUint(created.OrderID, 10)})
+				assert.NoError(t, err)
+				if j%2 == 0 {
+					assert.NoError(t, ex.CancelOrders(ctx, *created))
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				ex.SetReferencePrice(symbol, f(0.5))
+				_, err := ex.QueryOpenOrders(ctx, symbol)
+				assert.NoError(t, err)
+				_, _, _ = ex.BestBidAsk(symbol)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 参考价 0.5 高于买价，剩下的订单都不会成交
+	for symbol := range markets {
+		orders, err := ex.QueryOpenOrders(ctx, symbol)
+		if assert.NoError(t, err) {
+			assert.Len(t, orders, 10)
+			for _, o := range orders {
+				assert.Equal(t, symbol, o.Symbol)
+			}
+		}
+	}
+}
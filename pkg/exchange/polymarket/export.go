@@ -0,0 +1,51 @@
+package polymarket
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 成交导出：ExportTradesCSV 把内存中全部模拟成交按成交顺序写成 CSV，方便直接用表格软件打开分析，
+// 与 POLYMARKET_DRYRUN_LOG_FILE 的订单事件 JSON 日志互补。
+// - 列：time（UTC，RFC3339）、symbol、side、price、quantity、fee（USDC）、tag、order_id
+// - 只包含 dry-run 的模拟成交，live 的成交需要通过 QueryTrades 查询
+
+var tradesCSVHeader = []string{"time", "symbol", "side", "price", "quantity", "fee", "tag", "order_id"}
+
+// ExportTradesCSV 把全部 dry-run 模拟成交写成 CSV（含表头）。
+func (e *Exchange) ExportTradesCSV(w io.Writer) error {
+	e.balanceMu.Lock()
+	trades := append([]types.Trade(nil), e.trades...)
+	e.balanceMu.Unlock()
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(tradesCSVHeader); err != nil {
+		return err
+	}
+
+	for _, t := range trades {
+		if err := cw.Write(tradeCSVRecord(t)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func tradeCSVRecord(t types.Trade) []string {
+	return []string{
+		t.Time.Time().UTC().Format(time.RFC3339),
+		t.Symbol,
+		string(t.Side),
+		t.Price.String(),
+		t.Quantity.String(),
+		t.Fee.String(),
+		t.Tag,
+		strconv.FormatUint(t.OrderID, 10),
+	}
+}
@@ -0,0 +1,67 @@
+package polymarket
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_ExportTradesCSV(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunFillChunk, "6")
+	t.Setenv(envMarketFeeBps, "PM_EXPORT_YES_USDC:200")
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	// 没有成交时只有表头
+	var buf bytes.Buffer
+	assert.NoError(t, ex.ExportTradesCSV(&buf))
+	assert.Equal(t, "time,symbol,side,price,quantity,fee,tag,order_id\n", buf.String())
+
+	ctx := context.Background()
+	symbol := "PM_EXPORT_YES_USDC"
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.6),
+		Quantity: fixedpoint.NewFromFloat(10),
+		Tag:      "alpha,beta",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 成交 6 份与 4 份
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+
+	buf.Reset()
+	assert.NoError(t, ex.ExportTradesCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, records, 3) {
+		return
+	}
+
+	orderID := strconv.FormatUint(order.OrderID, 10)
+	for i, want := range [][]string{
+		{symbol, "BUY", "0.6", "6", "0.048", "alpha,beta", orderID},
+		{symbol, "BUY", "0.6", "4", "0.032", "alpha,beta", orderID},
+	} {
+		record := records[i+1]
+		_, err := time.Parse(time.RFC3339, record[0])
+		assert.NoError(t, err)
+		assert.Equal(t, want, record[1:])
+	}
+}
@@ -0,0 +1,96 @@
+package polymarket
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// Polymarket 的手续费以 bps 表示（订单里的 feeRateBps 字段），大部分市场为 0，部分市场会收取 taker 费。
+// - POLYMARKET_MAKER_FEE_BPS / POLYMARKET_TAKER_FEE_BPS：全局默认费率（默认 0）
+// - POLYMARKET_MARKET_FEE_BPS：按 symbol 覆盖 taker 费率，格式 "SYMBOL_A:100,SYMBOL_B:50"
+// - 通过 Gamma 发现的市场会使用 Gamma 返回的 takerBaseFee
+
+const (
+	envMakerFeeBps  = "POLYMARKET_MAKER_FEE_BPS"
+	envTakerFeeBps  = "POLYMARKET_TAKER_FEE_BPS"
+	envMarketFeeBps = "POLYMARKET_MARKET_FEE_BPS"
+)
+
+var bpsBase = fixedpoint.NewFromInt(10000)
+
+type feeSchedule struct {
+	makerBps int
+	takerBps int
+
+	mu sync.Mutex
+	// marketBps 为按 symbol 覆盖的费率
+	marketBps map[string]int
+}
+
+func newFeeScheduleFromEnv() *feeSchedule {
+	return &feeSchedule{
+		makerBps:  envInt(envMakerFeeBps, 0),
+		takerBps:  envInt(envTakerFeeBps, 0),
+		marketBps: parseMarketFeeBps(envString(envMarketFeeBps, "")),
+	}
+}
+
+func parseMarketFeeBps(s string) map[string]int {
+	out := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		symbol, bps, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			continue
+		}
+
+		v, err := strconv.Atoi(strings.TrimSpace(bps))
+		if err != nil {
+			log.WithError(err).Warnf("invalid %s entry: %q", envMarketFeeBps, item)
+			continue
+		}
+		out[strings.TrimSpace(symbol)] = v
+	}
+	return out
+}
+
+// FeeRateBps 返回 symbol 下单时使用的费率（bps），策略用它按含手续费的成本计算下单数量（见 SnapOrderWithFee）。
+func (e *Exchange) FeeRateBps(symbol string) int {
+	return e.fees.feeRateBps(symbol)
+}
+
+// feeRateBps 返回 symbol 下单时使用的 feeRateBps。
+func (f *feeSchedule) feeRateBps(symbol string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if bps, ok := f.marketBps[symbol]; ok {
+		return bps
+	}
+	return f.takerBps
+}
+
+// setMarketFeeBps 设置单个 symbol 的费率，已经通过 env 配置的 symbol 不会被覆盖。
+func (f *feeSchedule) setMarketFeeBps(symbol string, bps int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.marketBps[symbol]; ok {
+		return
+	}
+	f.marketBps[symbol] = bps
+}
+
+func (f *feeSchedule) makerFeeRate() fixedpoint.Value {
+	return bpsToRate(f.makerBps)
+}
+
+func (f *feeSchedule) takerFeeRate() fixedpoint.Value {
+	return bpsToRate(f.takerBps)
+}
+
+func bpsToRate(bps int) fixedpoint.Value {
+	return fixedpoint.NewFromInt(int64(bps)).Div(bpsBase)
+}
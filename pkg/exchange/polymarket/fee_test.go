@@ -0,0 +1,161 @@
+package polymarket
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_FeeRates(t *testing.T) {
+	t.Setenv(envMakerFeeBps, "0")
+	t.Setenv(envTakerFeeBps, "100")
+	t.Setenv(envMarketFeeBps, "PM_YES:200, bad, PM_NO:x")
+
+	ex := New("", "", "")
+	fee := ex.DefaultFeeRates()
+	assert.Equal(t, fixedpoint.Zero, fee.MakerFeeRate)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.01), fee.TakerFeeRate)
+
+	ex.markets = types.MarketMap{
+		"PM_YES":   {Symbol: "PM_YES", LocalSymbol: "111111111111"},
+		"PM_OTHER": {Symbol: "PM_OTHER", LocalSymbol: "222222222222"},
+	}
+
+	order, err := ex.buildOrder(types.SubmitOrder{
+		Symbol:   "PM_YES",
+		Side:     types.SideTypeBuy,
+		Price:    fixedpoint.NewFromFloat(0.55),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "200", order.FeeRateBps)
+	assert.Equal(t, "5500000", order.MakerAmount)
+	assert.Equal(t, "10000000", order.TakerAmount)
+
+	order, err = ex.buildOrder(types.SubmitOrder{
+		Symbol:   "PM_OTHER",
+		Side:     types.SideTypeSell,
+		Price:    fixedpoint.NewFromFloat(0.55),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "100", order.FeeRateBps)
+	assert.Equal(t, "10000000", order.MakerAmount)
+	assert.Equal(t, "5500000", order.TakerAmount)
+}
+
+// feeRateBps 是 EIP-712 签名结构的一部分：CLOB 与合约按签名中的费率收费，改动费率会改变签名的 hash。
+func TestFeeRateBps_SignedStructHash(t *testing.T) {
+	o := vectorOrder()
+	structHash, err := orderStructHash(o)
+	assert.NoError(t, err)
+
+	// 按 Order 类型的字段顺序逐个编码，feeRateBps 是第 10 个字段
+	fields := [][]byte{orderTypeHash, abiUint256(big.NewInt(o.Salt))}
+	for _, addr := range []string{o.Maker, o.Signer, o.Taker} {
+		b, _ := abiAddress(addr)
+		fields = append(fields, b)
+	}
+	for _, v := range []int64{1234, 100000000, 50000000, 0, 0, 100, 0, signatureTypeEOA} {
+		fields = append(fields, abiUint256(big.NewInt(v)))
+	}
+	assert.Equal(t, keccak256(fields...), structHash)
+
+	fields[10] = abiUint256(big.NewInt(200))
+	o.FeeRateBps = "200"
+	feeHash, err := orderStructHash(o)
+	assert.NoError(t, err)
+	assert.Equal(t, keccak256(fields...), feeHash)
+	assert.NotEqual(t, structHash, feeHash)
+
+	// buildOrder 按 symbol 的费率填充 feeRateBps，签名随费率变化
+	t.Setenv(envWalletAddress, "")
+	t.Setenv(envSignatureType, "")
+	t.Setenv(envMakerFeeBps, "0")
+	t.Setenv(envTakerFeeBps, "100")
+	t.Setenv(envMarketFeeBps, "PM_YES:200")
+
+	ex, err := NewWithPrivateKey("", "", "", vectorPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ex.markets = types.MarketMap{
+		"PM_YES":   {Symbol: "PM_YES", LocalSymbol: "111111111111"},
+		"PM_OTHER": {Symbol: "PM_OTHER", LocalSymbol: "111111111111"},
+	}
+
+	var signatures []string
+	for _, symbol := range []string{"PM_YES", "PM_OTHER"} {
+		order, err := ex.buildOrder(types.SubmitOrder{
+			Symbol:        symbol,
+			Side:          types.SideTypeBuy,
+			Price:         fixedpoint.NewFromFloat(0.55),
+			Quantity:      fixedpoint.NewFromFloat(10),
+			ClientOrderID: "same-salt",
+		})
+		if assert.NoError(t, err) && assert.NoError(t, ex.signOrder(order)) {
+			signatures = append(signatures, order.Signature)
+		}
+	}
+	if assert.Len(t, signatures, 2) {
+		assert.NotEqual(t, signatures[0], signatures[1])
+	}
+}
+
+func TestExchange_DryRunPartialFillFees(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunFillChunk, "4")
+	t.Setenv(envBalanceUSDC, "100")
+	t.Setenv(envMarketFeeBps, "PM_FEES_YES_USDC:200")
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	ctx := context.Background()
+	symbol := "PM_FEES_YES_USDC"
+	fees := feeMetrics.WithLabelValues(symbol, "dry_run")
+	notional := fillNotionalMetrics.WithLabelValues(symbol, "dry_run")
+
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.6),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+
+	// 每次撮合成交 4 份：4、4、2
+	for i := 0; i < 3; i++ {
+		ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	}
+
+	trades, err := ex.QueryTrades(ctx, symbol, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, trades, 3) {
+		// 每笔手续费为 0.02 * min(0.6, 0.4) * size
+		for i, want := range []float64{0.032, 0.032, 0.016} {
+			assert.InDelta(t, want, trades[i].Fee.Float64(), 1e-9)
+			assert.Equal(t, collateralCurrency, trades[i].FeeCurrency)
+		}
+	}
+
+	cost, err := ex.QueryOrderCost(ctx, types.OrderQuery{Symbol: symbol, OrderID: strconv.FormatUint(order.OrderID, 10)})
+	if assert.NoError(t, err) {
+		assert.InDelta(t, 0.08, cost.Fee.Float64(), 1e-9)
+	}
+
+	summary := ex.DryRunSummary()
+	assert.InDelta(t, 0.08, summary.Fees.Float64(), 1e-9)
+	assert.InDelta(t, 6, summary.FilledNotional.Float64(), 1e-9)
+
+	assert.InDelta(t, 0.08, metricValue(t, fees), 1e-9)
+	assert.InDelta(t, 6, metricValue(t, notional), 1e-9)
+}
@@ -0,0 +1,62 @@
+package polymarket
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 订单的平均成交价与成本：
+// - 返回的 types.Order 的 AveragePrice 为按成交量加权的平均成交价（dry-run 在撮合时更新，真实下单时 Stream 按收到的成交更新）
+// - types.Order 没有成本字段，总成本（成交金额与手续费）通过 QueryOrderCost 按订单的成交记录汇总
+
+// FillCost 为一个订单所有成交的汇总。
+type FillCost struct {
+	Quantity fixedpoint.Value `json:"quantity"`
+	// AveragePrice 为按成交量加权的平均成交价
+	AveragePrice fixedpoint.Value `json:"averagePrice"`
+	// Cost 为成交金额（price * quantity 之和，USDC），不含手续费
+	Cost fixedpoint.Value `json:"cost"`
+	Fee  fixedpoint.Value `json:"fee"`
+}
+
+func (c *FillCost) add(price, quantity, fee fixedpoint.Value) {
+	c.Quantity = c.Quantity.Add(quantity)
+	c.Cost = c.Cost.Add(price.Mul(quantity))
+	c.Fee = c.Fee.Add(fee)
+	if c.Quantity.Sign() > 0 {
+		c.AveragePrice = c.Cost.Div(c.Quantity)
+	}
+}
+
+// summarizeTrades 汇总成交记录。
+func summarizeTrades(trades []types.Trade) FillCost {
+	var c FillCost
+	for _, t := range trades {
+		c.add(t.Price, t.Quantity, t.Fee)
+	}
+	return c
+}
+
+// applyFill 把一笔 price 成交 quantity 计入订单的成交量与平均成交价，状态由调用方更新。
+func applyFill(o *types.Order, quantity, price fixedpoint.Value) {
+	executed := o.ExecutedQuantity.Add(quantity)
+	if o.ExecutedQuantity.IsZero() {
+		o.AveragePrice = price
+	} else {
+		o.AveragePrice = o.AveragePrice.Mul(o.ExecutedQuantity).Add(price.Mul(quantity)).Div(executed)
+	}
+	o.ExecutedQuantity = executed
+}
+
+// QueryOrderCost 按订单的成交记录汇总平均成交价、成交金额与手续费。
+func (e *Exchange) QueryOrderCost(ctx context.Context, q types.OrderQuery) (*FillCost, error) {
+	trades, err := e.QueryOrderTrades(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	c := summarizeTrades(trades)
+	return &c, nil
+}
@@ -0,0 +1,129 @@
+package polymarket
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestSummarizeTrades(t *testing.T) {
+	c := summarizeTrades([]types.Trade{
+		{Price: fixedpoint.NewFromFloat(0.5), Quantity: fixedpoint.NewFromFloat(4), Fee: fixedpoint.NewFromFloat(0.02)},
+		{Price: fixedpoint.NewFromFloat(0.6), Quantity: fixedpoint.NewFromFloat(6), Fee: fixedpoint.NewFromFloat(0.03)},
+	})
+	assert.Equal(t, "10", c.Quantity.String())
+	assert.InDelta(t, 5.6, c.Cost.Float64(), 1e-6)
+	assert.InDelta(t, 0.56, c.AveragePrice.Float64(), 1e-6)
+	assert.InDelta(t, 0.05, c.Fee.Float64(), 1e-6)
+
+	assert.True(t, summarizeTrades(nil).AveragePrice.IsZero())
+}
+
+func TestApplyFill(t *testing.T) {
+	o := &types.Order{SubmitOrder: types.SubmitOrder{Quantity: fixedpoint.NewFromFloat(10)}}
+	applyFill(o, fixedpoint.NewFromFloat(2), fixedpoint.NewFromFloat(0.4))
+	assert.Equal(t, "0.4", o.AveragePrice.String())
+
+	applyFill(o, fixedpoint.NewFromFloat(3), fixedpoint.NewFromFloat(0.5))
+	applyFill(o, fixedpoint.NewFromFloat(5), fixedpoint.NewFromFloat(0.6))
+	assert.Equal(t, "10", o.ExecutedQuantity.String())
+	// (0.8 + 1.5 + 3) / 10
+	assert.InDelta(t, 0.53, o.AveragePrice.Float64(), 1e-6)
+}
+
+func TestExchange_DryRunAveragePrice(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunFillChunk, "4")
+	t.Setenv(envBalanceUSDC, "100")
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+
+	// 每次撮合使用不同的滑点，成交价分别为 0.5、0.55、0.6
+	for _, slippage := range []float64{0, 0.1, 0.2} {
+		ex.mu.Lock()
+		ex.matcher.slippage = fixedpoint.NewFromFloat(slippage)
+		ex.mu.Unlock()
+		ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.45))
+	}
+
+	q := types.OrderQuery{Symbol: symbol, OrderID: strconv.FormatUint(order.OrderID, 10)}
+	filled, err := ex.QueryOrder(ctx, q)
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, filled.Status)
+	// (4 * 0.5 + 4 * 0.55 + 2 * 0.6) / 10
+	assert.InDelta(t, 0.54, filled.AveragePrice.Float64(), 1e-6)
+
+	cost, err := ex.QueryOrderCost(ctx, q)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", cost.Quantity.String())
+	assert.InDelta(t, 5.4, cost.Cost.Float64(), 1e-6)
+	assert.InDelta(t, filled.AveragePrice.Float64(), cost.AveragePrice.Float64(), 1e-6)
+}
+
+func TestStream_OrderUpdateAveragePrice(t *testing.T) {
+	stream := NewStream("key", "secret", "pass", false, func(assetID string) (string, bool) {
+		return "PM_YES", assetID == "111111111111"
+	})
+
+	var orders []types.Order
+	stream.OnOrderUpdate(func(o types.Order) {
+		orders = append(orders, o)
+	})
+
+	newTrade := func(id, price, size string) *TradeEvent {
+		return &TradeEvent{TradeRecord: TradeRecord{
+			ID:           id,
+			TakerOrderID: "0x0123",
+			AssetID:      "111111111111",
+			Side:         "BUY",
+			Size:         fixedpoint.MustNewFromString(size),
+			Price:        fixedpoint.MustNewFromString(price),
+			Status:       TradeStatusMatched,
+			MatchTime:    "1672290701",
+			Owner:        "key",
+		}}
+	}
+	newOrder := func(eventType, matched string) *OrderEvent {
+		return &OrderEvent{
+			ID:           "0x0123",
+			AssetID:      "111111111111",
+			Side:         "BUY",
+			Price:        fixedpoint.NewFromFloat(0.6),
+			OriginalSize: fixedpoint.NewFromFloat(10),
+			SizeMatched:  fixedpoint.MustNewFromString(matched),
+			Type:         eventType,
+		}
+	}
+
+	stream.dispatchEvent([]interface{}{newTrade("t1", "0.5", "4"), newOrder(OrderEventUpdate, "4")})
+	stream.dispatchEvent([]interface{}{newTrade("t2", "0.6", "6"), newOrder(OrderEventUpdate, "10")})
+
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, "0.5", orders[0].AveragePrice.String())
+		assert.Equal(t, types.OrderStatusFilled, orders[1].Status)
+		assert.InDelta(t, 0.56, orders[1].AveragePrice.Float64(), 1e-6)
+	}
+
+	// 订单结束后删除成交汇总
+	stream.mu.Lock()
+	assert.Empty(t, stream.fills)
+	stream.mu.Unlock()
+}
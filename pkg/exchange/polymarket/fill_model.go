@@ -0,0 +1,135 @@
+package polymarket
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 成交概率模型（替代“参考价穿过限价即全部成交”）：
+// - POLYMARKET_DRYRUN_FILL_CURVE="-0.05:0,0:0.3,0.02:1" 由 距离:概率 组成的分段线性曲线，
+//   距离为限价相对参考价“穿过”的幅度（买单为 限价-参考价，卖单为 参考价-限价），
+//   每个撮合周期按曲线上的概率成交，距离超出曲线两端时取端点的概率
+// - 没有参考价的订单仍按 POLYMARKET_DRYRUN_FILL_PROBABILITY 成交
+// - 每次成交的数量见 partial_fill.go
+
+const envDryRunFillCurve = "POLYMARKET_DRYRUN_FILL_CURVE"
+
+type fillCurvePoint struct {
+	distance    float64
+	probability float64
+}
+
+// fillCurve 为按 distance 升序排列的曲线点。
+type fillCurve []fillCurvePoint
+
+func parseFillCurve(s string) (fillCurve, error) {
+	var curve fillCurve
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		d, p, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid fill curve point %q, expect distance:probability", item)
+		}
+
+		distance, err := strconv.ParseFloat(strings.TrimSpace(d), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fill curve distance %q: %w", d, err)
+		}
+
+		probability, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fill curve probability %q: %w", p, err)
+		}
+		if probability < 0 || probability > 1 {
+			return nil, fmt.Errorf("fill curve probability %v out of range [0, 1]", probability)
+		}
+
+		curve = append(curve, fillCurvePoint{distance: distance, probability: probability})
+	}
+
+	if len(curve) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(curve, func(i, j int) bool { return curve[i].distance < curve[j].distance })
+	return curve, nil
+}
+
+// probability 按距离线性插值成交概率。
+func (c fillCurve) probability(distance float64) float64 {
+	if distance <= c[0].distance {
+		return c[0].probability
+	}
+
+	for i := 1; i < len(c); i++ {
+		if distance > c[i].distance {
+			continue
+		}
+
+		lo, hi := c[i-1], c[i]
+		if hi.distance == lo.distance {
+			return hi.probability
+		}
+		return lo.probability + (hi.probability-lo.probability)*(distance-lo.distance)/(hi.distance-lo.distance)
+	}
+
+	return c[len(c)-1].probability
+}
+
+func newFillCurveFromEnv() fillCurve {
+	curve, err := parseFillCurve(envString(envDryRunFillCurve, ""))
+	if err != nil {
+		log.WithError(err).Warnf("invalid %s, fall back to fill-on-cross", envDryRunFillCurve)
+		return nil
+	}
+	return curve
+}
+
+// fillDistance 返回限价相对参考价穿过的幅度，越大越容易成交。
+func fillDistance(o *types.Order, ref fixedpoint.Value) float64 {
+	if o.Side == types.SideTypeSell {
+		return ref.Sub(o.Price).Float64()
+	}
+	return o.Price.Sub(ref).Float64()
+}
+
+// fillQuantity 返回订单本轮撮合的成交数量，不成交时返回 0。
+// stepSize 为 market 的数量精度，部分成交的数量按它向下取整。
+func (m *dryRunMatcher) fillQuantity(o *types.Order, stepSize fixedpoint.Value, now time.Time) fixedpoint.Value {
+	if !m.shouldFillWithCurve(o, now) {
+		return fixedpoint.Zero
+	}
+	return m.increment(o, stepSize)
+}
+
+// shouldFillWithCurve 在配置了概率曲线且有参考价时按曲线判断是否成交，否则使用 shouldFill。
+func (m *dryRunMatcher) shouldFillWithCurve(o *types.Order, now time.Time) bool {
+	ref, hasRef := m.referencePrice(o.Symbol)
+	if m.curve == nil || !hasRef || ref.Sign() <= 0 {
+		return m.shouldFill(o, now)
+	}
+
+	if m.latency > 0 && now.Sub(o.CreationTime.Time()) < m.latency {
+		return false
+	}
+
+	return m.random() < m.curve.probability(fillDistance(o, ref))
+}
+
+func (m *dryRunMatcher) random() float64 {
+	if m.rand != nil {
+		return m.rand()
+	}
+	return rand.Float64()
+}
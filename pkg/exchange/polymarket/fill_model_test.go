@@ -0,0 +1,96 @@
+package polymarket
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestParseFillCurve(t *testing.T) {
+	curve, err := parseFillCurve("0.02:1, -0.05:0, 0:0.3")
+	assert.NoError(t, err)
+	if assert.Len(t, curve, 3) {
+		assert.Equal(t, -0.05, curve[0].distance)
+	}
+
+	assert.InDelta(t, 0, curve.probability(-0.1), 1e-9)
+	assert.InDelta(t, 0.15, curve.probability(-0.025), 1e-9)
+	assert.InDelta(t, 0.3, curve.probability(0), 1e-9)
+	assert.InDelta(t, 0.65, curve.probability(0.01), 1e-9)
+	assert.InDelta(t, 1, curve.probability(0.5), 1e-9)
+
+	curve, err = parseFillCurve("")
+	assert.NoError(t, err)
+	assert.Nil(t, curve)
+
+	_, err = parseFillCurve("0.1")
+	assert.Error(t, err)
+	_, err = parseFillCurve("0:1.5")
+	assert.Error(t, err)
+}
+
+func TestExchange_DryRunFillCurve(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunFillCurve, "-0.1:0,0:0.5,0.1:1")
+	t.Setenv(envDryRunFillRatio, "0.4")
+	t.Setenv(envBalanceUSDC, "100")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+
+	// 随机数 0.4：概率 > 0.4 时成交
+	ex.matcher.rand = func() float64 { return 0.4 }
+
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+
+	// 限价低于参考价 0.05，概率 0.25，不成交
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.55))
+	openOrders, err := ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	if assert.Len(t, openOrders, 1) {
+		assert.Equal(t, types.OrderStatusNew, openOrders[0].Status)
+	}
+
+	// 限价等于参考价，概率 0.5，成交 40%
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	openOrders, err = ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	if assert.Len(t, openOrders, 1) {
+		assert.Equal(t, types.OrderStatusPartiallyFilled, openOrders[0].Status)
+		assert.Equal(t, "4", openOrders[0].ExecutedQuantity.String())
+	}
+
+	// 继续成交 40%，最后一次成交剩余的 20%
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	openOrders, err = ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+
+	trades, err := ex.QueryOrderTrades(ctx, types.OrderQuery{Symbol: symbol, OrderID: strconv.FormatUint(order.OrderID, 10)})
+	assert.NoError(t, err)
+	if assert.Len(t, trades, 3) {
+		assert.Equal(t, "4", trades[0].Quantity.String())
+		assert.Equal(t, "4", trades[1].Quantity.String())
+		assert.Equal(t, "2", trades[2].Quantity.String())
+	}
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.InDelta(t, 95, balances["USDC"].Available.Float64(), 1e-6)
+	assert.InDelta(t, 0, balances["USDC"].Locked.Float64(), 1e-6)
+}
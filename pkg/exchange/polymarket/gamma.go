@@ -0,0 +1,262 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Gamma 是 Polymarket 的 market 元数据 API。
+// 周期性的 “Bitcoin Up or Down” 市场每个窗口都会换一组 token id，slug 形如 btc-updown-15m-<窗口开始的 unix 秒>，
+// 这里按当前窗口拼出 slug 去 Gamma 查询，并把 YES(Up)/NO(Down) 注册成 exchange 的 market。
+
+// GammaMarket 是 Gamma GET /markets 返回的 market（只保留用到的字段）。
+type GammaMarket struct {
+	ID          string `json:"id"`
+	Question    string `json:"question"`
+	ConditionID string `json:"conditionId"`
+	Slug        string `json:"slug"`
+
+	Description      string `json:"description"`
+	ResolutionSource string `json:"resolutionSource"`
+
+	// Outcomes / ClobTokenIDs 是 JSON 编码后的字符串数组，例如 "[\"Up\", \"Down\"]"
+	Outcomes     string `json:"outcomes"`
+	ClobTokenIDs string `json:"clobTokenIds"`
+	// OutcomePrices 与 Outcomes 一一对应，市场结算后赢的 outcome 为 "1"
+	OutcomePrices string `json:"outcomePrices"`
+
+	Active  bool      `json:"active"`
+	Closed  bool      `json:"closed"`
+	EndDate time.Time `json:"endDate"`
+	NegRisk bool      `json:"negRisk"`
+
+	OrderPriceMinTickSize float64 `json:"orderPriceMinTickSize"`
+	OrderMinSize          float64 `json:"orderMinSize"`
+
+	// MakerBaseFee / TakerBaseFee 为该市场的费率（bps）
+	MakerBaseFee int `json:"makerBaseFee"`
+	TakerBaseFee int `json:"takerBaseFee"`
+
+	// 流动性奖励配置，见 rewards.go；RewardsMaxSpread 的单位为美分
+	RewardsMinSize   float64           `json:"rewardsMinSize"`
+	RewardsMaxSpread float64           `json:"rewardsMaxSpread"`
+	ClobRewards      []GammaClobReward `json:"clobRewards"`
+}
+
+// GammaClobReward 为 Gamma 返回的一个奖励计划。
+type GammaClobReward struct {
+	ID               string  `json:"id"`
+	ConditionID      string  `json:"conditionId"`
+	AssetAddress     string  `json:"assetAddress"`
+	RewardsAmount    float64 `json:"rewardsAmount"`
+	RewardsDailyRate float64 `json:"rewardsDailyRate"`
+	StartDate        string  `json:"startDate"`
+	EndDate          string  `json:"endDate"`
+}
+
+// OutcomeTokenIDs 返回 outcome → token id 的映射。
+func (m GammaMarket) OutcomeTokenIDs() (map[string]string, error) {
+	var outcomes, tokenIDs []string
+	if err := json.Unmarshal([]byte(m.Outcomes), &outcomes); err != nil {
+		return nil, fmt.Errorf("polymarket: decode outcomes of %s failed: %w", m.Slug, err)
+	}
+	if err := json.Unmarshal([]byte(m.ClobTokenIDs), &tokenIDs); err != nil {
+		return nil, fmt.Errorf("polymarket: decode clobTokenIds of %s failed: %w", m.Slug, err)
+	}
+	if len(outcomes) != len(tokenIDs) {
+		return nil, fmt.Errorf("polymarket: %s has %d outcomes but %d token ids", m.Slug, len(outcomes), len(tokenIDs))
+	}
+
+	out := make(map[string]string, len(outcomes))
+	for i, outcome := range outcomes {
+		out[outcome] = tokenIDs[i]
+	}
+	return out, nil
+}
+
+var errGammaMarketNotFound = errors.New("polymarket: gamma market not found")
+
+func (c *restClient) queryGammaMarketBySlug(ctx context.Context, slug string) (*GammaMarket, error) {
+	var markets []GammaMarket
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/markets", url.Values{"slug": {slug}}, nil, &markets); err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("%w: %s", errGammaMarketNotFound, slug)
+	}
+	return &markets[0], nil
+}
+
+func (c *restClient) queryGammaMarketByTokenID(ctx context.Context, tokenID string) (*GammaMarket, error) {
+	var markets []GammaMarket
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/markets", url.Values{"clob_token_ids": {tokenID}}, nil, &markets); err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("%w: token %s", errGammaMarketNotFound, tokenID)
+	}
+	return &markets[0], nil
+}
+
+// UpDownMarket 是某个窗口的 up/down 市场，YesSymbol/NoSymbol 已注册到 exchange 的 market 列表。
+type UpDownMarket struct {
+	Slug        string
+	ConditionID string
+
+	WindowStart time.Time
+	WindowEnd   time.Time
+
+	YesSymbol  string
+	NoSymbol   string
+	YesTokenID string
+	NoTokenID  string
+
+	YesMarket types.Market
+	NoMarket  types.Market
+
+	NegRisk bool
+	// Closed 为 Gamma 返回的 closed 标记（窗口结束、等待结算或已结算）
+	Closed bool
+}
+
+// UpDownWindow 返回 at 所在的 interval 周期窗口的开始与结束时间（按 Unix 时间对齐，与 up/down 市场的 slug 一致）。
+func UpDownWindow(interval types.Interval, at time.Time) (start, end time.Time, err error) {
+	d := interval.Duration()
+	if d <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("polymarket: invalid up/down window interval %s", interval)
+	}
+
+	start = at.Truncate(d)
+	return start, start.Add(d), nil
+}
+
+// upDownSlug 拼出 asset（例如 btc）在 at 所在窗口的 slug。
+func upDownSlug(asset string, interval types.Interval, at time.Time) (string, time.Time, error) {
+	if d := interval.Duration(); d <= 0 || d >= time.Hour {
+		return "", time.Time{}, fmt.Errorf("polymarket: up/down market discovery does not support interval %s", interval)
+	}
+
+	start, _, err := UpDownWindow(interval, at)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return fmt.Sprintf("%s-updown-%s-%d", strings.ToLower(asset), interval, start.Unix()), start, nil
+}
+
+// DiscoverUpDownMarket 查询 asset 在 at 所在窗口的 “Up or Down” 市场，返回 YES/NO 的 symbol 与 token id。
+// 结果按窗口缓存，窗口结束后的缓存会被清理。
+func (e *Exchange) DiscoverUpDownMarket(ctx context.Context, asset string, interval types.Interval, at time.Time) (*UpDownMarket, error) {
+	slug, start, err := upDownSlug(asset, interval, at)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	cached, ok := e.upDownMarkets[slug]
+	e.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	gm, err := e.gamma.queryGammaMarketBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := newUpDownMarket(slug, gm, start, start.Add(interval.Duration()))
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.addMarketLocked(m.YesMarket)
+	e.addMarketLocked(m.NoMarket)
+	e.negRisk[m.YesSymbol] = m.NegRisk
+	e.negRisk[m.NoSymbol] = m.NegRisk
+	e.fees.setMarketFeeBps(m.YesSymbol, gm.TakerBaseFee)
+	e.fees.setMarketFeeBps(m.NoSymbol, gm.TakerBaseFee)
+
+	now := time.Now()
+	for key, cached := range e.upDownMarkets {
+		if cached.WindowEnd.Before(now) {
+			delete(e.upDownMarkets, key)
+		}
+	}
+	e.upDownMarkets[slug] = m
+	e.setUpDownMarketStatusLocked(m)
+	e.rebuildSymbolIndexLocked()
+	e.saveMarketsCacheLocked()
+	e.trackRedemption(m)
+
+	log.Infof("discovered up/down market %s: yes=%s no=%s", slug, m.YesTokenID, m.NoTokenID)
+	return m, nil
+}
+
+// newUpDownMarket 用 Gamma 返回的 market 构造 slug 对应的 [start, end) 窗口的 UpDownMarket。
+func newUpDownMarket(slug string, gm *GammaMarket, start, end time.Time) (*UpDownMarket, error) {
+	tokenIDs, err := gm.OutcomeTokenIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	yesTokenID, noTokenID := tokenIDs["Up"], tokenIDs["Down"]
+	if yesTokenID == "" || noTokenID == "" {
+		return nil, fmt.Errorf("polymarket: %s does not have Up/Down outcomes", slug)
+	}
+
+	base := "PM_" + strings.ToUpper(strings.ReplaceAll(slug, "-", "_"))
+	m := &UpDownMarket{
+		Slug:        slug,
+		ConditionID: gm.ConditionID,
+		WindowStart: start,
+		WindowEnd:   end,
+		YesSymbol:   base + "_YES_USDC",
+		NoSymbol:    base + "_NO_USDC",
+		YesTokenID:  yesTokenID,
+		NoTokenID:   noTokenID,
+		NegRisk:     gm.NegRisk,
+		Closed:      gm.Closed,
+	}
+	m.YesMarket = newOutcomeMarket(m.YesSymbol, yesTokenID, gm)
+	m.NoMarket = newOutcomeMarket(m.NoSymbol, noTokenID, gm)
+	return m, nil
+}
+
+// newOutcomeMarket 用 Gamma 的 tick size / 最小下单量构造一个 outcome token 的 market。
+func newOutcomeMarket(symbol, tokenID string, gm *GammaMarket) types.Market {
+	tickSize := gm.OrderPriceMinTickSize
+	if tickSize <= 0 {
+		tickSize = 0.01
+	}
+	minSize := gm.OrderMinSize
+	if minSize <= 0 {
+		minSize = 5
+	}
+
+	return types.Market{
+		Exchange:        types.ExchangePolymarket,
+		Symbol:          symbol,
+		LocalSymbol:     tokenID,
+		BaseCurrency:    strings.TrimSuffix(symbol, "_USDC"),
+		QuoteCurrency:   collateralCurrency,
+		PricePrecision:  int(math.Round(-math.Log10(tickSize))),
+		VolumePrecision: 2,
+		QuotePrecision:  2,
+		TickSize:        fixedpoint.NewFromFloat(tickSize),
+		StepSize:        fixedpoint.NewFromFloat(0.01),
+		MinNotional:     fixedpoint.NewFromFloat(1),
+		MinQuantity:     fixedpoint.NewFromFloat(minSize),
+	}
+}
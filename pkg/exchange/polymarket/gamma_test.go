@@ -0,0 +1,80 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DiscoverUpDownMarket(t *testing.T) {
+	at := time.Date(2025, 10, 15, 8, 7, 30, 0, time.UTC)
+	windowStart := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+
+	calls := 0
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		calls++
+		assert.Equal(t, "btc-updown-15m-1760515200", req.URL.Query().Get("slug"))
+		return httptesting.BuildResponseString(http.StatusOK, `[{
+			"id": "1",
+			"conditionId": "0xabc",
+			"slug": "btc-updown-15m-1760515200",
+			"outcomes": "[\"Up\", \"Down\"]",
+			"clobTokenIds": "[\"111111111111\", \"222222222222\"]",
+			"active": true,
+			"orderPriceMinTickSize": 0.01,
+			"orderMinSize": 5
+		}]`), nil
+	})
+
+	ex := New("", "", "")
+	ex.gamma = newTestRestClient(transport)
+
+	m, err := ex.DiscoverUpDownMarket(context.Background(), "BTC", types.Interval15m, at)
+	assert.NoError(t, err)
+	assert.Equal(t, windowStart, m.WindowStart)
+	assert.Equal(t, "111111111111", m.YesTokenID)
+	assert.Equal(t, "222222222222", m.NoTokenID)
+	assert.Equal(t, "PM_BTC_UPDOWN_15M_1760515200_YES_USDC", m.YesSymbol)
+
+	market, ok := ex.markets[m.YesSymbol]
+	if assert.True(t, ok) {
+		assert.Equal(t, "111111111111", market.LocalSymbol)
+		assert.Equal(t, fixedpoint.NewFromFloat(0.01), market.TickSize)
+		assert.Equal(t, 2, market.PricePrecision)
+	}
+
+	// 同一窗口命中缓存
+	_, err = ex.DiscoverUpDownMarket(context.Background(), "BTC", types.Interval15m, at.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestUpDownWindow(t *testing.T) {
+	at := time.Date(2025, 10, 15, 8, 7, 30, 0, time.UTC)
+
+	start, end, err := UpDownWindow(types.Interval15m, at)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2025, 10, 15, 8, 15, 0, 0, time.UTC), end)
+	}
+
+	// 窗口边界属于新的窗口
+	start, _, err = UpDownWindow(types.Interval5m, time.Date(2025, 10, 15, 8, 10, 0, 0, time.UTC))
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2025, 10, 15, 8, 10, 0, 0, time.UTC), start)
+	}
+
+	start, end, err = UpDownWindow(types.Interval1h, at)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2025, 10, 15, 9, 0, 0, 0, time.UTC), end)
+	}
+}
@@ -0,0 +1,109 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 健康检查：HealthCheck 给运维工具定期调用，一次性确认适配层是否正常工作。
+// - 配置检查（同 ValidateConfig）；dry-run 只做这一项
+// - live 模式下 GET CLOB /ok，确认 REST API 可达
+// - 已经建立 websocket 的 stream 必须处于 connected 状态，且在 pong 超时之内收到过 PONG
+// - market 列表已加载；POLYMARKET_HEALTH_MARKETS_MAX_AGE 大于 0 时要求最近一次加载不早于该时长（默认不检查），
+//   通过 Gamma 发现过 up/down 市场时要求最新的窗口还没有结束（否则说明窗口切换卡住了）
+
+const envHealthMarketsMaxAge = "POLYMARKET_HEALTH_MARKETS_MAX_AGE"
+
+// HealthError 汇总所有健康检查失败项。
+type HealthError struct {
+	Problems []string
+}
+
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("polymarket: unhealthy (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// HealthCheck 检查配置、CLOB 连通性、websocket 连接与 market 列表的新鲜度，全部正常时返回 nil，否则返回 *HealthError。
+func (e *Exchange) HealthCheck(ctx context.Context) error {
+	var problems []string
+	if err := e.ValidateConfig(); err != nil {
+		if configErr, ok := err.(*ConfigError); ok {
+			problems = append(problems, configErr.Problems...)
+		} else {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if !e.IsDryRun() {
+		now := time.Now()
+		if err := e.client.ping(ctx); err != nil {
+			problems = append(problems, fmt.Sprintf("CLOB is unreachable: %v", err))
+		}
+
+		e.streamMu.Lock()
+		streams := append([]*Stream{}, e.streams...)
+		e.streamMu.Unlock()
+
+		for _, stream := range streams {
+			if err := stream.checkHealth(now); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+
+		problems = append(problems, e.checkMarketsFreshness(now, envDuration(envHealthMarketsMaxAge, 0))...)
+	}
+
+	if len(problems) > 0 {
+		return &HealthError{Problems: problems}
+	}
+	return nil
+}
+
+// ping 请求 CLOB 的 /ok 端点。
+func (c *restClient) ping(ctx context.Context) error {
+	return c.do(ctx, c.limits.market, http.MethodGet, "/ok", nil, nil, nil)
+}
+
+// checkHealth 检查已建立 websocket 的 stream；没有启用 websocket、还没有 Connect 或已经 Close 的 stream 不检查。
+func (s *Stream) checkHealth(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.connected || s.closed {
+		return nil
+	}
+	if s.state != StreamStateConnected {
+		return fmt.Errorf("%s channel websocket is %s", s.channel(), s.state)
+	}
+	if since := now.Sub(s.lastPong); since > s.pongTimeout {
+		return fmt.Errorf("%s channel websocket has not received a pong for %s", s.channel(), since.Round(time.Second))
+	}
+	return nil
+}
+
+// checkMarketsFreshness 检查 market 列表，maxAge 为 0 时不检查加载时间。
+func (e *Exchange) checkMarketsFreshness(now time.Time, maxAge time.Duration) (problems []string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(e.markets) == 0 {
+		return []string{"no markets loaded"}
+	}
+	if maxAge > 0 && now.Sub(e.marketsUpdatedAt) > maxAge {
+		problems = append(problems, fmt.Sprintf("markets were last loaded %s ago (max age %s)", now.Sub(e.marketsUpdatedAt).Round(time.Second), maxAge))
+	}
+
+	var latest *UpDownMarket
+	for _, um := range e.upDownMarkets {
+		if latest == nil || um.WindowEnd.After(latest.WindowEnd) {
+			latest = um
+		}
+	}
+	if latest != nil && !now.Before(latest.WindowEnd) {
+		problems = append(problems, fmt.Sprintf("latest up/down market %s ended at %s", latest.Slug, latest.WindowEnd.Format(time.RFC3339)))
+	}
+	return problems
+}
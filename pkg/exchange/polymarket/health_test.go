@@ -0,0 +1,118 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestExchange_HealthCheck(t *testing.T) {
+	t.Run("dry-run", func(t *testing.T) {
+		ex := New("", "", "")
+		defer ex.Close()
+		assert.NoError(t, ex.HealthCheck(context.Background()))
+	})
+
+	t.Run("dry-run invalid config", func(t *testing.T) {
+		t.Setenv(envGammaURL, "gamma-api.polymarket.com")
+
+		ex := New("", "", "")
+		defer ex.Close()
+		var healthErr *HealthError
+		if assert.True(t, errors.As(ex.HealthCheck(context.Background()), &healthErr)) && assert.Len(t, healthErr.Problems, 1) {
+			assert.Contains(t, healthErr.Problems[0], envGammaURL)
+		}
+	})
+
+	t.Run("live", func(t *testing.T) {
+		t.Setenv(envDryRun, "false")
+		t.Setenv(envWalletAddress, testAddress)
+		t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+
+		ex, err := NewWithPrivateKey("key", "c2VjcmV0", "pass", testPrivateKey)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ex.Close()
+
+		up := true
+		transport := &httptesting.MockTransport{}
+		transport.GET("/ok", func(req *http.Request) (*http.Response, error) {
+			if up {
+				return httptesting.BuildResponseString(http.StatusOK, `"OK"`), nil
+			}
+			return httptesting.BuildResponseString(http.StatusServiceUnavailable, `down`), nil
+		})
+		client := newTestRestClient(transport)
+		client.auth = ex.client.auth
+		ex.client = client
+
+		ctx := context.Background()
+		var healthErr *HealthError
+		if assert.True(t, errors.As(ex.HealthCheck(ctx), &healthErr)) {
+			assert.Equal(t, []string{"no markets loaded"}, healthErr.Problems)
+		}
+
+		_, err = ex.QueryMarkets(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, ex.HealthCheck(ctx))
+
+		up = false
+		if assert.True(t, errors.As(ex.HealthCheck(ctx), &healthErr)) && assert.Len(t, healthErr.Problems, 1) {
+			assert.Contains(t, healthErr.Problems[0], "CLOB is unreachable")
+		}
+	})
+}
+
+func TestStream_CheckHealth(t *testing.T) {
+	now := time.Now()
+	stream := NewStream("", "", "", false, nil)
+	// 还没有 Connect
+	assert.NoError(t, stream.checkHealth(now))
+
+	stream.connected = true
+	assert.ErrorContains(t, stream.checkHealth(now), "user channel websocket is disconnected")
+
+	stream.state = StreamStateConnected
+	stream.lastPong = now.Add(-time.Second)
+	assert.NoError(t, stream.checkHealth(now))
+
+	stream.lastPong = now.Add(-time.Minute)
+	assert.ErrorContains(t, stream.checkHealth(now), "has not received a pong")
+
+	stream.closed = true
+	assert.NoError(t, stream.checkHealth(now))
+}
+
+func TestExchange_CheckMarketsFreshness(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+	_, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.Empty(t, ex.checkMarketsFreshness(now, 0))
+	assert.Empty(t, ex.checkMarketsFreshness(now, time.Minute))
+	assert.Len(t, ex.checkMarketsFreshness(now.Add(2*time.Minute), time.Minute), 1)
+
+	start := now.Add(-20 * time.Minute)
+	ex.mu.Lock()
+	ex.upDownMarkets["btc-updown-15m-1"] = &UpDownMarket{Slug: "btc-updown-15m-1", WindowStart: start, WindowEnd: start.Add(15 * time.Minute)}
+	ex.mu.Unlock()
+
+	problems := ex.checkMarketsFreshness(now, 0)
+	if assert.Len(t, problems, 1) {
+		assert.Contains(t, problems[0], "btc-updown-15m-1 ended")
+	}
+
+	ex.mu.Lock()
+	ex.upDownMarkets["btc-updown-15m-2"] = &UpDownMarket{Slug: "btc-updown-15m-2", WindowStart: now, WindowEnd: now.Add(15 * time.Minute)}
+	ex.mu.Unlock()
+	assert.Empty(t, ex.checkMarketsFreshness(now, 0))
+}
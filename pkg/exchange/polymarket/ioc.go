@@ -0,0 +1,165 @@
+package polymarket
+
+import (
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// IOC / FOK 订单（SubmitOrder.TimeInForce）：
+// - 真实下单把 TimeInForce 映射成 CLOB 的 orderType：IOC -> FAK（fill-and-kill，能成交多少成交多少，剩余撤单），
+//   FOK -> FOK（全部成交否则整单撤销），其它为 GTC；返回的订单按响应的 makingAmount / takingAmount 带上立即成交的数量
+// - dry-run 在下单时立即用 market channel 缓存的盘口深度撮合：买单吃价格 <= 限价的卖单档位、卖单吃价格 >= 限价的买单档位，
+//   按档位价格成交（价格优先），剩余数量立即撤单；FOK 在深度不足以全部成交时整单撤销、不成交
+// - 全部成交的订单状态为 FILLED，部分成交或没有成交的订单状态为 CANCELED（ExecutedQuantity 为已成交数量）
+// - 没有盘口缓存（未开启 POLYMARKET_WS_MARKET 或盘口超过 POLYMARKET_TICKER_MAX_AGE 没有更新）时视为没有深度，整单撤销
+// - 模拟成交不会消耗缓存的盘口，下一次 IOC 订单仍然看到同样的深度
+
+// clobOrderType 返回 TimeInForce 对应的 CLOB orderType。
+func clobOrderType(tif types.TimeInForce) string {
+	switch tif {
+	case types.TimeInForceIOC:
+		return "FAK"
+	case types.TimeInForceFOK:
+		return "FOK"
+	default:
+		return "GTC"
+	}
+}
+
+// isImmediateOrCancel 判断订单是否需要在下单时立即撮合、剩余部分撤单。
+func isImmediateOrCancel(order types.SubmitOrder) bool {
+	return order.TimeInForce == types.TimeInForceIOC || order.TimeInForce == types.TimeInForceFOK
+}
+
+// setLevel 把 price 档位的挂单量更新为 size，size 为 0 时删除该档位。
+func setLevel(levels []PriceLevel, price, size fixedpoint.Value) []PriceLevel {
+	for i, lv := range levels {
+		if lv.Price.Compare(price) != 0 {
+			continue
+		}
+		if size.Sign() <= 0 {
+			return append(levels[:i], levels[i+1:]...)
+		}
+		levels[i].Size = size
+		return levels
+	}
+
+	if size.Sign() <= 0 {
+		return levels
+	}
+	return append(levels, PriceLevel{Price: price, Size: size})
+}
+
+// updatePriceChange 用 price_change 消息更新对应档位的挂单量与最优买卖价。
+func (c *tickerCache) updatePriceChange(change PriceChange, at time.Time) {
+	t := c.entry(change.AssetID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if change.Price.Sign() > 0 {
+		switch change.Side {
+		case "BUY":
+			t.bids = setLevel(t.bids, change.Price, change.Size)
+		case "SELL":
+			t.asks = setLevel(t.asks, change.Price, change.Size)
+		}
+	}
+	t.buy, t.sell = change.BestBid, change.BestAsk
+	t.updatedAt, t.bookUpdatedAt = at, at
+}
+
+// depth 返回 side 方向的订单在 limit 价格以内可以吃到的对手方档位，按价格从优到劣排序；
+// 盘口超过 maxAge 没有更新时 ok 为 false。
+func (c *tickerCache) depth(assetID string, side types.SideType, limit fixedpoint.Value, now time.Time) (levels []PriceLevel, ok bool) {
+	t, found := c.lookup(assetID)
+	if !found {
+		return nil, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.bookUpdatedAt) > c.maxAge {
+		return nil, false
+	}
+
+	return crossingLevels(side, limit, t.bids, t.asks), true
+}
+
+// crossingLevels 返回 side 方向、限价 limit 的订单可以吃到的对手方档位，按价格从优到劣排序。
+func crossingLevels(side types.SideType, limit fixedpoint.Value, bids, asks []PriceLevel) (levels []PriceLevel) {
+	switch side {
+	case types.SideTypeBuy:
+		for _, lv := range asks {
+			if lv.Size.Sign() > 0 && lv.Price.Compare(limit) <= 0 {
+				levels = append(levels, lv)
+			}
+		}
+		sort.Slice(levels, func(i, j int) bool { return levels[i].Price.Compare(levels[j].Price) < 0 })
+	case types.SideTypeSell:
+		for _, lv := range bids {
+			if lv.Size.Sign() > 0 && lv.Price.Compare(limit) >= 0 {
+				levels = append(levels, lv)
+			}
+		}
+		sort.Slice(levels, func(i, j int) bool { return levels[i].Price.Compare(levels[j].Price) > 0 })
+	}
+	return levels
+}
+
+// fillImmediateLocked 用缓存的盘口深度立即撮合 IOC/FOK 订单并撤销剩余部分，返回订单状态变化的快照
+// （成交后的状态与最终状态，没有成交时只有最终状态），需要持有 e.mu 的读锁与订单所在的 sh.mu。
+func (e *Exchange) fillImmediateLocked(o *types.Order, now time.Time) (updates []types.Order) {
+	var levels []PriceLevel
+	if token, ok := e.tokenOfLocked(o.Symbol); ok {
+		levels, _ = e.tickers.depth(token.TokenID, o.Side, o.Price, now)
+	}
+
+	if o.TimeInForce == types.TimeInForceFOK {
+		available := fixedpoint.Zero
+		for _, lv := range levels {
+			available = available.Add(lv.Size)
+		}
+		if available.Compare(o.Quantity) < 0 {
+			levels = nil
+		}
+	}
+
+	at := types.Time(now)
+	for _, lv := range levels {
+		remaining := o.Quantity.Sub(o.ExecutedQuantity)
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		quantity := fixedpoint.Min(lv.Size, remaining)
+		e.settleFillLocked(o, quantity, lv.Price)
+		// 立即成交的订单是吃单方
+		e.recordFillLocked(o, quantity, lv.Price, at, false)
+		applyFill(o, quantity, lv.Price)
+	}
+	o.UpdateTime = at
+
+	if o.ExecutedQuantity.Compare(o.Quantity) >= 0 {
+		o.Status = types.OrderStatusFilled
+		o.OriginalStatus = "FILLED"
+		o.IsWorking = false
+		return append(updates, *o)
+	}
+
+	if o.ExecutedQuantity.Sign() > 0 {
+		o.Status = types.OrderStatusPartiallyFilled
+		o.OriginalStatus = "PARTIALLY_FILLED"
+		updates = append(updates, *o)
+	}
+
+	e.unlockBalanceLocked(o)
+	o.IsWorking = false
+	o.Status = types.OrderStatusCanceled
+	o.OriginalStatus = "CANCELED"
+	e.recordOrderCancel(o.Symbol, 1)
+	return append(updates, *o)
+}
@@ -0,0 +1,248 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DryRunImmediateOrCancel(t *testing.T) {
+	const tokenID = "111111111111"
+	f := fixedpoint.NewFromFloat
+	t.Setenv(envBalanceUSDC, "100")
+
+	newExchange := func(t *testing.T) *Exchange {
+		ex := New("", "", "")
+		t.Cleanup(func() { ex.Close() })
+		ex.markets = types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC", StepSize: f(0.01), TickSize: f(0.01)},
+		}
+		ex.tickers.updateBook(BookEvent{
+			AssetID: tokenID,
+			Bids:    []PriceLevel{{Price: f(0.45), Size: f(5)}},
+			Asks:    []PriceLevel{{Price: f(0.52), Size: f(10)}, {Price: f(0.48), Size: f(4)}, {Price: f(0.5), Size: f(3)}},
+		}, time.Now())
+		return ex
+	}
+
+	newOrder := func(tif types.TimeInForce, side types.SideType, price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:      "PM_YES",
+			Side:        side,
+			Type:        types.OrderTypeLimit,
+			Price:       f(price),
+			Quantity:    f(quantity),
+			TimeInForce: tif,
+		}
+	}
+
+	ctx := context.Background()
+
+	t.Run("full fill", func(t *testing.T) {
+		ex := newExchange(t)
+		order, err := ex.SubmitOrder(ctx, newOrder(types.TimeInForceIOC, types.SideTypeBuy, 0.5, 6))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, types.OrderStatusFilled, order.Status)
+		assert.False(t, order.IsWorking)
+		assert.Equal(t, "6", order.ExecutedQuantity.String())
+
+		trades, err := ex.QueryOrderTrades(ctx, types.OrderQuery{Symbol: "PM_YES", OrderID: strconv.FormatUint(order.OrderID, 10)})
+		if assert.NoError(t, err) && assert.Len(t, trades, 2) {
+			// 价格优先：先吃 0.48 的档位
+			assert.Equal(t, "0.48", trades[0].Price.String())
+			assert.Equal(t, "4", trades[0].Quantity.String())
+			assert.Equal(t, "0.5", trades[1].Price.String())
+			assert.Equal(t, "2", trades[1].Quantity.String())
+			assert.False(t, trades[0].IsMaker)
+		}
+
+		open, err := ex.QueryOpenOrders(ctx, "PM_YES")
+		assert.NoError(t, err)
+		assert.Empty(t, open)
+	})
+
+	t.Run("partial fill", func(t *testing.T) {
+		ex := newExchange(t)
+		stream := ex.NewStream()
+		var updates []types.Order
+		stream.OnOrderUpdate(func(o types.Order) { updates = append(updates, o) })
+
+		order, err := ex.SubmitOrder(ctx, newOrder(types.TimeInForceIOC, types.SideTypeBuy, 0.5, 10))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, types.OrderStatusCanceled, order.Status)
+		assert.False(t, order.IsWorking)
+		assert.Equal(t, "7", order.ExecutedQuantity.String())
+		assert.InDelta(t, 0.4886, order.AveragePrice.Float64(), 0.0001)
+
+		if assert.Len(t, updates, 3) {
+			assert.Equal(t, types.OrderStatusNew, updates[0].Status)
+			assert.Equal(t, types.OrderStatusPartiallyFilled, updates[1].Status)
+			assert.Equal(t, types.OrderStatusCanceled, updates[2].Status)
+		}
+
+		// 未成交部分解冻，只扣除成交金额
+		balances, err := ex.QueryAccountBalances(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "96.58", balances["USDC"].Available.String())
+			assert.Equal(t, "0", balances["USDC"].Locked.String())
+		}
+	})
+
+	t.Run("no fill", func(t *testing.T) {
+		ex := newExchange(t)
+		ex.balance.positions["PM_YES"] = f(5)
+		order, err := ex.SubmitOrder(ctx, newOrder(types.TimeInForceIOC, types.SideTypeSell, 0.46, 5))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, types.OrderStatusCanceled, order.Status)
+		assert.True(t, order.ExecutedQuantity.IsZero())
+
+		// 没有盘口缓存时整单撤销
+		ex.markets["PM_NO"] = types.Market{Symbol: "PM_NO", LocalSymbol: "222222222222", QuoteCurrency: "USDC"}
+		order, err = ex.SubmitOrder(ctx, types.SubmitOrder{Symbol: "PM_NO", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+			Price: f(0.5), Quantity: f(5), TimeInForce: types.TimeInForceIOC})
+		if assert.NoError(t, err) {
+			assert.Equal(t, types.OrderStatusCanceled, order.Status)
+		}
+
+		balances, err := ex.QueryAccountBalances(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "100", balances["USDC"].Available.String())
+		}
+	})
+
+	t.Run("fill or kill", func(t *testing.T) {
+		ex := newExchange(t)
+		order, err := ex.SubmitOrder(ctx, newOrder(types.TimeInForceFOK, types.SideTypeBuy, 0.5, 10))
+		if assert.NoError(t, err) {
+			assert.Equal(t, types.OrderStatusCanceled, order.Status)
+			assert.True(t, order.ExecutedQuantity.IsZero())
+		}
+
+		ex.balance.positions["PM_YES"] = f(5)
+		order, err = ex.SubmitOrder(ctx, newOrder(types.TimeInForceFOK, types.SideTypeSell, 0.45, 5))
+		if assert.NoError(t, err) {
+			assert.Equal(t, types.OrderStatusFilled, order.Status)
+			assert.Equal(t, "5", order.ExecutedQuantity.String())
+		}
+	})
+
+	t.Run("GTC order rests", func(t *testing.T) {
+		ex := newExchange(t)
+		order, err := ex.SubmitOrder(ctx, newOrder(types.TimeInForceGTC, types.SideTypeBuy, 0.5, 10))
+		if assert.NoError(t, err) {
+			assert.Equal(t, types.OrderStatusNew, order.Status)
+			assert.True(t, order.IsWorking)
+		}
+	})
+}
+
+func TestTickerCache_PriceChangeDepth(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	cache := &tickerCache{maxAge: time.Second, tickers: make(map[string]*cachedTicker)}
+	now := time.Now()
+
+	cache.updateBook(BookEvent{
+		AssetID: "1",
+		Bids:    []PriceLevel{{Price: f(0.45), Size: f(5)}},
+		Asks:    []PriceLevel{{Price: f(0.5), Size: f(3)}, {Price: f(0.52), Size: f(10)}},
+	}, now)
+
+	cache.updatePriceChange(PriceChange{AssetID: "1", Price: f(0.5), Size: f(0), Side: "SELL", BestBid: f(0.45), BestAsk: f(0.52)}, now)
+	cache.updatePriceChange(PriceChange{AssetID: "1", Price: f(0.51), Size: f(2), Side: "SELL", BestBid: f(0.45), BestAsk: f(0.51)}, now)
+
+	levels, ok := cache.depth("1", types.SideTypeBuy, f(0.52), now)
+	if assert.True(t, ok) && assert.Len(t, levels, 2) {
+		assert.Equal(t, "0.51", levels[0].Price.String())
+		assert.Equal(t, "0.52", levels[1].Price.String())
+	}
+
+	_, ask, ok := cache.bestBidAsk("1", now)
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.51", ask.String())
+	}
+
+	_, ok = cache.depth("1", types.SideTypeBuy, f(0.52), now.Add(2*time.Second))
+	assert.False(t, ok)
+}
+
+func TestExchange_LiveOrderType(t *testing.T) {
+	assert.Equal(t, "FAK", clobOrderType(types.TimeInForceIOC))
+	assert.Equal(t, "FOK", clobOrderType(types.TimeInForceFOK))
+	assert.Equal(t, "GTC", clobOrderType(types.TimeInForceGTC))
+	assert.Equal(t, "GTC", clobOrderType(""))
+
+	transport := &httptesting.MockTransport{}
+	transport.POST("/order", func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		var payload placeOrderRequest
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "FAK", payload.OrderType)
+		return httptesting.BuildResponseString(http.StatusOK, `{"success":true,"orderID":"0xplaced","status":"matched"}`), nil
+	})
+
+	c := newTestRestClient(transport)
+	c.auth = newAPICredentials("key", "c2VjcmV0", "pass")
+	_, err := c.PlaceOrder(context.Background(), &CLOBOrder{TokenID: "111111111111", Signature: "0xsig", OrderType: "FAK"})
+	assert.NoError(t, err)
+
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(false),
+		WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", TickSize: fixedpoint.NewFromFloat(0.01), StepSize: fixedpoint.NewFromFloat(0.01)},
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	order, err := ex.buildOrder(types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+		Price: fixedpoint.NewFromFloat(0.5), Quantity: fixedpoint.NewFromFloat(10), TimeInForce: types.TimeInForceIOC})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "FAK", order.OrderType)
+	}
+}
+
+func TestToGlobalPlacedOrder_ImmediateOrCancel(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	submit := types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+		Price: f(0.5), Quantity: f(10), TimeInForce: types.TimeInForceIOC}
+	now := time.Now()
+
+	// 部分成交，剩余撤单
+	order := toGlobalPlacedOrder(submit, &PlaceOrderResponse{Success: true, OrderID: "0x1", Status: "matched",
+		MakingAmount: f(2.94), TakingAmount: f(6)}, now)
+	assert.Equal(t, types.OrderStatusCanceled, order.Status)
+	assert.False(t, order.IsWorking)
+	assert.Equal(t, "6", order.ExecutedQuantity.String())
+	assert.Equal(t, "0.49", order.AveragePrice.String())
+
+	// 全部成交
+	order = toGlobalPlacedOrder(submit, &PlaceOrderResponse{Success: true, OrderID: "0x2", Status: "matched",
+		MakingAmount: f(5), TakingAmount: f(10)}, now)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+
+	// 没有成交
+	order = toGlobalPlacedOrder(submit, &PlaceOrderResponse{Success: true, OrderID: "0x3", Status: "unmatched"}, now)
+	assert.Equal(t, types.OrderStatusCanceled, order.Status)
+	assert.True(t, order.ExecutedQuantity.IsZero())
+
+	// 撮合延迟时结果未知
+	order = toGlobalPlacedOrder(submit, &PlaceOrderResponse{Success: true, OrderID: "0x4", Status: "delayed"}, now)
+	assert.Equal(t, types.OrderStatusNew, order.Status)
+	assert.True(t, order.IsWorking)
+}
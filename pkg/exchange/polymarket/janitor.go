@@ -0,0 +1,164 @@
+package polymarket
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 订单清理：已成交/已撤单的订单会一直留在各 symbol 的订单分片里，长时间运行（soak test）时不断增长。
+// - POLYMARKET_ORDER_RETENTION 为已结束订单的保留时长（默认 24h），超过后从分片中删除；0 表示不清理
+// - POLYMARKET_ORDER_CLEANUP_INTERVAL 为清理周期（默认 1m）
+// - 清理循环在第一次 dry-run 下单时启动，Exchange.Close 时停止
+// 被清理订单的状态计数（总计与按 tag）会保留在 DryRunSummary 中，模拟成交记录（e.trades）不受影响。
+
+const (
+	envOrderRetention       = "POLYMARKET_ORDER_RETENTION"
+	envOrderCleanupInterval = "POLYMARKET_ORDER_CLEANUP_INTERVAL"
+
+	defaultOrderRetention       = 24 * time.Hour
+	defaultOrderCleanupInterval = time.Minute
+)
+
+type orderJanitor struct {
+	retention time.Duration
+	interval  time.Duration
+
+	started atomic.Bool
+
+	// prunedByTag 为已清理订单按 tag、状态的计数，由 e.balanceMu 保护
+	prunedByTag map[string]*prunedCounts
+}
+
+// prunedCounts 为已清理订单按状态的计数
+type prunedCounts struct {
+	filled, canceled, others int
+}
+
+func (c prunedCounts) total() int {
+	return c.filled + c.canceled + c.others
+}
+
+func newOrderJanitorFromEnv() *orderJanitor {
+	interval := envDuration(envOrderCleanupInterval, defaultOrderCleanupInterval)
+	if interval <= 0 {
+		interval = defaultOrderCleanupInterval
+	}
+
+	return &orderJanitor{
+		retention: envDuration(envOrderRetention, defaultOrderRetention),
+		interval:  interval,
+	}
+}
+
+// prunedCountsLocked 复制已清理订单按 tag 的计数，需要持有 e.balanceMu。
+func (j *orderJanitor) prunedCountsLocked() map[string]prunedCounts {
+	counts := make(map[string]prunedCounts, len(j.prunedByTag))
+	for tag, c := range j.prunedByTag {
+		counts[tag] = *c
+	}
+	return counts
+}
+
+// sumPruned 返回 tag 下已清理订单的计数，tag 为 nil 时返回所有 tag 的合计。
+func sumPruned(byTag map[string]prunedCounts, tag *string) (counts prunedCounts) {
+	for t, c := range byTag {
+		if tag != nil && *tag != t {
+			continue
+		}
+		counts.filled += c.filled
+		counts.canceled += c.canceled
+		counts.others += c.others
+	}
+	return counts
+}
+
+// startJanitor 在第一次 dry-run 下单时启动清理循环。
+func (e *Exchange) startJanitor() {
+	if e.janitor.retention <= 0 || !e.janitor.started.CompareAndSwap(false, true) {
+		return
+	}
+
+	go e.runJanitor(e.background)
+}
+
+func (e *Exchange) runJanitor(ctx context.Context) {
+	ticker := time.NewTicker(e.janitor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if n := e.pruneOrders(now); n > 0 {
+				log.Debugf("pruned %d terminal dry-run orders", n)
+			}
+		}
+	}
+}
+
+// pruneOrders 逐个 shard 删除最后更新时间早于 now - retention 的已结束订单，返回删除的数量。
+func (e *Exchange) pruneOrders(now time.Time) int {
+	if e.janitor.retention <= 0 {
+		return 0
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	deadline := now.Add(-e.janitor.retention)
+	pruned := 0
+	for _, sh := range e.shardList() {
+		pruned += e.pruneShardLocked(sh, deadline)
+	}
+
+	if pruned > 0 {
+		e.saveOrdersLocked()
+	}
+	return pruned
+}
+
+// pruneShardLocked 删除 shard 中最后更新时间早于 deadline 的已结束订单，需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) pruneShardLocked(sh *orderShard, deadline time.Time) int {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	pruned := 0
+	for _, o := range sh.orders {
+		if o.IsWorking || !o.UpdateTime.Time().Before(deadline) {
+			continue
+		}
+
+		e.countPruned(o)
+		e.unindexOrderLocked(sh, o)
+		pruned++
+	}
+	return pruned
+}
+
+// countPruned 把被清理的订单计入 tag 与状态的计数。
+func (e *Exchange) countPruned(o *types.Order) {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	if e.janitor.prunedByTag == nil {
+		e.janitor.prunedByTag = make(map[string]*prunedCounts)
+	}
+	counts, ok := e.janitor.prunedByTag[o.Tag]
+	if !ok {
+		counts = &prunedCounts{}
+		e.janitor.prunedByTag[o.Tag] = counts
+	}
+
+	switch o.Status {
+	case types.OrderStatusFilled:
+		counts.filled++
+	case types.OrderStatusCanceled:
+		counts.canceled++
+	default:
+		counts.others++
+	}
+}
@@ -0,0 +1,67 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_PruneOrders(t *testing.T) {
+	t.Setenv(envOrderRetention, "1h")
+
+	ctx := context.Background()
+	ex := New("", "", "")
+	defer ex.Close()
+
+	var orders []*types.Order
+	for i := 0; i < 3; i++ {
+		o, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   "PM_BTC_15M_UP_YES_USDC",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		})
+		assert.NoError(t, err)
+		orders = append(orders, o)
+	}
+	assert.NoError(t, ex.CancelOrders(ctx, *orders[0], *orders[1]))
+
+	assert.True(t, ex.janitor.started.Load())
+	// 还在保留期内，不清理
+	assert.Equal(t, 0, ex.pruneOrders(time.Now()))
+	// 超过保留期只清理已结束的订单
+	assert.Equal(t, 2, ex.pruneOrders(time.Now().Add(2*time.Hour)))
+	assert.Len(t, ex.allOrders(nil), 1)
+
+	// 被清理的订单不能再按 id 或 client order id 找到
+	_, ok := ex.findOrder(orders[0].OrderID, "")
+	assert.False(t, ok)
+
+	summary := ex.DryRunSummary()
+	assert.Equal(t, 3, summary.TotalOrders)
+	assert.Equal(t, 1, summary.OpenOrders)
+	assert.Equal(t, 2, summary.CanceledOrders)
+}
+
+func TestExchange_PruneOrdersDisabled(t *testing.T) {
+	t.Setenv(envOrderRetention, "0")
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	sh := ex.shard("PM_YES")
+	sh.mu.Lock()
+	ex.indexOrderLocked(sh, &types.Order{OrderID: 1, Status: types.OrderStatusFilled})
+	sh.mu.Unlock()
+
+	ex.startJanitor()
+	assert.False(t, ex.janitor.started.Load())
+	assert.Equal(t, 0, ex.pruneOrders(time.Now().Add(24*365*time.Hour)))
+	assert.Len(t, ex.allOrders(nil), 1)
+}
@@ -0,0 +1,101 @@
+package polymarket
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 日志：
+// - POLYMARKET_LOG_LEVEL（trace/debug/info/warn/error）单独设置这个 adapter 的日志级别，
+//   未设置时使用 bbgo 的全局 logger；输出、格式与 hooks 始终跟随全局 logger
+// - 私钥、API secret / passphrase、订单签名不会出现在日志里：相关结构的 String/GoString 都会打码
+
+const envLogLevel = "POLYMARKET_LOG_LEVEL"
+
+var log = newLogger(os.Getenv(envLogLevel))
+
+// newLogger 返回带 exchange 字段的 logger，level 无效或为空时直接使用全局 logger。
+func newLogger(level string) *logrus.Entry {
+	std := logrus.StandardLogger()
+
+	lvl, err := logrus.ParseLevel(strings.TrimSpace(level))
+	if level == "" || err != nil {
+		return std.WithField("exchange", types.ExchangePolymarket)
+	}
+
+	logger := &logrus.Logger{
+		Out:       stdWriter{},
+		Formatter: stdFormatter{},
+		Hooks:     logrus.LevelHooks{},
+		Level:     lvl,
+		ExitFunc:  os.Exit,
+	}
+	logger.AddHook(stdHooks{})
+	return logger.WithField("exchange", types.ExchangePolymarket)
+}
+
+// stdWriter / stdFormatter / stdHooks 在写日志时才读取全局 logger 的设置，
+// 这样 bbgo 启动后再调整的输出与格式也会生效。
+type stdWriter struct{}
+
+func (stdWriter) Write(p []byte) (int, error) { return logrus.StandardLogger().Out.Write(p) }
+
+type stdFormatter struct{}
+
+func (stdFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	return logrus.StandardLogger().Formatter.Format(e)
+}
+
+type stdHooks struct{}
+
+func (stdHooks) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (stdHooks) Fire(e *logrus.Entry) error { return logrus.StandardLogger().Hooks.Fire(e.Level, e) }
+
+// redact 只保留前 4 个字符，用于在日志里区分不同的 key。
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 8 {
+		return "****"
+	}
+	return s[:4] + "****"
+}
+
+func (e *Exchange) String() string {
+	return fmt.Sprintf("polymarket.Exchange{key: %s, dryRun: %t, signer: %v}", redact(e.key), e.IsDryRun(), e.signer)
+}
+
+func (e *Exchange) GoString() string { return e.String() }
+
+func (c *apiCredentials) String() string {
+	return fmt.Sprintf("apiCredentials{address: %s, key: %s}", c.address, redact(c.key))
+}
+
+func (c *apiCredentials) GoString() string { return c.String() }
+
+func (a WsAuth) String() string {
+	return fmt.Sprintf("WsAuth{APIKey: %s}", redact(a.APIKey))
+}
+
+func (a WsAuth) GoString() string { return a.String() }
+
+func (s *Stream) String() string {
+	return fmt.Sprintf("polymarket.Stream{key: %s, publicOnly: %t}", redact(s.key), s.PublicOnly)
+}
+
+func (s *Stream) GoString() string { return s.String() }
+
+// String 不输出订单签名
+func (o CLOBOrder) String() string {
+	return fmt.Sprintf("CLOBOrder{Side: %s, TokenID: %s, MakerAmount: %s, TakerAmount: %s, FeeRateBps: %s, Salt: %d, NegRisk: %t, PostOnly: %t}",
+		o.Side, o.TokenID, o.MakerAmount, o.TakerAmount, o.FeeRateBps, o.Salt, o.NegRisk, o.PostOnly)
+}
+
+func (o CLOBOrder) GoString() string { return o.String() }
@@ -0,0 +1,64 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchange_DumpRedactsSecrets(t *testing.T) {
+	secret := base64.URLEncoding.EncodeToString([]byte("super-secret-value"))
+	passphrase := "my-passphrase"
+
+	ex, err := NewWithPrivateKey("api-key-123456", secret, passphrase, testPrivateKey)
+	assert.NoError(t, err)
+	defer ex.Close()
+
+	stream := ex.NewStream().(*Stream)
+	dumps := []interface{}{ex, ex.client.auth, stream, WsAuth{APIKey: "api-key-123456", Secret: secret, Passphrase: passphrase},
+		CLOBOrder{TokenID: "111111111111", Signature: "0xsignature"}}
+	for _, v := range dumps {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			out := fmt.Sprintf(format, v)
+			assert.NotContains(t, out, secret)
+			assert.NotContains(t, out, passphrase)
+			assert.NotContains(t, out, testPrivateKey)
+			assert.NotContains(t, out, "0xsignature")
+		}
+	}
+	assert.Contains(t, fmt.Sprintf("%v", ex), "api-****")
+}
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	std := logrus.StandardLogger()
+	out, level := std.Out, std.GetLevel()
+	std.SetOutput(&buf)
+	std.SetLevel(logrus.InfoLevel)
+	defer func() {
+		std.SetOutput(out)
+		std.SetLevel(level)
+	}()
+
+	// 单独调低 adapter 的级别，输出仍写到全局 logger
+	logger := newLogger("debug")
+	logger.Debug("adapter debug message")
+	assert.Contains(t, buf.String(), "adapter debug message")
+	assert.Contains(t, buf.String(), "exchange=polymarket")
+
+	buf.Reset()
+	logger = newLogger("warn")
+	logger.Info("adapter info message")
+	assert.Empty(t, buf.String())
+
+	// 未设置或无效时跟随全局 logger
+	logger = newLogger("")
+	logger.Debug("hidden")
+	logger.Info("visible")
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "visible")
+}
@@ -0,0 +1,91 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PolymarketMeta 是 outcome token 所属预测市场的元数据（来自 Gamma）。
+type PolymarketMeta struct {
+	Question    string
+	Description string
+	Slug        string
+	ConditionID string
+
+	TokenID string
+	// Outcome 为该 symbol 对应的 outcome，例如 "Up" / "Down" / "Yes" / "No"
+	Outcome string
+
+	EndDate          time.Time
+	ResolutionSource string
+
+	NegRisk bool
+	Active  bool
+	Closed  bool
+
+	// Rewards 为流动性奖励配置，市场没有奖励时为 nil，见 rewards.go
+	Rewards *MarketRewards
+}
+
+// QueryMarket 返回单个 symbol（也可以是 slug、condition id 等，见 ResolveSymbol）的 market，以及按 token id 从 Gamma 查询的预测市场元数据。
+// market 没有 CLOB token id（例如示例 market）时无法查询元数据，meta 为 nil。
+func (e *Exchange) QueryMarket(ctx context.Context, symbol string) (*types.Market, *PolymarketMeta, error) {
+	if _, err := e.QueryMarkets(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	// 支持用 slug / condition id / token id 引用 market，见 symbols.go
+	symbol, err := e.ResolveSymbol(symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e.mu.RLock()
+	market, ok := e.markets[symbol]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("polymarket: market %s not found", symbol)
+	}
+
+	token, ok := e.tokenOf(symbol)
+	if !ok {
+		return &market, nil, nil
+	}
+
+	gm, err := e.gamma.queryGammaMarketByTokenID(ctx, token.TokenID)
+	if err != nil {
+		return &market, nil, err
+	}
+
+	e.mu.Lock()
+	e.setMarketStatusLocked(symbol, marketStatus{Closed: gm.Closed, EndTime: gm.EndDate})
+	e.mu.Unlock()
+
+	meta := &PolymarketMeta{
+		Question:         gm.Question,
+		Description:      gm.Description,
+		Slug:             gm.Slug,
+		ConditionID:      gm.ConditionID,
+		TokenID:          token.TokenID,
+		EndDate:          gm.EndDate,
+		ResolutionSource: gm.ResolutionSource,
+		NegRisk:          gm.NegRisk || token.NegRisk,
+		Active:           gm.Active,
+		Closed:           gm.Closed,
+		Rewards:          toMarketRewards(gm),
+	}
+
+	tokenIDs, err := gm.OutcomeTokenIDs()
+	if err != nil {
+		return &market, nil, err
+	}
+	for outcome, id := range tokenIDs {
+		if id == token.TokenID {
+			meta.Outcome = outcome
+		}
+	}
+	return &market, meta, nil
+}
@@ -0,0 +1,59 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestExchange_QueryMarket(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[
+		{"symbol": "PM_NO", "localSymbol": "222222222222", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+		{"symbol": "PM_EXAMPLE", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}
+	]`)
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "222222222222", req.URL.Query().Get("clob_token_ids"))
+		return httptesting.BuildResponseString(http.StatusOK, `[{
+			"question": "Bitcoin Up or Down?",
+			"conditionId": "0xabc",
+			"slug": "btc-updown-15m-1760515200",
+			"resolutionSource": "https://data.chain.link/streams/btc-usd",
+			"outcomes": "[\"Up\", \"Down\"]",
+			"clobTokenIds": "[\"111111111111\", \"222222222222\"]",
+			"endDate": "2025-10-15T08:15:00Z",
+			"active": true,
+			"negRisk": false
+		}]`), nil
+	})
+
+	ex := New("", "", "")
+	ex.gamma = newTestRestClient(transport)
+	ctx := context.Background()
+
+	market, meta, err := ex.QueryMarket(ctx, "PM_NO")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_NO", market.Symbol)
+	if assert.NotNil(t, meta) {
+		assert.Equal(t, "Bitcoin Up or Down?", meta.Question)
+		assert.Equal(t, "Down", meta.Outcome)
+		assert.Equal(t, "0xabc", meta.ConditionID)
+		assert.Equal(t, "https://data.chain.link/streams/btc-usd", meta.ResolutionSource)
+		assert.Equal(t, int64(1760516100), meta.EndDate.Unix())
+		assert.True(t, meta.Active)
+	}
+
+	// 没有 token id 的 market 不查询 Gamma
+	market, meta, err = ex.QueryMarket(ctx, "PM_EXAMPLE")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_EXAMPLE", market.Symbol)
+	assert.Nil(t, meta)
+
+	_, _, err = ex.QueryMarket(ctx, "PM_UNKNOWN")
+	assert.Error(t, err)
+}
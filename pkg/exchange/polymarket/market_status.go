@@ -0,0 +1,60 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 已关闭 / 已结算的 market 不能再下单，下单前按缓存的 Gamma 状态快速失败：
+// - 状态来自 DiscoverUpDownMarket / RefreshMarkets 发现的 up/down 窗口，以及 QueryMarket 查询过的 market
+// - Gamma 标记 closed，或者已经过了结束时间（up/down 为窗口结束时间，其它为 endDate）的 market 视为关闭
+// - 没有状态的 market（例如只在 markets 文件里配置、没有查询过）不检查
+// dry-run 同样拒单，方便在测试中发现对已过期窗口下单的逻辑。
+
+// ErrMarketClosed 为对已关闭或已结算的 market 下单时返回的错误（通过 errors.Is 判断）。
+var ErrMarketClosed = errors.New("polymarket: market is closed")
+
+type marketStatus struct {
+	Closed bool
+	// EndTime 为交易结束时间，零值表示未知
+	EndTime time.Time
+}
+
+func (s marketStatus) closedAt(now time.Time) bool {
+	return s.Closed || (!s.EndTime.IsZero() && !now.Before(s.EndTime))
+}
+
+// setMarketStatusLocked 记录 symbol 的 market 状态，需要持有 e.mu。
+func (e *Exchange) setMarketStatusLocked(symbol string, status marketStatus) {
+	if e.marketStatus == nil {
+		e.marketStatus = make(map[string]marketStatus)
+	}
+	e.marketStatus[symbol] = status
+}
+
+// setUpDownMarketStatusLocked 记录 up/down 窗口两个 outcome 的状态，需要持有 e.mu。
+func (e *Exchange) setUpDownMarketStatusLocked(m *UpDownMarket) {
+	status := marketStatus{Closed: m.Closed, EndTime: m.WindowEnd}
+	e.setMarketStatusLocked(m.YesSymbol, status)
+	e.setMarketStatusLocked(m.NoSymbol, status)
+}
+
+// checkMarketOpenLocked 在下单前检查 market 是否已经关闭，需要持有 e.mu。
+func (e *Exchange) checkMarketOpenLocked(symbol string, now time.Time) error {
+	status, ok := e.marketStatus[symbol]
+	if !ok || !status.closedAt(now) {
+		return nil
+	}
+
+	if status.Closed {
+		return fmt.Errorf("%w: %s", ErrMarketClosed, symbol)
+	}
+	return fmt.Errorf("%w: %s ended at %s", ErrMarketClosed, symbol, status.EndTime.Format(time.RFC3339))
+}
+
+func (e *Exchange) checkMarketOpen(symbol string, now time.Time) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.checkMarketOpenLocked(symbol, now)
+}
@@ -0,0 +1,101 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func addTestUpDownMarket(t *testing.T, ex *Exchange, slug string, gm *GammaMarket, start, end time.Time) *UpDownMarket {
+	um, err := newUpDownMarket(slug, gm, start, end)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	ex.mu.Lock()
+	ex.addMarketLocked(um.YesMarket)
+	ex.addMarketLocked(um.NoMarket)
+	ex.upDownMarkets[slug] = um
+	ex.setUpDownMarketStatusLocked(um)
+	ex.mu.Unlock()
+	return um
+}
+
+func TestExchange_SubmitOrderToClosedMarket(t *testing.T) {
+	ex := New("", "", "")
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Minute)
+
+	newGammaMarket := func(closed bool) *GammaMarket {
+		return &GammaMarket{Outcomes: `["Up", "Down"]`, ClobTokenIDs: `["111111111111", "222222222222"]`, Closed: closed}
+	}
+	expired := addTestUpDownMarket(t, ex, "btc-updown-15m-expired", newGammaMarket(false), now.Add(-30*time.Minute), now.Add(-15*time.Minute))
+	closed := addTestUpDownMarket(t, ex, "btc-updown-15m-closed", newGammaMarket(true), now, now.Add(15*time.Minute))
+	open := addTestUpDownMarket(t, ex, "btc-updown-15m-open", newGammaMarket(false), now, now.Add(15*time.Minute))
+
+	newOrder := func(symbol string) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		}
+	}
+
+	_, err := ex.SubmitOrder(ctx, newOrder(expired.YesSymbol))
+	assert.True(t, errors.Is(err, ErrMarketClosed), "%v", err)
+	assert.ErrorContains(t, err, "ended at")
+
+	_, err = ex.SubmitOrder(ctx, newOrder(closed.NoSymbol))
+	assert.True(t, errors.Is(err, ErrMarketClosed), "%v", err)
+
+	_, err = ex.SubmitOrder(ctx, newOrder(open.YesSymbol))
+	assert.NoError(t, err)
+
+	// 没有状态的 market 不检查
+	_, err = ex.SubmitOrder(ctx, newOrder("PM_BTC_15M_UP_YES_USDC"))
+	assert.NoError(t, err)
+
+	// 批量下单只拒绝已关闭 market 的订单
+	created, err := ex.SubmitOrders(ctx, newOrder(open.NoSymbol), newOrder(expired.NoSymbol))
+	var batchErr *BatchOrderError
+	if assert.True(t, errors.As(err, &batchErr)) {
+		assert.NoError(t, batchErr.Errors[0])
+		assert.True(t, errors.Is(batchErr.Errors[1], ErrMarketClosed))
+	}
+	assert.NotZero(t, created[0].OrderID)
+	assert.Zero(t, created[1].OrderID)
+
+	openOrders, err := ex.QueryOpenOrders(ctx, expired.YesSymbol)
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+}
+
+func TestExchange_SubmitOrderToClosedMarketLive(t *testing.T) {
+	t.Setenv(envDryRun, "false")
+
+	ex := New("", "", "")
+	now := time.Now()
+	um := addTestUpDownMarket(t, ex, "btc-updown-15m-closed", &GammaMarket{
+		Outcomes:     `["Up", "Down"]`,
+		ClobTokenIDs: `["111111111111", "222222222222"]`,
+		Closed:       true,
+	}, now, now.Add(15*time.Minute))
+
+	// 在构造订单、检查授权之前失败
+	_, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   um.YesSymbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.True(t, errors.Is(err, ErrMarketClosed), "%v", err)
+}
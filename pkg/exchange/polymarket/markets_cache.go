@@ -0,0 +1,131 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// last-known-good market 缓存：
+// - POLYMARKET_MARKETS_CACHE_FILE 配置后，每次成功加载 / 刷新 market 列表以及通过 Gamma 发现新的 up/down 市场后，
+//   把完整的 market 列表写入该文件（与 POLYMARKET_MARKETS_FILE 相同的格式，包含 slug / conditionId / outcome）
+// - 启动时 markets 文件读取或解析失败、或者没有配置 market 时，先用缓存的 market 列表（打印警告），
+//   没有缓存时才回退到报错 / 默认示例 market，临时的 API 或配置问题不会让正在运行的部署起不来
+
+const envMarketsCacheFile = "POLYMARKET_MARKETS_CACHE_FILE"
+
+// cachedMarket 为缓存文件中的一个 market，引用字段与 markets 文件相同。
+type cachedMarket struct {
+	types.Market
+	Slug        string `json:"slug,omitempty"`
+	ConditionID string `json:"conditionId,omitempty"`
+	Outcome     string `json:"outcome,omitempty"`
+}
+
+// loadStartupMarkets 加载启动时的 market 列表，fromCache 表示使用了缓存。
+func loadStartupMarkets() (markets types.MarketMap, refs map[string]marketRef, fromCache bool, err error) {
+	markets, err = loadMarketsFromEnv()
+	if err == nil && len(markets) > 0 {
+		normalizeMarkets(markets)
+		return markets, loadMarketRefsFromEnv(), false, nil
+	}
+
+	if file := envString(envMarketsCacheFile, ""); file != "" {
+		cached, cachedRefs, cacheErr := loadMarketsCache(file)
+		switch {
+		case cacheErr == nil && err != nil:
+			log.WithError(err).Warnf("failed to load markets, fallback to %d last-known-good markets cached in %s", len(cached), file)
+			return cached, cachedRefs, true, nil
+
+		case cacheErr == nil:
+			log.Warnf("no markets configured, use %d last-known-good markets cached in %s", len(cached), file)
+			return cached, cachedRefs, true, nil
+
+		case !errors.Is(cacheErr, os.ErrNotExist):
+			log.WithError(cacheErr).Warnf("ignore invalid markets cache %s", file)
+		}
+	}
+
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// 兜底：如果用户没有配置 market，给一个可运行的默认 market 列表（用于示例策略）。
+	markets = defaultExampleMarkets()
+	normalizeMarkets(markets)
+	return markets, nil, false, nil
+}
+
+func loadMarketsCache(file string) (types.MarketMap, map[string]marketRef, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	markets, err := decodeMarketsJSON(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(markets) == 0 {
+		return nil, nil, errors.New("polymarket: markets cache is empty")
+	}
+
+	refs := decodeMarketRefs(b)
+	for symbol, ref := range refs {
+		if ref.Slug == "" && ref.ConditionID == "" && ref.Outcome == "" {
+			delete(refs, symbol)
+		}
+	}
+
+	normalizeMarkets(markets)
+	return markets, refs, nil
+}
+
+// saveMarketsCacheLocked 把当前的 market 列表写入缓存文件（没有配置时不做任何事），需要持有 e.mu。
+func (e *Exchange) saveMarketsCacheLocked() {
+	file := envString(envMarketsCacheFile, "")
+	if file == "" || len(e.markets) == 0 {
+		return
+	}
+
+	entries := make(map[string]cachedMarket, len(e.markets))
+	for symbol, m := range e.markets {
+		ref := e.marketRefs[symbol]
+		entries[symbol] = cachedMarket{Market: m, Slug: ref.Slug, ConditionID: ref.ConditionID, Outcome: ref.Outcome}
+	}
+	for _, um := range e.upDownMarkets {
+		for symbol, outcome := range map[string]string{um.YesSymbol: OutcomeUp, um.NoSymbol: OutcomeDown} {
+			if entry, ok := entries[symbol]; ok {
+				entry.Slug, entry.ConditionID, entry.Outcome = um.Slug, um.ConditionID, outcome
+				entries[symbol] = entry
+			}
+		}
+	}
+
+	if err := writeMarketsCache(file, entries); err != nil {
+		log.WithError(err).Warnf("failed to save markets cache %s", file)
+	}
+}
+
+// writeMarketsCache 先写临时文件再 rename，避免进程中断留下不完整的缓存。
+func writeMarketsCache(file string, entries map[string]cachedMarket) error {
+	if dir := filepath.Dir(file); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
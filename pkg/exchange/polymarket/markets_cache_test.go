@@ -0,0 +1,134 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_MarketsCacheFallback(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "cache", "markets.json")
+	t.Setenv(envMarketsCacheFile, cacheFile)
+	t.Setenv(envMarketsJSON, `[
+		{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2,
+		 "tickSize": "0.01", "stepSize": "0.01", "slug": "will-it-rain", "outcome": "Yes"}
+	]`)
+
+	ctx := context.Background()
+	ex := New("", "", "")
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+	ex.Close()
+
+	_, err = os.Stat(cacheFile)
+	assert.NoError(t, err, "markets should be cached after a successful load")
+
+	// markets 文件读取失败时使用缓存
+	t.Setenv(envMarketsJSON, "")
+	t.Setenv(envMarketsFile, filepath.Join(t.TempDir(), "missing.json"))
+
+	ex = New("", "", "")
+	defer ex.Close()
+	assert.NoError(t, ex.ValidateConfig())
+
+	markets, err := ex.QueryMarkets(ctx)
+	if assert.NoError(t, err) && assert.Len(t, markets, 1) {
+		assert.Equal(t, "111111111111", markets["PM_YES"].LocalSymbol)
+		assert.Equal(t, types.ExchangePolymarket, markets["PM_YES"].Exchange)
+		assert.Equal(t, "0.01", markets["PM_YES"].TickSize.String())
+	}
+
+	symbol, err := ex.ResolveSymbol("will-it-rain:Yes")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_YES", symbol)
+}
+
+func TestExchange_MarketsCacheInsteadOfExamples(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "markets.json")
+	t.Setenv(envMarketsCacheFile, cacheFile)
+	assert.NoError(t, writeMarketsCache(cacheFile, map[string]cachedMarket{
+		"PM_CACHED": {Market: types.Market{
+			Symbol:         "PM_CACHED",
+			QuoteCurrency:  "USDC",
+			PricePrecision: 2,
+			TickSize:       defaultExampleMarkets()["PM_BTC_15M_UP_YES_USDC"].TickSize,
+			StepSize:       defaultExampleMarkets()["PM_BTC_15M_UP_YES_USDC"].StepSize,
+		}},
+	}))
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, markets, "PM_CACHED")
+	assert.NotContains(t, markets, "PM_BTC_15M_UP_YES_USDC")
+}
+
+func TestExchange_MarketsCacheErrors(t *testing.T) {
+	// 没有缓存时 markets 文件的错误照常返回
+	t.Setenv(envMarketsCacheFile, filepath.Join(t.TempDir(), "markets.json"))
+	t.Setenv(envMarketsFile, filepath.Join(t.TempDir(), "missing.json"))
+
+	ex := New("", "", "")
+	defer ex.Close()
+	_, err := ex.QueryMarkets(context.Background())
+	assert.Error(t, err)
+
+	// 损坏的缓存被忽略
+	cacheFile := filepath.Join(t.TempDir(), "broken.json")
+	assert.NoError(t, os.WriteFile(cacheFile, []byte("{"), 0o644))
+	t.Setenv(envMarketsCacheFile, cacheFile)
+	t.Setenv(envMarketsFile, "")
+
+	ex = New("", "", "")
+	defer ex.Close()
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, markets, "PM_BTC_15M_UP_YES_USDC")
+}
+
+func TestExchange_MarketsCacheDiscovery(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "markets.json")
+	t.Setenv(envMarketsCacheFile, cacheFile)
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `[{
+			"conditionId": "0xabc",
+			"slug": "btc-updown-15m-1760515200",
+			"outcomes": "[\"Up\", \"Down\"]",
+			"clobTokenIds": "[\"111111111111\", \"222222222222\"]",
+			"active": true,
+			"orderPriceMinTickSize": 0.01,
+			"orderMinSize": 5
+		}]`), nil
+	})
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ex.gamma = newTestRestClient(transport)
+
+	ctx := context.Background()
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+	m, err := ex.DiscoverUpDownMarket(ctx, "BTC", types.Interval15m, time.Date(2025, 10, 15, 8, 7, 30, 0, time.UTC))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	markets, refs, err := loadMarketsCache(cacheFile)
+	if assert.NoError(t, err) {
+		assert.Contains(t, markets, "PM_BTC_15M_UP_YES_USDC")
+		assert.Equal(t, "222222222222", markets[m.NoSymbol].LocalSymbol)
+		assert.Equal(t, marketRef{Symbol: m.YesSymbol, Slug: m.Slug, ConditionID: "0xabc", Outcome: OutcomeUp}, refs[m.YesSymbol])
+	}
+}
@@ -0,0 +1,76 @@
+package polymarket
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// markets 目录：POLYMARKET_MARKETS_DIR 指向一个目录，目录下的每个 *.json 文件与 POLYMARKET_MARKETS_FILE 格式相同，
+// 例如按资产拆分成 btc.json / eth.json。所有文件按文件名顺序解析后合并成一个 market 列表，
+// 不同文件中出现相同的 symbol 时报错（指出两个文件），任一文件解析失败时整个目录加载失败。
+// 只读取目录本身的文件，不递归子目录；同时配置时 POLYMARKET_MARKETS_FILE 优先，其次目录，最后 POLYMARKET_MARKETS_JSON。
+
+const envMarketsDir = "POLYMARKET_MARKETS_DIR"
+
+// marketsDirFiles 返回目录下按文件名排序的 *.json 文件。
+func marketsDirFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isMarketsFileName(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isMarketsFileName 判断文件名是否为 markets 目录下的 market 文件，忽略编辑器的隐藏临时文件。
+func isMarketsFileName(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".json") && !strings.HasPrefix(name, ".")
+}
+
+// loadMarketsDir 解析并合并目录下的所有 market 文件，refs 为文件中的引用字段（slug / conditionId / outcome）。
+func loadMarketsDir(dir string) (types.MarketMap, map[string]marketRef, error) {
+	files, err := marketsDirFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("polymarket: read %s failed: %w", envMarketsDir, err)
+	}
+
+	markets := make(types.MarketMap)
+	refs := make(map[string]marketRef)
+	sources := make(map[string]string)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("polymarket: read markets file %s failed: %w", file, err)
+		}
+
+		mm, err := decodeMarketsJSON(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("polymarket: markets file %s: %w", file, err)
+		}
+
+		for symbol, m := range mm {
+			if source, ok := sources[symbol]; ok {
+				return nil, nil, fmt.Errorf("polymarket: duplicate market symbol %s in %s and %s", symbol, source, file)
+			}
+			sources[symbol] = file
+			markets[symbol] = m
+		}
+		for symbol, ref := range decodeMarketRefs(b) {
+			refs[symbol] = ref
+		}
+	}
+	return markets, refs, nil
+}
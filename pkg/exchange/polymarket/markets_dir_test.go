@@ -0,0 +1,108 @@
+package polymarket
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func writeMarketsFile(t *testing.T, dir, name, content string) {
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestLoadMarketsDir(t *testing.T) {
+	const btc = `[{"symbol": "PM_BTC", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01",
+		"slug": "btc-above-100k", "outcome": "Yes"}]`
+	const eth = `{"PM_ETH": {"quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}}`
+
+	t.Run("merge", func(t *testing.T) {
+		dir := t.TempDir()
+		writeMarketsFile(t, dir, "btc.json", btc)
+		writeMarketsFile(t, dir, "eth.json", eth)
+		writeMarketsFile(t, dir, "README.md", "not a markets file")
+		writeMarketsFile(t, dir, ".eth.json.swp", "{")
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, "archive.json"), 0755))
+
+		markets, refs, err := loadMarketsDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, markets, 2)
+		assert.Contains(t, markets, "PM_BTC")
+		assert.Contains(t, markets, "PM_ETH")
+		assert.Equal(t, "btc-above-100k", refs["PM_BTC"].Slug)
+	})
+
+	t.Run("duplicate symbol", func(t *testing.T) {
+		dir := t.TempDir()
+		writeMarketsFile(t, dir, "a.json", btc)
+		writeMarketsFile(t, dir, "b.json", btc)
+
+		_, _, err := loadMarketsDir(dir)
+		assert.ErrorContains(t, err, "duplicate market symbol PM_BTC")
+		assert.ErrorContains(t, err, filepath.Join(dir, "a.json"))
+		assert.ErrorContains(t, err, filepath.Join(dir, "b.json"))
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeMarketsFile(t, dir, "btc.json", btc)
+		writeMarketsFile(t, dir, "bad.json", `[{"symbol": "PM_BAD"}]`)
+
+		_, _, err := loadMarketsDir(dir)
+		assert.ErrorContains(t, err, filepath.Join(dir, "bad.json"))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, _, err := loadMarketsDir(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorContains(t, err, envMarketsDir)
+	})
+}
+
+func TestExchange_MarketsDir(t *testing.T) {
+	dir := t.TempDir()
+	writeMarketsFile(t, dir, "btc.json", `[{"symbol": "PM_BTC", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01",
+		"slug": "btc-above-100k", "outcome": "Yes"}]`)
+	t.Setenv(envMarketsDir, dir)
+
+	ex := New("", "", "")
+	defer ex.Close()
+
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, markets, 1)
+	assert.Equal(t, types.ExchangePolymarket, markets["PM_BTC"].Exchange)
+
+	symbol, err := ex.ResolveSymbol("btc-above-100k")
+	assert.NoError(t, err)
+	assert.Equal(t, "PM_BTC", symbol)
+
+	var reloaded types.MarketMap
+	ex.OnMarketsReloaded(func(markets types.MarketMap) {
+		reloaded = markets
+	})
+
+	writeMarketsFile(t, dir, "eth.json", `[{"symbol": "PM_ETH", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	ex.reloadMarketsDir(dir)
+	assert.Len(t, reloaded, 2)
+
+	// 重复的 symbol 不会覆盖当前 market
+	writeMarketsFile(t, dir, "eth2.json", `[{"symbol": "PM_ETH", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	ex.reloadMarketsDir(dir)
+
+	markets, err = ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, markets, 2)
+
+	// markets 文件优先于目录
+	path := filepath.Join(t.TempDir(), "markets.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"symbol": "PM_FILE", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`), 0644))
+	t.Setenv(envMarketsFile, path)
+	fromEnv, err := loadMarketsFromEnv()
+	assert.NoError(t, err)
+	assert.Len(t, fromEnv, 1)
+	assert.Contains(t, fromEnv, "PM_FILE")
+}
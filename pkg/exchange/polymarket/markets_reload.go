@@ -0,0 +1,257 @@
+package polymarket
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// markets 文件热加载：POLYMARKET_MARKETS_RELOAD=true 且配置了 POLYMARKET_MARKETS_FILE（或 POLYMARKET_MARKETS_DIR）时，
+// 第一次加载 markets 后开始监听文件（目录下任一 *.json 文件）变化，变化后重新解析并在 e.mu 下替换 market 列表。
+// 解析或校验失败时保留旧的 market 列表；通过 Gamma 发现的 market 会保留。
+
+const envMarketsReload = "POLYMARKET_MARKETS_RELOAD"
+
+// OnMarketsReloaded 注册 markets 文件重新加载后的回调，参数为新的完整 market 列表。
+// bbgo session 的 market 列表只在初始化时加载一次，策略可以在回调里调用 session.SetMarkets 同步。
+func (e *Exchange) OnMarketsReloaded(cb func(markets types.MarketMap)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.marketsReloadedCallbacks = append(e.marketsReloadedCallbacks, cb)
+}
+
+// startMarketsWatcherLocked 启动 markets 文件监听，需要持有 e.mu。
+func (e *Exchange) startMarketsWatcherLocked() {
+	if e.marketsWatcher != nil || !envBool(envMarketsReload, false) {
+		return
+	}
+
+	// 与 loadMarketsFromEnv 的优先级一致：markets 文件优先，其次 markets 目录
+	var dir string
+	var match func(name string) bool
+	var reload func()
+	if path := envString(envMarketsFile, ""); path != "" {
+		target := filepath.Clean(path)
+		dir = filepath.Dir(path)
+		match = func(name string) bool { return name == target }
+		reload = func() { e.reloadMarketsFile(path) }
+	} else if marketsDir := envString(envMarketsDir, ""); marketsDir != "" {
+		dir = marketsDir
+		match = func(name string) bool {
+			return filepath.Dir(name) == filepath.Clean(marketsDir) && isMarketsFileName(filepath.Base(name))
+		}
+		reload = func() { e.reloadMarketsDir(marketsDir) }
+	} else {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.WithError(err).Error("failed to create markets file watcher")
+		return
+	}
+
+	// 监听所在目录而不是文件本身：很多编辑器保存时会 rename/替换文件，直接监听文件会丢失事件。
+	if err := watcher.Add(dir); err != nil {
+		log.WithError(err).Errorf("failed to watch markets directory %s", dir)
+		_ = watcher.Close()
+		return
+	}
+
+	e.marketsWatcher = watcher
+	go e.watchMarketsFile(watcher, match, reload)
+	log.Infof("watching markets files in %s for changes", dir)
+}
+
+func (e *Exchange) watchMarketsFile(watcher *fsnotify.Watcher, match func(name string) bool, reload func()) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			// 目录模式下删除文件也需要重新加载
+			if !match(filepath.Clean(event.Name)) || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) {
+				continue
+			}
+
+			reload()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithError(err).Warn("markets file watcher error")
+		}
+	}
+}
+
+func (e *Exchange) reloadMarketsFile(path string) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		log.WithError(err).Warnf("failed to read markets file %s, keep the current markets", path)
+		return
+	}
+
+	markets, err := decodeMarketsJSON(b)
+	if err != nil {
+		log.WithError(err).Warnf("invalid markets file %s, keep the current markets", path)
+		return
+	}
+	normalizeMarkets(markets)
+
+	e.replaceMarkets(markets, decodeMarketRefs(b), nil)
+	log.Infof("reloaded %d markets from %s", len(markets), path)
+}
+
+func (e *Exchange) reloadMarketsDir(dir string) {
+	markets, refs, err := loadMarketsDir(dir)
+	if err != nil {
+		log.WithError(err).Warnf("invalid markets directory %s, keep the current markets", dir)
+		return
+	}
+	normalizeMarkets(markets)
+
+	e.replaceMarkets(markets, refs, nil)
+	log.Infof("reloaded %d markets from %s", len(markets), dir)
+}
+
+// RefreshMarkets 忽略缓存重新加载 market 列表：环境变量/文件中的 market 重新解析，
+// 通过 Gamma 发现的 up/down market 重新查询元数据（查询失败时保留旧的）。
+// 新列表整体替换旧列表，并触发 OnMarketsReloaded 回调。
+func (e *Exchange) RefreshMarkets(ctx context.Context) (types.MarketMap, error) {
+	markets, err := loadMarkets()
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	cached := make([]*UpDownMarket, 0, len(e.upDownMarkets))
+	for _, um := range e.upDownMarkets {
+		cached = append(cached, um)
+	}
+	e.mu.Unlock()
+
+	upDownMarkets := make(map[string]*UpDownMarket, len(cached))
+	for _, um := range cached {
+		gm, err := e.gamma.queryGammaMarketBySlug(ctx, um.Slug)
+		if err == nil {
+			var refreshed *UpDownMarket
+			if refreshed, err = newUpDownMarket(um.Slug, gm, um.WindowStart, um.WindowEnd); err == nil {
+				e.fees.setMarketFeeBps(refreshed.YesSymbol, gm.TakerBaseFee)
+				e.fees.setMarketFeeBps(refreshed.NoSymbol, gm.TakerBaseFee)
+				um = refreshed
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.WithError(err).Warnf("failed to refresh up/down market %s, keep the cached one", um.Slug)
+		}
+		upDownMarkets[um.Slug] = um
+	}
+
+	e.replaceMarkets(markets, loadMarketRefsFromEnv(), upDownMarkets)
+	log.Infof("refreshed %d markets", len(markets))
+	return copyMarkets(markets), nil
+}
+
+// replaceMarkets 在 e.mu 下用 markets 替换 market 列表（refs 为 markets 文件中的引用字段）并触发 OnMarketsReloaded 回调。
+// upDownMarkets 为 nil 时保留当前通过 Gamma 发现的 market，否则同时替换 up/down market 缓存。
+// markets 会被合并进 up/down market，调用之后不应再修改。
+func (e *Exchange) replaceMarkets(markets types.MarketMap, refs map[string]marketRef, upDownMarkets map[string]*UpDownMarket) {
+	e.mu.Lock()
+	if upDownMarkets != nil {
+		e.upDownMarkets = upDownMarkets
+	}
+	e.marketRefs = refs
+	for _, um := range e.upDownMarkets {
+		markets[um.YesSymbol] = um.YesMarket
+		markets[um.NoSymbol] = um.NoMarket
+		e.setUpDownMarketStatusLocked(um)
+		e.negRisk[um.YesSymbol] = um.NegRisk
+		e.negRisk[um.NoSymbol] = um.NegRisk
+	}
+	e.setMarketsLocked(markets)
+	e.saveMarketsCacheLocked()
+	callbacks := append([]func(types.MarketMap){}, e.marketsReloadedCallbacks...)
+	e.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(copyMarkets(markets))
+	}
+	e.reconcileStreams()
+}
+
+// reconcileStreams 让所有 market channel 的订阅与当前 market 列表一致，见 stream_subscriptions.go。
+func (e *Exchange) reconcileStreams() {
+	e.streamMu.Lock()
+	streams := append([]*Stream{}, e.streams...)
+	e.streamMu.Unlock()
+
+	for _, stream := range streams {
+		if err := stream.reconcileAssetIDs(); err != nil {
+			log.WithError(err).Warn("failed to update market channel subscriptions")
+		}
+	}
+}
+
+func copyMarkets(markets types.MarketMap) types.MarketMap {
+	out := make(types.MarketMap, len(markets))
+	for symbol, m := range markets {
+		out[symbol] = m
+	}
+	return out
+}
+
+// updateTickSize 在 market channel 推送 tick_size_change 后更新 symbol 的 tick size 与价格精度，
+// 通过 Gamma 发现的 up/down market 缓存同步更新，避免 RefreshMarkets 之前被旧值覆盖。
+// 之后的下单按新的 tick 取整（见 roundOrderPrice），并触发 OnMarketsReloaded 回调让 session 同步。
+func (e *Exchange) updateTickSize(symbol string, tickSize fixedpoint.Value) {
+	e.mu.Lock()
+
+	update := func(m types.Market) types.Market {
+		m.TickSize = tickSize
+		m.PricePrecision = int(math.Round(-math.Log10(tickSize.Float64())))
+		return m
+	}
+
+	m, ok := e.markets[symbol]
+	if !ok || m.TickSize.Eq(tickSize) {
+		e.mu.Unlock()
+		return
+	}
+	e.addMarketLocked(update(m))
+
+	// 缓存的 UpDownMarket 可能已经返回给调用方，替换成新的副本而不是原地修改
+	for slug, um := range e.upDownMarkets {
+		if symbol != um.YesSymbol && symbol != um.NoSymbol {
+			continue
+		}
+
+		updated := *um
+		if symbol == um.YesSymbol {
+			updated.YesMarket = update(um.YesMarket)
+		} else {
+			updated.NoMarket = update(um.NoMarket)
+		}
+		e.upDownMarkets[slug] = &updated
+	}
+
+	markets := copyMarkets(e.markets)
+	callbacks := append([]func(types.MarketMap){}, e.marketsReloadedCallbacks...)
+	e.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(copyMarkets(markets))
+	}
+}
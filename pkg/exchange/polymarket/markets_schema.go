@@ -0,0 +1,305 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// markets 文件 / JSON 的校验：encoding/json 会忽略拼错的字段，类型错误时也只报告偏移量。
+// 解析前先按 types.Market 与 marketRef 的 json tag 检查每个 market：
+// - 未知字段（给出最接近的字段名）与字段类型（字符串 / 整数 / 十进制数字或数字字符串）
+// - 与 validateMarket 相同的精度检查、symbol 为空或重复
+// 所有问题一次性汇总成 *MarketsJSONError，每条带上行号与 symbol（没有 symbol 时为下标）。
+// 可以用 ValidateMarketsJSON 在运行前检查 markets 文件。
+
+// MarketsJSONError 汇总 markets JSON 的所有问题。
+type MarketsJSONError struct {
+	Problems []string
+}
+
+func (e *MarketsJSONError) Error() string {
+	return fmt.Sprintf("polymarket: invalid markets json (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateMarketsJSON 检查 markets 文件 / JSON 的内容，没有问题时返回 nil，否则返回 *MarketsJSONError。
+func ValidateMarketsJSON(b []byte) error {
+	if problems := validateMarketsJSON(b); len(problems) > 0 {
+		return &MarketsJSONError{Problems: problems}
+	}
+	return nil
+}
+
+type marketFieldKind int
+
+const (
+	marketFieldString marketFieldKind = iota
+	marketFieldInt
+	marketFieldDecimal
+)
+
+func (k marketFieldKind) String() string {
+	switch k {
+	case marketFieldInt:
+		return "an integer"
+	case marketFieldDecimal:
+		return "a decimal number or numeric string"
+	}
+	return "a string"
+}
+
+// marketSchema 为 market 允许的字段，由 types.Market 与 marketRef 的 json tag 生成。
+var marketSchema = buildMarketSchema(types.Market{}, marketRef{})
+
+func buildMarketSchema(values ...interface{}) map[string]marketFieldKind {
+	decimalType := reflect.TypeOf(fixedpoint.Value(0))
+	schema := make(map[string]marketFieldKind)
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			switch {
+			case f.Type == decimalType:
+				schema[name] = marketFieldDecimal
+			case f.Type.Kind() == reflect.Int:
+				schema[name] = marketFieldInt
+			default:
+				schema[name] = marketFieldString
+			}
+		}
+	}
+	return schema
+}
+
+type marketsValidator struct {
+	data     []byte
+	problems []string
+	symbols  map[string]int
+}
+
+func validateMarketsJSON(b []byte) []string {
+	v := &marketsValidator{data: b, symbols: make(map[string]int)}
+
+	var syntaxErr *json.SyntaxError
+	var decoded interface{}
+	if err := json.Unmarshal(b, &decoded); errors.As(err, &syntaxErr) {
+		line, column := v.position(int(syntaxErr.Offset))
+		return []string{fmt.Sprintf("line %d, column %d: %s", line, column, syntaxErr.Error())}
+	} else if err != nil {
+		return []string{err.Error()}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	tok, err := dec.Token()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	switch tok {
+	case json.Delim('['):
+		for index := 0; dec.More(); index++ {
+			start := int(dec.InputOffset())
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return append(v.problems, err.Error())
+			}
+			v.checkMarket("", index, raw, start)
+		}
+
+	case json.Delim('{'):
+		for index := 0; dec.More(); index++ {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return append(v.problems, err.Error())
+			}
+			start := int(dec.InputOffset())
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return append(v.problems, err.Error())
+			}
+			v.checkMarket(keyTok.(string), index, raw, start)
+		}
+
+	default:
+		return []string{"line 1: markets json must be an array of markets or an object keyed by symbol"}
+	}
+
+	return v.problems
+}
+
+// position 返回偏移量 offset 所在的行号与列号（从 1 开始）。
+func (v *marketsValidator) position(offset int) (line, column int) {
+	if offset > len(v.data) {
+		offset = len(v.data)
+	}
+	before := v.data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = offset - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+func (v *marketsValidator) addf(offset int, format string, args ...interface{}) {
+	line, _ := v.position(offset)
+	v.problems = append(v.problems, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+}
+
+// checkMarket 检查一个 market，key 为 MarketMap 格式的 symbol（数组格式为空），start 为 raw 之前的偏移量。
+func (v *marketsValidator) checkMarket(key string, index int, raw json.RawMessage, start int) {
+	if i := bytes.IndexByte(v.data[start:], raw[0]); i >= 0 {
+		start += i
+	}
+
+	name := key
+	if name == "" {
+		name = fmt.Sprintf("#%d", index)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		v.addf(start, "market %s: must be an object", name)
+		return
+	}
+
+	if key == "" {
+		if s, err := strconv.Unquote(string(fields["symbol"])); err == nil && s != "" {
+			name = s
+		}
+	}
+
+	// 按字段在文件中的位置排序，问题按文件顺序报告
+	names := make([]string, 0, len(fields))
+	offsets := make(map[string]int, len(fields))
+	for field := range fields {
+		names = append(names, field)
+		offsets[field] = start + bytes.Index(raw, []byte(strconv.Quote(field)))
+	}
+	sort.Slice(names, func(i, j int) bool { return offsets[names[i]] < offsets[names[j]] })
+
+	valid := true
+	for _, field := range names {
+		kind, ok := marketSchema[field]
+		if !ok {
+			valid = false
+			if suggestion := suggestMarketField(field); suggestion != "" {
+				v.addf(offsets[field], "market %s: unknown field %q (did you mean %q?)", name, field, suggestion)
+			} else {
+				v.addf(offsets[field], "market %s: unknown field %q", name, field)
+			}
+			continue
+		}
+
+		if value := fields[field]; !checkMarketField(kind, value) {
+			valid = false
+			v.addf(offsets[field], "market %s: field %q must be %s, got %s", name, field, kind, value)
+		}
+	}
+
+	if !valid {
+		return
+	}
+
+	var m types.Market
+	if err := json.Unmarshal(raw, &m); err != nil {
+		v.addf(start, "market %s: %v", name, err)
+		return
+	}
+
+	symbol := m.Symbol
+	if key != "" {
+		if m.Symbol != "" && m.Symbol != key {
+			v.addf(offsets["symbol"], "market %s: symbol %q does not match the key", key, m.Symbol)
+		}
+		symbol = key
+	}
+	if symbol == "" {
+		v.addf(start, "market symbol is empty (index %d)", index)
+		return
+	}
+	if first, ok := v.symbols[symbol]; ok {
+		v.addf(start, "duplicate market symbol %s (index %d, first defined at index %d)", symbol, index, first)
+		return
+	}
+	v.symbols[symbol] = index
+
+	if err := validateMarket(symbol, m); err != nil {
+		v.addf(start, "%s", strings.TrimPrefix(err.Error(), "polymarket: "))
+	}
+}
+
+func checkMarketField(kind marketFieldKind, value json.RawMessage) bool {
+	if string(value) == "null" {
+		return true
+	}
+
+	switch kind {
+	case marketFieldInt:
+		_, err := strconv.Atoi(string(value))
+		return err == nil
+
+	case marketFieldDecimal:
+		s := string(value)
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			if unquoted == "" {
+				return true
+			}
+			s = unquoted
+		} else if value[0] == '"' {
+			return false
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return false
+		}
+		_, err := fixedpoint.NewFromString(s)
+		return err == nil
+	}
+
+	_, err := strconv.Unquote(string(value))
+	return err == nil
+}
+
+// suggestMarketField 返回与 field 最接近的已知字段（大小写不同或编辑距离不超过 2），没有时返回空字符串。
+func suggestMarketField(field string) string {
+	best, bestDistance := "", 3
+	for known := range marketSchema {
+		if strings.EqualFold(known, field) {
+			return known
+		}
+		if d := editDistance(strings.ToLower(known), strings.ToLower(field)); d < bestDistance || (d == bestDistance && known < best) {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,64 @@
+package polymarket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMarketsJSON(t *testing.T) {
+	assert.NoError(t, ValidateMarketsJSON([]byte(`[
+		{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2,
+		 "tickSize": "0.01", "stepSize": 0.01, "minQuantity": null, "slug": "will-it-rain", "outcome": "Yes"}
+	]`)))
+
+	err := ValidateMarketsJSON([]byte(`[
+	{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+	{
+		"symbol": "PM_B",
+		"quoteCurency": "USDC",
+		"pricePrecision": "2",
+		"tickSize": "abc",
+		"stepSize": "0.01"
+	},
+	{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+	{"symbol": "PM_C", "quoteCurrency": "USDC", "pricePrecision": 2, "stepSize": "0.01"}
+]`))
+
+	var marketsErr *MarketsJSONError
+	if assert.True(t, errors.As(err, &marketsErr), "%v", err) {
+		assert.Equal(t, []string{
+			`line 5: market PM_B: unknown field "quoteCurency" (did you mean "quoteCurrency"?)`,
+			`line 6: market PM_B: field "pricePrecision" must be an integer, got "2"`,
+			`line 7: market PM_B: field "tickSize" must be a decimal number or numeric string, got "abc"`,
+			`line 10: duplicate market symbol PM_A (index 2, first defined at index 0)`,
+			`line 11: market PM_C: tickSize must be positive`,
+		}, marketsErr.Problems)
+	}
+}
+
+func TestValidateMarketsJSON_Map(t *testing.T) {
+	err := ValidateMarketsJSON([]byte(`{
+		"PM_A": {"symbol": "PM_B", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+		"PM_C": {"QuoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}
+	}`))
+	assert.ErrorContains(t, err, `line 2: market PM_A: symbol "PM_B" does not match the key`)
+	assert.ErrorContains(t, err, `line 3: market PM_C: unknown field "QuoteCurrency" (did you mean "quoteCurrency"?)`)
+}
+
+func TestValidateMarketsJSON_Syntax(t *testing.T) {
+	err := ValidateMarketsJSON([]byte("[\n  {\"symbol\": \"PM_A\",}\n]"))
+	assert.ErrorContains(t, err, "line 2, column")
+
+	err = ValidateMarketsJSON([]byte(`"PM_A"`))
+	assert.ErrorContains(t, err, "must be an array of markets or an object keyed by symbol")
+
+	err = ValidateMarketsJSON([]byte(`["PM_A"]`))
+	assert.ErrorContains(t, err, "market #0: must be an object")
+}
+
+func TestDecodeMarketsJSON_SchemaErrors(t *testing.T) {
+	_, err := decodeMarketsJSON([]byte(`[{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01", "tickSzie": "0.001"}]`))
+	assert.ErrorContains(t, err, `unknown field "tickSzie" (did you mean "tickSize"?)`)
+}
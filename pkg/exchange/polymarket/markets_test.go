@@ -0,0 +1,138 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestDecodeMarketsJSON(t *testing.T) {
+	const valid = `{"symbol": "PM_YES", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}`
+
+	t.Run("array", func(t *testing.T) {
+		mm, err := decodeMarketsJSON([]byte(`[` + valid + `]`))
+		assert.NoError(t, err)
+		assert.Contains(t, mm, "PM_YES")
+	})
+
+	t.Run("map", func(t *testing.T) {
+		mm, err := decodeMarketsJSON([]byte(`{"PM_YES": ` + valid + `}`))
+		assert.NoError(t, err)
+		assert.Contains(t, mm, "PM_YES")
+	})
+
+	t.Run("duplicate symbol", func(t *testing.T) {
+		_, err := decodeMarketsJSON([]byte(`[` + valid + `,` + valid + `]`))
+		assert.ErrorContains(t, err, "duplicate market symbol PM_YES")
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		cases := map[string]string{
+			"tickSize":       `{"symbol": "PM_BAD", "quoteCurrency": "USDC", "pricePrecision": 2, "stepSize": "0.01"}`,
+			"stepSize":       `{"symbol": "PM_BAD", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01"}`,
+			"pricePrecision": `{"symbol": "PM_BAD", "quoteCurrency": "USDC", "tickSize": "0.01", "stepSize": "0.01"}`,
+			"quoteCurrency":  `{"symbol": "PM_BAD", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}`,
+		}
+		for field, raw := range cases {
+			_, err := decodeMarketsJSON([]byte(`[` + raw + `]`))
+			assert.ErrorContains(t, err, "market PM_BAD: "+field, field)
+		}
+	})
+}
+
+func TestExchange_ReloadMarketsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.json")
+	write := func(content string) {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write(`[{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	t.Setenv(envMarketsFile, path)
+
+	ex := New("", "", "")
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, markets, 1)
+
+	var reloaded types.MarketMap
+	ex.OnMarketsReloaded(func(markets types.MarketMap) {
+		reloaded = markets
+	})
+
+	write(`[
+		{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+		{"symbol": "PM_B", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}
+	]`)
+	ex.reloadMarketsFile(path)
+	assert.Len(t, reloaded, 2)
+	assert.Equal(t, types.ExchangePolymarket, reloaded["PM_B"].Exchange)
+
+	// 无效的文件不会覆盖当前 market
+	write(`[{"symbol": "PM_C"}]`)
+	ex.reloadMarketsFile(path)
+
+	markets, err = ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, markets, 2)
+}
+
+func TestExchange_RefreshMarkets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.json")
+	write := func(content string) {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write(`[{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	t.Setenv(envMarketsFile, path)
+
+	tickSize := "0.01"
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `[{
+			"slug": "btc-updown-15m-1760515200",
+			"outcomes": "[\"Up\", \"Down\"]",
+			"clobTokenIds": "[\"111111111111\", \"222222222222\"]",
+			"orderPriceMinTickSize": `+tickSize+`
+		}]`), nil
+	})
+
+	ex := New("", "", "")
+	ex.gamma = newTestRestClient(transport)
+
+	_, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	um, err := ex.DiscoverUpDownMarket(context.Background(), "btc", types.Interval15m, time.Unix(1760515200, 0))
+	assert.NoError(t, err)
+
+	var reloaded types.MarketMap
+	ex.OnMarketsReloaded(func(markets types.MarketMap) {
+		reloaded = markets
+	})
+
+	write(`[
+		{"symbol": "PM_A", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+		{"symbol": "PM_B", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}
+	]`)
+	tickSize = "0.001"
+
+	markets, err := ex.RefreshMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, markets, 4)
+	assert.Equal(t, markets, reloaded)
+	assert.Equal(t, types.ExchangePolymarket, markets["PM_B"].Exchange)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.001), markets[um.YesSymbol].TickSize)
+
+	markets, err = ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, markets, 4)
+}
@@ -0,0 +1,262 @@
+package polymarket
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 下的模拟撮合：
+// - POLYMARKET_DRYRUN_AUTOFILL=true 开启（默认关闭，订单保持 NEW）
+// - 参考价：通过 Exchange.SetReferencePrice 注入（例如策略用 Binance 行情换算的概率），
+//   买单在参考价 <= 限价时成交，卖单在参考价 >= 限价时成交
+// - 成交概率：POLYMARKET_DRYRUN_FILL_PROBABILITY（0~1），没有参考价的订单每个撮合周期按该概率成交
+// - 撮合周期：POLYMARKET_DRYRUN_FILL_INTERVAL（默认 1s）
+// - 确认延迟：POLYMARKET_DRYRUN_LATENCY_MS，订单创建后经过该时长才参与撮合（默认 0）
+// - 滑点：POLYMARKET_DRYRUN_SLIPPAGE_BPS，成交价比限价差该 bps（买单更高、卖单更低，限制在 [0, 1]），默认 0
+// - 成交概率曲线见 fill_model.go，部分成交见 partial_fill.go
+
+const (
+	envDryRunAutoFill        = "POLYMARKET_DRYRUN_AUTOFILL"
+	envDryRunFillProbability = "POLYMARKET_DRYRUN_FILL_PROBABILITY"
+	envDryRunFillInterval    = "POLYMARKET_DRYRUN_FILL_INTERVAL"
+	envDryRunLatencyMs       = "POLYMARKET_DRYRUN_LATENCY_MS"
+	envDryRunSlippageBps     = "POLYMARKET_DRYRUN_SLIPPAGE_BPS"
+
+	defaultDryRunFillInterval = time.Second
+)
+
+type dryRunMatcher struct {
+	enabled         bool
+	fillProbability float64
+	interval        time.Duration
+	latency         time.Duration
+	// slippage 为滑点比例（bps / 10000）
+	slippage fixedpoint.Value
+
+	// curve 不为空时，有参考价的订单按曲线上的概率成交
+	curve fillCurve
+	// 每次成交的数量比例与上限，见 increment
+	fillRatio fixedpoint.Value
+	fillChunk fixedpoint.Value
+	// rand 为测试注入的随机数，nil 时使用 math/rand
+	rand func() float64
+
+	// referencePrices 以 Polymarket symbol 为 key，由 mu 保护（不同 market 的撮合会同时读取）
+	mu              sync.RWMutex
+	referencePrices map[string]fixedpoint.Value
+
+	started atomic.Bool
+}
+
+func newDryRunMatcherFromEnv() *dryRunMatcher {
+	interval := envDuration(envDryRunFillInterval, defaultDryRunFillInterval)
+	if interval <= 0 {
+		interval = defaultDryRunFillInterval
+	}
+
+	latency := time.Duration(envInt(envDryRunLatencyMs, 0)) * time.Millisecond
+	if latency < 0 {
+		latency = 0
+	}
+
+	slippageBps := envFloat(envDryRunSlippageBps, 0)
+	if slippageBps < 0 {
+		slippageBps = 0
+	}
+
+	return &dryRunMatcher{
+		enabled:         envBool(envDryRunAutoFill, false),
+		fillProbability: envFloat(envDryRunFillProbability, 0),
+		interval:        interval,
+		latency:         latency,
+		slippage:        fixedpoint.NewFromFloat(slippageBps / 10000),
+		curve:           newFillCurveFromEnv(),
+		fillRatio:       newFillRatioFromEnv(),
+		fillChunk:       newFillChunkFromEnv(),
+		referencePrices: make(map[string]fixedpoint.Value),
+	}
+}
+
+// referencePrice 返回 symbol 的参考价。
+func (m *dryRunMatcher) referencePrice(symbol string) (fixedpoint.Value, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ref, ok := m.referencePrices[symbol]
+	return ref, ok
+}
+
+func (m *dryRunMatcher) setReferencePrice(symbol string, price fixedpoint.Value) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.referencePrices[symbol] = price
+}
+
+// shouldFill 判断订单在 now 时、当前参考价/成交概率下是否应该成交，确认延迟内的订单不成交。
+func (m *dryRunMatcher) shouldFill(o *types.Order, now time.Time) bool {
+	if m.latency > 0 && now.Sub(o.CreationTime.Time()) < m.latency {
+		return false
+	}
+
+	if ref, ok := m.referencePrice(o.Symbol); ok && ref.Sign() > 0 {
+		switch o.Side {
+		case types.SideTypeBuy:
+			return ref.Compare(o.Price) <= 0
+		case types.SideTypeSell:
+			return ref.Compare(o.Price) >= 0
+		}
+		return false
+	}
+
+	return m.fillProbability > 0 && m.random() < m.fillProbability
+}
+
+// fillPrice 返回订单加上滑点后的成交价。
+func (m *dryRunMatcher) fillPrice(o *types.Order) fixedpoint.Value {
+	if m.slippage.IsZero() {
+		return o.Price
+	}
+
+	switch o.Side {
+	case types.SideTypeBuy:
+		return fixedpoint.Min(o.Price.Mul(fixedpoint.One.Add(m.slippage)), fixedpoint.One)
+	case types.SideTypeSell:
+		return fixedpoint.Max(o.Price.Mul(fixedpoint.One.Sub(m.slippage)), fixedpoint.Zero)
+	}
+	return o.Price
+}
+
+// SetReferencePrice 设置 dry-run 撮合使用的参考价（概率价格 0~1）。
+// 开启 autofill 时会立即尝试撮合该 symbol 下的挂单，只锁该 symbol 的订单分片。
+func (e *Exchange) SetReferencePrice(symbol string, price fixedpoint.Value) {
+	e.matcher.setReferencePrice(symbol, price)
+
+	e.mu.RLock()
+	var filled []types.Order
+	if sh := e.lookupShard(symbol); sh != nil {
+		filled = e.matchShardLocked(sh, time.Now())
+	}
+	if len(filled) > 0 {
+		e.saveOrdersLocked()
+	}
+	e.mu.RUnlock()
+
+	e.emitFills(filled)
+}
+
+// startMatcher 在第一次 dry-run 下单时启动撮合循环。
+func (e *Exchange) startMatcher() {
+	if !e.matcher.enabled || !e.matcher.started.CompareAndSwap(false, true) {
+		return
+	}
+
+	go e.runMatcher(e.background)
+}
+
+func (e *Exchange) runMatcher(ctx context.Context) {
+	ticker := time.NewTicker(e.matcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.mu.RLock()
+			filled := e.matchOrdersLocked()
+			e.mu.RUnlock()
+
+			e.emitFills(filled)
+		}
+	}
+}
+
+// matchOrdersLocked 逐个 shard 撮合所有 working 订单，返回本轮成交（含部分成交）的订单快照，
+// 需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) matchOrdersLocked() (filled []types.Order) {
+	if !e.matcher.enabled {
+		return nil
+	}
+
+	now := time.Now()
+	for _, sh := range e.shardList() {
+		filled = append(filled, e.matchShardLocked(sh, now)...)
+	}
+
+	if len(filled) > 0 {
+		e.saveOrdersLocked()
+	}
+	return filled
+}
+
+// matchShardLocked 撮合 shard 中的 working 订单，返回成交（含部分成交）的订单快照，需要持有 e.mu 的读锁，不能持有 shard 锁。
+func (e *Exchange) matchShardLocked(sh *orderShard, now time.Time) (filled []types.Order) {
+	if !e.matcher.enabled {
+		return nil
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for _, o := range sh.orders {
+		if !o.IsWorking {
+			continue
+		}
+
+		quantity := e.matcher.fillQuantity(o, e.markets[o.Symbol].StepSize, now)
+		if quantity.Sign() <= 0 {
+			continue
+		}
+
+		price := e.matcher.fillPrice(o)
+		e.settleFillLocked(o, quantity, price)
+		e.recordFillLocked(o, quantity, price, types.Time(now), true)
+
+		applyFill(o, quantity, price)
+		if o.ExecutedQuantity.Compare(o.Quantity) >= 0 {
+			o.Status = types.OrderStatusFilled
+			o.OriginalStatus = "FILLED"
+			o.IsWorking = false
+		} else {
+			o.Status = types.OrderStatusPartiallyFilled
+			o.OriginalStatus = "PARTIALLY_FILLED"
+		}
+		o.UpdateTime = types.Time(now)
+
+		filled = append(filled, *o)
+	}
+
+	if len(filled) > 0 {
+		sh.updateMetricsLocked()
+	}
+	return filled
+}
+
+func (e *Exchange) emitFills(filled []types.Order) {
+	if len(filled) == 0 {
+		return
+	}
+
+	symbols := make([]string, 0, len(filled))
+	for _, o := range filled {
+		if o.Status == types.OrderStatusPartiallyFilled {
+			log.WithFields(o.LogFields()).Infof("polymarket(dry-run) order partially filled: %s", o.String())
+			e.logDryRunEvent(DryRunEventPartiallyFilled, o)
+		} else {
+			log.WithFields(o.LogFields()).Infof("polymarket(dry-run) order filled: %s", o.String())
+			e.logDryRunEvent(DryRunEventFilled, o)
+		}
+		e.emitOrderUpdate(o)
+		symbols = append(symbols, o.Symbol)
+	}
+
+	e.mu.RLock()
+	balances := e.balancesLocked(symbols...)
+	e.mu.RUnlock()
+
+	e.emitBalanceUpdate(balances)
+}
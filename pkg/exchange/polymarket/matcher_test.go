@@ -0,0 +1,112 @@
+package polymarket
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DryRunAutoFill(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	stream := ex.NewStream()
+
+	var updates []types.Order
+	stream.OnOrderUpdate(func(o types.Order) {
+		updates = append(updates, o)
+	})
+
+	order, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrderStatusNew, order.Status)
+
+	// 参考价高于买单限价，不成交
+	ex.SetReferencePrice(order.Symbol, fixedpoint.NewFromFloat(0.6))
+	openOrders, err := ex.QueryOpenOrders(context.Background(), order.Symbol)
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 1)
+
+	// 参考价穿过限价，成交
+	ex.SetReferencePrice(order.Symbol, fixedpoint.NewFromFloat(0.45))
+	openOrders, err = ex.QueryOpenOrders(context.Background(), order.Symbol)
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 0)
+
+	if assert.NotEmpty(t, updates) {
+		last := updates[len(updates)-1]
+		assert.Equal(t, types.OrderStatusFilled, last.Status)
+		assert.Equal(t, order.Quantity, last.ExecutedQuantity)
+	}
+}
+
+func TestExchange_DryRunLatencyAndSlippage(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envDryRunLatencyMs, "50")
+	t.Setenv(envDryRunSlippageBps, "200")
+	t.Setenv(envBalanceUSDC, "10")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+
+	// 确认延迟内即使参考价穿过限价也不成交
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.45))
+	openOrders, err := ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 1)
+
+	time.Sleep(60 * time.Millisecond)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.45))
+	openOrders, err = ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+
+	// 买单成交价比限价高 2%，多付的金额从可用余额扣除
+	trades, err := ex.QueryOrderTrades(ctx, types.OrderQuery{Symbol: symbol, OrderID: strconv.FormatUint(order.OrderID, 10)})
+	assert.NoError(t, err)
+	if assert.Len(t, trades, 1) {
+		assert.Equal(t, "0.51", trades[0].Price.String())
+		assert.InDelta(t, 5.1, trades[0].QuoteQuantity.Float64(), 1e-6)
+	}
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.InDelta(t, 4.9, balances["USDC"].Available.Float64(), 1e-6)
+	assert.Equal(t, "0", balances["USDC"].Locked.String())
+}
+
+func TestDryRunMatcher_ShouldFillUsesInjectedRand(t *testing.T) {
+	m := &dryRunMatcher{fillProbability: 0.5, referencePrices: make(map[string]fixedpoint.Value)}
+	o := &types.Order{SubmitOrder: types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Price: fixedpoint.NewFromFloat(0.5)}}
+
+	// 没有参考价的订单按注入的随机数决定是否成交
+	m.rand = func() float64 { return 0.49 }
+	assert.True(t, m.shouldFill(o, time.Now()))
+
+	m.rand = func() float64 { return 0.5 }
+	assert.False(t, m.shouldFill(o, time.Now()))
+}
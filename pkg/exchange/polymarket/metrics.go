@@ -0,0 +1,137 @@
+package polymarket
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Prometheus 指标，在第一次创建 Exchange / Stream 时注册。
+// mode 标签为 dry_run 或 live。
+
+var (
+	orderSubmittedMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "polymarket_order_submitted_total",
+			Help: "Total number of orders accepted by the Polymarket adapter",
+		}, []string{"symbol", "mode"},
+	)
+
+	orderRejectedMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "polymarket_order_rejected_total",
+			Help: "Total number of order submissions that failed",
+		}, []string{"symbol", "mode"},
+	)
+
+	orderCanceledMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "polymarket_order_canceled_total",
+			Help: "Total number of orders canceled",
+		}, []string{"symbol", "mode"},
+	)
+
+	fillNotionalMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "polymarket_fill_notional_total",
+			Help: "Total filled notional (price * quantity) in USDC",
+		}, []string{"symbol", "mode"},
+	)
+
+	feeMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "polymarket_fees_total",
+			Help: "Total trading fees of the fills in USDC",
+		}, []string{"symbol", "mode"},
+	)
+
+	openOrdersMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "polymarket_open_orders",
+			Help: "Current number of working orders, simulated orders in dry-run and orders tracked from the user channel in live",
+		}, []string{"symbol", "mode"},
+	)
+
+	requestDurationMetrics = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "polymarket_request_duration_milliseconds",
+			Help: "Latency of Polymarket REST API requests in milliseconds, partitioned by HTTP method and route template",
+			// 1ms ~ 10s
+			Buckets: []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+		}, []string{"method", "path"},
+	)
+
+	websocketReconnectMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "polymarket_websocket_reconnect_total",
+			Help: "Total number of websocket reconnections, partitioned by channel (user or market)",
+		}, []string{"channel"},
+	)
+)
+
+var registerMetricsOnce sync.Once
+
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		prometheus.MustRegister(
+			orderSubmittedMetrics,
+			orderRejectedMetrics,
+			orderCanceledMetrics,
+			fillNotionalMetrics,
+			feeMetrics,
+			openOrdersMetrics,
+			requestDurationMetrics,
+			websocketReconnectMetrics,
+			websocketEventLagMetrics,
+		)
+	})
+}
+
+func (e *Exchange) metricsMode() string {
+	if e.IsDryRun() {
+		return "dry_run"
+	}
+	return "live"
+}
+
+// recordOrderSubmission 按下单结果累加 submitted / rejected 计数。
+func (e *Exchange) recordOrderSubmission(order types.SubmitOrder, err error) {
+	labels := prometheus.Labels{"symbol": order.Symbol, "mode": e.metricsMode()}
+	if err != nil {
+		orderRejectedMetrics.With(labels).Inc()
+		return
+	}
+	orderSubmittedMetrics.With(labels).Inc()
+}
+
+func (e *Exchange) recordOrderCancel(symbol string, count int) {
+	if count <= 0 {
+		return
+	}
+	orderCanceledMetrics.With(prometheus.Labels{"symbol": symbol, "mode": e.metricsMode()}).Add(float64(count))
+}
+
+// recordFillMetrics 累加一笔成交的成交金额与手续费。
+func recordFillMetrics(trade types.Trade, mode string) {
+	labels := prometheus.Labels{"symbol": trade.Symbol, "mode": mode}
+	fillNotionalMetrics.With(labels).Add(trade.QuoteQuantity.Float64())
+	if trade.Fee.Sign() > 0 {
+		feeMetrics.With(labels).Add(trade.Fee.Float64())
+	}
+}
+
+// recordRequestDuration 记录请求耗时，route 为路由模板，查询参数不计入标签。
+func recordRequestDuration(method, route string, d time.Duration) {
+	if i := strings.IndexByte(route, '?'); i >= 0 {
+		route = route[:i]
+	}
+	requestDurationMetrics.With(prometheus.Labels{"method": method, "path": route}).Observe(float64(d.Milliseconds()))
+}
+
+func recordWebsocketReconnect(channel string) {
+	websocketReconnectMetrics.With(prometheus.Labels{"channel": channel}).Inc()
+}
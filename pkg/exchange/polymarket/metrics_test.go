@@ -0,0 +1,125 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_OrderMetrics(t *testing.T) {
+	t.Setenv(envBalanceUSDC, "10")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_METRICS_YES_USDC"
+
+	submitted := orderSubmittedMetrics.WithLabelValues(symbol, "dry_run")
+	rejected := orderRejectedMetrics.WithLabelValues(symbol, "dry_run")
+	canceled := orderCanceledMetrics.WithLabelValues(symbol, "dry_run")
+	openOrders := openOrdersMetrics.WithLabelValues(symbol, "dry_run")
+
+	newOrder := func(quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		}
+	}
+
+	order, err := ex.SubmitOrder(ctx, newOrder(4))
+	assert.NoError(t, err)
+	_, err = ex.SubmitOrder(ctx, newOrder(100))
+	assert.ErrorIs(t, err, errInsufficientBalance)
+
+	// 批量下单中余额不足的订单单独记为 rejected
+	_, err = ex.SubmitOrders(ctx, newOrder(4), newOrder(100))
+	assert.Error(t, err)
+
+	assert.Equal(t, 2.0, metricValue(t, submitted))
+	assert.Equal(t, 2.0, metricValue(t, rejected))
+	assert.Equal(t, 2.0, metricValue(t, openOrders))
+
+	assert.NoError(t, ex.CancelOrders(ctx, *order))
+	assert.Equal(t, 1.0, metricValue(t, canceled))
+	assert.Equal(t, 1.0, metricValue(t, openOrders))
+}
+
+func TestStream_LiveOpenOrdersMetrics(t *testing.T) {
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithDryRun(false))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	const symbol = "PM_METRICS_LIVE_YES_USDC"
+	stream := ex.NewStream().(*Stream)
+	openOrders := openOrdersMetrics.WithLabelValues(symbol, "live")
+
+	order := func(id string, working bool) types.Order {
+		return types.Order{SubmitOrder: types.SubmitOrder{Symbol: symbol}, UUID: id, IsWorking: working}
+	}
+
+	// user channel 推送的订单更新驱动 live 挂单数
+	stream.EmitOrderUpdate(order("0x1", true))
+	stream.EmitOrderUpdate(order("0x2", true))
+	stream.EmitOrderUpdate(types.Order{SubmitOrder: types.SubmitOrder{Symbol: "PM_OTHER"}, UUID: "0x3", IsWorking: true})
+	assert.Equal(t, 2.0, metricValue(t, openOrders))
+
+	stream.EmitOrderUpdate(order("0x1", false))
+	assert.Equal(t, 1.0, metricValue(t, openOrders))
+}
+
+func TestRequestDurationRoute(t *testing.T) {
+	const orderID = "0xdeadbeefcafe0001"
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/data/order/"+orderID, func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `{"id":"`+orderID+`","status":"LIVE"}`), nil
+	})
+
+	c := newTestRestClient(transport)
+	order, err := c.GetOrder(context.Background(), orderID)
+	assert.NoError(t, err)
+	assert.Equal(t, orderID, order.ID)
+
+	recordRequestDuration(http.MethodGet, "/prices-history?market=123", 0)
+
+	// 标签为路由模板，不包含订单 hash 与查询参数
+	var routes []string
+	ch := make(chan prometheus.Metric, 1024)
+	requestDurationMetrics.Collect(ch)
+	close(ch)
+	for m := range ch {
+		var out dto.Metric
+		assert.NoError(t, m.Write(&out))
+		for _, label := range out.GetLabel() {
+			if label.GetName() == "path" {
+				routes = append(routes, label.GetValue())
+				assert.NotContains(t, label.GetValue(), orderID)
+				assert.NotContains(t, label.GetValue(), "?")
+			}
+		}
+	}
+	assert.Contains(t, routes, "/data/order/{id}")
+	assert.Contains(t, routes, "/prices-history")
+}
+
+// metricValue 读取 counter / gauge 的当前值
+func metricValue(t *testing.T, m prometheus.Metric) float64 {
+	var out dto.Metric
+	assert.NoError(t, m.Write(&out))
+	if c := out.GetCounter(); c != nil {
+		return c.GetValue()
+	}
+	return out.GetGauge().GetValue()
+}
@@ -0,0 +1,56 @@
+package polymarket
+
+import "strings"
+
+// neg-risk（多结果）市场通过 NegRiskAdapter 交易，订单由 NegRisk CTF Exchange 合约撮合，
+// 签名时 EIP-712 domain 的 verifyingContract 也要换成该合约。
+// - 通过 Gamma 发现的市场使用 Gamma 返回的 negRisk
+// - POLYMARKET_NEG_RISK_MARKETS：标记 markets 文件/JSON 中的 neg-risk 市场，格式 "SYMBOL_A,SYMBOL_B"
+
+const envNegRiskMarkets = "POLYMARKET_NEG_RISK_MARKETS"
+
+// Polygon 主网上的 CTF Exchange 合约地址
+const (
+	ctfExchangeAddress        = "0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"
+	negRiskCTFExchangeAddress = "0xC5d563A36AE78145C45a50134d48A1215220f80a"
+)
+
+// exchangeAddress 返回撮合订单的合约地址（即 EIP-712 domain 的 verifyingContract）。
+func exchangeAddress(negRisk bool) string {
+	if negRisk {
+		return negRiskCTFExchangeAddress
+	}
+	return ctfExchangeAddress
+}
+
+func parseNegRiskMarkets(s string) map[string]bool {
+	out := make(map[string]bool)
+	for _, symbol := range strings.Split(s, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			out[symbol] = true
+		}
+	}
+	return out
+}
+
+// outcomeToken 是 symbol 对应的 CLOB outcome token。
+type outcomeToken struct {
+	TokenID string
+	NegRisk bool
+}
+
+// tokenOf 返回 symbol 对应的 CLOB token（token id 为 market 的 LocalSymbol）。
+func (e *Exchange) tokenOf(symbol string) (outcomeToken, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.tokenOfLocked(symbol)
+}
+
+// tokenOfLocked 同 tokenOf，需要持有 e.mu。
+func (e *Exchange) tokenOfLocked(symbol string) (outcomeToken, bool) {
+	m, ok := e.markets[symbol]
+	if !ok || !isTokenID(m.LocalSymbol) {
+		return outcomeToken{}, false
+	}
+	return outcomeToken{TokenID: m.LocalSymbol, NegRisk: e.negRisk[symbol]}, true
+}
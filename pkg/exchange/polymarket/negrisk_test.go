@@ -0,0 +1,55 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_BuildOrderNegRisk(t *testing.T) {
+	t.Setenv(envNegRiskMarkets, "PM_NEG, ")
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `[{
+			"outcomes": "[\"Up\", \"Down\"]",
+			"clobTokenIds": "[\"333333333333\", \"444444444444\"]",
+			"negRisk": true
+		}]`), nil
+	})
+
+	ex := New("", "", "")
+	ex.gamma = newTestRestClient(transport)
+	ex.markets = types.MarketMap{
+		"PM_NEG":   {Symbol: "PM_NEG", LocalSymbol: "111111111111"},
+		"PM_PLAIN": {Symbol: "PM_PLAIN", LocalSymbol: "222222222222"},
+	}
+
+	um, err := ex.DiscoverUpDownMarket(context.Background(), "btc", types.Interval15m, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, um.NegRisk)
+
+	for symbol, negRisk := range map[string]bool{
+		"PM_NEG":     true,
+		"PM_PLAIN":   false,
+		um.YesSymbol: true,
+	} {
+		order, err := ex.buildOrder(types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Price:    fixedpoint.NewFromFloat(0.5),
+			Quantity: fixedpoint.NewFromFloat(10),
+		})
+		if assert.NoError(t, err, symbol) {
+			assert.Equal(t, negRisk, order.NegRisk, symbol)
+			assert.Equal(t, exchangeAddress(negRisk), order.ExchangeAddress(), symbol)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// 订单 nonce：CTF Exchange 合约为每个 maker 记录一个 nonce，订单里的 nonce 必须等于链上的当前值；
+// maker 调用合约的 incrementNonce() 后，之前签名的订单全部失效（一次性在链上撤销所有订单）。
+// - 真实下单时，每个撮合合约（CTF Exchange / NegRisk CTF Exchange）第一次用到 nonce 时通过 POLYMARKET_RPC_URL
+//   查询钱包（POLYMARKET_WALLET_ADDRESS）的 nonces(address)，之后使用本地缓存，一批订单共用同一个 nonce
+// - POLYMARKET_ORDER_NONCE 显式指定 nonce（两个合约相同），不再查询链上；运行中可以用 Exchange.SetNonce 覆盖
+// - Exchange.BumpNonce 使所有已签名的订单失效：dry-run 把本地 nonce 加 1 并撤销全部模拟订单；
+//   真实下单依次向两个撮合合约发送 incrementNonce() 交易并等待打包（见 transactions.go），之后清空本地缓存，
+//   下一笔订单重新查询链上 nonce
+
+const envOrderNonce = "POLYMARKET_ORDER_NONCE"
+
+const (
+	selectorNonces         = "7ecebe00" // nonces(address)
+	selectorIncrementNonce = "627cdcb9" // incrementNonce()
+)
+
+type nonceManager struct {
+	mu sync.Mutex
+	// nonces 以撮合合约地址为 key
+	nonces map[string]uint64
+}
+
+func newNonceManagerFromEnv() *nonceManager {
+	m := &nonceManager{nonces: make(map[string]uint64)}
+
+	if v := envString(envOrderNonce, ""); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			log.WithError(err).Warnf("invalid %s: %q", envOrderNonce, v)
+		} else {
+			m.setAll(n)
+		}
+	}
+	return m
+}
+
+func (m *nonceManager) get(contract string) (uint64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nonces[contract]
+	return n, ok
+}
+
+func (m *nonceManager) set(contract string, n uint64) {
+	m.mu.Lock()
+	m.nonces[contract] = n
+	m.mu.Unlock()
+}
+
+func (m *nonceManager) setAll(n uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, contract := range []string{ctfExchangeAddress, negRiskCTFExchangeAddress} {
+		m.nonces[contract] = n
+	}
+}
+
+// bump 把两个合约的 nonce 都加 1，没有缓存的合约从 0 开始。
+func (m *nonceManager) bump() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, contract := range []string{ctfExchangeAddress, negRiskCTFExchangeAddress} {
+		m.nonces[contract]++
+	}
+}
+
+func (m *nonceManager) reset() {
+	m.mu.Lock()
+	m.nonces = make(map[string]uint64)
+	m.mu.Unlock()
+}
+
+// queryNonce 查询 owner 在撮合合约上的当前 nonce。
+func (c *restClient) queryNonce(ctx context.Context, contract, owner string) (uint64, error) {
+	v, err := c.ethCall(ctx, contract, encodeCall(selectorNonces, owner))
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("polymarket: nonce %s of %s is out of range", v.String(), owner)
+	}
+	return v.Uint64(), nil
+}
+
+// orderNonce 返回 negRisk 对应撮合合约的订单 nonce，没有缓存时：dry-run 为 0，真实下单查询链上。
+func (e *Exchange) orderNonce(ctx context.Context, negRisk bool) (uint64, error) {
+	contract := exchangeAddress(negRisk)
+	if n, ok := e.nonces.get(contract); ok {
+		return n, nil
+	}
+
+	if e.IsDryRun() {
+		return 0, nil
+	}
+
+	owner := envString(envWalletAddress, "")
+	if owner == "" {
+		return 0, fmt.Errorf("polymarket: %s or %s is required to sign orders", envWalletAddress, envOrderNonce)
+	}
+
+	n, err := e.rpc.queryNonce(ctx, contract, owner)
+	if err != nil {
+		return 0, err
+	}
+
+	e.nonces.set(contract, n)
+	log.Infof("polymarket wallet %s order nonce on %s: %d", owner, contract, n)
+	return n, nil
+}
+
+// assignNonces 填充一批 CLOB 订单的 nonce，每个撮合合约只查询一次。
+func (e *Exchange) assignNonces(ctx context.Context, orders ...*CLOBOrder) error {
+	for _, o := range orders {
+		if o == nil {
+			continue
+		}
+
+		n, err := e.orderNonce(ctx, o.NegRisk)
+		if err != nil {
+			return err
+		}
+		o.Nonce = strconv.FormatUint(n, 10)
+	}
+	return nil
+}
+
+// SetNonce 显式设置之后签名订单使用的 nonce（两个撮合合约相同），例如在链上手动 incrementNonce 之后。
+func (e *Exchange) SetNonce(nonce uint64) {
+	e.nonces.setAll(nonce)
+}
+
+// BumpNonce 使所有已签名、未成交的订单一次性失效，见文件开头的说明。
+func (e *Exchange) BumpNonce(ctx context.Context) error {
+	if err := e.checkWritable(); err != nil {
+		return err
+	}
+
+	if e.IsDryRun() {
+		e.nonces.bump()
+		n, _ := e.nonces.get(ctfExchangeAddress)
+		log.Infof("polymarket(dry-run) order nonce bumped to %d, canceling all orders", n)
+		return e.CancelAllOrders(ctx, "")
+	}
+
+	// 无论交易是否成功都清空缓存，下一笔订单重新查询链上 nonce
+	defer e.nonces.reset()
+
+	data, _ := hex.DecodeString(selectorIncrementNonce)
+	for _, contract := range []string{ctfExchangeAddress, negRiskCTFExchangeAddress} {
+		hash, err := e.sendTransaction(ctx, contract, data)
+		if err != nil {
+			return fmt.Errorf("polymarket: incrementNonce() on %s failed: %w", contract, err)
+		}
+		log.Infof("polymarket incrementNonce() on %s confirmed: %s", contract, hash)
+	}
+	return nil
+}
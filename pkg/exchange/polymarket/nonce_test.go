@@ -0,0 +1,168 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_OrderNonce(t *testing.T) {
+	const owner = "0x1111111111111111111111111111111111111111"
+	t.Setenv(envDryRun, "false")
+	t.Setenv(envWalletAddress, owner)
+
+	onChain := map[string]string{ctfExchangeAddress: "0x3", negRiskCTFExchangeAddress: "0x5"}
+	calls := 0
+	transport := &httptesting.MockTransport{}
+	transport.POST("", func(req *http.Request) (*http.Response, error) {
+		calls++
+		var rpcReq rpcRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+
+		call := rpcReq.Params[0].(map[string]interface{})
+		assert.Equal(t, encodeCall(selectorNonces, owner), call["data"])
+		return httptesting.BuildResponseString(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"`+onChain[call["to"].(string)]+`"}`), nil
+	})
+
+	ex := New("", "", "")
+	ex.rpc = newTestRestClient(transport)
+	ctx := context.Background()
+
+	// 一批订单每个撮合合约只查询一次
+	orders := []*CLOBOrder{{}, {NegRisk: true}, {}, nil}
+	assert.NoError(t, ex.assignNonces(ctx, orders...))
+	assert.Equal(t, "3", orders[0].Nonce)
+	assert.Equal(t, "5", orders[1].Nonce)
+	assert.Equal(t, "3", orders[2].Nonce)
+	assert.Equal(t, 2, calls)
+
+	// 显式指定的 nonce 不查询链上
+	ex.SetNonce(9)
+	n, err := ex.orderNonce(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), n)
+	assert.Equal(t, 2, calls)
+
+	// 没有私钥时 BumpNonce 不能发送交易，同样清空缓存，之后重新查询链上
+	err = ex.BumpNonce(ctx)
+	assert.ErrorContains(t, err, "private key is required")
+
+	onChain[ctfExchangeAddress] = "0x4"
+	n, err = ex.orderNonce(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), n)
+	assert.Equal(t, 3, calls)
+}
+
+func TestExchange_OrderNonceFromEnv(t *testing.T) {
+	t.Setenv(envDryRun, "false")
+	t.Setenv(envOrderNonce, "7")
+
+	ex := New("", "", "")
+	n, err := ex.orderNonce(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), n)
+}
+
+func TestExchange_BumpNonceDryRun(t *testing.T) {
+	ex := New("", "", "")
+	ctx := context.Background()
+
+	for _, price := range []float64{0.4, 0.5} {
+		_, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   "PM_BTC_15M_UP_YES_USDC",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(10),
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, ex.BumpNonce(ctx))
+
+	openOrders, err := ex.QueryOpenOrders(ctx, "PM_BTC_15M_UP_YES_USDC")
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+
+	n, err := ex.orderNonce(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), n)
+}
+
+func TestExchange_BumpNonceLive(t *testing.T) {
+	t.Setenv(envDryRun, "false")
+	t.Setenv(envWalletAddress, testAddress)
+	t.Setenv(envTxPollInterval, "1ms")
+
+	ex, err := NewWithPrivateKey("", "", "", testPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	receipts := 0
+	status := "0x1"
+	rpc, client := newMockRPC(t, map[string]func(params []interface{}) interface{}{
+		"eth_getTransactionCount": func(params []interface{}) interface{} { return "0x5" },
+		"eth_gasPrice":            func(params []interface{}) interface{} { return "0x6fc23ac00" },
+		"eth_estimateGas":         func(params []interface{}) interface{} { return "0xc350" },
+		"eth_sendRawTransaction":  func(params []interface{}) interface{} { return "0xhash" },
+		"eth_getTransactionReceipt": func(params []interface{}) interface{} {
+			// 每笔交易第一次查询时还没有打包
+			receipts++
+			if receipts%2 == 1 {
+				return nil
+			}
+			return map[string]string{"status": status, "blockNumber": "0x10"}
+		},
+	})
+	ex.rpc = client
+	ex.SetNonce(3)
+
+	ctx := context.Background()
+	assert.NoError(t, ex.BumpNonce(ctx))
+	assert.Equal(t, 4, receipts)
+
+	// 依次向两个撮合合约发送签名的 incrementNonce() 交易
+	sent := rpc.params("eth_sendRawTransaction")
+	if assert.Len(t, sent, 2) {
+		key, _ := parsePrivateKey(testPrivateKey)
+		for i, contract := range []string{ctfExchangeAddress, negRiskCTFExchangeAddress} {
+			data, _ := hex.DecodeString(selectorIncrementNonce)
+			raw, err := (&signer{key: key}).signTx(&legacyTx{
+				Nonce:    5,
+				GasPrice: big.NewInt(30000000000),
+				Gas:      60000,
+				To:       contract,
+				Data:     data,
+			}, polygonChainID)
+			assert.NoError(t, err)
+			assert.Equal(t, "0x"+hex.EncodeToString(raw), sent[i][0])
+		}
+	}
+	if estimates := rpc.params("eth_estimateGas"); assert.Len(t, estimates, 2) {
+		call := estimates[0][0].(map[string]interface{})
+		assert.Equal(t, testAddress, call["from"])
+		assert.Equal(t, "0x"+selectorIncrementNonce, call["data"])
+	}
+
+	// 本地缓存已清空，下一笔订单重新查询链上 nonce
+	_, ok := ex.nonces.get(ctfExchangeAddress)
+	assert.False(t, ok)
+
+	status = "0x0"
+	assert.ErrorContains(t, ex.BumpNonce(ctx), "reverted")
+
+	// proxy 钱包的 nonce 属于 proxy 合约，不能由私钥地址发送
+	t.Setenv(envWalletAddress, "0x1111111111111111111111111111111111111111")
+	assert.ErrorContains(t, ex.BumpNonce(ctx), "send the transaction from the wallet itself")
+}
@@ -0,0 +1,101 @@
+package polymarket
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 构造选项：在代码里（例如测试、嵌入到其它程序）创建 Exchange 时不必设置环境变量。
+// 没有通过选项指定的配置仍然从环境变量读取：
+//
+//	ex := polymarket.New(key, secret, passphrase,
+//		polymarket.WithDryRun(true),
+//		polymarket.WithMarkets(markets),
+//		polymarket.WithBaseURL("http://127.0.0.1:8080"),
+//	)
+//
+// WithPrivateKey 需要通过 NewWithOptions 创建（私钥解析可能失败），New 会忽略它。
+
+// Option 为 Exchange 的构造选项。
+type Option func(o *options)
+
+type options struct {
+	dryRun     *bool
+	markets    types.MarketMap
+	httpClient *http.Client
+	baseURL    string
+	privateKey string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDryRun 指定是否 dry-run，覆盖 POLYMARKET_DRY_RUN。
+func WithDryRun(dryRun bool) Option {
+	return func(o *options) {
+		o.dryRun = &dryRun
+	}
+}
+
+// WithMarkets 指定 market 列表，覆盖 POLYMARKET_MARKETS_FILE / POLYMARKET_MARKETS_JSON（不会监听 markets 文件）。
+func WithMarkets(markets types.MarketMap) Option {
+	return func(o *options) {
+		o.markets = markets
+	}
+}
+
+// WithHTTPClient 指定 CLOB、Gamma 与 Polygon RPC 请求使用的 http.Client，覆盖 POLYMARKET_HTTP_TIMEOUT。
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithBaseURL 指定 CLOB API 的地址，覆盖 POLYMARKET_CLOB_URL。
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithPrivateKey 指定真实下单签名用的钱包私钥（hex），覆盖 POLYMARKET_PRIVATE_KEY / keystore，只对 NewWithOptions 生效。
+func WithPrivateKey(privateKey string) Option {
+	return func(o *options) {
+		o.privateKey = privateKey
+	}
+}
+
+// applyOptions 把构造选项应用到新创建的 Exchange。
+func (e *Exchange) applyOptions(o *options) {
+	if o.dryRun != nil {
+		e.dryRun.Store(*o.dryRun)
+	}
+
+	if o.httpClient != nil {
+		for _, c := range []*restClient{e.client, e.gamma, e.rpc} {
+			c.httpClient = o.httpClient
+		}
+	}
+	if o.baseURL != "" {
+		e.client.baseURL = o.baseURL
+	}
+
+	if len(o.markets) > 0 {
+		markets := make(types.MarketMap, len(o.markets))
+		for symbol, m := range o.markets {
+			markets[symbol] = m
+		}
+		normalizeMarkets(markets)
+
+		e.mu.Lock()
+		e.setMarketsLocked(markets)
+		e.mu.Unlock()
+	}
+}
@@ -0,0 +1,127 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type countingTransport struct {
+	count int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.count++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNew_WithOptions(t *testing.T) {
+	t.Setenv(envDryRun, "true")
+	t.Setenv(envClobURL, "http://127.0.0.1:1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/book", r.URL.Path)
+		assert.Equal(t, "111111111111", r.URL.Query().Get("token_id"))
+		_, _ = w.Write([]byte(`{"bids": [{"price": "0.48", "size": "10"}], "asks": [{"price": "0.52", "size": "10"}], "timestamp": "1757908892351"}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	markets := types.MarketMap{
+		"PM_YES": {LocalSymbol: "111111111111", QuoteCurrency: "USDC", PricePrecision: 2, TickSize: fixedpoint.NewFromFloat(0.01)},
+	}
+	ex := New("", "", "",
+		WithDryRun(false),
+		WithMarkets(markets),
+		WithHTTPClient(&http.Client{Transport: transport, Timeout: time.Second}),
+		WithBaseURL(server.URL+"/"),
+	)
+	defer ex.Close()
+
+	assert.False(t, ex.IsDryRun())
+
+	loaded, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, loaded, 1) {
+		assert.Equal(t, "PM_YES", loaded["PM_YES"].Symbol)
+		assert.Equal(t, types.ExchangePolymarket, loaded["PM_YES"].Exchange)
+	}
+	// 不修改传入的 market 列表
+	assert.Empty(t, markets["PM_YES"].Symbol)
+
+	ticker, err := ex.QueryTicker(context.Background(), "PM_YES")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.48", ticker.Buy.String())
+		assert.Equal(t, "0.52", ticker.Sell.String())
+	}
+	assert.Equal(t, 1, transport.count)
+}
+
+func TestNew_EnvFallback(t *testing.T) {
+	t.Setenv(envDryRun, "false")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	assert.False(t, ex.IsDryRun())
+
+	ex = New("", "", "", WithDryRun(true))
+	defer ex.Close()
+	assert.True(t, ex.IsDryRun())
+}
+
+func TestExchange_SetDryRun(t *testing.T) {
+	t.Setenv(envDryRun, "true")
+
+	markets := types.MarketMap{
+		"PM_YES": {LocalSymbol: "111111111111", QuoteCurrency: "USDC", PricePrecision: 2, TickSize: fixedpoint.NewFromFloat(0.01)},
+	}
+	dryRun := New("", "", "", WithMarkets(markets))
+	defer dryRun.Close()
+	live := New("", "", "", WithMarkets(markets))
+	defer live.Close()
+
+	live.SetDryRun(false)
+	assert.True(t, dryRun.IsDryRun())
+	assert.False(t, live.IsDryRun())
+
+	// 创建之后修改环境变量不影响已创建的 Exchange
+	t.Setenv(envDryRun, "false")
+	assert.True(t, dryRun.IsDryRun())
+
+	order := types.SubmitOrder{
+		Symbol:   "PM_YES",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromInt(10),
+	}
+	created, err := dryRun.SubmitOrder(context.Background(), order)
+	if assert.NoError(t, err) {
+		assert.True(t, created.IsDryRun)
+	}
+
+	// live 模式没有钱包私钥，不能签名下单
+	_, err = live.SubmitOrder(context.Background(), order)
+	assert.Error(t, err)
+}
+
+func TestNewWithOptions_PrivateKey(t *testing.T) {
+	t.Setenv(envPrivateKey, "")
+
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(true))
+	if assert.NoError(t, err) {
+		defer ex.Close()
+		assert.NotNil(t, ex.signer)
+		assert.True(t, ex.IsDryRun())
+	}
+
+	_, err = NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey("invalid"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package polymarket
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// USDC 与 CTF outcome token 都是 6 位小数，CLOB 订单里的 makerAmount / takerAmount 使用最小单位。
+var amountScale = fixedpoint.NewFromInt(1_000_000)
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// CLOBOrder 是 CLOB POST /order 请求中的 order 结构，Maker/Signer/Signature 由签名时填充。
+type CLOBOrder struct {
+	Salt          int64  `json:"salt"`
+	Maker         string `json:"maker"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TokenID       string `json:"tokenId"`
+	MakerAmount   string `json:"makerAmount"`
+	TakerAmount   string `json:"takerAmount"`
+	Expiration    string `json:"expiration"`
+	Nonce         string `json:"nonce"`
+	FeeRateBps    string `json:"feeRateBps"`
+	Side          string `json:"side"`
+	SignatureType int    `json:"signatureType"`
+	Signature     string `json:"signature,omitempty"`
+
+	// ClientOrderID 为调用方指定的订单引用，用于对账；不属于请求体
+	ClientOrderID string `json:"-"`
+
+	// PostOnly 为 true 时提交请求带上 postOnly，会吃单的订单被 CLOB 拒绝；不属于 order 结构
+	PostOnly bool `json:"-"`
+
+	// OrderType 为提交请求中的 orderType（GTC / FAK / FOK，见 ioc.go）；不属于 order 结构
+	OrderType string `json:"-"`
+
+	// NegRisk 为 true 时订单需要提交给 NegRisk CTF Exchange，签名 domain 也随之不同；不属于请求体
+	NegRisk bool `json:"-"`
+}
+
+// ExchangeAddress 返回签名时 EIP-712 domain 的 verifyingContract。
+func (o *CLOBOrder) ExchangeAddress() string {
+	return exchangeAddress(o.NegRisk)
+}
+
+// buildOrder 把 bbgo 的 SubmitOrder 转换成 CLOB 订单：
+// - 买单 maker 付出 USDC（price * size），taker 给出 outcome token（size）
+// - 卖单相反
+// - feeRateBps 取该 symbol 的费率（见 fee.go）
+// - neg-risk 市场会标记 NegRisk，决定撮合合约（见 negrisk.go）
+// - salt 由 client order id 决定（见 orderSalt）
+// - nonce 在签名前由 assignNonces 填充（见 nonce.go）
+// - LIMIT_MAKER 订单标记 PostOnly（见 postonly.go），post-only 只支持挂单类的 GTC/GTD
+// - TimeInForce 映射成提交请求的 orderType，IOC 为 FAK（见 ioc.go）
+func (e *Exchange) buildOrder(order types.SubmitOrder) (*CLOBOrder, error) {
+	token, ok := e.tokenOf(order.Symbol)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: market %s has no CLOB token id (localSymbol)", order.Symbol)
+	}
+
+	if order.Price.Sign() <= 0 || order.Quantity.Sign() <= 0 {
+		return nil, fmt.Errorf("polymarket: invalid price %s or quantity %s", order.Price.String(), order.Quantity.String())
+	}
+
+	postOnly := isPostOnly(order)
+	if postOnly && (order.TimeInForce == types.TimeInForceIOC || order.TimeInForce == types.TimeInForceFOK) {
+		return nil, fmt.Errorf("polymarket: post-only order does not support time in force %s", order.TimeInForce)
+	}
+
+	size := order.Quantity
+	quote := order.Price.Mul(size)
+
+	var side string
+	var makerAmount, takerAmount fixedpoint.Value
+	switch order.Side {
+	case types.SideTypeBuy:
+		side = "BUY"
+		makerAmount, takerAmount = quote, size
+	case types.SideTypeSell:
+		side = "SELL"
+		makerAmount, takerAmount = size, quote
+	default:
+		return nil, fmt.Errorf("polymarket: unsupported order side %s", order.Side)
+	}
+
+	return &CLOBOrder{
+		Salt:        orderSalt(order.ClientOrderID),
+		Taker:       zeroAddress,
+		TokenID:     token.TokenID,
+		MakerAmount: toBaseUnits(makerAmount),
+		TakerAmount: toBaseUnits(takerAmount),
+		Expiration:  "0",
+		Nonce:       "0",
+		FeeRateBps:  strconv.Itoa(e.fees.feeRateBps(order.Symbol)),
+		Side:        side,
+		NegRisk:     token.NegRisk,
+		PostOnly:    postOnly,
+		OrderType:   clobOrderType(order.TimeInForce),
+
+		ClientOrderID: order.ClientOrderID,
+	}, nil
+}
+
+// orderSalt 返回订单的 salt：指定了 client order id 时由它决定，使重复提交得到相同的订单 hash，否则随机生成。
+func orderSalt(clientOrderID string) int64 {
+	if clientOrderID == "" {
+		return rand.Int63()
+	}
+	return int64(hashStringID(clientOrderID) >> 1)
+}
+
+func toBaseUnits(v fixedpoint.Value) string {
+	return strconv.FormatInt(v.Mul(amountScale).Trunc().Int64(), 10)
+}
@@ -0,0 +1,88 @@
+package polymarket
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 单笔订单的硬上限：作为策略出错时的最后一道防线（例如 QuoteAmount / 极低价格算出巨大的数量），
+// 不论哪个策略下单，超出上限的订单在 dry-run 与 live 中都直接拒绝，返回 *OrderLimitError。
+// - POLYMARKET_MAX_ORDER_QUANTITY 单笔订单的最大数量（份数），POLYMARKET_MAX_ORDER_NOTIONAL 单笔订单的最大金额（price * quantity，USDC）
+// - 默认 0 表示不限制；等于上限的订单可以下单
+// - 在 prepareOrder 中检查（价格取整之后），SubmitOrder、SubmitOrders、改单与 SimulateOrder 都会检查
+// 配置无效时不限制并由 ValidateConfig 报告。
+
+const (
+	envMaxOrderQuantity = "POLYMARKET_MAX_ORDER_QUANTITY"
+	envMaxOrderNotional = "POLYMARKET_MAX_ORDER_NOTIONAL"
+
+	OrderLimitQuantity = "quantity"
+	OrderLimitNotional = "notional"
+)
+
+// OrderLimitError 是订单超出 POLYMARKET_MAX_ORDER_QUANTITY / POLYMARKET_MAX_ORDER_NOTIONAL 时返回的错误。
+type OrderLimitError struct {
+	Symbol string
+	// Limit 为超出的上限：quantity 或 notional
+	Limit string
+	Value fixedpoint.Value
+	Max   fixedpoint.Value
+}
+
+func (e *OrderLimitError) Error() string {
+	return fmt.Sprintf("polymarket: order %s %s of %s exceeds the max order %s %s",
+		e.Limit, e.Value.String(), e.Symbol, e.Limit, e.Max.String())
+}
+
+// orderCap 为单笔订单的上限，零值表示不限制。
+type orderCap struct {
+	maxQuantity fixedpoint.Value
+	maxNotional fixedpoint.Value
+}
+
+// loadOrderCap 读取订单上限，取值无效时返回错误。
+func loadOrderCap() (orderCap, error) {
+	var c orderCap
+	for _, item := range []struct {
+		env string
+		v   *fixedpoint.Value
+	}{
+		{envMaxOrderQuantity, &c.maxQuantity},
+		{envMaxOrderNotional, &c.maxNotional},
+	} {
+		s := envString(item.env, "")
+		if s == "" {
+			continue
+		}
+
+		v, err := fixedpoint.NewFromString(s)
+		if err != nil || v.Sign() < 0 {
+			return orderCap{}, fmt.Errorf("%s %q is invalid, should be a non-negative number", item.env, s)
+		}
+		*item.v = v
+	}
+	return c, nil
+}
+
+// newOrderCapFromEnv 返回配置的订单上限，配置无效时不限制。
+func newOrderCapFromEnv() orderCap {
+	c, err := loadOrderCap()
+	if err != nil {
+		log.WithError(err).Warn("max order quantity/notional is not enforced")
+	}
+	return c
+}
+
+// check 检查订单的数量与金额是否超出上限。
+func (c orderCap) check(order types.SubmitOrder) error {
+	if c.maxQuantity.Sign() > 0 && order.Quantity.Compare(c.maxQuantity) > 0 {
+		return &OrderLimitError{Symbol: order.Symbol, Limit: OrderLimitQuantity, Value: order.Quantity, Max: c.maxQuantity}
+	}
+
+	if notional := order.Price.Mul(order.Quantity); c.maxNotional.Sign() > 0 && notional.Compare(c.maxNotional) > 0 {
+		return &OrderLimitError{Symbol: order.Symbol, Limit: OrderLimitNotional, Value: notional, Max: c.maxNotional}
+	}
+	return nil
+}
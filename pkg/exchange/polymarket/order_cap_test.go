@@ -0,0 +1,156 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestOrderCap_Check(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	newOrder := func(price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Price: f(price), Quantity: f(quantity)}
+	}
+
+	// 不限制
+	assert.NoError(t, orderCap{}.check(newOrder(0.5, 1e6)))
+
+	c := orderCap{maxQuantity: f(100), maxNotional: f(40)}
+
+	// 等于上限可以下单
+	assert.NoError(t, c.check(newOrder(0.4, 100)))
+	assert.NoError(t, c.check(newOrder(0.5, 80)))
+
+	var limitErr *OrderLimitError
+	err := c.check(newOrder(0.01, 100.01))
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, OrderLimitQuantity, limitErr.Limit)
+		assert.Equal(t, "100.01", limitErr.Value.String())
+		assert.Equal(t, "100", limitErr.Max.String())
+		assert.Equal(t, "polymarket: order quantity 100.01 of PM_YES exceeds the max order quantity 100", err.Error())
+	}
+
+	err = c.check(newOrder(0.5, 80.01))
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, OrderLimitNotional, limitErr.Limit)
+		assert.Equal(t, "40.005", limitErr.Value.String())
+		assert.Equal(t, "40", limitErr.Max.String())
+	}
+}
+
+func TestLoadOrderCap(t *testing.T) {
+	c, err := loadOrderCap()
+	if assert.NoError(t, err) {
+		assert.True(t, c.maxQuantity.IsZero())
+		assert.True(t, c.maxNotional.IsZero())
+	}
+
+	t.Setenv(envMaxOrderQuantity, "500")
+	t.Setenv(envMaxOrderNotional, "25.5")
+	c, err = loadOrderCap()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "500", c.maxQuantity.String())
+		assert.Equal(t, "25.5", c.maxNotional.String())
+	}
+
+	t.Setenv(envMaxOrderNotional, "-1")
+	_, err = loadOrderCap()
+	assert.ErrorContains(t, err, envMaxOrderNotional)
+
+	// 配置无效时不限制，由 ValidateConfig 报告
+	ex := New("", "", "")
+	defer ex.Close()
+	assert.True(t, ex.orderCap.maxQuantity.IsZero())
+
+	var configErr *ConfigError
+	if assert.True(t, errors.As(ex.ValidateConfig(), &configErr)) && assert.Len(t, configErr.Problems, 1) {
+		assert.Contains(t, configErr.Problems[0], envMaxOrderNotional)
+	}
+}
+
+func TestExchange_DryRunOrderCap(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	t.Setenv(envMaxOrderQuantity, "100")
+	t.Setenv(envMaxOrderNotional, "50")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ex.markets = types.MarketMap{
+		"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", StepSize: f(0.01), TickSize: f(0.01)},
+	}
+
+	newOrder := func(price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Type: types.OrderTypeLimit, Price: f(price), Quantity: f(quantity)}
+	}
+
+	ctx := context.Background()
+	var limitErr *OrderLimitError
+
+	_, err := ex.SubmitOrder(ctx, newOrder(0.5, 100))
+	assert.NoError(t, err)
+
+	_, err = ex.SubmitOrder(ctx, newOrder(0.5, 100.01))
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, OrderLimitQuantity, limitErr.Limit)
+	}
+
+	_, err = ex.SubmitOrder(ctx, newOrder(0.51, 99))
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, OrderLimitNotional, limitErr.Limit)
+		assert.Equal(t, "50.49", limitErr.Value.String())
+	}
+
+	_, err = ex.SimulateOrder(ctx, newOrder(0.1, 101))
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, OrderLimitQuantity, limitErr.Limit)
+	}
+
+	// 批量下单只拒绝超出上限的订单
+	orders, err := ex.SubmitOrders(ctx, newOrder(0.2, 50), newOrder(0.2, 100.01))
+	var batchErr *BatchOrderError
+	if assert.True(t, errors.As(err, &batchErr)) && assert.Len(t, orders, 2) {
+		assert.NotZero(t, orders[0].OrderID)
+		assert.Zero(t, orders[1].OrderID)
+		assert.NoError(t, batchErr.Errors[0])
+		assert.True(t, errors.As(batchErr.Errors[1], &limitErr))
+	}
+}
+
+func TestExchange_LiveOrderCap(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	t.Setenv(envMaxOrderNotional, "10")
+
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(false),
+		WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01)},
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	// 超出上限的订单不会发出请求
+	transport := &httptesting.MockTransport{}
+	transport.POST("/order", func(req *http.Request) (*http.Response, error) {
+		t.Error("order above the cap should not be posted")
+		return httptesting.BuildResponseString(http.StatusOK, `{"success":true,"orderID":"0xplaced","status":"live"}`), nil
+	})
+	ex.client = newTestRestClient(transport)
+	ex.client.auth = newAPICredentials("key", "c2VjcmV0", "pass")
+
+	_, err = ex.SubmitOrder(context.Background(), types.SubmitOrder{Symbol: "PM_YES", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+		Price: f(0.5), Quantity: f(20.02)})
+	var limitErr *OrderLimitError
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, OrderLimitNotional, limitErr.Limit)
+		assert.Equal(t, "10.01", limitErr.Value.String())
+		assert.Equal(t, "10", limitErr.Max.String())
+	}
+}
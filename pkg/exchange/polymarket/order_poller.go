@@ -0,0 +1,135 @@
+package polymarket
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// user websocket 不可用时的订单状态轮询：live 的 user data stream 断线期间（State 为 disconnected，包括重连中与放弃重连），
+// 每隔 POLYMARKET_ORDER_POLL_INTERVAL（默认 5s，0 表示关闭）轮询一次订单状态，推送与 user channel 相同的 OnOrderUpdate：
+// - 跟踪的订单为 user channel 推送过、仍在 working 的订单，以及轮询时查到的挂单
+// - QueryOpenOrders 查到的挂单成交量或状态有变化时推送订单更新（部分成交）
+// - 跟踪的订单不在挂单列表中时用 QueryOrder 查询最终状态（全部成交或撤单）并推送
+// - 有新的成交时查询余额并推送 BalanceUpdate
+// - 跟踪的 working 订单数按 symbol 记录到 polymarket_open_orders{mode="live"}
+// - websocket 恢复后再轮询一次，补上断线到重连之间的变化（user channel 不会补推断线期间的事件）
+
+const (
+	envOrderPollInterval = "POLYMARKET_ORDER_POLL_INTERVAL"
+
+	defaultOrderPollInterval = 5 * time.Second
+)
+
+// trackOrder 记录 working 订单的最新状态，订单结束后不再跟踪，并刷新该 symbol 的 live 挂单数指标。
+func (s *Stream) trackOrder(o types.Order) {
+	if o.UUID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o.IsWorking {
+		s.trackedOrders[o.UUID] = o
+	} else {
+		delete(s.trackedOrders, o.UUID)
+	}
+
+	n := 0
+	for _, tracked := range s.trackedOrders {
+		if tracked.Symbol == o.Symbol {
+			n++
+		}
+	}
+	openOrdersMetrics.With(prometheus.Labels{"symbol": o.Symbol, "mode": "live"}).Set(float64(n))
+}
+
+// orderChanged 判断轮询到的订单相对上次的状态是否有变化。
+func orderChanged(prev, current types.Order) bool {
+	return prev.Status != current.Status || prev.ExecutedQuantity.Compare(current.ExecutedQuantity) != 0
+}
+
+// runOrderPoller 在 websocket 断线期间定期轮询订单状态，ctx 取消或 stream 关闭时退出。
+func (s *Stream) runOrderPoller(ctx context.Context) {
+	ticker := time.NewTicker(s.orderPollInterval)
+	defer ticker.Stop()
+
+	wasDown := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.CloseC:
+			return
+		case <-ticker.C:
+			down := s.State() == StreamStateDisconnected
+			if down || wasDown {
+				if err := s.pollOrders(ctx); err != nil {
+					log.WithError(err).Warn("failed to poll order status while user channel is down")
+				}
+			}
+			wasDown = down
+		}
+	}
+}
+
+// pollOrders 轮询一次挂单与跟踪订单的状态，推送有变化的订单。
+func (s *Stream) pollOrders(ctx context.Context) error {
+	open, err := s.queryOpenOrders(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	tracked := make(map[string]types.Order, len(s.trackedOrders))
+	for id, o := range s.trackedOrders {
+		tracked[id] = o
+	}
+	s.mu.Unlock()
+
+	var updates []types.Order
+	filled := false
+	seen := make(map[string]bool, len(open))
+	for _, o := range open {
+		seen[o.UUID] = true
+		prev, ok := tracked[o.UUID]
+		switch {
+		case ok && orderChanged(prev, o), !ok && o.ExecutedQuantity.Sign() > 0:
+			updates = append(updates, o)
+			filled = filled || o.ExecutedQuantity.Compare(prev.ExecutedQuantity) > 0
+		case !ok:
+			// 断线期间新下的挂单，开始跟踪
+			s.trackOrder(o)
+		}
+	}
+
+	for id, prev := range tracked {
+		if seen[id] {
+			continue
+		}
+
+		o, err := s.queryOrder(ctx, types.OrderQuery{Symbol: prev.Symbol, OrderUUID: id})
+		if err != nil {
+			log.WithError(err).Warnf("failed to query order %s, will retry on next poll", id)
+			continue
+		}
+		if orderChanged(prev, *o) {
+			updates = append(updates, *o)
+			filled = filled || o.ExecutedQuantity.Compare(prev.ExecutedQuantity) > 0
+		}
+	}
+
+	for _, o := range updates {
+		log.WithFields(o.LogFields()).Infof("polled order update: %s", o.String())
+		s.EmitOrderUpdate(o)
+	}
+
+	if filled && s.queryBalances != nil {
+		go s.emitBalances()
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package polymarket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStream_PollOrders(t *testing.T) {
+	const tokenID = "111111111111"
+	f := fixedpoint.NewFromFloat
+
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(false),
+		WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01)},
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	mock := &mockClobClient{}
+	ex.clob = mock
+
+	stream := ex.NewStream().(*Stream)
+	stream.queryBalances = nil
+	var updates []types.Order
+	stream.OnOrderUpdate(func(o types.Order) { updates = append(updates, o) })
+
+	openOrder := func(id string, matched float64) OpenOrder {
+		return OpenOrder{ID: id, AssetID: tokenID, Side: "BUY", Price: f(0.4), OriginalSize: f(10), SizeMatched: f(matched), Status: "LIVE"}
+	}
+
+	// user channel 推送过的挂单
+	stream.EmitOrderUpdate(toGlobalOpenOrder(openOrder("0xa", 0), "PM_YES"))
+	stream.EmitOrderUpdate(toGlobalOpenOrder(openOrder("0xb", 0), "PM_YES"))
+	stream.EmitOrderUpdate(toGlobalOpenOrder(openOrder("0xc", 0), "PM_YES"))
+	updates = nil
+
+	ctx := context.Background()
+
+	// 0xa 部分成交，0xb 全部成交，0xc 被撤单，0xd 为断线期间新下的挂单
+	filled := openOrder("0xb", 10)
+	filled.Status = "MATCHED"
+	canceled := openOrder("0xc", 2)
+	canceled.Status = "CANCELED"
+	mock.openOrders = []OpenOrder{openOrder("0xa", 4), openOrder("0xd", 0)}
+	mock.orders = []OpenOrder{filled, canceled}
+
+	assert.NoError(t, stream.pollOrders(ctx))
+
+	statuses := make(map[string]types.OrderStatus)
+	for _, o := range updates {
+		statuses[o.UUID] = o.Status
+	}
+	assert.Equal(t, map[string]types.OrderStatus{
+		"0xa": types.OrderStatusPartiallyFilled,
+		"0xb": types.OrderStatusFilled,
+		"0xc": types.OrderStatusCanceled,
+	}, statuses)
+
+	stream.mu.Lock()
+	assert.Len(t, stream.trackedOrders, 2)
+	assert.Contains(t, stream.trackedOrders, "0xa")
+	assert.Contains(t, stream.trackedOrders, "0xd")
+	stream.mu.Unlock()
+
+	// 没有变化时不重复推送
+	updates = nil
+	assert.NoError(t, stream.pollOrders(ctx))
+	assert.Empty(t, updates)
+
+	// 查询失败的订单下次轮询重试
+	mock.openOrders = nil
+	mock.orders = nil
+	assert.NoError(t, stream.pollOrders(ctx))
+	assert.Empty(t, updates)
+	stream.mu.Lock()
+	assert.Len(t, stream.trackedOrders, 2)
+	stream.mu.Unlock()
+}
+
+func TestStream_OrderPollerOnlyWhenDisconnected(t *testing.T) {
+	stream := NewStream("key", "secret", "pass", false, nil)
+	stream.orderPollInterval = 5 * time.Millisecond
+
+	var mu sync.Mutex
+	polls := 0
+	stream.queryOpenOrders = func(ctx context.Context, symbol string) ([]types.Order, error) {
+		mu.Lock()
+		polls++
+		mu.Unlock()
+		return nil, nil
+	}
+	stream.queryOrder = func(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+		return nil, nil
+	}
+	countPolls := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls
+	}
+
+	stream.mu.Lock()
+	stream.state = StreamStateConnected
+	stream.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		stream.runOrderPoller(ctx)
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 0, countPolls())
+
+	stream.mu.Lock()
+	stream.state = StreamStateDisconnected
+	stream.mu.Unlock()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for countPolls() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Greater(t, countPolls(), 0)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("order poller did not exit after context cancellation")
+	}
+}
+
+func TestExchange_QueryLiveOrder(t *testing.T) {
+	const tokenID = "111111111111"
+	ex, err := NewWithOptions("key", "c2VjcmV0", "pass", WithPrivateKey(testPrivateKey), WithDryRun(false),
+		WithMarkets(types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC"},
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ex.Close()
+
+	ex.clob = &mockClobClient{orders: []OpenOrder{
+		{ID: "0xa", AssetID: tokenID, Side: "SELL", Price: fixedpoint.NewFromFloat(0.6), OriginalSize: fixedpoint.NewFromFloat(5),
+			SizeMatched: fixedpoint.NewFromFloat(5), Status: "MATCHED"},
+	}}
+	ctx := context.Background()
+
+	order, err := ex.QueryOrder(ctx, types.OrderQuery{OrderUUID: "0xa"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "PM_YES", order.Symbol)
+		assert.Equal(t, types.OrderStatusFilled, order.Status)
+		assert.False(t, order.IsWorking)
+	}
+
+	_, err = ex.QueryOrder(ctx, types.OrderQuery{OrderID: "1"})
+	assert.ErrorContains(t, err, "OrderUUID")
+
+	_, err = ex.QueryOrder(ctx, types.OrderQuery{OrderUUID: "0xmissing"})
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,86 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// client order id：
+// - SubmitOrder.ClientOrderID 会保存在创建的 types.Order 上（dry-run 原样带回）
+// - dry-run 下用已存在的 client order id 重复下单时直接返回原订单，不会重复创建，便于崩溃后幂等重试
+// - 真实下单时 client order id 决定 CLOB 订单的 salt（见 order_builder.go），相同参数重复提交得到相同的订单 hash
+// - CancelOrders / QueryOrder 在没有 OrderID 时按 client order id 查找订单
+
+// QueryOrder 按 OrderID 或 ClientOrderID 查询 dry-run 订单；live 时按 OrderUUID（CLOB 订单 hash）查询 CLOB。
+func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if !e.IsDryRun() {
+		return e.queryLiveOrder(ctx, q)
+	}
+
+	var orderID uint64
+	if q.OrderID != "" {
+		id, err := strconv.ParseUint(q.OrderID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: invalid order id %q: %w", q.OrderID, err)
+		}
+		orderID = id
+	}
+
+	if err := e.loadOrders(); err != nil {
+		return nil, err
+	}
+
+	o, ok := e.findOrder(orderID, q.ClientOrderID)
+	if !ok || (q.Symbol != "" && o.Symbol != q.Symbol) {
+		return nil, fmt.Errorf("polymarket: order not found (id %q, client order id %q)", q.OrderID, q.ClientOrderID)
+	}
+	return &o, nil
+}
+
+// QueryOrderTrades 返回 dry-run 订单的成交记录。
+func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) ([]types.Trade, error) {
+	o, err := e.QueryOrder(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	var trades []types.Trade
+	for _, t := range e.trades {
+		if t.OrderID == o.OrderID {
+			trades = append(trades, t)
+		}
+	}
+	return trades, nil
+}
+
+// queryLiveOrder 按订单 hash 查询 CLOB 订单，包括已经成交或撤销的订单。
+func (e *Exchange) queryLiveOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if q.OrderUUID == "" {
+		return nil, fmt.Errorf("polymarket: OrderUUID (CLOB order hash) is required to query live orders")
+	}
+	if e.client.auth == nil {
+		return nil, fmt.Errorf("polymarket: API key is required to query orders")
+	}
+
+	o, err := e.clobAPI().GetOrder(ctx, q.OrderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, ok := e.SymbolOfTokenID(o.AssetID)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: order %s has unknown asset id %s", q.OrderUUID, o.AssetID)
+	}
+	if q.Symbol != "" && symbol != q.Symbol {
+		return nil, fmt.Errorf("polymarket: order %s is of %s, not %s", q.OrderUUID, symbol, q.Symbol)
+	}
+
+	order := toGlobalOpenOrder(*o, symbol)
+	return &order, nil
+}
@@ -0,0 +1,77 @@
+package polymarket
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_ClientOrderID(t *testing.T) {
+	ctx := context.Background()
+	ex := New("", "", "")
+	stream := ex.NewStream()
+
+	updates := 0
+	stream.OnOrderUpdate(func(o types.Order) {
+		updates++
+	})
+
+	submit := types.SubmitOrder{
+		Symbol:        "PM_BTC_15M_UP_YES_USDC",
+		Side:          types.SideTypeBuy,
+		Type:          types.OrderTypeLimit,
+		Price:         fixedpoint.NewFromFloat(0.5),
+		Quantity:      fixedpoint.NewFromFloat(10),
+		ClientOrderID: "bet-1",
+	}
+	order, err := ex.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	assert.Equal(t, "bet-1", order.ClientOrderID)
+
+	// 重复提交返回原订单，不再创建或推送
+	again, err := ex.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	assert.Equal(t, order.OrderID, again.OrderID)
+
+	created, err := ex.SubmitOrders(ctx, submit)
+	assert.NoError(t, err)
+	if assert.Len(t, created, 1) {
+		assert.Equal(t, order.OrderID, created[0].OrderID)
+	}
+
+	open, err := ex.QueryOpenOrders(ctx, submit.Symbol)
+	assert.NoError(t, err)
+	assert.Len(t, open, 1)
+	assert.Equal(t, 1, updates)
+
+	found, err := ex.QueryOrder(ctx, types.OrderQuery{ClientOrderID: "bet-1"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, order.OrderID, found.OrderID)
+	}
+
+	found, err = ex.QueryOrder(ctx, types.OrderQuery{OrderID: strconv.FormatUint(order.OrderID, 10)})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "bet-1", found.ClientOrderID)
+	}
+
+	_, err = ex.QueryOrder(ctx, types.OrderQuery{ClientOrderID: "unknown"})
+	assert.Error(t, err)
+
+	// 只带 client order id 也能撤单
+	assert.NoError(t, ex.CancelOrders(ctx, types.Order{SubmitOrder: types.SubmitOrder{ClientOrderID: "bet-1"}}))
+	found, err = ex.QueryOrder(ctx, types.OrderQuery{ClientOrderID: "bet-1"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderStatusCanceled, found.Status)
+	}
+}
+
+func TestOrderSalt(t *testing.T) {
+	assert.Equal(t, orderSalt("bet-1"), orderSalt("bet-1"))
+	assert.NotEqual(t, orderSalt("bet-1"), orderSalt("bet-2"))
+	assert.GreaterOrEqual(t, orderSalt("bet-1"), int64(0))
+}
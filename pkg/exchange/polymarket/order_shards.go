@@ -0,0 +1,206 @@
+package polymarket
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 订单按 symbol 分片：
+// - 每个 symbol 的订单保存在各自的 orderShard 中，下单、改单、撤单、撮合与查询只锁订单所在的 shard，
+//   不同 market 的操作不会互相等待
+// - e.ordersMu 只保护 shard 列表与 order id / client order id → symbol 的索引，查找或登记订单时短暂持有
+// - e.balanceMu 保护各 shard 共用的 dry-run 余额、token 持仓、成交记录与清理计数，只在冻结、解冻、结算与记账时持有
+// - 持久化（persistence.go）在 shard 锁之外逐个 shard 复制 working 订单，e.saveMu 串行化写入，
+//   每次写入的快照都在调用方的修改之后复制，最后一次写入总是包含之前的所有修改
+// - 每个 symbol 只对应一个 outcome token，卖单冻结的 token 只需要统计该 symbol 的 shard（见 balance.go）
+// 加锁顺序：e.mu（读锁，market 元数据）→ shard.mu → e.ordersMu → e.balanceMu，持久化为 e.saveMu → shard.mu；
+// 同一时间最多持有一个 shard 的锁，需要遍历所有订单的操作（报表、余额汇总、持久化、清理）逐个 shard 加锁。
+
+// orderShard 为一个 symbol 的 dry-run 订单
+type orderShard struct {
+	mu     sync.Mutex
+	symbol string
+	orders map[uint64]*types.Order
+}
+
+func newOrderShard(symbol string) *orderShard {
+	return &orderShard{symbol: symbol, orders: make(map[uint64]*types.Order)}
+}
+
+// findLocked 按 OrderID 查找订单，orderID 为 0 时按 clientOrderID 查找，需要持有 sh.mu。
+func (sh *orderShard) findLocked(orderID uint64, clientOrderID string) (*types.Order, bool) {
+	if orderID != 0 {
+		o, ok := sh.orders[orderID]
+		return o, ok
+	}
+
+	if clientOrderID == "" {
+		return nil, false
+	}
+	for _, o := range sh.orders {
+		if o.ClientOrderID == clientOrderID {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// snapshot 返回 shard 中满足 filter 的订单副本，filter 为 nil 时返回全部。
+func (sh *orderShard) snapshot(filter func(o *types.Order) bool) (orders []types.Order) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for _, o := range sh.orders {
+		if filter == nil || filter(o) {
+			orders = append(orders, *o)
+		}
+	}
+	return orders
+}
+
+// updateMetricsLocked 刷新 symbol 的 working 订单数指标，需要持有 sh.mu。
+func (sh *orderShard) updateMetricsLocked() {
+	n := 0
+	for _, o := range sh.orders {
+		if o.IsWorking {
+			n++
+		}
+	}
+	openOrdersMetrics.With(prometheus.Labels{"symbol": sh.symbol, "mode": "dry_run"}).Set(float64(n))
+}
+
+// shard 返回 symbol 的订单分片，不存在时创建。
+func (e *Exchange) shard(symbol string) *orderShard {
+	if sh := e.lookupShard(symbol); sh != nil {
+		return sh
+	}
+
+	e.ordersMu.Lock()
+	defer e.ordersMu.Unlock()
+
+	sh, ok := e.shards[symbol]
+	if !ok {
+		sh = newOrderShard(symbol)
+		e.shards[symbol] = sh
+	}
+	return sh
+}
+
+// lookupShard 返回 symbol 的订单分片，还没有订单时返回 nil。
+func (e *Exchange) lookupShard(symbol string) *orderShard {
+	e.ordersMu.RLock()
+	defer e.ordersMu.RUnlock()
+	return e.shards[symbol]
+}
+
+// shardList 返回按 symbol 排序的所有订单分片。
+func (e *Exchange) shardList() []*orderShard {
+	e.ordersMu.RLock()
+	shards := make([]*orderShard, 0, len(e.shards))
+	for _, sh := range e.shards {
+		shards = append(shards, sh)
+	}
+	e.ordersMu.RUnlock()
+
+	sort.Slice(shards, func(i, j int) bool { return shards[i].symbol < shards[j].symbol })
+	return shards
+}
+
+// shardOf 按索引返回订单所在的分片，orderID 为 0 时按 clientOrderID 查找。
+func (e *Exchange) shardOf(orderID uint64, clientOrderID string) (*orderShard, bool) {
+	e.ordersMu.RLock()
+	var symbol string
+	var ok bool
+	if orderID != 0 {
+		symbol, ok = e.orderSymbols[orderID]
+	} else if clientOrderID != "" {
+		symbol, ok = e.clientOrderSymbols[clientOrderID]
+	}
+	sh := e.shards[symbol]
+	e.ordersMu.RUnlock()
+
+	return sh, ok && sh != nil
+}
+
+// findOrder 查找订单并返回副本，不能持有 shard 锁。
+func (e *Exchange) findOrder(orderID uint64, clientOrderID string) (types.Order, bool) {
+	sh, ok := e.shardOf(orderID, clientOrderID)
+	if !ok {
+		return types.Order{}, false
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	o, ok := sh.findLocked(orderID, clientOrderID)
+	if !ok {
+		return types.Order{}, false
+	}
+	return *o, true
+}
+
+// allOrders 逐个 shard 复制满足 filter 的订单，filter 为 nil 时返回全部，不能持有 shard 锁。
+func (e *Exchange) allOrders(filter func(o *types.Order) bool) (orders []types.Order) {
+	for _, sh := range e.shardList() {
+		orders = append(orders, sh.snapshot(filter)...)
+	}
+	return orders
+}
+
+// reserveClientOrderID 登记 client order id 属于 symbol，已经被其他 symbol 的订单使用时返回错误，需要持有 symbol 的 shard 锁。
+func (e *Exchange) reserveClientOrderID(clientOrderID, symbol string) error {
+	if clientOrderID == "" {
+		return nil
+	}
+
+	e.ordersMu.Lock()
+	defer e.ordersMu.Unlock()
+
+	if used, ok := e.clientOrderSymbols[clientOrderID]; ok && used != symbol {
+		return fmt.Errorf("polymarket(dry-run): client order id %q is already used by a %s order", clientOrderID, used)
+	}
+	e.clientOrderSymbols[clientOrderID] = symbol
+	return nil
+}
+
+// releaseClientOrderID 撤销 reserveClientOrderID 的登记（订单没有创建成功时）。
+func (e *Exchange) releaseClientOrderID(clientOrderID string) {
+	if clientOrderID == "" {
+		return
+	}
+
+	e.ordersMu.Lock()
+	defer e.ordersMu.Unlock()
+	delete(e.clientOrderSymbols, clientOrderID)
+}
+
+// indexOrderLocked 把订单加入 shard 与索引，需要持有 sh.mu。
+func (e *Exchange) indexOrderLocked(sh *orderShard, o *types.Order) {
+	sh.orders[o.OrderID] = o
+
+	e.ordersMu.Lock()
+	defer e.ordersMu.Unlock()
+
+	e.orderSymbols[o.OrderID] = sh.symbol
+	if o.ClientOrderID != "" {
+		e.clientOrderSymbols[o.ClientOrderID] = sh.symbol
+	}
+}
+
+// unindexOrderLocked 把订单从 shard 与索引中删除，需要持有 sh.mu。
+func (e *Exchange) unindexOrderLocked(sh *orderShard, o *types.Order) {
+	delete(sh.orders, o.OrderID)
+
+	e.ordersMu.Lock()
+	defer e.ordersMu.Unlock()
+
+	delete(e.orderSymbols, o.OrderID)
+	if o.ClientOrderID != "" && e.clientOrderSymbols[o.ClientOrderID] == sh.symbol {
+		delete(e.clientOrderSymbols, o.ClientOrderID)
+	}
+}
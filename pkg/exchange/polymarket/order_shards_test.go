@@ -0,0 +1,145 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newShardTestExchange(t *testing.T, n int) (*Exchange, types.MarketMap) {
+	f := fixedpoint.NewFromFloat
+	t.Setenv("POLYMARKET_DRYRUN_AUTOFILL", "true")
+	t.Setenv("POLYMARKET_DRYRUN_FILL_INTERVAL", "1h")
+	t.Setenv(envRateOrder, "0")
+	t.Setenv(envRateCancel, "0")
+
+	markets := types.MarketMap{}
+	for i := 1; i <= n; i++ {
+		symbol := "PM_" + strconv.Itoa(i)
+		markets[symbol] = types.Market{Symbol: symbol, LocalSymbol: strings.Repeat(strconv.Itoa(i), 12), QuoteCurrency: "USDC",
+			TickSize: f(0.01), StepSize: f(0.01)}
+	}
+	ex := New("", "", "", WithMarkets(markets))
+	t.Cleanup(func() { ex.Close() })
+	return ex, markets
+}
+
+func TestExchange_ShardIsolation(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	ex, _ := newShardTestExchange(t, 2)
+	ctx := context.Background()
+
+	_, err := ex.SubmitOrder(ctx, types.SubmitOrder{Symbol: "PM_1", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+		Price: f(0.3), Quantity: f(10)})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// PM_1 的分片被占用时，PM_2 的下单、查询、撮合与撤单不需要等待
+	busy := ex.lookupShard("PM_1")
+	busy.mu.Lock()
+	defer busy.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		created, err := ex.SubmitOrder(ctx, types.SubmitOrder{Symbol: "PM_2", Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+			Price: f(0.3), Quantity: f(10)})
+		if err != nil {
+			done <- err
+			return
+		}
+		if _, err := ex.QueryOpenOrders(ctx, "PM_2"); err != nil {
+			done <- err
+			return
+		}
+		ex.SetReferencePrice("PM_2", f(0.5))
+		done <- ex.CancelOrders(ctx, *created)
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("PM_2 orders are blocked by the PM_1 shard")
+	}
+}
+
+func TestExchange_ShardsShareBalance(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	t.Setenv(envBalanceUSDC, "30")
+	ex, markets := newShardTestExchange(t, 4)
+	ctx := context.Background()
+
+	// 4 个 market 并发下单，每单冻结 3 USDC，共用的 30 USDC 只够 10 单
+	var mu sync.Mutex
+	var created []types.Order
+	var wg sync.WaitGroup
+	for symbol := range markets {
+		symbol := symbol
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				o, err := ex.SubmitOrder(ctx, types.SubmitOrder{Symbol: symbol, Side: types.SideTypeBuy, Type: types.OrderTypeLimit,
+					Price: f(0.3), Quantity: f(10)})
+				if err != nil {
+					assert.True(t, errors.Is(err, errInsufficientBalance), err.Error())
+					continue
+				}
+				mu.Lock()
+				created = append(created, *o)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, created, 10)
+	account, err := ex.QueryAccount(ctx)
+	if assert.NoError(t, err) {
+		usdc, _ := account.Balance(collateralCurrency)
+		assert.Equal(t, "0", usdc.Available.String())
+		assert.Equal(t, "30", usdc.Locked.String())
+	}
+
+	// 并发撤单后冻结金额全部释放
+	for _, o := range created {
+		o := o
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ex.CancelOrders(ctx, o))
+		}()
+	}
+	wg.Wait()
+
+	account, err = ex.QueryAccount(ctx)
+	if assert.NoError(t, err) {
+		usdc, _ := account.Balance(collateralCurrency)
+		assert.Equal(t, "30", usdc.Available.String())
+		assert.Equal(t, "0", usdc.Locked.String())
+	}
+}
+
+func TestExchange_ClientOrderIDAcrossShards(t *testing.T) {
+	ex, _ := newShardTestExchange(t, 2)
+
+	sh := ex.shard("PM_1")
+	sh.mu.Lock()
+	assert.NoError(t, ex.reserveClientOrderID("cid-1", "PM_1"))
+	sh.mu.Unlock()
+
+	// 同一个 client order id 不能同时用于两个 market 的订单
+	assert.Error(t, ex.reserveClientOrderID("cid-1", "PM_2"))
+	ex.releaseClientOrderID("cid-1")
+	assert.NoError(t, ex.reserveClientOrderID("cid-1", "PM_2"))
+}
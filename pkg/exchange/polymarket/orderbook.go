@@ -0,0 +1,77 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// CLOB 的 token id 是一个很长的十进制数字串；示例 market 的 LocalSymbol 不是 token id，不能拿去请求 CLOB。
+var tokenIDPattern = regexp.MustCompile(`^[0-9]{10,}$`)
+
+func isTokenID(s string) bool {
+	return tokenIDPattern.MatchString(s)
+}
+
+type PriceLevel struct {
+	Price fixedpoint.Value `json:"price"`
+	Size  fixedpoint.Value `json:"size"`
+}
+
+// OrderBookSummary 是 CLOB GET /book 的响应。
+type OrderBookSummary struct {
+	Market    string                     `json:"market"`
+	AssetID   string                     `json:"asset_id"`
+	Bids      []PriceLevel               `json:"bids"`
+	Asks      []PriceLevel               `json:"asks"`
+	Hash      string                     `json:"hash"`
+	Timestamp types.MillisecondTimestamp `json:"timestamp"`
+}
+
+// BestBid 返回最高买价。CLOB 返回的档位顺序不保证是“最优在前”，这里逐档比较。
+func (b OrderBookSummary) BestBid() (PriceLevel, bool) {
+	var best PriceLevel
+	for _, lv := range b.Bids {
+		if best.Price.IsZero() || lv.Price.Compare(best.Price) > 0 {
+			best = lv
+		}
+	}
+	return best, !best.Price.IsZero()
+}
+
+// BestAsk 返回最低卖价。
+func (b OrderBookSummary) BestAsk() (PriceLevel, bool) {
+	var best PriceLevel
+	for _, lv := range b.Asks {
+		if best.Price.IsZero() || lv.Price.Compare(best.Price) < 0 {
+			best = lv
+		}
+	}
+	return best, !best.Price.IsZero()
+}
+
+func (c *restClient) queryOrderBook(ctx context.Context, tokenID string) (*OrderBookSummary, error) {
+	var book OrderBookSummary
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/book", url.Values{"token_id": {tokenID}}, nil, &book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+func toGlobalTicker(book *OrderBookSummary) *types.Ticker {
+	t := &types.Ticker{Time: book.Timestamp.Time()}
+	if bid, ok := book.BestBid(); ok {
+		t.Buy = bid.Price
+	}
+	if ask, ok := book.BestAsk(); ok {
+		t.Sell = ask.Price
+	}
+	if !t.Buy.IsZero() && !t.Sell.IsZero() {
+		t.Last = t.Buy.Add(t.Sell).Div(fixedpoint.Two)
+	}
+	return t
+}
@@ -0,0 +1,59 @@
+package polymarket
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 部分成交：订单每次满足成交条件时只成交一部分，剩余部分在之后的撮合周期
+// （POLYMARKET_DRYRUN_FILL_INTERVAL）继续成交，每次成交都会推送一次订单更新，
+// 状态依次为 PARTIALLY_FILLED → FILLED：
+// - POLYMARKET_DRYRUN_FILL_RATIO（0~1，默认 1）每次成交的数量占订单数量的比例
+// - POLYMARKET_DRYRUN_FILL_CHUNK 每次成交的最大数量（shares，默认 0 表示不限制）
+// 两者同时配置时取较小值；数量按 market 的 stepSize 向下取整，不足一个 step 或超过剩余数量时成交全部剩余数量
+
+const (
+	envDryRunFillRatio = "POLYMARKET_DRYRUN_FILL_RATIO"
+	envDryRunFillChunk = "POLYMARKET_DRYRUN_FILL_CHUNK"
+)
+
+var defaultFillStepSize = fixedpoint.NewFromFloat(0.01)
+
+func newFillRatioFromEnv() fixedpoint.Value {
+	ratio := envFloat(envDryRunFillRatio, 1)
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	return fixedpoint.NewFromFloat(ratio)
+}
+
+func newFillChunkFromEnv() fixedpoint.Value {
+	chunk := envFloat(envDryRunFillChunk, 0)
+	if chunk < 0 {
+		chunk = 0
+	}
+	return fixedpoint.NewFromFloat(chunk)
+}
+
+// increment 返回订单本次成交的数量，保证 ExecutedQuantity 加上它不超过 Quantity。
+func (m *dryRunMatcher) increment(o *types.Order, stepSize fixedpoint.Value) fixedpoint.Value {
+	remaining := o.Quantity.Sub(o.ExecutedQuantity)
+	if remaining.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	if stepSize.Sign() <= 0 {
+		stepSize = defaultFillStepSize
+	}
+
+	quantity := o.Quantity.Mul(m.fillRatio)
+	if m.fillChunk.Sign() > 0 {
+		quantity = fixedpoint.Min(quantity, m.fillChunk)
+	}
+
+	quantity = floorToStep(quantity, stepSize)
+	if quantity.Sign() <= 0 || quantity.Compare(remaining) >= 0 {
+		return remaining
+	}
+	return quantity
+}
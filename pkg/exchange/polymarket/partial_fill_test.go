@@ -0,0 +1,104 @@
+package polymarket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestDryRunMatcher_Increment(t *testing.T) {
+	m := &dryRunMatcher{fillRatio: fixedpoint.One}
+	o := &types.Order{SubmitOrder: types.SubmitOrder{Quantity: fixedpoint.NewFromFloat(10)}}
+
+	// 默认整单成交
+	assert.Equal(t, "10", m.increment(o, fixedpoint.Zero).String())
+
+	m.fillChunk = fixedpoint.NewFromFloat(3)
+	assert.Equal(t, "3", m.increment(o, fixedpoint.Zero).String())
+
+	// 比例与上限取较小值
+	m.fillRatio = fixedpoint.NewFromFloat(0.25)
+	assert.Equal(t, "2.5", m.increment(o, fixedpoint.Zero).String())
+	assert.Equal(t, "2", m.increment(o, fixedpoint.One).String())
+
+	// 不超过剩余数量
+	o.ExecutedQuantity = fixedpoint.NewFromFloat(9)
+	assert.Equal(t, "1", m.increment(o, fixedpoint.Zero).String())
+
+	o.ExecutedQuantity = o.Quantity
+	assert.True(t, m.increment(o, fixedpoint.Zero).IsZero())
+}
+
+func TestExchange_DryRunPartialFills(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "10ms")
+	t.Setenv(envDryRunFillChunk, "3")
+	t.Setenv(envBalanceUSDC, "100")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	stream := ex.NewStream()
+
+	var mu sync.Mutex
+	var updates []types.Order
+	done := make(chan struct{})
+	stream.OnOrderUpdate(func(o types.Order) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, o)
+		if o.Status == types.OrderStatusFilled {
+			close(done)
+		}
+	})
+
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.45))
+
+	_, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	})
+	assert.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("order not filled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// NEW 之后每个撮合周期成交 3，最后一次成交剩余的 1
+	var executed []string
+	for _, o := range updates {
+		assert.True(t, o.ExecutedQuantity.Compare(o.Quantity) <= 0)
+		if o.ExecutedQuantity.IsZero() {
+			continue
+		}
+		executed = append(executed, o.ExecutedQuantity.String())
+		if o.ExecutedQuantity.Compare(o.Quantity) < 0 {
+			assert.Equal(t, types.OrderStatusPartiallyFilled, o.Status)
+			assert.True(t, o.IsWorking)
+		} else {
+			assert.Equal(t, types.OrderStatusFilled, o.Status)
+			assert.False(t, o.IsWorking)
+		}
+	}
+	assert.Equal(t, []string{"3", "6", "9", "10"}, executed)
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.InDelta(t, 95, balances["USDC"].Available.Float64(), 1e-6)
+	assert.InDelta(t, 0, balances["USDC"].Locked.Float64(), 1e-6)
+}
@@ -0,0 +1,142 @@
+package polymarket
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 订单持久化：
+// - POLYMARKET_PERSIST=true 开启
+// - POLYMARKET_PERSIST_NAMESPACE 为 store id（默认 polymarket-dryrun），不同实例/账号可以用不同 namespace 隔离
+//
+// 这个包不能直接依赖 pkg/service（service 依赖 pkg/exchange，会形成 import cycle），
+// 所以这里只定义与 service.Store 相同签名的 Store 接口，由上层（例如策略）用 bbgo 的 persistence service 创建 store 后注入。
+
+const (
+	envPersist          = "POLYMARKET_PERSIST"
+	envPersistNamespace = "POLYMARKET_PERSIST_NAMESPACE"
+
+	defaultPersistNamespace = "polymarket-dryrun"
+)
+
+// Store 与 service.Store 的方法集一致，service 的 Redis/JSON/Memory store 都可以直接传入。
+type Store interface {
+	Load(val interface{}) error
+	Save(val interface{}) error
+	Reset() error
+}
+
+// persistentState 是写入 store 的内容：未结束的订单以及 order id 计数器。
+type persistentState struct {
+	NextOrderID uint64        `json:"nextOrderID"`
+	Orders      []types.Order `json:"orders"`
+}
+
+// PersistenceEnabled 返回是否开启了 dry-run 订单持久化。
+func (e *Exchange) PersistenceEnabled() bool {
+	return envBool(envPersist, false)
+}
+
+// PersistenceNamespace 返回持久化使用的 store id。
+func (e *Exchange) PersistenceNamespace() string {
+	return envString(envPersistNamespace, defaultPersistNamespace)
+}
+
+// SetPersistenceStore 注入持久化 store，并立即从 store 恢复订单。
+func (e *Exchange) SetPersistenceStore(store Store) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.store = store
+	e.storeLoaded = false
+	return e.loadOrdersLocked()
+}
+
+// loadOrders 在第一次使用时从 store 恢复订单，已经恢复过时只持有读锁检查，查询订单不会互相阻塞。
+func (e *Exchange) loadOrders() error {
+	e.mu.RLock()
+	loaded := e.store == nil || e.storeLoaded
+	e.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.loadOrdersLocked()
+}
+
+// loadOrdersLocked 在第一次使用时从 store 恢复订单，需要持有 e.mu。
+func (e *Exchange) loadOrdersLocked() error {
+	if e.store == nil || e.storeLoaded {
+		return nil
+	}
+	e.storeLoaded = true
+
+	var state persistentState
+	if err := e.store.Load(&state); err != nil {
+		if isNotExistError(err) {
+			return nil
+		}
+		return err
+	}
+
+	restored := 0
+	for i := range state.Orders {
+		o := state.Orders[i]
+		e.advanceOrderID(o.OrderID + 1)
+
+		// 挂上 store 之前已经创建的订单可能占用了相同的 id，保留内存中的订单
+		if _, exists := e.shardOf(o.OrderID, ""); exists {
+			log.Warnf("skip restoring dry-run order %d: the order id is already used", o.OrderID)
+			continue
+		}
+
+		// 旧版本持久化的订单没有 isDryRun 字段
+		o.IsDryRun = true
+		sh := e.shard(o.Symbol)
+		sh.mu.Lock()
+		e.indexOrderLocked(sh, &o)
+		e.relockRestoredLocked(&o)
+		sh.updateMetricsLocked()
+		sh.mu.Unlock()
+		restored++
+	}
+	e.advanceOrderID(state.NextOrderID)
+
+	log.Infof("restored %d dry-run orders from persistence, next order id = %d", restored, e.nextOrderID.Load())
+	return nil
+}
+
+// saveOrdersLocked 在每次订单变化后写回 store，需要持有 e.mu（至少读锁），不能持有 shard 锁。
+// 写入由 e.saveMu 串行化，快照在调用方的修改之后逐个 shard 复制，并发写入时最后一次写入总是包含之前的所有修改。
+func (e *Exchange) saveOrdersLocked() {
+	if e.store == nil {
+		return
+	}
+
+	e.saveMu.Lock()
+	defer e.saveMu.Unlock()
+
+	state := persistentState{
+		NextOrderID: e.nextOrderID.Load(),
+		Orders:      e.allOrders(func(o *types.Order) bool { return o.IsWorking }),
+	}
+	sort.Slice(state.Orders, func(i, j int) bool { return state.Orders[i].OrderID < state.Orders[j].OrderID })
+
+	if err := e.store.Save(&state); err != nil {
+		log.WithError(err).Error("failed to save dry-run orders")
+	}
+}
+
+// isNotExistError 判断 store 里还没有数据。service.ErrPersistenceNotExists 无法直接引用，这里按错误信息判断。
+func isNotExistError(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err.Error() == "persistent data does not exists" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// memoryStore 模拟 service 的 store（测试里不能 import pkg/service，会形成 import cycle）
+type memoryStore struct {
+	data []byte
+}
+
+func (s *memoryStore) Load(val interface{}) error {
+	if s.data == nil {
+		return errors.New("persistent data does not exists")
+	}
+	return json.Unmarshal(s.data, val)
+}
+
+func (s *memoryStore) Save(val interface{}) (err error) {
+	s.data, err = json.Marshal(val)
+	return err
+}
+
+func (s *memoryStore) Reset() error {
+	s.data = nil
+	return nil
+}
+
+func TestExchange_PersistOrders(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryStore{}
+
+	ex := New("", "", "")
+	assert.NoError(t, ex.SetPersistenceStore(store))
+
+	submit := types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	}
+	o1, err := ex.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	o2, err := ex.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	assert.NoError(t, ex.CancelOrders(ctx, *o1))
+
+	// 模拟重启
+	restarted := New("", "", "")
+	assert.NoError(t, restarted.SetPersistenceStore(store))
+
+	openOrders, err := restarted.QueryOpenOrders(ctx, "")
+	assert.NoError(t, err)
+	if assert.Len(t, openOrders, 1) {
+		assert.Equal(t, o2.OrderID, openOrders[0].OrderID)
+	}
+
+	o3, err := restarted.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	assert.Equal(t, o2.OrderID+1, o3.OrderID)
+}
+
+func TestExchange_OrderIDsAfterLateStore(t *testing.T) {
+	ctx := context.Background()
+	submit := types.SubmitOrder{
+		Symbol:   "PM_BTC_15M_UP_YES_USDC",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	}
+
+	// 上一次运行持久化了 order 1 ~ 3，计数器停在 4
+	previous := New("", "", "")
+	store := &memoryStore{}
+	assert.NoError(t, previous.SetPersistenceStore(store))
+	for i := 0; i < 3; i++ {
+		_, err := previous.SubmitOrder(ctx, submit)
+		assert.NoError(t, err)
+	}
+
+	// 挂上 store 之前已经创建了 order 1，恢复后不会覆盖它，之后的 id 也不会与恢复的订单冲突
+	ex := New("", "", "")
+	o1, err := ex.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), o1.OrderID)
+
+	assert.NoError(t, ex.SetPersistenceStore(store))
+	openOrders, err := ex.QueryOpenOrders(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 3)
+
+	o4, err := ex.SubmitOrder(ctx, submit)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), o4.OrderID)
+}
+
+func TestExchange_ConcurrentOrderIDs(t *testing.T) {
+	t.Setenv(envRateBurst, "100")
+
+	ctx := context.Background()
+	ex := New("", "", "")
+	assert.NoError(t, ex.SetPersistenceStore(&memoryStore{}))
+
+	const n = 50
+	ids := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+				Symbol:   "PM_BTC_15M_UP_YES_USDC",
+				Side:     types.SideTypeBuy,
+				Type:     types.OrderTypeLimit,
+				Price:    fixedpoint.NewFromFloat(0.5),
+				Quantity: fixedpoint.NewFromFloat(10),
+			})
+			if assert.NoError(t, err) {
+				ids <- o.OrderID
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[uint64]bool)
+	for id := range ids {
+		assert.False(t, seen[id], "duplicated order id %d", id)
+		seen[id] = true
+	}
+	assert.Len(t, seen, n)
+}
@@ -0,0 +1,61 @@
+package polymarket
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// post-only（只做 maker）：SubmitOrder.Type 为 types.OrderTypeLimitMaker 时
+// - 真实下单在请求中带上 postOnly，会立即成交（吃单）的订单由 CLOB 拒绝
+// - dry-run 用 market channel 的盘口缓存判断：买单价格 >= 最优卖价、卖单价格 <= 最优买价时拒单；
+//   没有盘口缓存时无法判断，照常接受
+
+// PostOnlyRejectedError 是 post-only 订单因为会吃单而被拒绝时返回的错误。
+type PostOnlyRejectedError struct {
+	Symbol string
+	Side   types.SideType
+	Price  fixedpoint.Value
+	// BestPrice 为对手方的最优价格（买单为最优卖价，卖单为最优买价）
+	BestPrice fixedpoint.Value
+}
+
+func (e *PostOnlyRejectedError) Error() string {
+	return fmt.Sprintf("polymarket: post-only %s order of %s at %s would cross the best price %s",
+		e.Side, e.Symbol, e.Price.String(), e.BestPrice.String())
+}
+
+func isPostOnly(order types.SubmitOrder) bool {
+	return order.Type == types.OrderTypeLimitMaker
+}
+
+// checkPostOnlyLocked 检查 dry-run 的 post-only 订单是否会吃单，需要持有 e.mu。
+func (e *Exchange) checkPostOnlyLocked(order types.SubmitOrder) error {
+	if !isPostOnly(order) {
+		return nil
+	}
+
+	token, ok := e.tokenOfLocked(order.Symbol)
+	if !ok {
+		return nil
+	}
+
+	bid, ask, ok := e.tickers.bestBidAsk(token.TokenID, time.Now())
+	if !ok {
+		return nil
+	}
+
+	switch order.Side {
+	case types.SideTypeBuy:
+		if order.Price.Compare(ask) >= 0 {
+			return &PostOnlyRejectedError{Symbol: order.Symbol, Side: order.Side, Price: order.Price, BestPrice: ask}
+		}
+	case types.SideTypeSell:
+		if order.Price.Compare(bid) <= 0 {
+			return &PostOnlyRejectedError{Symbol: order.Symbol, Side: order.Side, Price: order.Price, BestPrice: bid}
+		}
+	}
+	return nil
+}
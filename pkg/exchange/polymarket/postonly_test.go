@@ -0,0 +1,86 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DryRunPostOnly(t *testing.T) {
+	const tokenID = "111111111111"
+
+	ex := New("", "", "")
+	ex.markets = types.MarketMap{
+		"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID},
+	}
+	ex.tickers.updateBook(BookEvent{
+		AssetID: tokenID,
+		Bids:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.45), Size: fixedpoint.NewFromFloat(10)}},
+		Asks:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.5), Size: fixedpoint.NewFromFloat(10)}},
+	}, time.Now())
+
+	newOrder := func(orderType types.OrderType, side types.SideType, price float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "PM_YES",
+			Side:     side,
+			Type:     orderType,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(10),
+		}
+	}
+
+	ctx := context.Background()
+
+	// 买单价格达到最优卖价会吃单，被拒绝
+	_, err := ex.SubmitOrder(ctx, newOrder(types.OrderTypeLimitMaker, types.SideTypeBuy, 0.5))
+	var rejected *PostOnlyRejectedError
+	if assert.ErrorAs(t, err, &rejected) {
+		assert.Equal(t, "0.5", rejected.BestPrice.String())
+	}
+
+	// 卖单价格低于最优买价
+	_, err = ex.SubmitOrder(ctx, newOrder(types.OrderTypeLimitMaker, types.SideTypeSell, 0.4))
+	assert.ErrorAs(t, err, &rejected)
+
+	// 不会吃单的 post-only 订单与普通限价单照常接受
+	_, err = ex.SubmitOrder(ctx, newOrder(types.OrderTypeLimitMaker, types.SideTypeBuy, 0.49))
+	assert.NoError(t, err)
+	_, err = ex.SubmitOrder(ctx, newOrder(types.OrderTypeLimit, types.SideTypeBuy, 0.5))
+	assert.NoError(t, err)
+
+	// 批量下单中单独拒绝
+	_, err = ex.SubmitOrders(ctx, newOrder(types.OrderTypeLimitMaker, types.SideTypeBuy, 0.48), newOrder(types.OrderTypeLimitMaker, types.SideTypeBuy, 0.55))
+	var batchErr *BatchOrderError
+	if assert.ErrorAs(t, err, &batchErr) {
+		assert.NoError(t, batchErr.Errors[0])
+		assert.ErrorAs(t, batchErr.Errors[1], &rejected)
+	}
+}
+
+func TestExchange_BuildPostOnlyOrder(t *testing.T) {
+	ex := New("", "", "")
+	ex.markets = types.MarketMap{
+		"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111"},
+	}
+
+	order := types.SubmitOrder{
+		Symbol:   "PM_YES",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimitMaker,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	}
+
+	clobOrder, err := ex.buildOrder(order)
+	assert.NoError(t, err)
+	assert.True(t, clobOrder.PostOnly)
+
+	order.TimeInForce = types.TimeInForceIOC
+	_, err = ex.buildOrder(order)
+	assert.ErrorContains(t, err, "post-only")
+}
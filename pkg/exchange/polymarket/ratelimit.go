@@ -0,0 +1,139 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Polymarket CLOB 对每个 endpoint 都有限频，超过会返回 429。
+// 这里按“下单 / 撤单 / 行情查询”三类分别做 token bucket 限流，速率可以通过 env 配置：
+// - POLYMARKET_RATE_ORDER / POLYMARKET_RATE_CANCEL / POLYMARKET_RATE_MARKET：每秒请求数
+// - POLYMARKET_RATE_BURST：每个 bucket 的突发容量
+// - POLYMARKET_MAX_RETRIES：遇到 429 时的最大重试次数；GET 请求（行情、market 查询）遇到网络错误或 5xx 时同样重试，
+//   4xx 客户端错误不重试
+// - POLYMARKET_RETRY_BACKOFF：首次重试的退避时间（之后指数增长并带抖动）
+// 真实交易只在 REST client（restClient.do）限流与重试，Exchange 层不再包一层（否则每个请求消耗两个 token，
+// 429 时两层重试叠加）；dry-run 没有 HTTP 请求，在 Exchange 层按同样的速率限流，保持与真实交易相同的节奏。
+
+const (
+	envRateOrder    = "POLYMARKET_RATE_ORDER"
+	envRateCancel   = "POLYMARKET_RATE_CANCEL"
+	envRateMarket   = "POLYMARKET_RATE_MARKET"
+	envRateBurst    = "POLYMARKET_RATE_BURST"
+	envMaxRetries   = "POLYMARKET_MAX_RETRIES"
+	envRetryBackoff = "POLYMARKET_RETRY_BACKOFF"
+
+	defaultOrderRate    = 5.0
+	defaultCancelRate   = 5.0
+	defaultMarketRate   = 10.0
+	defaultRateBurst    = 5
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// ErrTooManyRequests 表示 CLOB 返回了 429。
+var ErrTooManyRequests = errors.New("polymarket: too many requests")
+
+type rateLimits struct {
+	order  *rate.Limiter
+	cancel *rate.Limiter
+	market *rate.Limiter
+
+	maxRetries int
+	backoff    time.Duration
+}
+
+func newRateLimitsFromEnv() *rateLimits {
+	burst := envInt(envRateBurst, defaultRateBurst)
+	if burst <= 0 {
+		burst = defaultRateBurst
+	}
+
+	return &rateLimits{
+		order:      newLimiter(envFloat(envRateOrder, defaultOrderRate), burst),
+		cancel:     newLimiter(envFloat(envRateCancel, defaultCancelRate), burst),
+		market:     newLimiter(envFloat(envRateMarket, defaultMarketRate), burst),
+		maxRetries: envInt(envMaxRetries, defaultMaxRetries),
+		backoff:    envDuration(envRetryBackoff, defaultRetryBackoff),
+	}
+}
+
+// newLimiter 中 rps <= 0 表示不限流。
+func newLimiter(rps float64, burst int) *rate.Limiter {
+	if rps <= 0 {
+		return rate.NewLimiter(rate.Inf, burst)
+	}
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// do 先等待 limiter 放行再执行 fn；fn 返回 ErrTooManyRequests 时按指数退避 + 抖动重试，
+// 直到成功、遇到其他错误、超过最大重试次数或 ctx 结束。
+func (r *rateLimits) do(ctx context.Context, limiter *rate.Limiter, fn func() error) error {
+	return r.doRetry(ctx, limiter, isRateLimited, fn)
+}
+
+// doRetry 与 do 相同，但由 retryable 决定哪些错误需要重试。
+func (r *rateLimits) doRetry(ctx context.Context, limiter *rate.Limiter, retryable func(error) bool, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil || !retryable(err) || attempt >= r.maxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := r.backoffDuration(attempt)
+		log.WithError(err).Warnf("request failed, retrying in %s (attempt %d/%d)", wait, attempt+1, r.maxRetries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoffDuration 返回第 attempt 次重试前的等待时间：base * 2^attempt，再加上 [0, base) 的随机抖动。
+func (r *rateLimits) backoffDuration(attempt int) time.Duration {
+	base := r.backoff
+	if base <= 0 {
+		base = defaultRetryBackoff
+	}
+
+	d := base << uint(attempt)
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+
+	return d + time.Duration(rand.Int63n(int64(base)))
+}
+
+func isRateLimited(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
+
+// isTransientError 判断错误是否是暂时性的：429、5xx 或网络错误（连接失败、超时、响应被截断）。
+func isTransientError(err error) bool {
+	if isRateLimited(err) {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
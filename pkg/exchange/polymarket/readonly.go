@@ -0,0 +1,43 @@
+package polymarket
+
+import (
+	"errors"
+)
+
+// 只读模式（kill switch）：
+// - POLYMARKET_READONLY=true 启动时开启，运行中可以通过 Exchange.SetReadOnly 打开/关闭
+// - 行情、账户、订单查询照常工作；下单、撤单直接返回 ErrReadOnly，不产生任何副作用
+//   （比 dry-run 更严格：dry-run 仍然会创建模拟订单）
+
+const envReadOnly = "POLYMARKET_READONLY"
+
+// ErrReadOnly 为只读模式下调用下单/撤单等接口时返回的错误。
+var ErrReadOnly = errors.New("polymarket: exchange is in read-only mode, order submission and cancellation are disabled")
+
+// SetReadOnly 打开或关闭只读模式。
+func (e *Exchange) SetReadOnly(readOnly bool) {
+	e.mu.Lock()
+	e.readOnly = readOnly
+	e.mu.Unlock()
+
+	if readOnly {
+		log.Warn("polymarket exchange is switched to read-only mode")
+	} else {
+		log.Info("polymarket exchange read-only mode is turned off")
+	}
+}
+
+// IsReadOnly 返回是否处于只读模式。
+func (e *Exchange) IsReadOnly() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.readOnly
+}
+
+// checkWritable 在会改变订单状态的调用开始前检查只读模式。
+func (e *Exchange) checkWritable() error {
+	if e.IsReadOnly() {
+		return ErrReadOnly
+	}
+	return nil
+}
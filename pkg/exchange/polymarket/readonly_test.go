@@ -0,0 +1,54 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_ReadOnly(t *testing.T) {
+	t.Setenv(envReadOnly, "true")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+	order := types.SubmitOrder{
+		Symbol:   symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(0.5),
+		Quantity: fixedpoint.NewFromFloat(10),
+	}
+
+	// 查询照常
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+	_, err = ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+
+	// 下单、撤单被拒绝，也不会创建模拟订单
+	_, err = ex.SubmitOrder(ctx, order)
+	assert.ErrorIs(t, err, ErrReadOnly)
+	_, err = ex.SubmitOrders(ctx, order, order)
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, ex.CancelAllOrders(ctx, ""), ErrReadOnly)
+
+	openOrders, err := ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+
+	// 运行中关闭只读模式后恢复下单，再次打开后撤单被拒绝
+	ex.SetReadOnly(false)
+	created, err := ex.SubmitOrder(ctx, order)
+	assert.NoError(t, err)
+
+	ex.SetReadOnly(true)
+	assert.ErrorIs(t, ex.CancelOrders(ctx, *created), ErrReadOnly)
+	openOrders, err = ex.QueryOpenOrders(ctx, symbol)
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 1)
+}
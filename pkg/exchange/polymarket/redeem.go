@@ -0,0 +1,161 @@
+package polymarket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// dry-run 结算兑付：市场结算后 outcome token 按结果兑付，赢的 token 每个兑付 1 USDC，输的作废。
+// - dry-run 下 DiscoverUpDownMarket 发现的市场登记为待兑付，窗口结束后每隔 POLYMARKET_REDEEM_INTERVAL（默认 1m，0 关闭）
+//   查询 Gamma，市场结算后自动兑付；Gamma 上找不到的市场不再查询
+// - RedeemUpDownMarket 也可以直接调用：先撤销两个 outcome 上的 working 订单，再把赢的 token 持仓按 1 USDC 记入可用余额
+//   （不收手续费），两边的持仓清零，并推送 BalanceUpdate；市场还没有结算时不做改动
+// - 兑付只影响余额与持仓，DryRunReport 的盈亏仍按模拟成交计算
+// - 真实交易的兑付需要在链上调用 CTF 的 redeemPositions，不在这里处理
+
+const envRedeemInterval = "POLYMARKET_REDEEM_INTERVAL"
+
+const defaultRedeemInterval = time.Minute
+
+type dryRunRedeemer struct {
+	interval time.Duration
+	started  atomic.Bool
+
+	mu sync.Mutex
+	// pending 为等待结算的 up/down 市场，key 为 slug
+	pending map[string]*UpDownMarket
+}
+
+func newDryRunRedeemerFromEnv() *dryRunRedeemer {
+	return &dryRunRedeemer{
+		interval: envDuration(envRedeemInterval, defaultRedeemInterval),
+		pending:  make(map[string]*UpDownMarket),
+	}
+}
+
+// trackRedemption 登记 dry-run 发现的 up/down 市场，并在第一次登记时启动兑付循环。
+func (e *Exchange) trackRedemption(m *UpDownMarket) {
+	if !e.IsDryRun() || e.redeemer.interval <= 0 {
+		return
+	}
+
+	e.redeemer.mu.Lock()
+	e.redeemer.pending[m.Slug] = m
+	e.redeemer.mu.Unlock()
+
+	if e.redeemer.started.CompareAndSwap(false, true) {
+		go e.runRedeemer(e.background)
+	}
+}
+
+func (e *Exchange) runRedeemer(ctx context.Context) {
+	ticker := time.NewTicker(e.redeemer.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.redeemResolved(ctx, now)
+		}
+	}
+}
+
+// redeemResolved 兑付窗口已经结束且已经结算的待兑付市场，返回兑付的市场数。
+func (e *Exchange) redeemResolved(ctx context.Context, now time.Time) int {
+	var due []*UpDownMarket
+	e.redeemer.mu.Lock()
+	for _, m := range e.redeemer.pending {
+		if !now.Before(m.WindowEnd) {
+			due = append(due, m)
+		}
+	}
+	e.redeemer.mu.Unlock()
+
+	redeemed := 0
+	for _, m := range due {
+		payout, resolved, err := e.RedeemUpDownMarket(ctx, m)
+		if errors.Is(err, errGammaMarketNotFound) {
+			log.Warnf("polymarket(dry-run) up/down market %s is not found, stop waiting for its resolution", m.Slug)
+		} else if err != nil {
+			log.WithError(err).Warnf("polymarket(dry-run) failed to redeem up/down market %s", m.Slug)
+			continue
+		} else if !resolved {
+			continue
+		} else {
+			redeemed++
+			log.Infof("polymarket(dry-run) up/down market %s resolved, redeemed %s USDC", m.Slug, payout.String())
+		}
+
+		e.redeemer.mu.Lock()
+		delete(e.redeemer.pending, m.Slug)
+		e.redeemer.mu.Unlock()
+	}
+	return redeemed
+}
+
+// RedeemUpDownMarket 在 up/down 市场结算后兑付 dry-run 持仓，返回兑付的 USDC；市场还没有结算时 resolved 为 false。
+// 见文件开头的说明。
+func (e *Exchange) RedeemUpDownMarket(ctx context.Context, m *UpDownMarket) (payout fixedpoint.Value, resolved bool, err error) {
+	if !e.IsDryRun() {
+		return fixedpoint.Zero, false, fmt.Errorf("polymarket: redeeming positions is only simulated in dry-run, redeem live positions on chain")
+	}
+
+	gm, err := e.gamma.queryGammaMarketBySlug(ctx, m.Slug)
+	if err != nil {
+		return fixedpoint.Zero, false, err
+	}
+
+	outcome, ok, err := gm.resolvedOutcome()
+	if err != nil || !ok {
+		return fixedpoint.Zero, false, err
+	}
+
+	var winner, loser string
+	switch outcome {
+	case OutcomeUp:
+		winner, loser = m.YesSymbol, m.NoSymbol
+	case OutcomeDown:
+		winner, loser = m.NoSymbol, m.YesSymbol
+	default:
+		return fixedpoint.Zero, false, fmt.Errorf("polymarket: %s resolved to unknown outcome %q", m.Slug, outcome)
+	}
+
+	// 结算后的市场不能再交易，先撤销剩余的订单（买单解冻余额）
+	for _, symbol := range []string{winner, loser} {
+		if err := e.CancelAllOrders(ctx, symbol); err != nil {
+			return fixedpoint.Zero, false, err
+		}
+	}
+	return e.redeemPositions(winner, loser), true, nil
+}
+
+// redeemPositions 兑付 winner 的 token 持仓（每个 1 USDC）并清零 losers 的持仓，返回兑付的 USDC。
+func (e *Exchange) redeemPositions(winner string, losers ...string) fixedpoint.Value {
+	e.mu.RLock()
+
+	e.balanceMu.Lock()
+	currency := e.positionCurrencyLocked(winner)
+	payout := e.balance.positions[currency]
+	delete(e.balance.positions, currency)
+	for _, symbol := range losers {
+		delete(e.balance.positions, e.positionCurrencyLocked(symbol))
+	}
+	if e.balance.enabled {
+		e.balance.available = e.balance.available.Add(payout)
+	}
+	e.balanceMu.Unlock()
+
+	balances := e.balancesLocked(append([]string{winner}, losers...)...)
+	e.mu.RUnlock()
+
+	e.emitBalanceUpdate(balances)
+	return payout
+}
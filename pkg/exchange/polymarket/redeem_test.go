@@ -0,0 +1,95 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_RedeemUpDownMarket(t *testing.T) {
+	t.Setenv(envBalanceUSDC, "10")
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+	t.Setenv(envRedeemInterval, "1h")
+
+	closed, prices := false, ""
+	newMockServer(t, map[string]func(w http.ResponseWriter, r *http.Request, body []byte){
+		"GET /gamma/markets": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, []map[string]interface{}{{
+				"slug":          r.URL.Query().Get("slug"),
+				"outcomes":      `["Up", "Down"]`,
+				"clobTokenIds":  `["111111111111", "222222222222"]`,
+				"closed":        closed,
+				"outcomePrices": prices,
+			}})
+		},
+	})
+
+	ex := New("", "", "")
+	ctx := context.Background()
+
+	um, err := ex.DiscoverUpDownMarket(ctx, "btc", types.Interval15m, time.Now())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	newOrder := func(symbol string, price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		}
+	}
+
+	assertBalance := func(available, locked string) {
+		balances, err := ex.QueryAccountBalances(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, available, balances["USDC"].Available.String())
+		assert.Equal(t, locked, balances["USDC"].Locked.String())
+	}
+
+	// 买入 10 个 YES 与 4 个 NO 并成交，NO 上还有一个挂单
+	_, err = ex.SubmitOrder(ctx, newOrder(um.YesSymbol, 0.5, 10))
+	assert.NoError(t, err)
+	ex.SetReferencePrice(um.YesSymbol, fixedpoint.NewFromFloat(0.5))
+	_, err = ex.SubmitOrder(ctx, newOrder(um.NoSymbol, 0.4, 4))
+	assert.NoError(t, err)
+	ex.SetReferencePrice(um.NoSymbol, fixedpoint.NewFromFloat(0.4))
+	_, err = ex.SubmitOrder(ctx, newOrder(um.NoSymbol, 0.1, 5))
+	assert.NoError(t, err)
+	assertBalance("2.9", "0.5")
+
+	// 窗口还没有结束、或结束了但还没有结算时不兑付
+	assert.Equal(t, 0, ex.redeemResolved(ctx, um.WindowStart))
+	assert.Equal(t, 0, ex.redeemResolved(ctx, um.WindowEnd))
+	assertBalance("2.9", "0.5")
+
+	// 结算为 Up：YES 每个兑付 1 USDC，NO 作废，剩余订单撤销
+	closed, prices = true, `["1", "0"]`
+	assert.Equal(t, 1, ex.redeemResolved(ctx, um.WindowEnd))
+	assertBalance("13.4", "0")
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+
+	open, err := ex.QueryOpenOrders(ctx, um.NoSymbol)
+	assert.NoError(t, err)
+	assert.Empty(t, open)
+
+	// 已兑付的市场不再查询
+	assert.Equal(t, 0, ex.redeemResolved(ctx, um.WindowEnd))
+	assertBalance("13.4", "0")
+
+	ex.SetDryRun(false)
+	_, _, err = ex.RedeemUpDownMarket(ctx, um)
+	assert.ErrorContains(t, err, "only simulated in dry-run")
+}
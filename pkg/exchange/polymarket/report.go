@@ -0,0 +1,232 @@
+package polymarket
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// dry-run 模拟盘的账务统计：
+// - 订单数按状态统计各 symbol 分片里的订单，加上已被清理的订单
+// - 持仓与盈亏由模拟成交（e.trades）按平均成本法计算，买入手续费计入成本，卖出手续费从已实现盈亏中扣除
+// - 未实现盈亏用 SetReferencePrice 注入的参考价作为标记价格，没有参考价的持仓不计算
+// - DryRunSummaryByTag 按订单的 Tag（成交记录继承订单的 Tag）分组统计，同一个 session 上运行多个策略或策略变体时
+//   可以区分各自的订单数、成交与盈亏；没有 tag 的订单归在 "" 下
+
+// DryRunPosition 为单个 symbol 的模拟持仓。
+type DryRunPosition struct {
+	Symbol        string           `json:"symbol"`
+	Quantity      fixedpoint.Value `json:"quantity"`
+	AverageCost   fixedpoint.Value `json:"averageCost"`
+	MarkPrice     fixedpoint.Value `json:"markPrice"`
+	RealizedPnL   fixedpoint.Value `json:"realizedPnL"`
+	UnrealizedPnL fixedpoint.Value `json:"unrealizedPnL"`
+}
+
+// DryRunSummary 为 dry-run 模拟盘的汇总。
+type DryRunSummary struct {
+	// Tag 为 DryRunSummaryByTag 分组的订单 tag，总汇总为空
+	Tag string `json:"tag,omitempty"`
+
+	TotalOrders    int `json:"totalOrders"`
+	OpenOrders     int `json:"openOrders"`
+	FilledOrders   int `json:"filledOrders"`
+	CanceledOrders int `json:"canceledOrders"`
+
+	// FilledNotional 为所有模拟成交的成交金额（USDC），Fees 为手续费合计
+	FilledNotional fixedpoint.Value `json:"filledNotional"`
+	Fees           fixedpoint.Value `json:"fees"`
+
+	RealizedPnL   fixedpoint.Value `json:"realizedPnL"`
+	UnrealizedPnL fixedpoint.Value `json:"unrealizedPnL"`
+
+	// Positions 按 symbol 排序
+	Positions []DryRunPosition `json:"positions"`
+}
+
+var _ types.DryRunExchange = (*Exchange)(nil)
+
+// IsDryRun 返回当前是否为 dry-run 模式（WithDryRun / SetDryRun 指定，否则为创建时的 POLYMARKET_DRY_RUN，默认 true）。
+func (e *Exchange) IsDryRun() bool {
+	return e.dryRun.Load()
+}
+
+// SetDryRun 指定这个 Exchange 是否 dry-run，覆盖 POLYMARKET_DRY_RUN，不影响同一进程中的其它 session。
+// 需要在创建 stream 与下单之前调用（bbgo 按 session 配置的 dryRun 在初始化 session 时调用）。
+func (e *Exchange) SetDryRun(dryRun bool) {
+	e.dryRun.Store(dryRun)
+}
+
+// dryRunLedger 为汇总时复制的订单、模拟成交与已清理订单的计数
+type dryRunLedger struct {
+	orders []types.Order
+	trades []types.Trade
+	pruned map[string]prunedCounts
+}
+
+// dryRunLedger 逐个 shard 复制订单，再复制成交记录与清理计数。
+func (e *Exchange) dryRunLedger() dryRunLedger {
+	ledger := dryRunLedger{orders: e.allOrders(nil)}
+
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	ledger.trades = append([]types.Trade(nil), e.trades...)
+	ledger.pruned = e.janitor.prunedCountsLocked()
+	return ledger
+}
+
+// DryRunSummary 返回 dry-run 模拟盘的订单、成交与盈亏汇总。
+func (e *Exchange) DryRunSummary() DryRunSummary {
+	return e.dryRunSummary(e.dryRunLedger(), nil)
+}
+
+// DryRunSummaryByTag 按订单 tag 分组返回 dry-run 汇总，key 为 tag（没有 tag 的订单为 ""）。
+func (e *Exchange) DryRunSummaryByTag() map[string]DryRunSummary {
+	ledger := e.dryRunLedger()
+
+	tags := make(map[string]struct{})
+	for _, o := range ledger.orders {
+		tags[o.Tag] = struct{}{}
+	}
+	for _, t := range ledger.trades {
+		tags[t.Tag] = struct{}{}
+	}
+	for tag := range ledger.pruned {
+		tags[tag] = struct{}{}
+	}
+
+	summaries := make(map[string]DryRunSummary, len(tags))
+	for tag := range tags {
+		summary := e.dryRunSummary(ledger, &tag)
+		summary.Tag = tag
+		summaries[tag] = summary
+	}
+	return summaries
+}
+
+// dryRunSummary 汇总 ledger 中 tag 的订单与成交，tag 为 nil 时汇总全部。
+func (e *Exchange) dryRunSummary(ledger dryRunLedger, tag *string) DryRunSummary {
+	// 已被清理的订单（见 janitor.go）也计入订单数
+	pruned := sumPruned(ledger.pruned, tag)
+	summary := DryRunSummary{
+		TotalOrders:    pruned.total(),
+		FilledOrders:   pruned.filled,
+		CanceledOrders: pruned.canceled,
+	}
+	for _, o := range ledger.orders {
+		if tag != nil && o.Tag != *tag {
+			continue
+		}
+
+		summary.TotalOrders++
+		switch {
+		case o.IsWorking:
+			summary.OpenOrders++
+		case o.Status == types.OrderStatusFilled:
+			summary.FilledOrders++
+		case o.Status == types.OrderStatusCanceled:
+			summary.CanceledOrders++
+		}
+	}
+
+	positions := make(map[string]*DryRunPosition)
+	for _, t := range ledger.trades {
+		if tag != nil && t.Tag != *tag {
+			continue
+		}
+
+		p, ok := positions[t.Symbol]
+		if !ok {
+			p = &DryRunPosition{Symbol: t.Symbol}
+			positions[t.Symbol] = p
+		}
+
+		summary.FilledNotional = summary.FilledNotional.Add(t.QuoteQuantity)
+		summary.Fees = summary.Fees.Add(t.Fee)
+
+		switch t.Side {
+		case types.SideTypeBuy:
+			cost := p.AverageCost.Mul(p.Quantity).Add(t.QuoteQuantity).Add(t.Fee)
+			p.Quantity = p.Quantity.Add(t.Quantity)
+			p.AverageCost = cost.Div(p.Quantity)
+
+		case types.SideTypeSell:
+			quantity := fixedpoint.Min(t.Quantity, p.Quantity)
+			p.RealizedPnL = p.RealizedPnL.Add(t.Price.Sub(p.AverageCost).Mul(quantity)).Sub(t.Fee)
+			p.Quantity = p.Quantity.Sub(quantity)
+			if p.Quantity.IsZero() {
+				p.AverageCost = fixedpoint.Zero
+			}
+		}
+	}
+
+	for symbol, p := range positions {
+		if ref, ok := e.matcher.referencePrice(symbol); ok && ref.Sign() > 0 && p.Quantity.Sign() > 0 {
+			p.MarkPrice = ref
+			p.UnrealizedPnL = ref.Sub(p.AverageCost).Mul(p.Quantity)
+		}
+
+		summary.RealizedPnL = summary.RealizedPnL.Add(p.RealizedPnL)
+		summary.UnrealizedPnL = summary.UnrealizedPnL.Add(p.UnrealizedPnL)
+		summary.Positions = append(summary.Positions, *p)
+	}
+
+	sort.Slice(summary.Positions, func(i, j int) bool {
+		return summary.Positions[i].Symbol < summary.Positions[j].Symbol
+	})
+	return summary
+}
+
+// DryRunReport 返回可直接打印的 dry-run 模拟盘报告，订单有多个 tag 时附带按 tag 的汇总。
+func (e *Exchange) DryRunReport() string {
+	report := e.DryRunSummary().String()
+
+	byTag := e.DryRunSummaryByTag()
+	if len(byTag) <= 1 {
+		return report
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var sb strings.Builder
+	sb.WriteString(report)
+	fmt.Fprintf(&sb, "by tag:\n")
+	for _, tag := range tags {
+		s := byTag[tag]
+		name := tag
+		if name == "" {
+			name = "(untagged)"
+		}
+		fmt.Fprintf(&sb, "- %s: orders=%d filled=%d notional=%s fees=%s realized=%s unrealized=%s\n",
+			name, s.TotalOrders, s.FilledOrders, s.FilledNotional.String(), s.Fees.String(),
+			s.RealizedPnL.String(), s.UnrealizedPnL.String())
+	}
+	return sb.String()
+}
+
+func (s DryRunSummary) String() string {
+	var sb strings.Builder
+	if s.Tag != "" {
+		fmt.Fprintf(&sb, "polymarket dry-run report (tag %s)\n", s.Tag)
+	} else {
+		fmt.Fprintf(&sb, "polymarket dry-run report\n")
+	}
+	fmt.Fprintf(&sb, "orders: total=%d open=%d filled=%d canceled=%d\n",
+		s.TotalOrders, s.OpenOrders, s.FilledOrders, s.CanceledOrders)
+	fmt.Fprintf(&sb, "filled notional: %s USDC, fees: %s USDC\n", s.FilledNotional.String(), s.Fees.String())
+	fmt.Fprintf(&sb, "pnl: realized=%s unrealized=%s USDC\n", s.RealizedPnL.String(), s.UnrealizedPnL.String())
+	for _, p := range s.Positions {
+		fmt.Fprintf(&sb, "- %s: quantity=%s averageCost=%s markPrice=%s realized=%s unrealized=%s\n",
+			p.Symbol, p.Quantity.String(), p.AverageCost.String(), p.MarkPrice.String(),
+			p.RealizedPnL.String(), p.UnrealizedPnL.String())
+	}
+	return sb.String()
+}
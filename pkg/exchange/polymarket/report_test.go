@@ -0,0 +1,138 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_DryRunSummary(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+
+	submit := func(side types.SideType, price, quantity float64) *types.Order {
+		order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+		})
+		assert.NoError(t, err)
+		return order
+	}
+
+	// 0.4 买入 10，0.6 卖出 4，剩余 6 按参考价 0.5 计算未实现盈亏
+	submit(types.SideTypeBuy, 0.4, 10)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.4))
+	submit(types.SideTypeSell, 0.6, 4)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.6))
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+
+	canceled := submit(types.SideTypeBuy, 0.1, 10)
+	assert.NoError(t, ex.CancelOrders(ctx, *canceled))
+	submit(types.SideTypeBuy, 0.2, 10)
+
+	summary := ex.DryRunSummary()
+	assert.Equal(t, 4, summary.TotalOrders)
+	assert.Equal(t, 1, summary.OpenOrders)
+	assert.Equal(t, 2, summary.FilledOrders)
+	assert.Equal(t, 1, summary.CanceledOrders)
+	assert.Equal(t, "6.4", summary.FilledNotional.String())
+	assert.Equal(t, "0.8", summary.RealizedPnL.String())
+	assert.Equal(t, "0.6", summary.UnrealizedPnL.String())
+
+	if assert.Len(t, summary.Positions, 1) {
+		p := summary.Positions[0]
+		assert.Equal(t, symbol, p.Symbol)
+		assert.Equal(t, "6", p.Quantity.String())
+		assert.Equal(t, "0.4", p.AverageCost.String())
+		assert.Equal(t, "0.5", p.MarkPrice.String())
+	}
+
+	assert.Contains(t, ex.DryRunReport(), "orders: total=4 open=1 filled=2 canceled=1")
+}
+
+func TestExchange_DryRunSummaryByTag(t *testing.T) {
+	t.Setenv(envDryRunAutoFill, "true")
+	t.Setenv(envDryRunFillInterval, "1h")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ctx := context.Background()
+	symbol := "PM_BTC_15M_UP_YES_USDC"
+
+	submit := func(tag string, side types.SideType, price, quantity float64) *types.Order {
+		order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   symbol,
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+			Tag:      tag,
+		})
+		assert.NoError(t, err)
+		return order
+	}
+
+	// a: 0.4 买入 10，0.6 卖出 4；b: 0.3 买入 10；没有 tag 的订单被撤销
+	submit("a", types.SideTypeBuy, 0.4, 10)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.4))
+	submit("b", types.SideTypeBuy, 0.3, 10)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.3))
+	submit("a", types.SideTypeSell, 0.6, 4)
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.6))
+	ex.SetReferencePrice(symbol, fixedpoint.NewFromFloat(0.5))
+	canceled := submit("", types.SideTypeBuy, 0.1, 10)
+	assert.NoError(t, ex.CancelOrders(ctx, *canceled))
+
+	trades, err := ex.QueryTrades(ctx, symbol, nil)
+	assert.NoError(t, err)
+	for _, trade := range trades {
+		assert.NotEmpty(t, trade.Tag)
+	}
+
+	// 清理已结束的订单后按 tag 的订单数不变
+	assert.Equal(t, 4, ex.pruneOrders(time.Now().Add(48*time.Hour)))
+
+	byTag := ex.DryRunSummaryByTag()
+	if assert.Len(t, byTag, 3) {
+		a := byTag["a"]
+		assert.Equal(t, "a", a.Tag)
+		assert.Equal(t, 2, a.TotalOrders)
+		assert.Equal(t, 2, a.FilledOrders)
+		assert.Equal(t, "6.4", a.FilledNotional.String())
+		assert.Equal(t, "0.8", a.RealizedPnL.String())
+		assert.Equal(t, "0.6", a.UnrealizedPnL.String())
+
+		b := byTag["b"]
+		assert.Equal(t, 1, b.TotalOrders)
+		assert.Equal(t, "3", b.FilledNotional.String())
+		assert.True(t, b.RealizedPnL.IsZero())
+		assert.Equal(t, "2", b.UnrealizedPnL.String())
+
+		untagged := byTag[""]
+		assert.Equal(t, 1, untagged.CanceledOrders)
+		assert.Empty(t, untagged.Positions)
+	}
+
+	// 总汇总按合并后的平均成本（0.35）计算，与各 tag 之和不同
+	summary := ex.DryRunSummary()
+	assert.Equal(t, 4, summary.TotalOrders)
+	assert.Equal(t, "1", summary.RealizedPnL.String())
+	assert.Equal(t, "2.4", summary.UnrealizedPnL.String())
+
+	report := ex.DryRunReport()
+	assert.Contains(t, report, "by tag:")
+	assert.Contains(t, report, "- a: orders=2 filled=2 notional=6.4")
+	assert.Contains(t, report, "- (untagged): orders=1")
+}
@@ -0,0 +1,169 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 历史结算结果：按窗口拼出 up/down 市场的 slug 逐个查询 Gamma，取已结算市场的 outcome，用于回测时对齐 K 线。
+// - POLYMARKET_RESOLUTIONS_CACHE_DIR 结算结果的磁盘缓存目录（默认 ~/.bbgo/cache/polymarket-resolutions），
+//   每个窗口一个 <slug>.json，结算结果不会再变化，命中缓存时不再请求 Gamma
+
+const envResolutionsCacheDir = "POLYMARKET_RESOLUTIONS_CACHE_DIR"
+
+const (
+	OutcomeUp   = "Up"
+	OutcomeDown = "Down"
+)
+
+// UpDownResolution 是一个已结算窗口的结果。
+// WindowStart 与同一 interval 的 K 线 StartTime 相同，回测时可以直接用它对齐 K 线。
+type UpDownResolution struct {
+	Slug        string    `json:"slug"`
+	ConditionID string    `json:"conditionId"`
+	WindowStart time.Time `json:"windowStart"`
+	WindowEnd   time.Time `json:"windowEnd"`
+
+	// Outcome 为 OutcomeUp 或 OutcomeDown
+	Outcome string `json:"outcome"`
+}
+
+// Up 返回该窗口是否结算为 Up。
+func (r UpDownResolution) Up() bool {
+	return r.Outcome == OutcomeUp
+}
+
+// resolvedOutcome 返回已结算市场赢的 outcome，未结算（未关闭或没有价格为 1 的 outcome）时 ok 为 false。
+func (m GammaMarket) resolvedOutcome() (outcome string, ok bool, err error) {
+	if !m.Closed || m.OutcomePrices == "" {
+		return "", false, nil
+	}
+
+	var outcomes, prices []string
+	if err := json.Unmarshal([]byte(m.Outcomes), &outcomes); err != nil {
+		return "", false, fmt.Errorf("polymarket: decode outcomes of %s failed: %w", m.Slug, err)
+	}
+	if err := json.Unmarshal([]byte(m.OutcomePrices), &prices); err != nil {
+		return "", false, fmt.Errorf("polymarket: decode outcomePrices of %s failed: %w", m.Slug, err)
+	}
+	if len(outcomes) != len(prices) {
+		return "", false, fmt.Errorf("polymarket: %s has %d outcomes but %d prices", m.Slug, len(outcomes), len(prices))
+	}
+
+	for i, price := range prices {
+		if p, err := strconv.ParseFloat(price, 64); err == nil && p == 1 {
+			return outcomes[i], true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// QueryUpDownResolutions 查询 asset 在 [since, until) 内开始、且已经结束的窗口的结算结果，按 WindowStart 升序返回。
+// Gamma 上不存在的窗口以及尚未结算的窗口会被跳过。
+func (e *Exchange) QueryUpDownResolutions(ctx context.Context, asset string, interval types.Interval, since, until time.Time) ([]UpDownResolution, error) {
+	d := interval.Duration()
+	if _, _, err := upDownSlug(asset, interval, since); err != nil {
+		return nil, err
+	}
+
+	cacheDir := resolutionsCacheDir()
+	now := time.Now()
+
+	var resolutions []UpDownResolution
+	for at := since.Truncate(d); at.Before(until) && !at.Add(d).After(now); at = at.Add(d) {
+		slug, start, _ := upDownSlug(asset, interval, at)
+
+		if r, ok := loadResolution(cacheDir, slug); ok {
+			resolutions = append(resolutions, r)
+			continue
+		}
+
+		gm, err := e.gamma.queryGammaMarketBySlug(ctx, slug)
+		if errors.Is(err, errGammaMarketNotFound) {
+			log.Debugf("skip up/down window %s: market not found", slug)
+			continue
+		} else if err != nil {
+			return resolutions, err
+		}
+
+		outcome, ok, err := gm.resolvedOutcome()
+		if err != nil {
+			return resolutions, err
+		} else if !ok {
+			log.Debugf("skip up/down window %s: market is not resolved yet", slug)
+			continue
+		}
+
+		r := UpDownResolution{
+			Slug:        slug,
+			ConditionID: gm.ConditionID,
+			WindowStart: start,
+			WindowEnd:   start.Add(d),
+			Outcome:     outcome,
+		}
+		if err := saveResolution(cacheDir, r); err != nil {
+			log.WithError(err).Warnf("failed to cache the resolution of %s", slug)
+		}
+		resolutions = append(resolutions, r)
+	}
+	return resolutions, nil
+}
+
+// resolutionsCacheDir 返回结算结果的缓存目录，取不到 home 目录时返回空字符串（不缓存）。
+func resolutionsCacheDir() string {
+	if dir := envString(envResolutionsCacheDir, ""); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".bbgo", "cache", "polymarket-resolutions")
+}
+
+func loadResolution(dir, slug string) (r UpDownResolution, ok bool) {
+	if dir == "" {
+		return r, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, slug+".json"))
+	if err != nil {
+		return r, false
+	}
+	if err := json.Unmarshal(data, &r); err != nil || r.Slug != slug {
+		log.Warnf("ignore invalid cached resolution of %s", slug)
+		return r, false
+	}
+	return r, true
+}
+
+// saveResolution 先写临时文件再 rename，避免进程中断留下不完整的缓存。
+func saveResolution(dir string, r UpDownResolution) error {
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, r.Slug+".json")
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,94 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_QueryUpDownResolutions(t *testing.T) {
+	t.Setenv(envResolutionsCacheDir, t.TempDir())
+
+	since := time.Date(2025, 10, 15, 8, 7, 0, 0, time.UTC)
+	until := time.Date(2025, 10, 15, 9, 0, 0, 0, time.UTC)
+
+	calls := map[string]int{}
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		slug := req.URL.Query().Get("slug")
+		calls[slug]++
+		switch slug {
+		case "btc-updown-15m-1760515200":
+			return httptesting.BuildResponseString(http.StatusOK, `[{
+				"conditionId": "0xabc",
+				"slug": "btc-updown-15m-1760515200",
+				"outcomes": "[\"Up\", \"Down\"]",
+				"outcomePrices": "[\"0\", \"1\"]",
+				"closed": true
+			}]`), nil
+		case "btc-updown-15m-1760516100":
+			return httptesting.BuildResponseString(http.StatusOK, `[{
+				"conditionId": "0xdef",
+				"slug": "btc-updown-15m-1760516100",
+				"outcomes": "[\"Up\", \"Down\"]",
+				"outcomePrices": "[\"1\", \"0\"]",
+				"closed": true
+			}]`), nil
+		case "btc-updown-15m-1760517000":
+			// 已结束但尚未结算
+			return httptesting.BuildResponseString(http.StatusOK, `[{
+				"slug": "btc-updown-15m-1760517000",
+				"outcomes": "[\"Up\", \"Down\"]",
+				"outcomePrices": "[\"0.5\", \"0.5\"]",
+				"closed": false
+			}]`), nil
+		}
+		return httptesting.BuildResponseString(http.StatusOK, `[]`), nil
+	})
+
+	ex := New("", "", "")
+	ex.gamma = newTestRestClient(transport)
+
+	resolutions, err := ex.QueryUpDownResolutions(context.Background(), "BTC", types.Interval15m, since, until)
+	assert.NoError(t, err)
+	if assert.Len(t, resolutions, 2) {
+		assert.Equal(t, time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC), resolutions[0].WindowStart.UTC())
+		assert.Equal(t, time.Date(2025, 10, 15, 8, 15, 0, 0, time.UTC), resolutions[0].WindowEnd.UTC())
+		assert.Equal(t, OutcomeDown, resolutions[0].Outcome)
+		assert.False(t, resolutions[0].Up())
+		assert.Equal(t, "0xdef", resolutions[1].ConditionID)
+		assert.True(t, resolutions[1].Up())
+	}
+	assert.Len(t, calls, 4)
+
+	// 已结算的窗口从磁盘缓存读取，未结算/不存在的窗口重新查询
+	cached, err := ex.QueryUpDownResolutions(context.Background(), "BTC", types.Interval15m, since, until)
+	assert.NoError(t, err)
+	assert.Equal(t, len(resolutions), len(cached))
+	assert.Equal(t, 1, calls["btc-updown-15m-1760515200"])
+	assert.Equal(t, 1, calls["btc-updown-15m-1760516100"])
+	assert.Equal(t, 2, calls["btc-updown-15m-1760517000"])
+}
+
+func TestGammaMarket_ResolvedOutcome(t *testing.T) {
+	m := GammaMarket{Slug: "x", Outcomes: `["Up", "Down"]`, OutcomePrices: `["1", "0"]`}
+	_, ok, err := m.resolvedOutcome()
+	assert.NoError(t, err)
+	assert.False(t, ok, "market is not closed")
+
+	m.Closed = true
+	outcome, ok, err := m.resolvedOutcome()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, OutcomeUp, outcome)
+
+	m.OutcomePrices = `["1"]`
+	_, _, err = m.resolvedOutcome()
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// 流动性奖励：部分市场按挂单距中间价的价差与挂单数量发放奖励（liquidity rewards）。
+// 只有距中间价不超过 MaxSpread、数量不小于 MinSize 的挂单计入奖励，做市策略可以据此把报价放在奖励区间内。
+// 配置来自 Gamma 的 rewardsMinSize / rewardsMaxSpread / clobRewards。
+
+var centsPerDollar = fixedpoint.NewFromInt(100)
+
+// MarketRewards 为市场的流动性奖励配置。
+type MarketRewards struct {
+	// MinSize 为计入奖励的最小挂单数量（shares）
+	MinSize fixedpoint.Value
+	// MaxSpread 为计入奖励的挂单距中间价的最大价差（概率价格，Gamma 返回的是美分，例如 3.5 换算为 0.035）
+	MaxSpread fixedpoint.Value
+	// DailyRate 为每天发放的奖励（USDC），多个奖励计划时为合计
+	DailyRate fixedpoint.Value
+}
+
+// toMarketRewards 从 Gamma market 提取奖励配置，没有奖励时返回 nil。
+func toMarketRewards(gm *GammaMarket) *MarketRewards {
+	r := &MarketRewards{
+		MinSize:   fixedpoint.NewFromFloat(gm.RewardsMinSize),
+		MaxSpread: fixedpoint.NewFromFloat(gm.RewardsMaxSpread).Div(centsPerDollar),
+	}
+	for _, reward := range gm.ClobRewards {
+		r.DailyRate = r.DailyRate.Add(fixedpoint.NewFromFloat(reward.RewardsDailyRate))
+	}
+
+	if r.MaxSpread.Sign() <= 0 && r.DailyRate.Sign() <= 0 {
+		return nil
+	}
+	return r
+}
+
+// InBand 返回中间价为 midpoint 时，price 上数量为 quantity 的挂单是否计入奖励。
+func (r *MarketRewards) InBand(midpoint, price, quantity fixedpoint.Value) bool {
+	if r == nil || r.MaxSpread.Sign() <= 0 {
+		return false
+	}
+	if quantity.Compare(r.MinSize) < 0 {
+		return false
+	}
+	return price.Sub(midpoint).Abs().Compare(r.MaxSpread) <= 0
+}
+
+// QueryMarketRewards 查询 symbol 所属市场的流动性奖励配置，市场没有奖励时返回 nil。
+func (e *Exchange) QueryMarketRewards(ctx context.Context, symbol string) (*MarketRewards, error) {
+	_, meta, err := e.QueryMarket(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("polymarket: market %s has no CLOB token id (localSymbol)", symbol)
+	}
+	return meta.Rewards, nil
+}
@@ -0,0 +1,75 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestMarketRewards_InBand(t *testing.T) {
+	r := toMarketRewards(&GammaMarket{
+		RewardsMinSize:   50,
+		RewardsMaxSpread: 3.5,
+		ClobRewards:      []GammaClobReward{{RewardsDailyRate: 10}, {RewardsDailyRate: 5}},
+	})
+	if !assert.NotNil(t, r) {
+		return
+	}
+	assert.Equal(t, "50", r.MinSize.String())
+	assert.Equal(t, "0.035", r.MaxSpread.String())
+	assert.Equal(t, "15", r.DailyRate.String())
+
+	mid := fixedpoint.NewFromFloat(0.5)
+	size := fixedpoint.NewFromFloat(100)
+	assert.True(t, r.InBand(mid, fixedpoint.NewFromFloat(0.47), size))
+	assert.True(t, r.InBand(mid, fixedpoint.NewFromFloat(0.535), size))
+	assert.False(t, r.InBand(mid, fixedpoint.NewFromFloat(0.46), size))
+	assert.False(t, r.InBand(mid, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(10)))
+
+	// 没有奖励的市场
+	assert.Nil(t, toMarketRewards(&GammaMarket{}))
+	var none *MarketRewards
+	assert.False(t, none.InBand(mid, mid, size))
+}
+
+func TestExchange_QueryMarketRewards(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[
+		{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"},
+		{"symbol": "PM_EXAMPLE", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}
+	]`)
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/markets", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `[{
+			"conditionId": "0xabc",
+			"outcomes": "[\"Yes\", \"No\"]",
+			"clobTokenIds": "[\"111111111111\", \"222222222222\"]",
+			"rewardsMinSize": 20,
+			"rewardsMaxSpread": 3,
+			"clobRewards": [{"id": "1", "conditionId": "0xabc", "rewardsAmount": 0, "rewardsDailyRate": 25, "startDate": "2025-10-01", "endDate": "2500-12-31"}]
+		}]`), nil
+	})
+
+	ex := New("", "", "")
+	ex.gamma = newTestRestClient(transport)
+	ctx := context.Background()
+
+	rewards, err := ex.QueryMarketRewards(ctx, "PM_YES")
+	if assert.NoError(t, err) && assert.NotNil(t, rewards) {
+		assert.Equal(t, "20", rewards.MinSize.String())
+		assert.Equal(t, "0.03", rewards.MaxSpread.String())
+		assert.Equal(t, "25", rewards.DailyRate.String())
+	}
+
+	_, meta, err := ex.QueryMarket(ctx, "PM_YES")
+	assert.NoError(t, err)
+	assert.Equal(t, rewards, meta.Rewards)
+
+	_, err = ex.QueryMarketRewards(ctx, "PM_EXAMPLE")
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package polymarket
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SnappedOrder 是按 market 精度调整后的下单参数。
+type SnappedOrder struct {
+	Price    fixedpoint.Value
+	Quantity fixedpoint.Value
+	// Notional 为 Price * Quantity，Fee 为按 feeRateBps 估算的手续费，Notional + Fee 不会超过期望的下注金额
+	Notional fixedpoint.Value
+	Fee      fixedpoint.Value
+}
+
+// SnapOrder 把期望的下注金额 quoteAmount 调整成 market 可以接受的 (price, quantity)：
+// - price 按 tickSize 向下取整，并限制在 [tickSize, 1 - tickSize] 之间（概率价格不能是 0 或 1）
+// - quantity = quoteAmount / price，按 stepSize 向下截断，保证金额不超出预算
+// ok 为 false 表示调整后的数量低于 MinQuantity 或金额低于 MinNotional，这样的订单会被拒绝。
+// 不计手续费，市场收费时用 SnapOrderWithFee。
+func SnapOrder(market types.Market, price, quoteAmount fixedpoint.Value) (order SnappedOrder, ok bool) {
+	return SnapOrderWithFee(market, price, quoteAmount, 0)
+}
+
+// SnapOrderWithFee 与 SnapOrder 相同，但按 feeRateBps 把手续费计入成本：
+// 每单位成本为 price + feeRate * min(price, 1 - price)（见 tradeFee），quantity = quoteAmount / 每单位成本，
+// 保证 Notional + Fee 不超过 quoteAmount，且再多一个 step 就会超出。
+func SnapOrderWithFee(market types.Market, price, quoteAmount fixedpoint.Value, feeRateBps int) (order SnappedOrder, ok bool) {
+	if tick := market.TickSize; tick.Sign() > 0 {
+		price = floorToStep(price, tick)
+		price = fixedpoint.Min(fixedpoint.Max(price, tick), fixedpoint.One.Sub(tick))
+	}
+	if price.Sign() <= 0 || quoteAmount.Sign() <= 0 {
+		return SnappedOrder{Price: price}, false
+	}
+
+	bps := fixedpoint.NewFromInt(int64(feeRateBps))
+	unitCost := price.Add(tradeFee(price, fixedpoint.One, bps))
+	quantity := quoteAmount.Div(unitCost)
+	if step := market.StepSize; step.Sign() > 0 {
+		quantity = floorToStep(quantity, step)
+	}
+	order = SnappedOrder{
+		Price:    price,
+		Quantity: quantity,
+		Notional: price.Mul(quantity),
+		Fee:      tradeFee(price, quantity, bps),
+	}
+
+	if quantity.Sign() <= 0 || quantity.Compare(market.MinQuantity) < 0 || order.Notional.Compare(market.MinNotional) < 0 {
+		return order, false
+	}
+	return order, true
+}
+
+// snapTolerance（以 step 为单位）用来吸收 fixedpoint 乘除法（经过 float64 并截断）的误差：
+// 例如 1.1 / 0.5 得到 2.19999999，直接向下取整到 0.01 会变成 2.19。
+var snapTolerance = fixedpoint.NewFromFloat(1e-4)
+
+// floorToStep 把 v 向下取整到 step 的整数倍。
+func floorToStep(v, step fixedpoint.Value) fixedpoint.Value {
+	return v.Div(step).Add(snapTolerance).Floor().Mul(step)
+}
+
+// ceilToStep 把 v 向上取整到 step 的整数倍。
+func ceilToStep(v, step fixedpoint.Value) fixedpoint.Value {
+	return v.Div(step).Sub(snapTolerance).Ceil().Mul(step)
+}
+
+// roundOrderPrice 按 market 当前的 tickSize 调整限价：买单向下、卖单向上取整，调整后的价格不会比原限价差。
+// tick size 可能在运行中变化（见 updateTickSize），所以每次下单时读取最新的 market。
+func (e *Exchange) roundOrderPrice(order types.SubmitOrder) types.SubmitOrder {
+	e.mu.RLock()
+	market, ok := e.markets[order.Symbol]
+	e.mu.RUnlock()
+
+	if !ok || market.TickSize.Sign() <= 0 || order.Price.Sign() <= 0 {
+		return order
+	}
+
+	switch order.Side {
+	case types.SideTypeBuy:
+		order.Price = floorToStep(order.Price, market.TickSize)
+	case types.SideTypeSell:
+		order.Price = ceilToStep(order.Price, market.TickSize)
+	}
+	return order
+}
+
+// prepareOrder 按 tick 取整限价，检查价格边界与单笔订单上限（见 order_cap.go），SubmitOrder 与 SimulateOrder 共用。
+func (e *Exchange) prepareOrder(order types.SubmitOrder) (types.SubmitOrder, error) {
+	order = e.roundOrderPrice(order)
+	if err := e.checkOrderPrice(order); err != nil {
+		return order, err
+	}
+	return order, e.orderCap.check(order)
+}
+
+// checkOrderPrice 检查按 tick 取整后的限价在概率价格的边界内：有 tickSize 时为 [tickSize, 1 - tickSize]，否则为 (0, 1)。
+// 临近结算时价格会到 0.001 / 0.999 附近，取整后可能变成 0 或 1（买单向下、卖单向上取整），这样的订单直接拒绝，
+// 而不是改成更差的价格或提交一个数量爆炸的订单。
+func (e *Exchange) checkOrderPrice(order types.SubmitOrder) error {
+	if order.Price.Sign() <= 0 || order.Price.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("polymarket: price %s of %s is out of range (0, 1)", order.Price.String(), order.Symbol)
+	}
+
+	e.mu.RLock()
+	market, ok := e.markets[order.Symbol]
+	e.mu.RUnlock()
+
+	if tick := market.TickSize; ok && tick.Sign() > 0 {
+		if order.Price.Compare(tick) < 0 || order.Price.Compare(fixedpoint.One.Sub(tick)) > 0 {
+			return fmt.Errorf("polymarket: price %s of %s is out of range [%s, %s]",
+				order.Price.String(), order.Symbol, tick.String(), fixedpoint.One.Sub(tick).String())
+		}
+	}
+	return nil
+}
+
+// SnapOrder 按 symbol 的 market 精度与费率调整下单参数，见 SnapOrderWithFee。
+func (e *Exchange) SnapOrder(symbol string, price, quoteAmount fixedpoint.Value) (SnappedOrder, bool, error) {
+	e.mu.RLock()
+	market, found := e.markets[symbol]
+	e.mu.RUnlock()
+
+	if !found {
+		return SnappedOrder{}, false, fmt.Errorf("polymarket: market %s not found", symbol)
+	}
+
+	order, ok := SnapOrderWithFee(market, price, quoteAmount, e.fees.feeRateBps(symbol))
+	return order, ok, nil
+}
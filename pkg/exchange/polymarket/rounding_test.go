@@ -0,0 +1,220 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestSnapOrder(t *testing.T) {
+	market := types.Market{
+		Symbol:      "PM_YES",
+		TickSize:    fixedpoint.NewFromFloat(0.01),
+		StepSize:    fixedpoint.NewFromFloat(0.01),
+		MinQuantity: fixedpoint.NewFromFloat(5),
+		MinNotional: fixedpoint.NewFromFloat(1),
+	}
+
+	tests := []struct {
+		name                    string
+		price, quote            float64
+		wantPrice, wantQuantity string
+		wantNotional            string
+		wantOK                  bool
+	}{
+		{name: "exact", price: 0.5, quote: 5, wantPrice: "0.5", wantQuantity: "10", wantNotional: "5", wantOK: true},
+		{name: "truncate price and quantity", price: 0.337, quote: 5, wantPrice: "0.33", wantQuantity: "15.15", wantNotional: "4.9995", wantOK: true},
+		{name: "clamp price to 1 - tick", price: 1, quote: 5, wantPrice: "0.99", wantQuantity: "5.05", wantNotional: "4.99949999", wantOK: true},
+		{name: "clamp price to tick", price: 0.001, quote: 1, wantPrice: "0.01", wantQuantity: "100", wantNotional: "1", wantOK: true},
+		{name: "below min notional", price: 0.1, quote: 0.9, wantPrice: "0.1", wantQuantity: "9", wantNotional: "0.9", wantOK: false},
+		{name: "below min quantity", price: 0.9, quote: 4, wantPrice: "0.9", wantQuantity: "4.44", wantNotional: "3.996", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, ok := SnapOrder(market, fixedpoint.NewFromFloat(tt.price), fixedpoint.NewFromFloat(tt.quote))
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantPrice, order.Price.String())
+			assert.Equal(t, tt.wantQuantity, order.Quantity.String())
+			assert.Equal(t, tt.wantNotional, order.Notional.String())
+		})
+	}
+}
+
+func TestSnapOrderWithFee(t *testing.T) {
+	market := types.Market{
+		Symbol:   "PM_YES",
+		TickSize: fixedpoint.NewFromFloat(0.01),
+		StepSize: fixedpoint.NewFromFloat(0.01),
+	}
+
+	for _, tt := range []struct {
+		price, quote float64
+		feeRateBps   int
+	}{
+		{0.5, 5, 0},
+		{0.5, 5, 200},
+		{0.33, 5, 100},
+		{0.9, 10, 1000},
+		{0.07, 3, 250},
+		{0.99, 5, 10000},
+	} {
+		price, quote := fixedpoint.NewFromFloat(tt.price), fixedpoint.NewFromFloat(tt.quote)
+		order, ok := SnapOrderWithFee(market, price, quote, tt.feeRateBps)
+		assert.True(t, ok)
+
+		// 含手续费的总成本不超过预算，再多一个 step 就会超出
+		bps := fixedpoint.NewFromInt(int64(tt.feeRateBps))
+		cost := order.Notional.Add(order.Fee)
+		assert.LessOrEqual(t, cost.Compare(quote), 0, "price %v fee %d: cost %s > %s", tt.price, tt.feeRateBps, cost.String(), quote.String())
+		more := order.Quantity.Add(market.StepSize)
+		assert.Greater(t, price.Mul(more).Add(tradeFee(price, more, bps)).Compare(quote), 0, "price %v fee %d: quantity %s is not maximal", tt.price, tt.feeRateBps, order.Quantity.String())
+		assert.Equal(t, tradeFee(price, order.Quantity, bps), order.Fee)
+	}
+
+	// 费率 200bps、价格 0.5 时每单位成本 0.51
+	order, _ := SnapOrderWithFee(market, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(5), 200)
+	assert.Equal(t, "9.8", order.Quantity.String())
+	assert.Equal(t, "0.098", order.Fee.String())
+}
+
+func TestExchange_SnapOrder(t *testing.T) {
+	ex := New("", "", "")
+	ex.markets = defaultExampleMarkets()
+
+	order, ok, err := ex.SnapOrder("PM_BTC_15M_UP_YES_USDC", fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(5))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "10", order.Quantity.String())
+
+	_, _, err = ex.SnapOrder("UNKNOWN", fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(5))
+	assert.Error(t, err)
+
+	// 按 symbol 的费率计入手续费
+	ex.fees.setMarketFeeBps("PM_BTC_15M_UP_YES_USDC", 200)
+	assert.Equal(t, 200, ex.FeeRateBps("PM_BTC_15M_UP_YES_USDC"))
+	order, ok, err = ex.SnapOrder("PM_BTC_15M_UP_YES_USDC", fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(5))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "9.8", order.Quantity.String())
+}
+
+func TestExchange_RoundingAfterTickSizeChange(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01", "minNotional": "1", "minQuantity": "1"}]`)
+
+	ex := New("", "", "")
+	ctx := context.Background()
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	var reloaded []types.MarketMap
+	ex.OnMarketsReloaded(func(markets types.MarketMap) {
+		reloaded = append(reloaded, markets)
+	})
+
+	submit := func(side types.SideType) string {
+		order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol:   "PM_YES",
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(0.5555),
+			Quantity: fixedpoint.NewFromFloat(10),
+		})
+		assert.NoError(t, err)
+		return order.Price.String()
+	}
+
+	// 买单向下、卖单向上取整到 0.01
+	assert.Equal(t, "0.55", submit(types.SideTypeBuy))
+	assert.Equal(t, "0.56", submit(types.SideTypeSell))
+
+	stream := ex.NewStream().(*Stream)
+	stream.dispatchEvent([]interface{}{&TickSizeChangeEvent{
+		AssetID:     "111111111111",
+		OldTickSize: fixedpoint.NewFromFloat(0.01),
+		NewTickSize: fixedpoint.NewFromFloat(0.001),
+	}})
+
+	// 之后的下单按新的 tick 取整
+	assert.Equal(t, "0.555", submit(types.SideTypeBuy))
+	assert.Equal(t, "0.556", submit(types.SideTypeSell))
+
+	snapped, ok, err := ex.SnapOrder("PM_YES", fixedpoint.NewFromFloat(0.5555), fixedpoint.NewFromFloat(5))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "0.555", snapped.Price.String())
+
+	if assert.Len(t, reloaded, 1) {
+		assert.Equal(t, "0.001", reloaded[0]["PM_YES"].TickSize.String())
+	}
+
+	// tick size 没有变化时不触发回调
+	ex.updateTickSize("PM_YES", fixedpoint.NewFromFloat(0.001))
+	assert.Len(t, reloaded, 1)
+}
+
+func TestExchange_PriceBoundaries(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01", "minNotional": "1", "minQuantity": "1"}]`)
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ctx := context.Background()
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	order := func(side types.SideType, price float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "PM_YES",
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(10),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		side    types.SideType
+		price   float64
+		wantErr bool
+	}{
+		// 0.001 向下取整到 0，不能改成更高的买价
+		{name: "buy at 0.001", side: types.SideTypeBuy, price: 0.001, wantErr: true},
+		{name: "buy at 0.5", side: types.SideTypeBuy, price: 0.5},
+		{name: "buy at 0.999", side: types.SideTypeBuy, price: 0.999},
+		{name: "sell at 0.001", side: types.SideTypeSell, price: 0.001},
+		{name: "sell at 0.5", side: types.SideTypeSell, price: 0.5},
+		// 0.999 向上取整到 1
+		{name: "sell at 0.999", side: types.SideTypeSell, price: 0.999, wantErr: true},
+		{name: "buy above 1", side: types.SideTypeBuy, price: 1.2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := ex.SubmitOrder(ctx, order(tt.side, tt.price))
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "out of range")
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.True(t, created.Price.Sign() > 0 && created.Price.Compare(fixedpoint.One) < 0)
+			}
+		})
+	}
+
+	// 批量下单只拒绝越界的订单
+	created, err := ex.SubmitOrders(ctx, order(types.SideTypeBuy, 0.5), order(types.SideTypeBuy, 0.001))
+	var batchErr *BatchOrderError
+	if assert.ErrorAs(t, err, &batchErr) {
+		assert.NoError(t, batchErr.Errors[0])
+		assert.ErrorContains(t, batchErr.Errors[1], "out of range")
+	}
+	if assert.Len(t, created, 2) {
+		assert.NotZero(t, created[0].OrderID)
+		assert.Zero(t, created[1].OrderID)
+	}
+}
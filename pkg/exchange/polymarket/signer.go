@@ -0,0 +1,281 @@
+package polymarket
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+// 真实下单签名用的钱包私钥，按以下优先级加载（找到第一个即停止）：
+// 1. New 的 privateKey 参数（exchange factory 传入 session 的 <PREFIX>_API_PRIVATE_KEY）
+// 2. POLYMARKET_PRIVATE_KEY：hex 私钥，可以带 0x 前缀
+// 3. POLYMARKET_KEYSTORE_FILE：以太坊 keystore v3 文件（scrypt/pbkdf2 + aes-128-ctr），
+//    密码取 POLYMARKET_KEYSTORE_PASSWORD，未设置时读取 POLYMARKET_KEYSTORE_PASSWORD_FILE 文件内容
+// 都没有配置时不加载私钥，只能 dry-run。私钥只保存在内存里，不会被打印，Exchange.Close 时清零。
+// secp256k1 曲线运算使用 github.com/decred/dcrd/dcrec/secp256k1（常数时间实现），标准库 crypto/elliptic 没有提供该曲线；
+// 私钥通过 signer.sign 用于订单与链上交易的签名，签名使用 RFC 6979 的确定性 k。
+// 配置了 POLYMARKET_SIGNER_ADDRESS 时会检查它与私钥推导的地址一致，未配置时 L2 鉴权使用私钥的地址。
+
+const (
+	envPrivateKey           = "POLYMARKET_PRIVATE_KEY"
+	envKeystoreFile         = "POLYMARKET_KEYSTORE_FILE"
+	envKeystorePassword     = "POLYMARKET_KEYSTORE_PASSWORD"
+	envKeystorePasswordFile = "POLYMARKET_KEYSTORE_PASSWORD_FILE"
+)
+
+// signer 持有钱包私钥，String/GoString 只输出地址，避免私钥出现在日志里。
+type signer struct {
+	key     *secp256k1.PrivateKey
+	address string
+}
+
+func (s *signer) String() string { return fmt.Sprintf("signer(%s)", s.address) }
+
+func (s *signer) GoString() string { return s.String() }
+
+// zero 清零私钥
+func (s *signer) zero() {
+	if s == nil || s.key == nil {
+		return
+	}
+
+	s.key.Zero()
+	s.key = nil
+}
+
+// sign 对 32 字节的 hash 签名，返回以太坊格式的 r || s || v（65 字节），v 为 recovery id（0 或 1），
+// 订单签名与交易签名再各自换算成 27/28 或 EIP-155 的 v。
+func (s *signer) sign(hash []byte) ([]byte, error) {
+	if s == nil || s.key == nil {
+		return nil, fmt.Errorf("polymarket: a wallet private key is required to sign, see %s", envPrivateKey)
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("polymarket: signing hash must be 32 bytes, got %d", len(hash))
+	}
+
+	// SignCompact 返回 (27 + recovery id) || r || s，未压缩公钥不加 4
+	compact := ecdsa.SignCompact(s.key, hash, false)
+	sig := make([]byte, 65)
+	copy(sig, compact[1:])
+	sig[64] = compact[0] - 27
+	return sig, nil
+}
+
+// loadSigner 按优先级加载私钥，没有配置时返回 nil。
+func loadSigner(privateKey string) (*signer, error) {
+	if privateKey == "" {
+		privateKey = envString(envPrivateKey, "")
+	}
+	if privateKey != "" {
+		key, err := parsePrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return newSigner(key), nil
+	}
+
+	path := envString(envKeystoreFile, "")
+	if path == "" {
+		return nil, nil
+	}
+
+	password, err := keystorePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: read %s failed: %w", envKeystoreFile, err)
+	}
+
+	raw, err := decryptKeystore(data, password)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(raw)
+
+	key, err := toPrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return newSigner(key), nil
+}
+
+func keystorePassword() (string, error) {
+	if password, ok := os.LookupEnv(envKeystorePassword); ok {
+		return password, nil
+	}
+
+	if path := envString(envKeystorePasswordFile, ""); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("polymarket: read %s failed: %w", envKeystorePasswordFile, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	return "", fmt.Errorf("polymarket: %s or %s is required to decrypt %s", envKeystorePassword, envKeystorePasswordFile, envKeystoreFile)
+}
+
+func newSigner(key *secp256k1.PrivateKey) *signer {
+	return &signer{key: key, address: addressOf(key.PubKey())}
+}
+
+// parsePrivateKey 解析 hex 私钥，错误信息里不会包含私钥内容。
+func parsePrivateKey(s string) (*secp256k1.PrivateKey, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "0x"), "0X")
+
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: private key is not valid hex")
+	}
+	defer zeroBytes(raw)
+
+	return toPrivateKey(raw)
+}
+
+func toPrivateKey(raw []byte) (*secp256k1.PrivateKey, error) {
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("polymarket: private key must be 32 bytes, got %d", len(raw))
+	}
+
+	// PrivKeyFromBytes 会把超出范围的值按 N 取模，这里先检查范围
+	var d secp256k1.ModNScalar
+	if overflow := d.SetByteSlice(raw); overflow || d.IsZero() {
+		d.Zero()
+		return nil, fmt.Errorf("polymarket: private key is out of the secp256k1 range")
+	}
+	return secp256k1.NewPrivateKey(&d), nil
+}
+
+// addressOf 返回公钥对应的以太坊地址（小写 hex）：keccak256(X || Y) 的后 20 字节。
+func addressOf(pub *secp256k1.PublicKey) string {
+	// 未压缩格式为 0x04 || X || Y
+	return "0x" + hex.EncodeToString(keccak256(pub.SerializeUncompressed()[1:])[12:])
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// keystoreV3 为以太坊 keystore v3 文件中解密需要的字段
+type keystoreV3 struct {
+	Version int `json:"version"`
+	Crypto  struct {
+		Cipher       string `json:"cipher"`
+		CipherText   string `json:"ciphertext"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		KDF       string `json:"kdf"`
+		KDFParams struct {
+			DKLen int    `json:"dklen"`
+			Salt  string `json:"salt"`
+			// scrypt
+			N int `json:"n"`
+			R int `json:"r"`
+			P int `json:"p"`
+			// pbkdf2
+			C   int    `json:"c"`
+			PRF string `json:"prf"`
+		} `json:"kdfparams"`
+		MAC string `json:"mac"`
+	} `json:"crypto"`
+}
+
+// decryptKeystore 解密 keystore v3 文件，返回私钥的原始字节。
+func decryptKeystore(data []byte, password string) ([]byte, error) {
+	var ks keystoreV3
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("polymarket: decode keystore failed: %w", err)
+	}
+	if ks.Version != 3 {
+		return nil, fmt.Errorf("polymarket: unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("polymarket: unsupported keystore cipher %q", ks.Crypto.Cipher)
+	}
+
+	decode := func(name, s string) ([]byte, error) {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: invalid keystore %s: %w", name, err)
+		}
+		return b, nil
+	}
+
+	salt, err := decode("salt", ks.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := decode("iv", ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := decode("ciphertext", ks.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := decode("mac", ks.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	params := ks.Crypto.KDFParams
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("polymarket: keystore dklen must be at least 32, got %d", params.DKLen)
+	}
+
+	var derivedKey []byte
+	switch ks.Crypto.KDF {
+	case "scrypt":
+		derivedKey, err = scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DKLen)
+	case "pbkdf2":
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("polymarket: unsupported keystore pbkdf2 prf %q", params.PRF)
+		}
+		derivedKey, err = pbkdf2.Key(sha256.New, password, salt, params.C, params.DKLen)
+	default:
+		return nil, fmt.Errorf("polymarket: unsupported keystore kdf %q", ks.Crypto.KDF)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: derive keystore key failed: %w", err)
+	}
+	defer zeroBytes(derivedKey)
+
+	if subtle.ConstantTimeCompare(keccak256(derivedKey[16:32], cipherText), mac) != 1 {
+		return nil, fmt.Errorf("polymarket: keystore password is incorrect")
+	}
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(raw, cipherText)
+	return raw, nil
+}
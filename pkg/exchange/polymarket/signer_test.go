@@ -0,0 +1,189 @@
+package polymarket
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	testPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	testAddress    = "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	for _, s := range []string{testPrivateKey, "0x" + testPrivateKey} {
+		key, err := parsePrivateKey(s)
+		if assert.NoError(t, err) {
+			assert.True(t, key.PubKey().IsOnCurve())
+			assert.Equal(t, testAddress, addressOf(key.PubKey()))
+		}
+	}
+
+	key, err := parsePrivateKey("0000000000000000000000000000000000000000000000000000000000000001")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", addressOf(key.PubKey()))
+	}
+
+	_, err = parsePrivateKey("not-hex")
+	assert.Error(t, err)
+	_, err = parsePrivateKey("0x1234")
+	assert.Error(t, err)
+	_, err = parsePrivateKey(hex.EncodeToString(make([]byte, 32)))
+	assert.Error(t, err)
+	// 等于曲线阶 N
+	_, err = parsePrivateKey("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	assert.ErrorContains(t, err, "out of the secp256k1 range")
+}
+
+func TestSigner_NeverPrintsKey(t *testing.T) {
+	key, err := parsePrivateKey(testPrivateKey)
+	assert.NoError(t, err)
+
+	s := newSigner(key)
+	for _, out := range []string{fmt.Sprintf("%v", s), fmt.Sprintf("%+v", s), fmt.Sprintf("%#v", s), fmt.Sprint(s)} {
+		assert.NotContains(t, out, testPrivateKey)
+		assert.Contains(t, out, testAddress)
+	}
+
+	s.zero()
+	assert.Nil(t, s.key)
+	assert.True(t, key.Key.IsZero())
+}
+
+func TestDecryptKeystore_PBKDF2(t *testing.T) {
+	// Web3 Secret Storage 规范的测试向量
+	data := []byte(`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`)
+
+	raw, err := decryptKeystore(data, "testpassword")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d", hex.EncodeToString(raw))
+	}
+
+	_, err = decryptKeystore(data, "wrong")
+	assert.EqualError(t, err, "polymarket: keystore password is incorrect")
+}
+
+// newTestKeystore 用较小的 scrypt 参数加密私钥，生成 keystore v3 文件内容。
+func newTestKeystore(t *testing.T, privateKey, password string) []byte {
+	salt := []byte("0123456789abcdef0123456789abcdef")
+	iv := []byte("0123456789abcdef")
+
+	derivedKey, err := scrypt.Key([]byte(password), salt, 1024, 8, 1, 32)
+	assert.NoError(t, err)
+
+	raw, _ := hex.DecodeString(privateKey)
+	block, err := aes.NewCipher(derivedKey[:16])
+	assert.NoError(t, err)
+
+	cipherText := make([]byte, len(raw))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, raw)
+
+	data, err := json.Marshal(map[string]interface{}{
+		"version": 3,
+		"crypto": map[string]interface{}{
+			"cipher":       "aes-128-ctr",
+			"ciphertext":   hex.EncodeToString(cipherText),
+			"cipherparams": map[string]string{"iv": hex.EncodeToString(iv)},
+			"kdf":          "scrypt",
+			"kdfparams": map[string]interface{}{
+				"dklen": 32, "n": 1024, "r": 8, "p": 1, "salt": hex.EncodeToString(salt),
+			},
+			"mac": hex.EncodeToString(keccak256(derivedKey[16:32], cipherText)),
+		},
+	})
+	assert.NoError(t, err)
+	return data
+}
+
+func TestLoadSigner(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		s, err := loadSigner("")
+		assert.NoError(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("argument takes precedence over env", func(t *testing.T) {
+		t.Setenv(envPrivateKey, "0000000000000000000000000000000000000000000000000000000000000001")
+		s, err := loadSigner("0x" + testPrivateKey)
+		if assert.NoError(t, err) {
+			assert.Equal(t, testAddress, s.address)
+		}
+	})
+
+	t.Run("env takes precedence over keystore", func(t *testing.T) {
+		t.Setenv(envPrivateKey, testPrivateKey)
+		t.Setenv(envKeystoreFile, "/not/exists")
+		s, err := loadSigner("")
+		if assert.NoError(t, err) {
+			assert.Equal(t, testAddress, s.address)
+		}
+	})
+
+	t.Run("keystore", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "keystore.json")
+		assert.NoError(t, os.WriteFile(path, newTestKeystore(t, testPrivateKey, "secret"), 0o600))
+		passwordFile := filepath.Join(dir, "password")
+		assert.NoError(t, os.WriteFile(passwordFile, []byte("secret\n"), 0o600))
+
+		t.Setenv(envKeystoreFile, path)
+		_, err := loadSigner("")
+		assert.Error(t, err, "password is required")
+
+		t.Setenv(envKeystorePasswordFile, passwordFile)
+		s, err := loadSigner("")
+		if assert.NoError(t, err) {
+			assert.Equal(t, testAddress, s.address)
+		}
+	})
+}
+
+func TestNewWithPrivateKey(t *testing.T) {
+	ex, err := NewWithPrivateKey("key", "c2VjcmV0", "pass", testPrivateKey)
+	if assert.NoError(t, err) {
+		assert.Equal(t, testAddress, ex.signer.address)
+		// 没有配置 signer 地址时 L2 鉴权使用私钥的地址
+		assert.Equal(t, testAddress, ex.client.auth.address)
+
+		assert.NoError(t, ex.Close())
+		assert.Nil(t, ex.signer)
+	}
+
+	t.Setenv(envSignerAddress, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf")
+	_, err = NewWithPrivateKey("key", "c2VjcmV0", "pass", testPrivateKey)
+	assert.Error(t, err)
+}
+
+func TestSigner_Sign(t *testing.T) {
+	key, err := parsePrivateKey(testPrivateKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := newSigner(key)
+
+	// web3.js 文档中 web3.eth.accounts.sign("Some data", testPrivateKey) 的结果
+	hash := keccak256([]byte("\x19Ethereum Signed Message:\n9Some data"))
+	assert.Equal(t, "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655", hex.EncodeToString(hash))
+
+	sig, err := s.sign(hash)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a029", hex.EncodeToString(sig[:64]))
+		assert.Equal(t, byte(1), sig[64])
+	}
+
+	_, err = s.sign(hash[:31])
+	assert.Error(t, err)
+
+	s.zero()
+	_, err = s.sign(hash)
+	assert.Error(t, err)
+}
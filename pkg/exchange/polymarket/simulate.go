@@ -0,0 +1,166 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 下单预演：SimulateOrder 与 SubmitOrder 使用相同的取整与检查（prepareOrder、market 开放状态、post-only、dry-run 余额），
+// 再按当前盘口估算订单提交后立即成交的数量与均价，返回假想的订单（OrderID 为 0），不创建订单、不冻结余额、也不请求下单接口。
+// - 另外检查 market 的 MinQuantity / MinNotional
+// - 盘口优先使用 market channel 缓存的深度，过期或没有缓存时请求 REST /book（market 的 localSymbol 需要是 CLOB token id）
+// - 能吃到的档位按价格从优到劣成交；GTC 订单剩余部分挂单（New / PartiallyFilled），IOC 剩余部分撤单，FOK 深度不足时整单撤单
+// - 没有盘口数据时，dry-run 开启 autofill 且参考价可以成交的订单按模拟撮合的成交价（含滑点）估算全部成交
+// 预演只反映当前盘口，不保证真实提交时的成交结果。
+
+// SimulateOrder 预演下单，返回订单提交后预计的状态、成交量与成交均价，没有任何副作用。
+func (e *Exchange) SimulateOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	order, err := e.prepareOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := e.checkMarketOpen(order.Symbol, now); err != nil {
+		return nil, err
+	}
+
+	token, hasToken, err := e.checkSimulatedOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &types.Order{
+		SubmitOrder:    order,
+		Exchange:       types.ExchangePolymarket,
+		Status:         types.OrderStatusNew,
+		OriginalStatus: "NEW",
+		IsWorking:      true,
+		CreationTime:   types.Time(now),
+		UpdateTime:     types.Time(now),
+		IsDryRun:       e.IsDryRun(),
+	}
+
+	var levels []PriceLevel
+	ok := false
+	if hasToken {
+		if levels, ok = e.tickers.depth(token.TokenID, order.Side, order.Price, now); !ok {
+			book, err := e.client.queryOrderBook(ctx, token.TokenID)
+			if err != nil {
+				return nil, err
+			}
+			levels, ok = crossingLevels(order.Side, order.Price, book.Bids, book.Asks), true
+		}
+	}
+
+	if ok {
+		simulateFill(o, levels)
+	} else if price, fill := e.simulateDryRunFill(o); fill {
+		applyFill(o, o.Quantity, price)
+	}
+
+	finishSimulatedOrder(o)
+	return o, nil
+}
+
+// checkSimulatedOrder 检查 market 精度、post-only 与 dry-run 余额，返回订单的 outcome token。
+func (e *Exchange) checkSimulatedOrder(order types.SubmitOrder) (outcomeToken, bool, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	market, ok := e.markets[order.Symbol]
+	if !ok {
+		return outcomeToken{}, false, fmt.Errorf("polymarket: market %s not found", order.Symbol)
+	}
+	if order.Quantity.Sign() <= 0 || order.Quantity.Compare(market.MinQuantity) < 0 {
+		return outcomeToken{}, false, fmt.Errorf("polymarket: quantity %s of %s is below the min quantity %s",
+			order.Quantity.String(), order.Symbol, market.MinQuantity.String())
+	}
+	if notional := order.Price.Mul(order.Quantity); notional.Compare(market.MinNotional) < 0 {
+		return outcomeToken{}, false, fmt.Errorf("polymarket: notional %s of %s is below the min notional %s",
+			notional.String(), order.Symbol, market.MinNotional.String())
+	}
+
+	if err := e.checkPostOnlyLocked(order); err != nil {
+		return outcomeToken{}, false, err
+	}
+	if e.IsDryRun() {
+		sh := e.shard(order.Symbol)
+		sh.mu.Lock()
+		_, err := e.checkBalanceLocked(sh, order)
+		sh.mu.Unlock()
+		if err != nil {
+			return outcomeToken{}, false, err
+		}
+	}
+
+	token, ok := e.tokenOfLocked(order.Symbol)
+	return token, ok, nil
+}
+
+// simulateFill 按 levels（从优到劣）吃单，FOK 订单深度不足时不成交。
+func simulateFill(o *types.Order, levels []PriceLevel) {
+	if o.TimeInForce == types.TimeInForceFOK {
+		available := fixedpoint.Zero
+		for _, lv := range levels {
+			available = available.Add(lv.Size)
+		}
+		if available.Compare(o.Quantity) < 0 {
+			return
+		}
+	}
+
+	for _, lv := range levels {
+		remaining := o.Quantity.Sub(o.ExecutedQuantity)
+		if remaining.Sign() <= 0 {
+			break
+		}
+		applyFill(o, fixedpoint.Min(lv.Size, remaining), lv.Price)
+	}
+}
+
+// simulateDryRunFill 没有盘口数据时按 dry-run 模拟撮合估算：开启 autofill 且参考价可以成交时返回成交价。
+func (e *Exchange) simulateDryRunFill(o *types.Order) (fixedpoint.Value, bool) {
+	if !e.IsDryRun() {
+		return fixedpoint.Zero, false
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	m := e.matcher
+	ref, ok := m.referencePrice(o.Symbol)
+	if !m.enabled || !ok || ref.Sign() <= 0 {
+		return fixedpoint.Zero, false
+	}
+
+	switch o.Side {
+	case types.SideTypeBuy:
+		ok = ref.Compare(o.Price) <= 0
+	case types.SideTypeSell:
+		ok = ref.Compare(o.Price) >= 0
+	default:
+		ok = false
+	}
+	return m.fillPrice(o), ok
+}
+
+// finishSimulatedOrder 按成交量与 TimeInForce 设置订单的预计状态。
+func finishSimulatedOrder(o *types.Order) {
+	switch {
+	case o.ExecutedQuantity.Compare(o.Quantity) >= 0:
+		o.Status, o.OriginalStatus, o.IsWorking = types.OrderStatusFilled, "FILLED", false
+	case isImmediateOrCancel(o.SubmitOrder):
+		o.Status, o.OriginalStatus, o.IsWorking = types.OrderStatusCanceled, "CANCELED", false
+	case o.ExecutedQuantity.Sign() > 0:
+		o.Status, o.OriginalStatus = types.OrderStatusPartiallyFilled, "PARTIALLY_FILLED"
+	}
+}
@@ -0,0 +1,144 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_SimulateOrder(t *testing.T) {
+	const tokenID = "111111111111"
+	f := fixedpoint.NewFromFloat
+	t.Setenv(envBalanceUSDC, "10")
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ex.markets = types.MarketMap{
+		"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC", StepSize: f(0.01), TickSize: f(0.01),
+			MinQuantity: f(5), MinNotional: f(1)},
+		"PM_LOCAL": {Symbol: "PM_LOCAL", LocalSymbol: "PM_LOCAL", QuoteCurrency: "USDC", StepSize: f(0.01), TickSize: f(0.01)},
+	}
+	ex.tickers.updateBook(BookEvent{
+		AssetID: tokenID,
+		Bids:    []PriceLevel{{Price: f(0.45), Size: f(5)}},
+		Asks:    []PriceLevel{{Price: f(0.5), Size: f(3)}, {Price: f(0.48), Size: f(4)}, {Price: f(0.52), Size: f(10)}},
+	}, time.Now())
+
+	newOrder := func(symbol string, tif types.TimeInForce, price, quantity float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:      symbol,
+			Side:        types.SideTypeBuy,
+			Type:        types.OrderTypeLimit,
+			Price:       f(price),
+			Quantity:    f(quantity),
+			TimeInForce: tif,
+		}
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		order        types.SubmitOrder
+		wantStatus   types.OrderStatus
+		wantExecuted string
+		wantAverage  string
+	}{
+		// 限价 0.509 取整到 0.5，吃掉 0.48 x 4 与 0.5 x 3，剩余 3 挂单
+		{name: "gtc rests the remaining", order: newOrder("PM_YES", types.TimeInForceGTC, 0.509, 10),
+			wantStatus: types.OrderStatusPartiallyFilled, wantExecuted: "7", wantAverage: "0.48857142"},
+		{name: "gtc full fill", order: newOrder("PM_YES", types.TimeInForceGTC, 0.5, 5),
+			wantStatus: types.OrderStatusFilled, wantExecuted: "5", wantAverage: "0.484"},
+		{name: "ioc cancels the remaining", order: newOrder("PM_YES", types.TimeInForceIOC, 0.5, 10),
+			wantStatus: types.OrderStatusCanceled, wantExecuted: "7", wantAverage: "0.48857142"},
+		{name: "fok without enough depth", order: newOrder("PM_YES", types.TimeInForceFOK, 0.5, 10),
+			wantStatus: types.OrderStatusCanceled, wantExecuted: "0"},
+		{name: "not crossing", order: newOrder("PM_YES", types.TimeInForceGTC, 0.4, 10),
+			wantStatus: types.OrderStatusNew, wantExecuted: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := ex.SimulateOrder(ctx, tt.order)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.wantStatus, o.Status)
+			assert.Equal(t, tt.wantExecuted, o.ExecutedQuantity.String())
+			if tt.wantAverage != "" {
+				assert.Equal(t, tt.wantAverage, o.AveragePrice.String())
+			}
+			assert.Zero(t, o.OrderID)
+			assert.True(t, o.IsDryRun)
+		})
+	}
+
+	// 与 SubmitOrder 相同的检查
+	_, err := ex.SimulateOrder(ctx, newOrder("PM_YES", types.TimeInForceGTC, 0.001, 10))
+	assert.ErrorContains(t, err, "out of range")
+	_, err = ex.SimulateOrder(ctx, newOrder("PM_YES", types.TimeInForceGTC, 0.5, 4))
+	assert.ErrorContains(t, err, "min quantity")
+	_, err = ex.SimulateOrder(ctx, newOrder("PM_YES", types.TimeInForceGTC, 0.5, 30))
+	assert.ErrorIs(t, err, errInsufficientBalance)
+
+	// 没有任何副作用
+	openOrders, err := ex.QueryOpenOrders(ctx, "")
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+	balances, err := ex.QueryAccountBalances(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "10", balances[collateralCurrency].Available.String())
+	}
+
+	// 没有盘口时按 dry-run 模拟撮合的参考价估算
+	o, err := ex.SimulateOrder(ctx, newOrder("PM_LOCAL", types.TimeInForceGTC, 0.5, 10))
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderStatusNew, o.Status)
+	}
+
+	ex.matcher.enabled = true
+	ex.SetReferencePrice("PM_LOCAL", f(0.45))
+	o, err = ex.SimulateOrder(ctx, newOrder("PM_LOCAL", types.TimeInForceGTC, 0.5, 10))
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderStatusFilled, o.Status)
+		assert.Equal(t, "0.5", o.AveragePrice.String())
+	}
+}
+
+func TestExchange_SimulateOrderWithRESTBook(t *testing.T) {
+	const tokenID = "111111111111"
+	f := fixedpoint.NewFromFloat
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, tokenID, req.URL.Query().Get("token_id"))
+		return httptesting.BuildResponseString(http.StatusOK,
+			`{"asset_id": "`+tokenID+`", "bids": [{"price": "0.40", "size": "10"}, {"price": "0.42", "size": "2"}], "asks": [{"price": "0.44", "size": "8"}]}`), nil
+	})
+
+	ex := New("", "", "")
+	defer ex.Close()
+	ex.client = newTestRestClient(transport)
+	ex.markets = types.MarketMap{
+		"PM_YES": {Symbol: "PM_YES", LocalSymbol: tokenID, QuoteCurrency: "USDC", StepSize: f(0.01), TickSize: f(0.01)},
+	}
+
+	o, err := ex.SimulateOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "PM_YES",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeLimit,
+		Price:    f(0.4),
+		Quantity: f(5),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderStatusFilled, o.Status)
+		// 先吃 0.42 x 2，再吃 0.40 x 3
+		assert.Equal(t, "0.408", o.AveragePrice.String())
+	}
+}
@@ -2,34 +2,653 @@ package polymarket
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/gorilla/websocket"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
+const (
+	envWsUserURL      = "POLYMARKET_WS_USER_URL"
+	envWsMarketURL    = "POLYMARKET_WS_MARKET_URL"
+	envWsPingInterval = "POLYMARKET_WS_PING_INTERVAL"
+	envWsPongTimeout  = "POLYMARKET_WS_PONG_TIMEOUT"
+
+	defaultWsUserURL   = "wss://ws-subscriptions-clob.polymarket.com/ws/user"
+	defaultWsMarketURL = "wss://ws-subscriptions-clob.polymarket.com/ws/market"
+
+	// 服务端会断开空闲连接，官方建议每 10s 发送一次 PING
+	defaultWsPingInterval = 10 * time.Second
+	defaultWsPongTimeout  = 30 * time.Second
+)
+
+// closeTimeout 是 Close 等待 reader 处理完已收到的消息并退出的最长时间，超时后直接关闭连接。
+const closeTimeout = 3 * time.Second
+
+// balanceQueryTimeout 是成交后查询余额的超时
+const balanceQueryTimeout = 5 * time.Second
+
+var errStreamClosed = errors.New("polymarket: stream is closed")
+
+// StreamState 是 stream 的真实连接状态。
+// 不建立 websocket 时 Connect 仍会 EmitConnect（否则等待 connectivity 的策略会一直阻塞），
+// 所以 bbgo 的 Connectivity 无法区分“已连接”和“未启用”，监控/面板应以 State 为准。
+type StreamState string
+
+const (
+	StreamStateDisconnected StreamState = "disconnected"
+	StreamStateConnected    StreamState = "connected"
+	StreamStateClosed       StreamState = "closed"
+
+	// StreamStateMarketDataDisabled 表示 public-only stream 没有开启 market channel（POLYMARKET_WS_MARKET），不会推送任何行情
+	StreamStateMarketDataDisabled StreamState = "market_data_disabled"
+	// StreamStateDryRun 表示 dry-run 的 user data stream 不连接 websocket，订单事件由 Exchange 直接推送
+	StreamStateDryRun StreamState = "dry_run"
+)
+
 // Stream 是一个“最小可用”的 stream：
 // - 满足 bbgo 的 Stream 接口要求
-// - Connect 不会真正建立 websocket（避免因为 Polymarket websocket 细节未知而导致启动失败）
+// - dry-run 的 user data stream 不建立 websocket（订单状态由 Exchange 直接推送）
+// - live 模式下的 user data stream 会连接 CLOB user channel，把订单事件转换成 OnOrderUpdate
+// - public-only stream 在 POLYMARKET_WS_MARKET=true 时连接 CLOB market channel，更新 Exchange 的 ticker 缓存（见 ticker_cache.go）
 //
 // 这对“用 Binance 做行情源、用 Polymarket 做交易端”的跨交易所策略足够用。
-// 如果你希望从 Polymarket 拉盘口/成交/价格，可以再在这里接入真实 websocket 并派发事件。
 type Stream struct {
 	types.StandardStream
+
+	key, secret, passphrase string
+
+	dryRun bool
+
+	// symbolOf 把 CLOB 的 asset id（token id）映射回 bbgo symbol
+	symbolOf func(assetID string) (string, bool)
+
+	// market channel：marketChannel 表示 public-only 时是否连接 market channel，
+	// assetIDsOf 返回 symbols 对应的 token id（symbols 为空时返回所有 market 的 token id），
+	// tickers 为 Exchange 的 ticker 缓存，由 Exchange.NewStream 注入
+	marketChannel bool
+	assetIDsOf    func(symbols []string) []string
+	tickers       *tickerCache
+
+	// queryBalances 在 user channel 推送成交后查询最新余额并推送 BalanceUpdate，由 Exchange.NewStream 注入
+	queryBalances func(ctx context.Context) (types.BalanceMap, error)
+
+	// queryOpenOrders / queryOrder 在 user channel 断线期间轮询订单状态，由 Exchange.NewStream 注入，见 order_poller.go
+	queryOpenOrders   func(ctx context.Context, symbol string) ([]types.Order, error)
+	queryOrder        func(ctx context.Context, q types.OrderQuery) (*types.Order, error)
+	orderPollInterval time.Duration
+	// trackedOrders 为订单 hash → 最近一次推送的 working 订单
+	trackedOrders map[string]types.Order
+
+	// updateTickSize 在 market channel 推送 tick_size_change 时更新 market 的 tick size，由 Exchange.NewStream 注入
+	updateTickSize func(symbol string, tickSize fixedpoint.Value)
+
+	mu sync.Mutex
+	// handlers 为 event_type → 处理函数，见 stream_dispatch.go
+	handlers map[WsEventType]WsEventHandler
+	// fills 为订单 hash → 已收到的成交汇总，用于填充订单更新的 AveragePrice，订单结束后删除
+	fills     map[string]*FillCost
+	connected bool
+	closed    bool
+	state     StreamState
+
+	closeOnce sync.Once
+	closeErr  error
+
+	// disconnectC 在 reader 退出（EmitDisconnect）时收到信号，Close 用它等待 reader 退出
+	disconnectC chan struct{}
+
+	// pongTimeout 内没有收到 PONG 就认为连接已失效，关闭连接触发重连
+	pongTimeout time.Duration
+	lastPong    time.Time
+
+	// connects 为 websocket 建立连接的次数，第二次起计为重连
+	connects int
+
+	// writeMu 串行化对连接的写入（订阅消息与 PING），gorilla websocket 不支持并发写
+	writeMu sync.Mutex
+
+	// activeAssetIDs 为 market channel 当前连接上已订阅的 token id，subscribeBatch 为每条订阅消息最多的 token id 数，
+	// 见 stream_subscriptions.go
+	activeAssetIDs map[string]bool
+	subscribeBatch int
+
+	// klines 为 market channel 聚合的 K 线，klineSource 为聚合使用的价格来源，见 stream_kline.go
+	klines      *klineAggregator
+	klineSource string
+
+	// reconnectPolicy 为断线重连的退避策略，reconnectRand 为测试注入的随机数（nil 时使用 math/rand），见 stream_reconnect.go
+	reconnectPolicy ReconnectPolicy
+	reconnectRand   func() float64
+
+	// lags 为最近事件的接收延迟（nil 表示关闭统计），now 返回按服务器时钟偏差修正的当前时间，由 Exchange.NewStream 注入，
+	// 见 stream_lag.go
+	lags *lagWindow
+	now  func() time.Time
 }
 
-func NewStream() *Stream {
-	ss := types.NewStandardStream()
-	return &Stream{StandardStream: ss}
+func NewStream(key, secret, passphrase string, dryRun bool, symbolOf func(assetID string) (string, bool)) *Stream {
+	registerMetrics()
+	stream := &Stream{
+		StandardStream: types.NewStandardStream(),
+		key:            key,
+		secret:         secret,
+		passphrase:     passphrase,
+		dryRun:         dryRun,
+		symbolOf:       symbolOf,
+		marketChannel:  envBool(envWsMarket, false),
+		state:          StreamStateDisconnected,
+		disconnectC:    make(chan struct{}, 1),
+		pongTimeout:    envDuration(envWsPongTimeout, defaultWsPongTimeout),
+		subscribeBatch: envInt(envWsSubscribeBatch, defaultWsSubscribeBatch),
+		klines:         newKLineAggregator(),
+		klineSource:    klineSourceFromEnv(),
+
+		reconnectPolicy: newReconnectPolicyFromEnv(),
+		lags:            newLagWindow(envInt(envWsLagWindow, defaultWsLagWindow)),
+
+		orderPollInterval: envDuration(envOrderPollInterval, defaultOrderPollInterval),
+		trackedOrders:     make(map[string]types.Order),
+	}
+
+	stream.registerDefaultHandlers()
+	stream.SetPingInterval(envDuration(envWsPingInterval, defaultWsPingInterval))
+	stream.SetHeartBeat(stream.heartBeat)
+	stream.SetEndpointCreator(stream.createEndpoint)
+	stream.SetDialer(newWsDialer(envBool(envWsCompression, true)))
+	stream.SetParser(parseWebSocketEvent)
+	stream.SetDispatcher(stream.dispatchEvent)
+	stream.OnConnect(stream.handleConnect)
+	stream.OnDisconnect(stream.handleDisconnect)
+	if !dryRun {
+		stream.OnOrderUpdate(stream.trackOrder)
+	}
+	return stream
+}
+
+func (s *Stream) useWebsocket() bool {
+	if s.PublicOnly {
+		return s.marketChannel
+	}
+	return !s.dryRun
 }
 
 func (s *Stream) Connect(ctx context.Context) error {
-	// 不进行真实连接，但要让框架认为“已连接”，避免 connectivity 一直处于 disconnected。
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errStreamClosed
+	}
+	useWebsocket := s.useWebsocket()
+	s.connected = useWebsocket
+	if !useWebsocket {
+		s.state = s.disabledState()
+	}
+	state := s.state
+	s.mu.Unlock()
+
+	if useWebsocket {
+		// 不使用 StandardStream.Connect，重连由按 ReconnectPolicy 退避的 reconnector 负责
+		if err := s.DialAndConnect(ctx); err != nil {
+			return err
+		}
+		go s.reconnector(ctx)
+		if s.PublicOnly {
+			go s.runKLineCloser(ctx)
+		} else if s.queryOpenOrders != nil && s.queryOrder != nil && s.orderPollInterval > 0 {
+			go s.runOrderPoller(ctx)
+		}
+		s.EmitStart()
+		return nil
+	}
+
+	// 不进行真实连接，但要让框架认为“已连接”，避免 connectivity 一直处于 disconnected；
+	// 真实状态通过 State() 暴露。
+	log.Infof("websocket is not enabled for this stream, state = %s", state)
 	s.EmitConnect()
 	s.EmitStart()
 	return nil
 }
 
+// State 返回 stream 的真实状态，见 StreamState。
+func (s *Stream) State() StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// channel 返回连接的 CLOB channel：public-only 为 market，否则为 user。
+func (s *Stream) channel() string {
+	if s.PublicOnly {
+		return "market"
+	}
+	return "user"
+}
+
+func (s *Stream) disabledState() StreamState {
+	if s.PublicOnly {
+		return StreamStateMarketDataDisabled
+	}
+	return StreamStateDryRun
+}
+
+// Close 关闭 stream，可以重复调用：
+// - 停止 reconnector / reader / ping，并阻止之后的重连
+// - 发送 websocket close 帧，等待 reader 处理完已收到的消息后退出（reader 退出时 EmitDisconnect）
+// - 超过 closeTimeout 仍未退出时直接关闭连接
 func (s *Stream) Close() error {
-	s.EmitDisconnect()
+	s.closeOnce.Do(func() {
+		s.closeErr = s.close()
+	})
+	return s.closeErr
+}
+
+func (s *Stream) close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.state = StreamStateClosed
+	connected := s.connected
+	s.mu.Unlock()
+
+	if !connected {
+		s.EmitDisconnect()
+		return nil
+	}
+
+	// 丢掉之前重连时留下的信号，只等待这次关闭引起的 disconnect
+	select {
+	case <-s.disconnectC:
+	default:
+	}
+
+	close(s.CloseC)
+
+	s.ConnLock.Lock()
+	conn := s.Conn
+	if s.ConnCancel != nil {
+		s.ConnCancel()
+	}
+	s.ConnLock.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second)); err != nil {
+		// 连接已经断开（例如 pong 超时被关闭），reader 也已经或即将退出，不需要再等待
+		_ = conn.Close()
+		return fmt.Errorf("polymarket: write websocket close message failed: %w", err)
+	}
+
+	// 服务端回复 close 帧后 reader 会退出；没有回复时关闭连接让阻塞中的读取返回
+	select {
+	case <-s.disconnectC:
+	case <-time.After(closeTimeout):
+		_ = conn.Close()
+		select {
+		case <-s.disconnectC:
+		case <-time.After(closeTimeout):
+			log.Warn("user stream reader did not exit after close")
+		}
+	}
+
+	_ = conn.Close()
 	return nil
 }
 
+func (s *Stream) handleDisconnect() {
+	s.mu.Lock()
+	if s.connected && !s.closed {
+		s.state = StreamStateDisconnected
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.disconnectC <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Stream) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Stream) createEndpoint(_ context.Context) (string, error) {
+	// 关闭后 reconnector 可能还在冷却中，这里返回错误阻止它重新建立连接
+	if s.isClosed() {
+		return "", errStreamClosed
+	}
+	if s.PublicOnly {
+		return envString(envWsMarketURL, defaultWsMarketURL), nil
+	}
+	return envString(envWsUserURL, defaultWsUserURL), nil
+}
+
+func (s *Stream) handleConnect() {
+	if !s.useWebsocket() {
+		return
+	}
+
+	s.ConnLock.Lock()
+	conn := s.Conn
+	s.ConnLock.Unlock()
+
+	if conn == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.lastPong = time.Now()
+	s.state = StreamStateConnected
+	s.connects++
+	reconnected := s.connects > 1
+	s.mu.Unlock()
+
+	if reconnected {
+		recordWebsocketReconnect(s.channel())
+	}
+
+	if s.PublicOnly {
+		if err := s.subscribeMarketChannel(conn); err != nil {
+			log.WithError(err).Error("failed to subscribe market channel")
+		}
+		return
+	}
+
+	err := s.writeJSON(conn, WsSubscribeRequest{
+		Auth: &WsAuth{
+			APIKey:     s.key,
+			Secret:     s.secret,
+			Passphrase: s.passphrase,
+		},
+		Type: "user",
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to subscribe user channel")
+		return
+	}
+
+	s.EmitAuth()
+}
+
+// subscribedAssetIDs 返回 market channel 要订阅的 token id（去重）：有订阅时只订阅这些 symbol，否则订阅全部 market。
+func (s *Stream) subscribedAssetIDs() []string {
+	if s.assetIDsOf == nil {
+		return nil
+	}
+
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, sub := range s.GetSubscriptions() {
+		if !seen[sub.Symbol] {
+			seen[sub.Symbol] = true
+			symbols = append(symbols, sub.Symbol)
+		}
+	}
+	return s.assetIDsOf(symbols)
+}
+
+// heartBeat 由 ping worker 按 ping interval 调用：发送文本 PING 保活，
+// 超过 pongTimeout 没有收到 PONG 时关闭连接，返回错误后 ping worker 会触发重连。
+func (s *Stream) heartBeat(conn *websocket.Conn) error {
+	s.mu.Lock()
+	lastPong := s.lastPong
+	s.mu.Unlock()
+
+	if since := time.Since(lastPong); since > s.pongTimeout {
+		log.Warnf("no pong received in %s, closing user stream connection", since)
+		// 让阻塞在读取中的 reader 立即返回
+		_ = conn.Close()
+		return fmt.Errorf("polymarket: pong timeout after %s", since)
+	}
+
+	s.writeMu.Lock()
+	err := conn.WriteMessage(websocket.TextMessage, wsPingMessage)
+	s.writeMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("polymarket: write ping failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Stream) handlePong() {
+	s.mu.Lock()
+	s.lastPong = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *Stream) dispatchEvent(event interface{}) {
+	if _, ok := event.(*types.WebsocketPongEvent); ok {
+		s.handlePong()
+		return
+	}
+
+	events, ok := event.([]interface{})
+	if !ok {
+		return
+	}
+
+	receivedAt := s.receivedAt()
+	for _, e := range events {
+		eventType := eventTypeOf(e)
+		s.recordEventLag(e, eventType, receivedAt)
+
+		handler := s.eventHandler(eventType)
+		if handler == nil {
+			log.Debugf("drop websocket event %q without handler", eventType)
+			continue
+		}
+		handler(e)
+	}
+}
+
+func (s *Stream) handleOrderEvent(e OrderEvent) {
+	symbol, ok := s.symbolOf(e.AssetID)
+	if !ok {
+		log.Debugf("skip order event of unknown asset %s", e.AssetID)
+		return
+	}
+
+	s.EmitOrderUpdate(s.withFillCost(toGlobalOrder(e, symbol)))
+
+	// UPDATE 表示订单有新的成交，余额与持仓随之变化
+	if e.Type == OrderEventUpdate && s.queryBalances != nil {
+		go s.emitBalances()
+	}
+}
+
+// handleTradeEvent 在成交第一次推送（MATCHED）时推送自己一方的成交，之后的上链状态变化不再重复推送。
+func (s *Stream) handleTradeEvent(e TradeEvent) {
+	switch e.Status {
+	case TradeStatusMatched:
+	case TradeStatusFailed:
+		log.Warnf("trade %s failed on chain (taker order %s)", e.ID, e.TakerOrderID)
+		return
+	default:
+		return
+	}
+
+	r := e.TradeRecord
+	if r.MatchTime == "" {
+		r.MatchTime = e.WsMatchTime
+	}
+	if r.TraderSide == "" {
+		r.TraderSide = "MAKER"
+		if r.Owner == s.key {
+			r.TraderSide = "TAKER"
+		}
+	}
+
+	// maker 一方的订单可能在互补的 token 上，按 token 分别转换
+	assetIDs := []string{r.AssetID}
+	if r.TraderSide == "MAKER" {
+		assetIDs = nil
+		for _, mo := range r.MakerOrders {
+			if mo.Owner == s.key {
+				assetIDs = append(assetIDs, mo.AssetID)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, assetID := range assetIDs {
+		if seen[assetID] {
+			continue
+		}
+		seen[assetID] = true
+
+		symbol, ok := s.symbolOf(assetID)
+		if !ok {
+			log.Debugf("skip trade event %s of unknown asset %s", e.ID, assetID)
+			continue
+		}
+
+		trades, err := toGlobalTrades(r, s.key, assetID, symbol)
+		if err != nil {
+			log.WithError(err).Warnf("failed to convert trade event %s", e.ID)
+			continue
+		}
+		for _, trade := range trades {
+			s.recordFill(trade)
+			recordFillMetrics(trade, "live")
+			s.EmitTradeUpdate(trade)
+		}
+	}
+}
+
+// recordFill 把自己的成交计入订单的成交汇总。
+func (s *Stream) recordFill(trade types.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fills == nil {
+		s.fills = make(map[string]*FillCost)
+	}
+	c, ok := s.fills[trade.OrderUUID]
+	if !ok {
+		c = &FillCost{}
+		s.fills[trade.OrderUUID] = c
+	}
+	c.add(trade.Price, trade.Quantity, trade.Fee)
+}
+
+// withFillCost 用收到的成交填充订单的 AveragePrice，订单结束后删除成交汇总。
+func (s *Stream) withFillCost(o types.Order) types.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.fills[o.UUID]; ok {
+		o.AveragePrice = c.AveragePrice
+	}
+	if !o.IsWorking {
+		delete(s.fills, o.UUID)
+	}
+	return o
+}
+
+func (s *Stream) handleBookEvent(e BookEvent) {
+	symbol, ok := s.symbolOf(e.AssetID)
+	if !ok {
+		log.Debugf("skip book event of unknown asset %s", e.AssetID)
+		return
+	}
+
+	if s.tickers != nil {
+		s.tickers.updateBook(e, time.Now())
+	}
+
+	book := OrderBookSummary{Bids: e.Bids, Asks: e.Asks}
+	bid, _ := book.BestBid()
+	ask, _ := book.BestAsk()
+	s.EmitBookTickerUpdate(types.BookTicker{
+		Symbol:   symbol,
+		Buy:      bid.Price,
+		BuySize:  bid.Size,
+		Sell:     ask.Price,
+		SellSize: ask.Size,
+	})
+	s.addKLineTick(KLineSourceMidpoint, symbol, midpointOf(bid.Price, ask.Price), fixedpoint.Zero, eventTime(e.Timestamp))
+}
+
+func (s *Stream) handleLastTradePriceEvent(e LastTradePriceEvent) {
+	symbol, ok := s.symbolOf(e.AssetID)
+	if !ok {
+		log.Debugf("skip last trade price event of unknown asset %s", e.AssetID)
+		return
+	}
+
+	if s.tickers != nil {
+		s.tickers.updateLastTrade(e, time.Now())
+	}
+
+	side := toGlobalSide(e.Side)
+	s.EmitMarketTrade(types.Trade{
+		Exchange:      types.ExchangePolymarket,
+		Symbol:        symbol,
+		Price:         e.Price,
+		Quantity:      e.Size,
+		QuoteQuantity: e.Price.Mul(e.Size),
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		Time:          types.Time(e.Timestamp.Time()),
+	})
+	s.addKLineTick(KLineSourceTrade, symbol, e.Price, e.Size, eventTime(e.Timestamp))
+}
+
+// handlePriceChangeEvent 用盘口增量里的最优买卖价推送 BookTickerUpdate（不含挂单量）。
+func (s *Stream) handlePriceChangeEvent(e PriceChangeEvent) {
+	now := time.Now()
+	for _, change := range e.PriceChanges {
+		symbol, ok := s.symbolOf(change.AssetID)
+		if !ok {
+			log.Debugf("skip price change event of unknown asset %s", change.AssetID)
+			continue
+		}
+
+		if s.tickers != nil {
+			s.tickers.updatePriceChange(change, now)
+		}
+
+		s.EmitBookTickerUpdate(types.BookTicker{
+			Symbol: symbol,
+			Buy:    change.BestBid,
+			Sell:   change.BestAsk,
+		})
+		s.addKLineTick(KLineSourceMidpoint, symbol, midpointOf(change.BestBid, change.BestAsk), fixedpoint.Zero, eventTime(e.Timestamp))
+	}
+}
+
+func (s *Stream) handleTickSizeChangeEvent(e TickSizeChangeEvent) {
+	symbol, ok := s.symbolOf(e.AssetID)
+	if !ok {
+		log.Debugf("skip tick size change event of unknown asset %s", e.AssetID)
+		return
+	}
+
+	log.Infof("polymarket %s tick size changed from %s to %s", symbol, e.OldTickSize.String(), e.NewTickSize.String())
+	if s.updateTickSize != nil && e.NewTickSize.Sign() > 0 {
+		s.updateTickSize(symbol, e.NewTickSize)
+	}
+}
+
+func (s *Stream) emitBalances() {
+	ctx, cancel := context.WithTimeout(context.Background(), balanceQueryTimeout)
+	defer cancel()
+
+	balances, err := s.queryBalances(ctx)
+	if err != nil {
+		log.WithError(err).Warn("failed to query balances after fill")
+		return
+	}
+	if len(balances) > 0 {
+		s.EmitBalanceUpdate(balances)
+	}
+}
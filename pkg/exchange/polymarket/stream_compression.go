@@ -0,0 +1,26 @@
+package polymarket
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocket 压缩：连接时通过 permessage-deflate 扩展协商压缩（POLYMARKET_WS_COMPRESSION，默认 true），
+// 繁忙 market 的 book 快照很大，压缩可以明显减少流量。
+// - 服务端接受扩展后，压缩的消息由 gorilla websocket 在读取时透明解压，parser 看到的仍是原始 JSON
+// - 服务端不支持压缩时照常使用未压缩的消息
+// - 只有排查问题时才需要关闭
+
+const envWsCompression = "POLYMARKET_WS_COMPRESSION"
+
+// newWsDialer 返回 stream 使用的 websocket dialer，compression 为 true 时协商 permessage-deflate。
+func newWsDialer(compression bool) *websocket.Dialer {
+	return &websocket.Dialer{
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  10 * time.Second,
+		ReadBufferSize:    4096,
+		EnableCompression: compression,
+	}
+}
@@ -0,0 +1,132 @@
+package polymarket
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// deflatedTextFrame 按 RFC 7692 构造服务端发送的压缩 text 帧（FIN + RSV1，不带 mask）：
+// payload 为 raw DEFLATE 数据，去掉 sync flush 结尾的 00 00 ff ff。
+func deflatedTextFrame(t *testing.T, message []byte) []byte {
+	var compressed bytes.Buffer
+	w, err := flate.NewWriter(&compressed, flate.BestCompression)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	_, _ = w.Write(message)
+	assert.NoError(t, w.Flush())
+	payload := bytes.TrimSuffix(compressed.Bytes(), []byte{0x00, 0x00, 0xff, 0xff})
+
+	frame := []byte{0xc1}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	return append(frame, payload...)
+}
+
+// largeBookSnapshot 返回 levels 档买卖盘的 book 快照。
+func largeBookSnapshot(tokenID string, levels int) []byte {
+	var bids, asks []string
+	for i := 0; i < levels; i++ {
+		bids = append(bids, fmt.Sprintf(`{"price": "0.%04d", "size": "%d"}`, 4999-i, 100+i))
+		asks = append(asks, fmt.Sprintf(`{"price": "0.%04d", "size": "%d"}`, 5001+i, 100+i))
+	}
+	return []byte(`[{"event_type": "book", "asset_id": "` + tokenID + `", "bids": [` + strings.Join(bids, ",") +
+		`], "asks": [` + strings.Join(asks, ",") + `], "timestamp": "1672290687"}]`)
+}
+
+func TestStream_CompressedMarketChannel(t *testing.T) {
+	const tokenID = "111111111111"
+	const levels = 2000
+
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "`+tokenID+`", "quoteCurrency": "USDC", "pricePrecision": 4, "tickSize": "0.0001", "stepSize": "0.01"}]`)
+	t.Setenv(envWsMarket, "true")
+	t.Setenv(envTickerMaxAge, "1m")
+
+	extensions := make(chan string, 1)
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		extensions <- r.Header.Get("Sec-Websocket-Extensions")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req WsSubscribeRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		// 手工构造的压缩帧，不依赖 gorilla 的写入实现
+		if _, err := conn.UnderlyingConn().Write(deflatedTextFrame(t, largeBookSnapshot(tokenID, levels))); err != nil {
+			return
+		}
+
+		conn.EnableWriteCompression(true)
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event_type": "last_trade_price", "asset_id": "`+tokenID+`", "price": "0.5", "side": "BUY", "size": "5", "timestamp": "1672290688"}`))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer ws.Close()
+	t.Setenv(envWsMarketURL, "ws"+strings.TrimPrefix(ws.URL, "http"))
+
+	ctx := context.Background()
+	ex := New("", "", "")
+	defer ex.Close()
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	stream := ex.NewStream().(*Stream)
+	stream.SetPublicOnly()
+	assert.NoError(t, stream.Connect(ctx))
+	defer stream.Close()
+
+	select {
+	case ext := <-extensions:
+		assert.Contains(t, ext, "permessage-deflate")
+	case <-time.After(3 * time.Second):
+		t.Fatal("websocket not connected")
+	}
+
+	// 只读取缓存，不回退到 REST
+	assert.Eventually(t, func() bool {
+		ticker, ok := ex.tickers.get(tokenID, time.Now())
+		return ok && ticker.Last.String() == "0.5"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	book, err := ex.QueryDepth(ctx, "PM_YES")
+	if assert.NoError(t, err) && assert.Len(t, book.Bids, levels) && assert.Len(t, book.Asks, levels) {
+		assert.Equal(t, "0.4999", book.Bids[0].Price.String())
+		assert.Equal(t, "0.5001", book.Asks[0].Price.String())
+		assert.Equal(t, "0.3000", fmt.Sprintf("%.4f", book.Bids[levels-1].Price.Float64()))
+		assert.Equal(t, "2099", book.Asks[levels-1].Volume.String())
+	}
+}
+
+func TestNewWsDialer(t *testing.T) {
+	assert.True(t, newWsDialer(true).EnableCompression)
+	assert.False(t, newWsDialer(false).EnableCompression)
+}
@@ -0,0 +1,83 @@
+package polymarket
+
+import (
+	"encoding/json"
+)
+
+// websocket 事件按 event_type 分发，新增事件类型不需要修改读循环：
+// - wsEventDecoders：event_type → 解析函数，parseWebSocketEvent 用它把消息解析成具体的事件结构
+// - Stream 的 handlers：event_type → 处理函数，NewStream 注册内置的处理函数（转换成 bbgo 事件），
+//   可以通过 Stream.OnEvent 新增或替换
+// - 没有解析函数的事件解析成 *UnknownEvent，没有处理函数的事件以 debug 级别记录后丢弃
+
+// WsEventHandler 处理一个解析后的 websocket 事件（例如 *OrderEvent）。
+type WsEventHandler func(event interface{})
+
+var wsEventDecoders = map[WsEventType]func(raw json.RawMessage) (interface{}, error){
+	WsEventTypeOrder:          decodeWsEvent[OrderEvent],
+	WsEventTypeTrade:          decodeWsEvent[TradeEvent],
+	WsEventTypeBook:           decodeWsEvent[BookEvent],
+	WsEventTypeLastTradePrice: decodeWsEvent[LastTradePriceEvent],
+	WsEventTypePriceChange:    decodeWsEvent[PriceChangeEvent],
+	WsEventTypeTickSizeChange: decodeWsEvent[TickSizeChangeEvent],
+}
+
+func decodeWsEvent[T any](raw json.RawMessage) (interface{}, error) {
+	var e T
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// eventTypeOf 返回事件的 event_type。事件结构的 EventType 字段只在解析时填充，这里按类型判断。
+func eventTypeOf(event interface{}) WsEventType {
+	switch e := event.(type) {
+	case *OrderEvent:
+		return WsEventTypeOrder
+	case *TradeEvent:
+		return WsEventTypeTrade
+	case *BookEvent:
+		return WsEventTypeBook
+	case *LastTradePriceEvent:
+		return WsEventTypeLastTradePrice
+	case *PriceChangeEvent:
+		return WsEventTypePriceChange
+	case *TickSizeChangeEvent:
+		return WsEventTypeTickSizeChange
+	case *UnknownEvent:
+		return e.EventType
+	}
+	return ""
+}
+
+// registerDefaultHandlers 注册内置事件的处理函数。
+func (s *Stream) registerDefaultHandlers() {
+	s.handlers = map[WsEventType]WsEventHandler{
+		WsEventTypeOrder:          func(e interface{}) { s.handleOrderEvent(*e.(*OrderEvent)) },
+		WsEventTypeTrade:          func(e interface{}) { s.handleTradeEvent(*e.(*TradeEvent)) },
+		WsEventTypeBook:           func(e interface{}) { s.handleBookEvent(*e.(*BookEvent)) },
+		WsEventTypeLastTradePrice: func(e interface{}) { s.handleLastTradePriceEvent(*e.(*LastTradePriceEvent)) },
+		WsEventTypePriceChange:    func(e interface{}) { s.handlePriceChangeEvent(*e.(*PriceChangeEvent)) },
+		WsEventTypeTickSizeChange: func(e interface{}) { s.handleTickSizeChangeEvent(*e.(*TickSizeChangeEvent)) },
+	}
+}
+
+// OnEvent 注册 eventType 的处理函数，替换已有的（包括内置的）处理函数；handler 为 nil 时丢弃该类型的事件。
+// 没有注册解析函数的事件类型收到的是 *UnknownEvent。
+func (s *Stream) OnEvent(eventType WsEventType, handler WsEventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if handler == nil {
+		delete(s.handlers, eventType)
+		return
+	}
+	s.handlers[eventType] = handler
+}
+
+func (s *Stream) eventHandler(eventType WsEventType) WsEventHandler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handlers[eventType]
+}
@@ -0,0 +1,110 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestParseWebSocketEvent_Dispatch(t *testing.T) {
+	event, err := parseWebSocketEvent([]byte(`[
+		{"event_type": "price_change", "market": "0xabc", "timestamp": "1757908892351",
+		 "price_changes": [{"asset_id": "111111111111", "price": "0.5", "size": "200", "side": "BUY", "hash": "0x1", "best_bid": "0.5", "best_ask": "0.52"}]},
+		{"event_type": "tick_size_change", "asset_id": "111111111111", "market": "0xabc", "old_tick_size": "0.01", "new_tick_size": "0.001", "timestamp": "100000000"},
+		{"event_type": "market_resolved", "market": "0xabc"}
+	]`))
+	assert.NoError(t, err)
+
+	events := event.([]interface{})
+	if assert.Len(t, events, 3) {
+		priceChange, ok := events[0].(*PriceChangeEvent)
+		if assert.True(t, ok) && assert.Len(t, priceChange.PriceChanges, 1) {
+			assert.Equal(t, "0.52", priceChange.PriceChanges[0].BestAsk.String())
+		}
+
+		tickSizeChange, ok := events[1].(*TickSizeChangeEvent)
+		if assert.True(t, ok) {
+			assert.Equal(t, "0.001", tickSizeChange.NewTickSize.String())
+		}
+
+		unknown, ok := events[2].(*UnknownEvent)
+		if assert.True(t, ok) {
+			assert.Equal(t, WsEventType("market_resolved"), unknown.EventType)
+			assert.JSONEq(t, `{"event_type": "market_resolved", "market": "0xabc"}`, string(unknown.Raw))
+		}
+	}
+
+	_, err = parseWebSocketEvent([]byte(`{"event_type": "book", "bids": "invalid"}`))
+	assert.ErrorContains(t, err, "decode book event failed")
+}
+
+func TestStream_OnEvent(t *testing.T) {
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_YES", assetID == "111111111111"
+	})
+
+	var bookTickers []types.BookTicker
+	stream.OnBookTickerUpdate(func(b types.BookTicker) {
+		bookTickers = append(bookTickers, b)
+	})
+
+	// 没有处理函数的事件被丢弃
+	resolved := &UnknownEvent{EventType: "market_resolved"}
+	stream.dispatchEvent([]interface{}{resolved})
+
+	var got []interface{}
+	stream.OnEvent("market_resolved", func(e interface{}) {
+		got = append(got, e)
+	})
+	stream.dispatchEvent([]interface{}{
+		resolved,
+		&PriceChangeEvent{PriceChanges: []PriceChange{
+			{AssetID: "111111111111", BestBid: fixedpoint.NewFromFloat(0.5), BestAsk: fixedpoint.NewFromFloat(0.52)},
+			{AssetID: "999999999999", BestBid: fixedpoint.NewFromFloat(0.1), BestAsk: fixedpoint.NewFromFloat(0.2)},
+		}},
+	})
+	assert.Equal(t, []interface{}{resolved}, got)
+	if assert.Len(t, bookTickers, 1) {
+		assert.Equal(t, "PM_YES", bookTickers[0].Symbol)
+		assert.Equal(t, "0.5", bookTickers[0].Buy.String())
+		assert.Equal(t, "0.52", bookTickers[0].Sell.String())
+	}
+
+	// 替换内置的处理函数
+	var books int
+	stream.OnEvent(WsEventTypeBook, func(e interface{}) {
+		books++
+	})
+	stream.dispatchEvent([]interface{}{&BookEvent{AssetID: "111111111111"}})
+	assert.Equal(t, 1, books)
+	assert.Len(t, bookTickers, 1)
+
+	// handler 为 nil 时丢弃
+	stream.OnEvent(WsEventTypeBook, nil)
+	stream.dispatchEvent([]interface{}{&BookEvent{AssetID: "111111111111"}})
+	assert.Equal(t, 1, books)
+}
+
+func TestStream_TickSizeChange(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+
+	ex := New("", "", "")
+	_, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	stream := ex.NewStream().(*Stream)
+	stream.dispatchEvent([]interface{}{&TickSizeChangeEvent{
+		AssetID:     "111111111111",
+		OldTickSize: fixedpoint.NewFromFloat(0.01),
+		NewTickSize: fixedpoint.NewFromFloat(0.001),
+	}})
+
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "0.001", markets["PM_YES"].TickSize.String())
+	assert.Equal(t, 3, markets["PM_YES"].PricePrecision)
+}
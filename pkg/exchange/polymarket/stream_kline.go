@@ -0,0 +1,215 @@
+package polymarket
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 由 market channel 聚合 K 线：public-only stream 开启 market channel 后，对 KLineChannel 的订阅
+// （session.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: ...})）
+// 把 Polymarket 的价格（即概率）聚合成 types.KLine，策略可以直接 OnKLineClosed 到概率本身。
+// - POLYMARKET_WS_KLINE_SOURCE=trade（默认）用 last_trade_price 的成交价与成交量，midpoint 用盘口最优买卖价的中间价（成交量为 0）
+// - K 线按 interval 对齐到 UTC 整点（start = t.Truncate(interval)），EndTime 为下一根的开始时间减 1ms（与 binance 一致）
+// - 收到下一根 K 线的 tick 或者到达 K 线结束时间（每秒检查一次）时推送 KLineClosed，期间每个 tick 推送 KLine 更新
+// - 没有 tick 的区间推送平盘 K 线（开高低收都为上一根的收盘价、成交量为 0），保证 K 线连续；第一根 K 线从第一个 tick 开始
+// - 晚于当前 K 线开始时间之前的 tick（乱序或延迟到达）被丢弃
+
+const (
+	envWsKLineSource = "POLYMARKET_WS_KLINE_SOURCE"
+
+	KLineSourceTrade    = "trade"
+	KLineSourceMidpoint = "midpoint"
+)
+
+// klineCloseCheckInterval 是检查 K 线是否到达结束时间的间隔
+const klineCloseCheckInterval = time.Second
+
+type klineKey struct {
+	symbol   string
+	interval types.Interval
+}
+
+// klineAggregator 按 symbol + interval 聚合 tick，线程安全。
+type klineAggregator struct {
+	mu      sync.Mutex
+	candles map[klineKey]*types.KLine
+}
+
+func newKLineAggregator() *klineAggregator {
+	return &klineAggregator{candles: make(map[klineKey]*types.KLine)}
+}
+
+func newAggregatedKLine(symbol string, interval types.Interval, start time.Time, price fixedpoint.Value) *types.KLine {
+	return &types.KLine{
+		Exchange:  types.ExchangePolymarket,
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: types.Time(start),
+		EndTime:   types.Time(start.Add(interval.Duration() - time.Millisecond)),
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+	}
+}
+
+// advanceLocked 结束 until 之前已经到期的 K 线（包括期间的平盘 K 线），返回结束的 K 线。
+func (a *klineAggregator) advanceLocked(key klineKey, until time.Time) (closed []types.KLine) {
+	d := key.interval.Duration()
+	for k := a.candles[key]; k != nil; k = a.candles[key] {
+		end := k.StartTime.Time().Add(d)
+		if until.Before(end) {
+			break
+		}
+
+		k.Closed = true
+		closed = append(closed, *k)
+		a.candles[key] = newAggregatedKLine(key.symbol, key.interval, end, k.Close)
+	}
+	return closed
+}
+
+// add 把一个 tick 加入 symbol 的各个 interval，返回因此结束的 K 线与更新后的当前 K 线。
+func (a *klineAggregator) add(symbol string, intervals []types.Interval, price, volume fixedpoint.Value, trade bool, at time.Time) (closed, updated []types.KLine) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, interval := range intervals {
+		d := interval.Duration()
+		if d <= 0 {
+			continue
+		}
+
+		key := klineKey{symbol: symbol, interval: interval}
+		closed = append(closed, a.advanceLocked(key, at)...)
+
+		k, ok := a.candles[key]
+		if !ok {
+			k = newAggregatedKLine(symbol, interval, at.Truncate(d), price)
+			a.candles[key] = k
+		} else if at.Before(k.StartTime.Time()) {
+			continue
+		}
+
+		k.High = fixedpoint.Max(k.High, price)
+		k.Low = fixedpoint.Min(k.Low, price)
+		k.Close = price
+		if trade {
+			k.Volume = k.Volume.Add(volume)
+			k.QuoteVolume = k.QuoteVolume.Add(price.Mul(volume))
+			k.NumberOfTrades++
+		}
+		updated = append(updated, *k)
+	}
+	return closed, updated
+}
+
+// closeUntil 结束所有在 now 之前到期的 K 线，按开始时间排序返回；subscribed 返回 false 的 K 线（已取消订阅）被丢弃。
+func (a *klineAggregator) closeUntil(now time.Time, subscribed func(symbol string, interval types.Interval) bool) (closed []types.KLine) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key := range a.candles {
+		if !subscribed(key.symbol, key.interval) {
+			delete(a.candles, key)
+			continue
+		}
+		closed = append(closed, a.advanceLocked(key, now)...)
+	}
+
+	sort.SliceStable(closed, func(i, j int) bool {
+		return closed[i].StartTime.Before(closed[j].StartTime.Time())
+	})
+	return closed
+}
+
+// klineIntervals 返回 symbol 的 KLineChannel 订阅的 interval。
+func (s *Stream) klineIntervals(symbol string) (intervals []types.Interval) {
+	for _, sub := range s.GetSubscriptions() {
+		if sub.Channel == types.KLineChannel && sub.Symbol == symbol && sub.Options.Interval != "" {
+			intervals = append(intervals, sub.Options.Interval)
+		}
+	}
+	return intervals
+}
+
+// klineSourceFromEnv 读取 POLYMARKET_WS_KLINE_SOURCE，无效的取值打印警告后使用 trade。
+func klineSourceFromEnv() string {
+	switch source := envString(envWsKLineSource, KLineSourceTrade); source {
+	case KLineSourceTrade, KLineSourceMidpoint:
+		return source
+	default:
+		log.Warnf("invalid %s %q, should be one of %q, %q; use %q", envWsKLineSource, source, KLineSourceTrade, KLineSourceMidpoint, KLineSourceTrade)
+		return KLineSourceTrade
+	}
+}
+
+// addKLineTick 把 source 来源的 tick 聚合进 K 线并推送事件，其它来源的 tick 被忽略。
+func (s *Stream) addKLineTick(source, symbol string, price, volume fixedpoint.Value, at time.Time) {
+	if s.klines == nil || s.klineSource != source || price.Sign() <= 0 {
+		return
+	}
+
+	intervals := s.klineIntervals(symbol)
+	if len(intervals) == 0 {
+		return
+	}
+
+	closed, updated := s.klines.add(symbol, intervals, price, volume, source == KLineSourceTrade, at)
+	for _, k := range closed {
+		s.EmitKLineClosed(k)
+	}
+	for _, k := range updated {
+		s.EmitKLine(k)
+	}
+}
+
+// runKLineCloser 每秒结束到期的 K 线，ctx 取消或 stream 关闭时退出。
+// 没有 K 线订阅时只是空转，这样连接后通过 Resubscribe 新增的 K 线订阅也能按时收盘。
+func (s *Stream) runKLineCloser(ctx context.Context) {
+	ticker := time.NewTicker(klineCloseCheckInterval)
+	defer ticker.Stop()
+
+	subscribed := func(symbol string, interval types.Interval) bool {
+		for _, i := range s.klineIntervals(symbol) {
+			if i == interval {
+				return true
+			}
+		}
+		return false
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.CloseC:
+			return
+		case now := <-ticker.C:
+			for _, k := range s.klines.closeUntil(now, subscribed) {
+				s.EmitKLineClosed(k)
+			}
+		}
+	}
+}
+
+// midpointOf 返回最优买卖价的中间价，任一边为空时返回 0。
+func midpointOf(bid, ask fixedpoint.Value) fixedpoint.Value {
+	if bid.Sign() <= 0 || ask.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+	return bid.Add(ask).Div(fixedpoint.NewFromInt(2))
+}
+
+// eventTime 返回事件的时间戳，没有时间戳时使用当前时间。
+func eventTime(ts types.MillisecondTimestamp) time.Time {
+	if t := ts.Time(); t.Unix() > 0 {
+		return t
+	}
+	return time.Now()
+}
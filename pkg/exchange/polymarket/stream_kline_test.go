@@ -0,0 +1,135 @@
+package polymarket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestKLineAggregator(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	t0 := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+	intervals := []types.Interval{types.Interval1m}
+	a := newKLineAggregator()
+
+	// 第一根 K 线对齐到整分钟
+	closed, updated := a.add("PM_YES", intervals, f(0.5), f(10), true, t0.Add(20*time.Second))
+	assert.Empty(t, closed)
+	if assert.Len(t, updated, 1) {
+		assert.True(t, t0.Equal(updated[0].StartTime.Time()))
+		assert.True(t, t0.Add(time.Minute-time.Millisecond).Equal(updated[0].EndTime.Time()))
+		assert.False(t, updated[0].Closed)
+	}
+
+	a.add("PM_YES", intervals, f(0.55), f(5), true, t0.Add(40*time.Second))
+	a.add("PM_YES", intervals, f(0.45), f(5), true, t0.Add(50*time.Second))
+
+	// 落在下一根 K 线开始时间的 tick 结束上一根
+	closed, updated = a.add("PM_YES", intervals, f(0.52), f(1), true, t0.Add(time.Minute))
+	if assert.Len(t, closed, 1) {
+		k := closed[0]
+		assert.True(t, k.Closed)
+		assert.Equal(t, "0.5", k.Open.String())
+		assert.Equal(t, "0.55", k.High.String())
+		assert.Equal(t, "0.45", k.Low.String())
+		assert.Equal(t, "0.45", k.Close.String())
+		assert.Equal(t, "20", k.Volume.String())
+		assert.Equal(t, "10", k.QuoteVolume.String())
+		assert.Equal(t, uint64(3), k.NumberOfTrades)
+	}
+	if assert.Len(t, updated, 1) {
+		assert.True(t, t0.Add(time.Minute).Equal(updated[0].StartTime.Time()))
+		assert.Equal(t, "0.45", updated[0].Open.String())
+		assert.Equal(t, "0.52", updated[0].Close.String())
+	}
+
+	// 晚到的 tick 被丢弃
+	closed, updated = a.add("PM_YES", intervals, f(0.9), f(1), true, t0.Add(30*time.Second))
+	assert.Empty(t, closed)
+	assert.Empty(t, updated)
+
+	// 没有 tick 的区间补平盘 K 线
+	subscribed := func(symbol string, interval types.Interval) bool { return true }
+	closed = a.closeUntil(t0.Add(3*time.Minute+time.Second), subscribed)
+	if assert.Len(t, closed, 2) {
+		assert.Equal(t, "0.52", closed[0].Close.String())
+		flat := closed[1]
+		assert.True(t, t0.Add(2*time.Minute).Equal(flat.StartTime.Time()))
+		assert.Equal(t, "0.52", flat.Open.String())
+		assert.Equal(t, "0.52", flat.High.String())
+		assert.Equal(t, "0.52", flat.Close.String())
+		assert.True(t, flat.Volume.IsZero())
+	}
+	assert.Empty(t, a.closeUntil(t0.Add(3*time.Minute+2*time.Second), subscribed))
+
+	// 取消订阅后丢弃
+	a.closeUntil(t0.Add(5*time.Minute), func(string, types.Interval) bool { return false })
+	assert.Empty(t, a.candles)
+}
+
+func TestStream_AggregateKLines(t *testing.T) {
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_YES", assetID == "111111111111"
+	})
+	stream.SetPublicOnly()
+	stream.Subscribe(types.KLineChannel, "PM_YES", types.SubscribeOptions{Interval: types.Interval1m})
+	stream.Subscribe(types.KLineChannel, "PM_YES", types.SubscribeOptions{Interval: types.Interval5m})
+
+	var updates, closed []types.KLine
+	stream.OnKLine(func(k types.KLine) { updates = append(updates, k) })
+	stream.OnKLineClosed(func(k types.KLine) { closed = append(closed, k) })
+
+	t0 := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+	trade := func(price float64, at time.Time) *LastTradePriceEvent {
+		return &LastTradePriceEvent{AssetID: "111111111111", Price: fixedpoint.NewFromFloat(price), Size: fixedpoint.NewFromFloat(10),
+			Side: "BUY", Timestamp: types.MillisecondTimestamp(at)}
+	}
+
+	stream.dispatchEvent([]interface{}{trade(0.5, t0.Add(10*time.Second)), trade(0.6, t0.Add(70*time.Second))})
+	assert.Len(t, updates, 4)
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, types.Interval1m, closed[0].Interval)
+		assert.Equal(t, "PM_YES", closed[0].Symbol)
+		assert.Equal(t, types.ExchangePolymarket, closed[0].Exchange)
+	}
+
+	// 默认来源为成交价，盘口变化不参与聚合
+	stream.dispatchEvent([]interface{}{&PriceChangeEvent{PriceChanges: []PriceChange{
+		{AssetID: "111111111111", BestBid: fixedpoint.NewFromFloat(0.4), BestAsk: fixedpoint.NewFromFloat(0.42)},
+	}, Timestamp: types.MillisecondTimestamp(t0.Add(80 * time.Second))}})
+	assert.Len(t, updates, 4)
+}
+
+func TestStream_AggregateMidpointKLines(t *testing.T) {
+	t.Setenv(envWsKLineSource, KLineSourceMidpoint)
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_YES", true
+	})
+	stream.SetPublicOnly()
+	stream.Subscribe(types.KLineChannel, "PM_YES", types.SubscribeOptions{Interval: types.Interval1m})
+
+	var updates []types.KLine
+	stream.OnKLine(func(k types.KLine) { updates = append(updates, k) })
+
+	t0 := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+	stream.dispatchEvent([]interface{}{&PriceChangeEvent{PriceChanges: []PriceChange{
+		{AssetID: "111111111111", BestBid: fixedpoint.NewFromFloat(0.4), BestAsk: fixedpoint.NewFromFloat(0.42)},
+	}, Timestamp: types.MillisecondTimestamp(t0)}})
+	stream.dispatchEvent([]interface{}{&LastTradePriceEvent{AssetID: "111111111111", Price: fixedpoint.NewFromFloat(0.9),
+		Size: fixedpoint.NewFromFloat(1), Timestamp: types.MillisecondTimestamp(t0)}})
+
+	if assert.Len(t, updates, 1) {
+		assert.Equal(t, "0.41", updates[0].Close.String())
+		assert.True(t, updates[0].Volume.IsZero())
+	}
+}
+
+func TestKLineSourceFromEnv(t *testing.T) {
+	assert.Equal(t, KLineSourceTrade, klineSourceFromEnv())
+	t.Setenv(envWsKLineSource, "bogus")
+	assert.Equal(t, KLineSourceTrade, klineSourceFromEnv())
+}
@@ -0,0 +1,146 @@
+package polymarket
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// websocket 事件延迟：读循环分发每条消息时，用事件自带的交易所时间戳（毫秒）计算接收延迟（接收时间 - 事件时间），
+// 用来发现推送落后（服务端拥塞、网络抖动或本机处理不过来）。
+// - 接收时间按 CLOB 服务器时间的时钟偏差修正（见 clock.go），避免本机时钟漂移被算成延迟；偏差修正后小于 0 的延迟记为 0
+// - 延迟记录到 polymarket_websocket_event_lag_milliseconds（按 channel 与 event_type 区分），
+//   Stream.EventLag 返回最近一次的延迟与最近 POLYMARKET_WS_LAG_WINDOW（默认 100，0 关闭统计）个事件的滚动平均
+// - 带时间戳的事件（order / book / last_trade_price / price_change / tick_size_change）的 Lag 字段在交给处理函数之前填充，
+//   通过 Stream.OnEvent 注册的处理函数可以直接读取；关闭统计时 Lag 为 0
+// - trade 事件只有秒级的撮合时间，不参与统计
+
+const (
+	envWsLagWindow = "POLYMARKET_WS_LAG_WINDOW"
+
+	defaultWsLagWindow = 100
+)
+
+var websocketEventLagMetrics = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: "polymarket_websocket_event_lag_milliseconds",
+		Help: "Delay between the exchange timestamp of a websocket event and the time it is received, in milliseconds",
+		// 1ms ~ 30s
+		Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000},
+	}, []string{"channel", "event_type"},
+)
+
+// EventLag 为 websocket 事件的接收延迟统计。
+type EventLag struct {
+	// Last 为最近一个事件的延迟
+	Last time.Duration
+	// Average 为最近 Samples 个事件延迟的滚动平均
+	Average time.Duration
+	Samples int
+	// UpdatedAt 为最近一个事件的接收时间
+	UpdatedAt time.Time
+}
+
+// lagWindow 保存最近 len(samples) 个延迟，计算滚动平均。
+type lagWindow struct {
+	samples []time.Duration
+	next    int
+	count   int
+	sum     time.Duration
+
+	last      time.Duration
+	updatedAt time.Time
+}
+
+func newLagWindow(size int) *lagWindow {
+	if size <= 0 {
+		return nil
+	}
+	return &lagWindow{samples: make([]time.Duration, size)}
+}
+
+func (w *lagWindow) add(lag time.Duration, at time.Time) {
+	if w.count == len(w.samples) {
+		w.sum -= w.samples[w.next]
+	} else {
+		w.count++
+	}
+	w.samples[w.next] = lag
+	w.sum += lag
+	w.next = (w.next + 1) % len(w.samples)
+
+	w.last, w.updatedAt = lag, at
+}
+
+func (w *lagWindow) stats() EventLag {
+	if w.count == 0 {
+		return EventLag{}
+	}
+	return EventLag{
+		Last:      w.last,
+		Average:   w.sum / time.Duration(w.count),
+		Samples:   w.count,
+		UpdatedAt: w.updatedAt,
+	}
+}
+
+// eventLag 返回事件的延迟字段，没有交易所时间戳的事件返回 nil。
+func eventLag(event interface{}) (ts time.Time, lag *time.Duration) {
+	switch e := event.(type) {
+	case *OrderEvent:
+		return e.Timestamp.Time(), &e.Lag
+	case *BookEvent:
+		return e.Timestamp.Time(), &e.Lag
+	case *LastTradePriceEvent:
+		return e.Timestamp.Time(), &e.Lag
+	case *PriceChangeEvent:
+		return e.Timestamp.Time(), &e.Lag
+	case *TickSizeChangeEvent:
+		return e.Timestamp.Time(), &e.Lag
+	}
+	return time.Time{}, nil
+}
+
+// recordEventLag 计算事件的接收延迟，填充事件的 Lag 字段并计入统计。
+func (s *Stream) recordEventLag(event interface{}, eventType WsEventType, receivedAt time.Time) {
+	if s.lags == nil {
+		return
+	}
+
+	ts, lag := eventLag(event)
+	if lag == nil || ts.Unix() <= 0 {
+		return
+	}
+
+	d := receivedAt.Sub(ts)
+	if d < 0 {
+		d = 0
+	}
+	*lag = d
+
+	s.mu.Lock()
+	s.lags.add(d, receivedAt)
+	s.mu.Unlock()
+
+	websocketEventLagMetrics.With(prometheus.Labels{"channel": s.channel(), "event_type": string(eventType)}).Observe(float64(d.Milliseconds()))
+}
+
+// EventLag 返回 websocket 事件的接收延迟统计，还没有收到带时间戳的事件或关闭统计（POLYMARKET_WS_LAG_WINDOW=0）时 ok 为 false。
+func (s *Stream) EventLag() (lag EventLag, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lags == nil {
+		return EventLag{}, false
+	}
+	lag = s.lags.stats()
+	return lag, lag.Samples > 0
+}
+
+// receivedAt 返回按服务器时钟偏差修正后的当前时间。
+func (s *Stream) receivedAt() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,105 @@
+package polymarket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestLagWindow(t *testing.T) {
+	assert.Nil(t, newLagWindow(0))
+
+	w := newLagWindow(3)
+	assert.Zero(t, w.stats().Samples)
+
+	now := time.Now()
+	w.add(100*time.Millisecond, now)
+	w.add(200*time.Millisecond, now)
+	stats := w.stats()
+	assert.Equal(t, 2, stats.Samples)
+	assert.Equal(t, 150*time.Millisecond, stats.Average)
+	assert.Equal(t, 200*time.Millisecond, stats.Last)
+
+	// 超过窗口大小时丢掉最早的延迟
+	w.add(300*time.Millisecond, now)
+	w.add(700*time.Millisecond, now.Add(time.Second))
+	stats = w.stats()
+	assert.Equal(t, 3, stats.Samples)
+	assert.Equal(t, 400*time.Millisecond, stats.Average)
+	assert.Equal(t, 700*time.Millisecond, stats.Last)
+	assert.Equal(t, now.Add(time.Second), stats.UpdatedAt)
+}
+
+func TestStream_EventLag(t *testing.T) {
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_YES", assetID == "111111111111"
+	})
+	stream.SetPublicOnly()
+
+	now := time.Now()
+	stream.now = func() time.Time { return now }
+
+	_, ok := stream.EventLag()
+	assert.False(t, ok)
+
+	var lags []time.Duration
+	stream.OnEvent(WsEventTypeBook, func(e interface{}) {
+		lags = append(lags, e.(*BookEvent).Lag)
+	})
+
+	stream.dispatchEvent([]interface{}{
+		&BookEvent{AssetID: "111111111111", Timestamp: types.MillisecondTimestamp(now.Add(-120 * time.Millisecond))},
+		// 时钟偏差修正后事件时间晚于接收时间，记为 0
+		&BookEvent{AssetID: "111111111111", Timestamp: types.MillisecondTimestamp(now.Add(50 * time.Millisecond))},
+		// 没有时间戳的事件不参与统计
+		&BookEvent{AssetID: "111111111111"},
+		&LastTradePriceEvent{AssetID: "111111111111", Timestamp: types.MillisecondTimestamp(now.Add(-300 * time.Millisecond))},
+	})
+	assert.Equal(t, []time.Duration{120 * time.Millisecond, 0, 0}, lags)
+
+	lag, ok := stream.EventLag()
+	if assert.True(t, ok) {
+		assert.Equal(t, 3, lag.Samples)
+		assert.Equal(t, 300*time.Millisecond, lag.Last)
+		assert.Equal(t, 140*time.Millisecond, lag.Average)
+		assert.Equal(t, now, lag.UpdatedAt)
+	}
+}
+
+func TestStream_EventLagDisabled(t *testing.T) {
+	t.Setenv(envWsLagWindow, "0")
+
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_YES", true
+	})
+
+	var lag time.Duration = -1
+	stream.OnEvent(WsEventTypeOrder, func(e interface{}) {
+		lag = e.(*OrderEvent).Lag
+	})
+	stream.dispatchEvent([]interface{}{&OrderEvent{AssetID: "111111111111", Timestamp: types.MillisecondTimestamp(time.Now().Add(-time.Second))}})
+	assert.Zero(t, lag)
+
+	_, ok := stream.EventLag()
+	assert.False(t, ok)
+}
+
+func TestExchange_StreamEventLagUsesServerTime(t *testing.T) {
+	ex := New("", "", "")
+	defer ex.Close()
+
+	// 本机时钟比服务器慢 1 分钟，修正后不会把时钟偏差算成负的延迟
+	ex.client.clock.set(time.Minute)
+	stream := ex.NewStream().(*Stream)
+	stream.SetPublicOnly()
+
+	var lag time.Duration
+	stream.OnEvent(WsEventTypeTickSizeChange, func(e interface{}) {
+		lag = e.(*TickSizeChangeEvent).Lag
+	})
+	stream.dispatchEvent([]interface{}{&TickSizeChangeEvent{Timestamp: types.MillisecondTimestamp(time.Now().Add(time.Minute - time.Second))}})
+	assert.InDelta(t, float64(time.Second), float64(lag), float64(100*time.Millisecond))
+}
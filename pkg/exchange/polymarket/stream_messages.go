@@ -0,0 +1,198 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// CLOB websocket 的事件类型（event_type 字段）
+type WsEventType string
+
+const (
+	WsEventTypeOrder WsEventType = "order"
+	WsEventTypeTrade WsEventType = "trade"
+
+	// market channel
+	WsEventTypeBook           WsEventType = "book"
+	WsEventTypeLastTradePrice WsEventType = "last_trade_price"
+	WsEventTypePriceChange    WsEventType = "price_change"
+	WsEventTypeTickSizeChange WsEventType = "tick_size_change"
+)
+
+// OrderEvent 中 type 字段的取值
+const (
+	OrderEventPlacement    = "PLACEMENT"
+	OrderEventUpdate       = "UPDATE"
+	OrderEventCancellation = "CANCELLATION"
+)
+
+// user channel 的文本心跳：客户端发送 PING，服务端回复 PONG
+var (
+	wsPingMessage = []byte("PING")
+	wsPongMessage = []byte("PONG")
+)
+
+// WsSubscribeRequest 是连接 user / market channel 后发送的订阅消息。
+// user channel 按 condition id（Markets）订阅，market channel 按 token id（AssetIDs）订阅。
+// 连接后的第一条消息带 Type；之后在同一个连接上增量订阅 / 退订时只带 Operation（subscribe / unsubscribe）。
+type WsSubscribeRequest struct {
+	Auth      *WsAuth  `json:"auth,omitempty"`
+	Markets   []string `json:"markets,omitempty"`
+	AssetIDs  []string `json:"assets_ids,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Operation string   `json:"operation,omitempty"`
+}
+
+type WsAuth struct {
+	APIKey     string `json:"apiKey"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase"`
+}
+
+type wsEventHeader struct {
+	EventType WsEventType `json:"event_type"`
+}
+
+// OrderEvent 是 user channel 推送的订单事件。
+type OrderEvent struct {
+	EventType    WsEventType                `json:"event_type"`
+	ID           string                     `json:"id"`
+	Market       string                     `json:"market"`
+	AssetID      string                     `json:"asset_id"`
+	Outcome      string                     `json:"outcome"`
+	Side         string                     `json:"side"`
+	Price        fixedpoint.Value           `json:"price"`
+	OriginalSize fixedpoint.Value           `json:"original_size"`
+	SizeMatched  fixedpoint.Value           `json:"size_matched"`
+	Type         string                     `json:"type"`
+	Timestamp    types.MillisecondTimestamp `json:"timestamp"`
+
+	// Lag 为事件的接收延迟，由 stream 在分发前填充（market channel 带时间戳的事件相同），见 stream_lag.go
+	Lag time.Duration `json:"-"`
+}
+
+// TradeEvent 是 user channel 推送的成交事件，字段与 GET /data/trades 的成交记录相同，
+// 只是撮合时间为 matchtime。同一笔成交会随上链进度（MATCHED → MINED → CONFIRMED，失败时 RETRYING / FAILED）推送多次。
+type TradeEvent struct {
+	TradeRecord
+
+	EventType WsEventType `json:"event_type"`
+	// WsMatchTime 为撮合时间（unix 秒）
+	WsMatchTime string `json:"matchtime"`
+}
+
+// TradeEvent 中 status 字段的取值
+const (
+	TradeStatusMatched = "MATCHED"
+	TradeStatusFailed  = "FAILED"
+)
+
+// BookEvent 是 market channel 推送的盘口快照。
+type BookEvent struct {
+	EventType WsEventType                `json:"event_type"`
+	AssetID   string                     `json:"asset_id"`
+	Market    string                     `json:"market"`
+	Bids      []PriceLevel               `json:"bids"`
+	Asks      []PriceLevel               `json:"asks"`
+	Hash      string                     `json:"hash"`
+	Timestamp types.MillisecondTimestamp `json:"timestamp"`
+
+	Lag time.Duration `json:"-"`
+}
+
+// LastTradePriceEvent 是 market channel 推送的最新成交价。
+type LastTradePriceEvent struct {
+	EventType WsEventType                `json:"event_type"`
+	AssetID   string                     `json:"asset_id"`
+	Market    string                     `json:"market"`
+	Price     fixedpoint.Value           `json:"price"`
+	Side      string                     `json:"side"`
+	Size      fixedpoint.Value           `json:"size"`
+	Timestamp types.MillisecondTimestamp `json:"timestamp"`
+
+	Lag time.Duration `json:"-"`
+}
+
+// PriceChangeEvent 是 market channel 推送的盘口增量，每一项带有变化后该 token 的最优买卖价。
+type PriceChangeEvent struct {
+	EventType    WsEventType                `json:"event_type"`
+	Market       string                     `json:"market"`
+	PriceChanges []PriceChange              `json:"price_changes"`
+	Timestamp    types.MillisecondTimestamp `json:"timestamp"`
+
+	Lag time.Duration `json:"-"`
+}
+
+type PriceChange struct {
+	AssetID string           `json:"asset_id"`
+	Price   fixedpoint.Value `json:"price"`
+	Size    fixedpoint.Value `json:"size"`
+	Side    string           `json:"side"`
+	Hash    string           `json:"hash"`
+	BestBid fixedpoint.Value `json:"best_bid"`
+	BestAsk fixedpoint.Value `json:"best_ask"`
+}
+
+// TickSizeChangeEvent 是 market channel 推送的最小价格变动单位调整（价格接近 0 或 1 时 tick 会变小）。
+type TickSizeChangeEvent struct {
+	EventType   WsEventType                `json:"event_type"`
+	AssetID     string                     `json:"asset_id"`
+	Market      string                     `json:"market"`
+	OldTickSize fixedpoint.Value           `json:"old_tick_size"`
+	NewTickSize fixedpoint.Value           `json:"new_tick_size"`
+	Timestamp   types.MillisecondTimestamp `json:"timestamp"`
+
+	Lag time.Duration `json:"-"`
+}
+
+// UnknownEvent 为没有注册解析函数的事件，保留原始消息。
+type UnknownEvent struct {
+	EventType WsEventType
+	Raw       json.RawMessage
+}
+
+// parseWebSocketEvent 解析 CLOB websocket 消息。服务端可能推送单个对象或对象数组，
+// 这里统一返回 []interface{}，事件按 wsEventDecoders 解析（见 stream_dispatch.go），
+// 未知事件类型解析成 *UnknownEvent；心跳回复 PONG 返回 *types.WebsocketPongEvent。
+func parseWebSocketEvent(message []byte) (interface{}, error) {
+	message = bytes.TrimSpace(message)
+	if bytes.Equal(message, wsPongMessage) {
+		return &types.WebsocketPongEvent{}, nil
+	}
+
+	var raws []json.RawMessage
+	if len(message) > 0 && message[0] == '[' {
+		if err := json.Unmarshal(message, &raws); err != nil {
+			return nil, err
+		}
+	} else {
+		raws = []json.RawMessage{message}
+	}
+
+	var events []interface{}
+	for _, raw := range raws {
+		var header wsEventHeader
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, err
+		}
+
+		decode, ok := wsEventDecoders[header.EventType]
+		if !ok {
+			events = append(events, &UnknownEvent{EventType: header.EventType, Raw: raw})
+			continue
+		}
+
+		e, err := decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("polymarket: decode %s event failed: %w", header.EventType, err)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,168 @@
+package polymarket
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// websocket 重连策略：连接断开（读取失败、pong 超时、Resubscribe）后按指数退避重连，替代 StandardStream 固定 15s 的冷却。
+// - 第 n 次重连前等待 InitialDelay * Multiplier^(n-1)，不超过 MaxDelay，再加上 ±Jitter 比例的随机抖动，避免多个 bot 同时重连
+// - 重连成功后次数清零；MaxAttempts 次连续失败后放弃重连（stream 保持 disconnected），MaxAttempts <= 0 表示无限重试
+// - 等待期间 ctx 取消或 stream 关闭时立即退出
+// - 默认值可以通过环境变量覆盖，也可以在 Connect 之前用 Stream.SetReconnectPolicy 设置
+
+const (
+	envWsReconnectInitialDelay = "POLYMARKET_WS_RECONNECT_INITIAL_DELAY"
+	envWsReconnectMaxDelay     = "POLYMARKET_WS_RECONNECT_MAX_DELAY"
+	envWsReconnectMultiplier   = "POLYMARKET_WS_RECONNECT_MULTIPLIER"
+	envWsReconnectJitter       = "POLYMARKET_WS_RECONNECT_JITTER"
+	envWsReconnectMaxAttempts  = "POLYMARKET_WS_RECONNECT_MAX_ATTEMPTS"
+
+	defaultReconnectInitialDelay = time.Second
+	defaultReconnectMaxDelay     = time.Minute
+	defaultReconnectMultiplier   = 2.0
+	defaultReconnectJitter       = 0.2
+)
+
+// ReconnectPolicy 是 websocket 断线重连的退避策略。
+type ReconnectPolicy struct {
+	// InitialDelay 为第一次重连前的等待时间
+	InitialDelay time.Duration
+	// MaxDelay 为等待时间的上限（抖动之前）
+	MaxDelay time.Duration
+	// Multiplier 为每次失败后等待时间的倍数，小于 1 时按 1 处理（固定间隔）
+	Multiplier float64
+	// Jitter 为随机抖动的比例（0~1），等待时间在 [d*(1-Jitter), d*(1+Jitter)] 之间
+	Jitter float64
+	// MaxAttempts 为连续失败多少次后放弃重连，<= 0 表示无限重试
+	MaxAttempts int
+}
+
+// DefaultReconnectPolicy 返回默认的重连策略：1s 起、每次翻倍、最多 1m，±20% 抖动，无限重试。
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		InitialDelay: defaultReconnectInitialDelay,
+		MaxDelay:     defaultReconnectMaxDelay,
+		Multiplier:   defaultReconnectMultiplier,
+		Jitter:       defaultReconnectJitter,
+	}
+}
+
+func newReconnectPolicyFromEnv() ReconnectPolicy {
+	return ReconnectPolicy{
+		InitialDelay: envDuration(envWsReconnectInitialDelay, defaultReconnectInitialDelay),
+		MaxDelay:     envDuration(envWsReconnectMaxDelay, defaultReconnectMaxDelay),
+		Multiplier:   envFloat(envWsReconnectMultiplier, defaultReconnectMultiplier),
+		Jitter:       envFloat(envWsReconnectJitter, defaultReconnectJitter),
+		MaxAttempts:  envInt(envWsReconnectMaxAttempts, 0),
+	}.normalize()
+}
+
+// normalize 修正无效的字段：等待时间不为正时使用默认值，MaxDelay 不小于 InitialDelay，Jitter 限制在 [0, 1]。
+func (p ReconnectPolicy) normalize() ReconnectPolicy {
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = defaultReconnectInitialDelay
+	}
+	if p.MaxDelay < p.InitialDelay {
+		p.MaxDelay = p.InitialDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 1
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	return p
+}
+
+// delay 返回第 attempt 次（从 1 开始）重连前的等待时间，random 返回 [0, 1) 的随机数。
+func (p ReconnectPolicy) delay(attempt int, random func() float64) time.Duration {
+	d := float64(p.InitialDelay)
+	for i := 1; i < attempt && d < float64(p.MaxDelay); i++ {
+		d *= p.Multiplier
+	}
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*random()-1)
+	}
+	return time.Duration(d)
+}
+
+// exhausted 判断连续失败 attempts 次后是否应该放弃重连。
+func (p ReconnectPolicy) exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// SetReconnectPolicy 设置断线重连策略，需要在 Connect 之前调用。
+func (s *Stream) SetReconnectPolicy(p ReconnectPolicy) {
+	s.mu.Lock()
+	s.reconnectPolicy = p.normalize()
+	s.mu.Unlock()
+}
+
+// ReconnectPolicy 返回当前的断线重连策略。
+func (s *Stream) ReconnectPolicy() ReconnectPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reconnectPolicy
+}
+
+// reconnector 替代 StandardStream 的 reconnector：收到 ReconnectC 信号后按 ReconnectPolicy 退避重连，
+// ctx 取消、stream 关闭或重连次数用尽时退出。
+func (s *Stream) reconnector(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.CloseC:
+			return
+		case <-s.ReconnectC:
+		}
+
+		if !s.reconnect(ctx) {
+			return
+		}
+	}
+}
+
+// reconnect 按退避策略重连直到成功，成功时返回 true；ctx 取消、stream 关闭或重连次数用尽时返回 false。
+func (s *Stream) reconnect(ctx context.Context) bool {
+	policy := s.ReconnectPolicy()
+	random := s.reconnectRand
+	if random == nil {
+		random = rand.Float64
+	}
+
+	for attempt := 1; ; attempt++ {
+		d := policy.delay(attempt, random)
+		log.Warnf("%s channel disconnected, reconnecting in %s (attempt %d)...", s.channel(), d, attempt)
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-s.CloseC:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		err := s.DialAndConnect(ctx)
+		if err == nil {
+			return true
+		}
+
+		if policy.exhausted(attempt) {
+			log.WithError(err).Errorf("%s channel reconnect failed %d times, giving up", s.channel(), attempt)
+			return false
+		}
+		log.WithError(err).Warnf("%s channel reconnect failed", s.channel())
+	}
+}
@@ -0,0 +1,117 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectPolicy_Delay(t *testing.T) {
+	p := ReconnectPolicy{InitialDelay: time.Second, MaxDelay: 10 * time.Second, Multiplier: 2}
+	noJitter := func() float64 { return 0.5 }
+
+	var delays []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		delays = append(delays, p.delay(attempt, noJitter))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}, delays)
+
+	// 抖动在 ±Jitter 之间
+	p.Jitter = 0.2
+	assert.Equal(t, 800*time.Millisecond, p.delay(1, func() float64 { return 0 }))
+	assert.Equal(t, 12*time.Second, p.delay(10, func() float64 { return 1 }))
+
+	// 无限重试
+	assert.False(t, p.exhausted(1000000))
+	p.MaxAttempts = 3
+	assert.False(t, p.exhausted(2))
+	assert.True(t, p.exhausted(3))
+}
+
+func TestReconnectPolicy_Normalize(t *testing.T) {
+	p := ReconnectPolicy{MaxDelay: time.Millisecond, Multiplier: 0.5, Jitter: 3}.normalize()
+	assert.Equal(t, defaultReconnectInitialDelay, p.InitialDelay)
+	assert.Equal(t, defaultReconnectInitialDelay, p.MaxDelay)
+	assert.Equal(t, 1.0, p.Multiplier)
+	assert.Equal(t, 1.0, p.Jitter)
+
+	t.Setenv(envWsReconnectInitialDelay, "500ms")
+	t.Setenv(envWsReconnectMaxAttempts, "5")
+	stream := NewStream("", "", "", false, nil)
+	assert.Equal(t, 500*time.Millisecond, stream.ReconnectPolicy().InitialDelay)
+	assert.Equal(t, 5, stream.ReconnectPolicy().MaxAttempts)
+	assert.Equal(t, defaultReconnectMaxDelay, stream.ReconnectPolicy().MaxDelay)
+}
+
+func TestStream_ReconnectBackoff(t *testing.T) {
+	// 拒绝 websocket 升级，每次重连都失败
+	var dials atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dials.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	t.Setenv(envWsUserURL, "ws"+strings.TrimPrefix(server.URL, "http"))
+
+	t.Run("max attempts", func(t *testing.T) {
+		dials.Store(0)
+		stream := NewStream("key", "secret", "pass", false, nil)
+		stream.SetReconnectPolicy(ReconnectPolicy{InitialDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond, Multiplier: 2, MaxAttempts: 3})
+
+		start := time.Now()
+		assert.False(t, stream.reconnect(context.Background()))
+		assert.Equal(t, int32(3), dials.Load())
+		// 10ms + 20ms + 40ms
+		assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		dials.Store(0)
+		stream := NewStream("key", "secret", "pass", false, nil)
+		stream.SetReconnectPolicy(ReconnectPolicy{InitialDelay: time.Hour, Multiplier: 2})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			stream.reconnector(ctx)
+			close(done)
+		}()
+
+		stream.Reconnect()
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			t.Fatal("reconnector did not exit after context cancellation")
+		}
+		assert.Equal(t, int32(0), dials.Load())
+	})
+
+	t.Run("stream closed", func(t *testing.T) {
+		stream := NewStream("key", "secret", "pass", false, nil)
+		stream.SetReconnectPolicy(ReconnectPolicy{InitialDelay: time.Hour})
+		// 模拟已经建立过连接，Close 会关闭 CloseC
+		stream.connected = true
+
+		done := make(chan bool)
+		go func() {
+			done <- stream.reconnect(context.Background())
+		}()
+		assert.NoError(t, stream.Close())
+
+		select {
+		case ok := <-done:
+			assert.False(t, ok)
+		case <-time.After(3 * time.Second):
+			t.Fatal("reconnect did not exit after close")
+		}
+	})
+}
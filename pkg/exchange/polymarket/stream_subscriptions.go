@@ -0,0 +1,171 @@
+package polymarket
+
+import (
+	"sort"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// market channel 的订阅管理：
+// - Stream 记录当前连接上已订阅的 token id，连接建立时按 subscribedAssetIDs 全量订阅
+// - 订阅消息按 POLYMARKET_WS_SUBSCRIBE_BATCH（默认 100）个 token id 一批发送：第一批为带 type 的初始订阅，
+//   之后的批次与增量修改都用 operation=subscribe / unsubscribe，在当前连接上完成，不需要重连
+// - Resubscribe（例如策略在窗口切换时把旧窗口的 symbol 换成新窗口的）只发送变化的部分，旧窗口的 token 被退订
+// - Exchange 的 market 列表重新加载 / 刷新后，所有 market channel 按新的 market 列表对齐订阅（见 Exchange.reconcileStreams）
+
+const (
+	envWsSubscribeBatch     = "POLYMARKET_WS_SUBSCRIBE_BATCH"
+	defaultWsSubscribeBatch = 100
+)
+
+const (
+	wsOperationSubscribe   = "subscribe"
+	wsOperationUnsubscribe = "unsubscribe"
+)
+
+// Resubscribe 更新订阅。market channel 不重连：已连接时只订阅新增、退订移除的 token id，未连接时连接后按新的订阅订阅；
+// user channel 与 StandardStream 一样更新订阅后重连。
+func (s *Stream) Resubscribe(fn func(old []types.Subscription) ([]types.Subscription, error)) error {
+	if !s.PublicOnly {
+		return s.StandardStream.Resubscribe(fn)
+	}
+
+	if _, err := s.UpdateSubscriptions(fn); err != nil {
+		return err
+	}
+	return s.reconcileAssetIDs()
+}
+
+// ActiveAssetIDs 返回 market channel 当前连接上已订阅的 token id（排序）。
+func (s *Stream) ActiveAssetIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.activeAssetIDs))
+	for id := range s.activeAssetIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// subscribeMarketChannel 在新建立的连接上按 subscribedAssetIDs 分批订阅，并重置已订阅的 token id。
+func (s *Stream) subscribeMarketChannel(conn *websocket.Conn) error {
+	ids := s.subscribedAssetIDs()
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	s.activeAssetIDs = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		s.activeAssetIDs[id] = true
+	}
+	s.mu.Unlock()
+
+	batches := batchAssetIDs(ids, s.subscribeBatch)
+	if len(batches) == 0 {
+		batches = [][]string{nil}
+	}
+	for i, batch := range batches {
+		req := WsSubscribeRequest{AssetIDs: batch, Type: "market"}
+		if i > 0 {
+			req = WsSubscribeRequest{AssetIDs: batch, Operation: wsOperationSubscribe}
+		}
+		if err := conn.WriteJSON(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileAssetIDs 让当前连接上订阅的 token id 与 subscribedAssetIDs 一致：先退订不再需要的，再订阅新增的。
+// 没有连接 market channel 时不做任何事。
+func (s *Stream) reconcileAssetIDs() error {
+	if !s.PublicOnly || !s.marketChannel {
+		return nil
+	}
+
+	want := s.subscribedAssetIDs()
+
+	s.ConnLock.Lock()
+	conn := s.Conn
+	s.ConnLock.Unlock()
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	if conn == nil || s.state != StreamStateConnected {
+		s.mu.Unlock()
+		return nil
+	}
+	added, removed := diffAssetIDs(s.activeAssetIDs, want)
+	for _, id := range removed {
+		delete(s.activeAssetIDs, id)
+	}
+	for _, id := range added {
+		s.activeAssetIDs[id] = true
+	}
+	active := len(s.activeAssetIDs)
+	s.mu.Unlock()
+
+	for _, op := range []struct {
+		operation string
+		ids       []string
+	}{
+		{wsOperationUnsubscribe, removed},
+		{wsOperationSubscribe, added},
+	} {
+		for _, batch := range batchAssetIDs(op.ids, s.subscribeBatch) {
+			if err := conn.WriteJSON(WsSubscribeRequest{AssetIDs: batch, Operation: op.operation}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(added) > 0 || len(removed) > 0 {
+		log.Infof("market channel subscriptions updated: %d subscribed, %d unsubscribed, %d active", len(added), len(removed), active)
+	}
+	return nil
+}
+
+// writeJSON 在 writeMu 下写入一条 JSON 消息。
+func (s *Stream) writeJSON(conn *websocket.Conn, v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return conn.WriteJSON(v)
+}
+
+// diffAssetIDs 返回 want 中新增的与 active 中不再需要的 token id（都按 want / 排序后的顺序）。
+func diffAssetIDs(active map[string]bool, want []string) (added, removed []string) {
+	wanted := make(map[string]bool, len(want))
+	for _, id := range want {
+		wanted[id] = true
+		if !active[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range active {
+		if !wanted[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	return added, removed
+}
+
+// batchAssetIDs 把 ids 按 size 分批，size <= 0 时不分批。
+func batchAssetIDs(ids []string, size int) (batches [][]string) {
+	if size <= 0 {
+		size = len(ids)
+	}
+	for len(ids) > 0 {
+		n := min(size, len(ids))
+		batches = append(batches, ids[:n])
+		ids = ids[n:]
+	}
+	return batches
+}
@@ -0,0 +1,131 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestBatchAssetIDs(t *testing.T) {
+	assert.Equal(t, [][]string{{"1", "2"}, {"3"}}, batchAssetIDs([]string{"1", "2", "3"}, 2))
+	assert.Equal(t, [][]string{{"1", "2", "3"}}, batchAssetIDs([]string{"1", "2", "3"}, 0))
+	assert.Empty(t, batchAssetIDs(nil, 2))
+}
+
+func TestDiffAssetIDs(t *testing.T) {
+	added, removed := diffAssetIDs(map[string]bool{"1": true, "2": true, "3": true}, []string{"2", "4", "3"})
+	assert.Equal(t, []string{"4"}, added)
+	assert.Equal(t, []string{"1"}, removed)
+
+	added, removed = diffAssetIDs(nil, []string{"1"})
+	assert.Equal(t, []string{"1"}, added)
+	assert.Empty(t, removed)
+}
+
+const (
+	testTokenA = "111111111111"
+	testTokenB = "222222222222"
+	testTokenC = "333333333333"
+	testTokenD = "444444444444"
+)
+
+func testMarketsJSON(tokens ...string) string {
+	var items []string
+	for _, token := range tokens {
+		items = append(items, `{"symbol": "PM_`+token[:1]+`", "localSymbol": "`+token+`", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}`)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestStream_MarketChannelSubscriptions(t *testing.T) {
+	requests := make(chan WsSubscribeRequest, 16)
+	var connections atomic.Int32
+
+	upgrader := websocket.Upgrader{}
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connections.Add(1)
+
+		for {
+			var req WsSubscribeRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			requests <- req
+		}
+	}))
+	defer ws.Close()
+
+	t.Setenv(envWsMarket, "true")
+	t.Setenv(envWsMarketURL, "ws"+strings.TrimPrefix(ws.URL, "http"))
+	t.Setenv(envWsSubscribeBatch, "2")
+	t.Setenv(envMarketsJSON, testMarketsJSON(testTokenA, testTokenB, testTokenC, testTokenD))
+
+	ctx := context.Background()
+	ex := New("", "", "")
+	defer ex.Close()
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	stream := ex.NewStream().(*Stream)
+	stream.SetPublicOnly()
+	for _, symbol := range []string{"PM_1", "PM_2", "PM_3"} {
+		stream.Subscribe(types.BookChannel, symbol, types.SubscribeOptions{})
+	}
+	assert.NoError(t, stream.Connect(ctx))
+	defer stream.Close()
+
+	next := func() WsSubscribeRequest {
+		select {
+		case req := <-requests:
+			return req
+		case <-time.After(3 * time.Second):
+			t.Fatal("subscription request not received")
+		}
+		return WsSubscribeRequest{}
+	}
+
+	// 初始订阅分批发送
+	assert.Equal(t, WsSubscribeRequest{AssetIDs: []string{testTokenA, testTokenB}, Type: "market"}, next())
+	assert.Equal(t, WsSubscribeRequest{AssetIDs: []string{testTokenC}, Operation: wsOperationSubscribe}, next())
+	assert.Equal(t, []string{testTokenA, testTokenB, testTokenC}, stream.ActiveAssetIDs())
+
+	// 窗口切换：退订旧的，订阅新的，不重连
+	assert.NoError(t, stream.Resubscribe(func(old []types.Subscription) ([]types.Subscription, error) {
+		return []types.Subscription{
+			{Channel: types.BookChannel, Symbol: "PM_2"},
+			{Channel: types.BookChannel, Symbol: "PM_3"},
+			{Channel: types.BookChannel, Symbol: "PM_4"},
+		}, nil
+	}))
+	assert.Equal(t, WsSubscribeRequest{AssetIDs: []string{testTokenA}, Operation: wsOperationUnsubscribe}, next())
+	assert.Equal(t, WsSubscribeRequest{AssetIDs: []string{testTokenD}, Operation: wsOperationSubscribe}, next())
+	assert.Equal(t, []string{testTokenB, testTokenC, testTokenD}, stream.ActiveAssetIDs())
+
+	// market 列表刷新后移除的 market 被退订
+	t.Setenv(envMarketsJSON, testMarketsJSON(testTokenA, testTokenB, testTokenD))
+	_, err = ex.RefreshMarkets(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, WsSubscribeRequest{AssetIDs: []string{testTokenC}, Operation: wsOperationUnsubscribe}, next())
+	assert.Equal(t, []string{testTokenB, testTokenD}, stream.ActiveAssetIDs())
+
+	assert.Equal(t, int32(1), connections.Load())
+	select {
+	case req := <-requests:
+		t.Fatalf("unexpected subscription request: %+v", req)
+	default:
+	}
+}
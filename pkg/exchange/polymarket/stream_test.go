@@ -0,0 +1,369 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStream_OrderEvent(t *testing.T) {
+	msg := []byte(`[{
+		"event_type": "order",
+		"id": "0xff354cd7ca7539dfa9c28d90943ab5779a4eac34b9b37a757d7b32bdfb11790b",
+		"market": "0xbd31dc8a20211944f6b70f31557f1001557b59905b7738480ca09bd4532f84af",
+		"asset_id": "52114319501245915516055106046884209969926127482827954674443846427813813222426",
+		"side": "BUY",
+		"price": "0.57",
+		"original_size": "10",
+		"size_matched": "4",
+		"type": "UPDATE",
+		"timestamp": "1672290687"
+	}]`)
+
+	event, err := parseWebSocketEvent(msg)
+	assert.NoError(t, err)
+
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_BTC_15M_UP_YES_USDC", true
+	})
+
+	var got []types.Order
+	stream.OnOrderUpdate(func(o types.Order) {
+		got = append(got, o)
+	})
+	stream.dispatchEvent(event)
+
+	if assert.Len(t, got, 1) {
+		o := got[0]
+		assert.Equal(t, "PM_BTC_15M_UP_YES_USDC", o.Symbol)
+		assert.Equal(t, types.SideTypeBuy, o.Side)
+		assert.Equal(t, types.OrderStatusPartiallyFilled, o.Status)
+		assert.Equal(t, fixedpoint.NewFromFloat(4), o.ExecutedQuantity)
+		assert.Equal(t, int64(1672290687), o.CreationTime.Unix())
+		assert.True(t, o.IsWorking)
+	}
+}
+
+func TestStream_TradeEvent(t *testing.T) {
+	msg := []byte(`[{
+		"event_type": "trade",
+		"id": "28c4d2eb-bbea-40e7-a9f0-b2fdb56b2c2e",
+		"taker_order_id": "0x06bc63e346ed4ceddce9efd6b3af37c8f8f440c92fe7da6b2d0f9e4ccbc50c42",
+		"market": "0xbd31dc8a20211944f6b70f31557f1001557b59905b7738480ca09bd4532f84af",
+		"asset_id": "111111111111",
+		"side": "BUY",
+		"size": "10",
+		"fee_rate_bps": "0",
+		"price": "0.57",
+		"status": "MATCHED",
+		"matchtime": "1672290701",
+		"owner": "taker-key",
+		"maker_orders": [
+			{"order_id": "0xff354cd7", "owner": "key", "matched_amount": "4", "price": "0.57", "fee_rate_bps": "0", "asset_id": "111111111111", "side": "SELL"},
+			{"order_id": "0xaa11", "owner": "other-key", "matched_amount": "6", "price": "0.57", "fee_rate_bps": "0", "asset_id": "111111111111", "side": "SELL"}
+		]
+	}]`)
+
+	event, err := parseWebSocketEvent(msg)
+	assert.NoError(t, err)
+
+	stream := NewStream("key", "secret", "pass", false, func(assetID string) (string, bool) {
+		if assetID == "111111111111" {
+			return "PM_YES", true
+		}
+		return "", false
+	})
+
+	var got []types.Trade
+	stream.OnTradeUpdate(func(trade types.Trade) {
+		got = append(got, trade)
+	})
+	stream.dispatchEvent(event)
+
+	// 只推送自己的 maker 订单
+	if assert.Len(t, got, 1) {
+		trade := got[0]
+		assert.Equal(t, "PM_YES", trade.Symbol)
+		assert.Equal(t, "0xff354cd7", trade.OrderUUID)
+		assert.Equal(t, types.SideTypeSell, trade.Side)
+		assert.True(t, trade.IsMaker)
+		assert.Equal(t, "4", trade.Quantity.String())
+		assert.Equal(t, int64(1672290701), trade.Time.Time().Unix())
+	}
+
+	// 上链状态变化不重复推送
+	e := *event.([]interface{})[0].(*TradeEvent)
+	e.Status = "CONFIRMED"
+	stream.dispatchEvent([]interface{}{&e})
+	assert.Len(t, got, 1)
+
+	// taker 成交
+	stream.dispatchEvent([]interface{}{&TradeEvent{TradeRecord: TradeRecord{
+		ID:           "t2",
+		TakerOrderID: "0x0123",
+		AssetID:      "111111111111",
+		Side:         "BUY",
+		Size:         fixedpoint.NewFromFloat(2),
+		Price:        fixedpoint.NewFromFloat(0.6),
+		Status:       TradeStatusMatched,
+		MatchTime:    "1672290702",
+		Owner:        "key",
+	}}})
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "0x0123", got[1].OrderUUID)
+		assert.Equal(t, types.SideTypeBuy, got[1].Side)
+		assert.False(t, got[1].IsMaker)
+	}
+
+	// 未知 token id 的成交被丢弃
+	stream.dispatchEvent([]interface{}{&TradeEvent{TradeRecord: TradeRecord{
+		ID: "t3", AssetID: "999999999999", Status: TradeStatusMatched, MatchTime: "1672290703", Owner: "key",
+	}}})
+	assert.Len(t, got, 2)
+}
+
+func TestStream_BalanceUpdateAfterMatch(t *testing.T) {
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		return "PM_BTC_15M_UP_YES_USDC", true
+	})
+	stream.queryBalances = func(ctx context.Context) (types.BalanceMap, error) {
+		return types.BalanceMap{"USDC": {Currency: "USDC", Available: fixedpoint.NewFromFloat(7)}}, nil
+	}
+
+	balancesC := make(chan types.BalanceMap, 1)
+	stream.OnBalanceUpdate(func(balances types.BalanceMap) {
+		balancesC <- balances
+	})
+
+	// 下单事件不查询余额
+	stream.handleOrderEvent(OrderEvent{AssetID: "1", Type: OrderEventPlacement})
+	select {
+	case <-balancesC:
+		t.Fatal("unexpected balance update on placement")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	stream.handleOrderEvent(OrderEvent{AssetID: "1", Type: OrderEventUpdate})
+	select {
+	case balances := <-balancesC:
+		assert.Equal(t, "7", balances["USDC"].Available.String())
+	case <-time.After(time.Second):
+		t.Fatal("balance update not emitted after match")
+	}
+}
+
+func TestStream_Close(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// 订阅请求
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`[{"event_type": "order", "id": "0x1", "asset_id": "1", "side": "BUY", "price": "0.5", "original_size": "10", "type": "PLACEMENT", "timestamp": "1672290687"}]`))
+
+		// 读到 close 帧后默认的 close handler 会回复 close 帧
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(envWsUserURL, "ws"+strings.TrimPrefix(server.URL, "http"))
+	baseline := runtime.NumGoroutine()
+
+	stream := NewStream("key", "secret", "pass", false, func(assetID string) (string, bool) {
+		return "PM_YES", true
+	})
+
+	var mu sync.Mutex
+	var events []string
+	received := make(chan struct{})
+	stream.OnOrderUpdate(func(o types.Order) {
+		mu.Lock()
+		events = append(events, "order")
+		mu.Unlock()
+		close(received)
+	})
+	stream.OnDisconnect(func() {
+		mu.Lock()
+		events = append(events, "disconnect")
+		mu.Unlock()
+	})
+
+	assert.NoError(t, stream.Connect(context.Background()))
+
+	select {
+	case <-received:
+	case <-time.After(3 * time.Second):
+		t.Fatal("order event not received")
+	}
+	assert.Equal(t, StreamStateConnected, stream.State())
+
+	assert.NoError(t, stream.Close())
+	// 重复关闭不会 panic，也不会再次 EmitDisconnect
+	assert.NoError(t, stream.Close())
+	assert.ErrorIs(t, stream.Connect(context.Background()), errStreamClosed)
+
+	assert.Equal(t, StreamStateClosed, stream.State())
+
+	mu.Lock()
+	assert.Equal(t, []string{"order", "disconnect"}, events)
+	mu.Unlock()
+
+	// reader / ping / reconnector 都已退出（assert.Eventually 自己会起 goroutine，这里手动轮询）
+	deadline := time.Now().Add(3 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+}
+
+func TestParseWebSocketEvent_Pong(t *testing.T) {
+	event, err := parseWebSocketEvent([]byte("PONG"))
+	assert.NoError(t, err)
+	assert.IsType(t, &types.WebsocketPongEvent{}, event)
+}
+
+// newHeartbeatServer 启动一个 user channel 的测试服务端，收到 PING 时按 reply 决定是否回复 PONG，
+// 每收到一个 PING 往 pings 发送一次信号。
+func newHeartbeatServer(t *testing.T, reply bool, pings chan<- struct{}) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if string(message) != "PING" {
+				continue
+			}
+
+			select {
+			case pings <- struct{}{}:
+			default:
+			}
+
+			if reply {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte("PONG"))
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(envWsUserURL, "ws"+strings.TrimPrefix(server.URL, "http"))
+	t.Setenv(envWsPingInterval, "20ms")
+	t.Setenv(envWsPongTimeout, "100ms")
+	return server
+}
+
+func TestStream_Heartbeat(t *testing.T) {
+	pings := make(chan struct{}, 1)
+	newHeartbeatServer(t, true, pings)
+
+	stream := NewStream("key", "secret", "pass", false, func(assetID string) (string, bool) {
+		return "", false
+	})
+
+	disconnected := make(chan struct{}, 1)
+	stream.OnDisconnect(func() {
+		select {
+		case disconnected <- struct{}{}:
+		default:
+		}
+	})
+
+	assert.NoError(t, stream.Connect(context.Background()))
+	defer stream.Close()
+
+	select {
+	case <-pings:
+	case <-time.After(3 * time.Second):
+		t.Fatal("ping not received")
+	}
+
+	// 服务端持续回复 PONG，超过 pong timeout 之后连接仍然保持
+	select {
+	case <-disconnected:
+		t.Fatal("stream disconnected while pongs are received")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestStream_HeartbeatPongTimeout(t *testing.T) {
+	pings := make(chan struct{}, 1)
+	newHeartbeatServer(t, false, pings)
+
+	stream := NewStream("key", "secret", "pass", false, func(assetID string) (string, bool) {
+		return "", false
+	})
+
+	disconnected := make(chan struct{}, 1)
+	stream.OnDisconnect(func() {
+		select {
+		case disconnected <- struct{}{}:
+		default:
+		}
+	})
+
+	assert.NoError(t, stream.Connect(context.Background()))
+	defer stream.Close()
+
+	// 没有 PONG 时 pong timeout 后关闭连接，reader 退出并触发重连
+	select {
+	case <-disconnected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("stream not disconnected after pong timeout")
+	}
+	assert.Equal(t, StreamStateDisconnected, stream.State())
+}
+
+func TestStream_StateWithoutWebsocket(t *testing.T) {
+	symbolOf := func(assetID string) (string, bool) { return "", false }
+
+	userStream := NewStream("", "", "", true, symbolOf)
+	marketStream := NewStream("", "", "", false, symbolOf)
+	marketStream.SetPublicOnly()
+
+	for _, stream := range []*Stream{userStream, marketStream} {
+		assert.Equal(t, StreamStateDisconnected, stream.State())
+
+		// connectivity 仍然会收到 connect，避免策略一直等待
+		connected := false
+		stream.OnConnect(func() { connected = true })
+		assert.NoError(t, stream.Connect(context.Background()))
+		assert.True(t, connected)
+	}
+
+	assert.Equal(t, StreamStateDryRun, userStream.State())
+	assert.Equal(t, StreamStateMarketDataDisabled, marketStream.State())
+
+	assert.NoError(t, marketStream.Close())
+	assert.Equal(t, StreamStateClosed, marketStream.State())
+}
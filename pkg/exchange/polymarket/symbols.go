@@ -0,0 +1,165 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// bbgo 用 symbol 标识 market，Polymarket 的网页 / API 则使用 slug、condition id 或 token id。
+// ResolveSymbol 把这些引用统一解析成 symbol（不区分大小写），支持：
+// - bbgo symbol 本身，例如 PM_BTC_15M_UP_YES_USDC
+// - CLOB token id（market 的 LocalSymbol）
+// - slug 或 condition id：只有一个 outcome 对应 symbol 时直接解析，否则需要带上 outcome
+// - <slug|condition id>:<outcome>，例如 btc-updown-15m-1760515200:Up
+// markets 文件 / JSON 里的 market 可以额外填写 slug、conditionId、outcome 字段；
+// 通过 Gamma 发现的 up/down market 自动加入（Up/Yes 对应 YES symbol，Down/No 对应 NO symbol）。
+// 索引随 market 列表一起重建。
+
+// marketRef 为 market 在 Polymarket 上的引用。
+type marketRef struct {
+	Symbol      string `json:"symbol"`
+	Slug        string `json:"slug"`
+	ConditionID string `json:"conditionId"`
+	Outcome     string `json:"outcome"`
+}
+
+// decodeMarketRefs 从 markets JSON（与 decodeMarketsJSON 相同的两种格式）中读取引用字段，以 symbol 为 key。
+func decodeMarketRefs(b []byte) map[string]marketRef {
+	refs := make(map[string]marketRef)
+
+	var mm map[string]marketRef
+	if err := json.Unmarshal(b, &mm); err == nil && len(mm) > 0 {
+		for symbol, ref := range mm {
+			ref.Symbol = symbol
+			refs[symbol] = ref
+		}
+		return refs
+	}
+
+	var arr []marketRef
+	if err := json.Unmarshal(b, &arr); err != nil {
+		return refs
+	}
+	for _, ref := range arr {
+		if ref.Symbol != "" {
+			refs[ref.Symbol] = ref
+		}
+	}
+	return refs
+}
+
+// loadMarketRefsFromEnv 读取 loadMarketsFromEnv 所用 markets 文件 / JSON 中的引用字段。
+func loadMarketRefsFromEnv() map[string]marketRef {
+	if path := strings.TrimSpace(os.Getenv(envMarketsFile)); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		return decodeMarketRefs(b)
+	}
+
+	if dir := strings.TrimSpace(os.Getenv(envMarketsDir)); dir != "" {
+		_, refs, err := loadMarketsDir(dir)
+		if err != nil {
+			return nil
+		}
+		return refs
+	}
+
+	if raw := strings.TrimSpace(os.Getenv(envMarketsJSON)); raw != "" {
+		return decodeMarketRefs([]byte(raw))
+	}
+	return nil
+}
+
+func upDownMarketRefs(m *UpDownMarket) []marketRef {
+	var refs []marketRef
+	for _, outcome := range []string{"Up", "Yes"} {
+		refs = append(refs, marketRef{Symbol: m.YesSymbol, Slug: m.Slug, ConditionID: m.ConditionID, Outcome: outcome})
+	}
+	for _, outcome := range []string{"Down", "No"} {
+		refs = append(refs, marketRef{Symbol: m.NoSymbol, Slug: m.Slug, ConditionID: m.ConditionID, Outcome: outcome})
+	}
+	return refs
+}
+
+func symbolIndexKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// buildSymbolIndex 构建 引用 → symbols 的索引，只收录 markets 中存在的 symbol。
+func buildSymbolIndex(markets types.MarketMap, refs []marketRef) map[string][]string {
+	index := make(map[string][]string)
+	add := func(key, symbol string) {
+		if key = symbolIndexKey(key); key == "" {
+			return
+		}
+		for _, s := range index[key] {
+			if s == symbol {
+				return
+			}
+		}
+		index[key] = append(index[key], symbol)
+	}
+
+	for symbol, m := range markets {
+		add(symbol, symbol)
+		add(m.LocalSymbol, symbol)
+	}
+
+	for _, ref := range refs {
+		if _, ok := markets[ref.Symbol]; !ok {
+			continue
+		}
+
+		for _, id := range []string{ref.Slug, ref.ConditionID} {
+			if id == "" {
+				continue
+			}
+			add(id, ref.Symbol)
+			if ref.Outcome != "" {
+				add(id+":"+ref.Outcome, ref.Symbol)
+			}
+		}
+	}
+
+	for _, symbols := range index {
+		sort.Strings(symbols)
+	}
+	return index
+}
+
+// rebuildSymbolIndexLocked 用当前的 market、markets 文件中的引用与 up/down market 重建索引，需要持有 e.mu。
+func (e *Exchange) rebuildSymbolIndexLocked() {
+	refs := make([]marketRef, 0, len(e.marketRefs))
+	for _, ref := range e.marketRefs {
+		refs = append(refs, ref)
+	}
+	for _, um := range e.upDownMarkets {
+		refs = append(refs, upDownMarketRefs(um)...)
+	}
+
+	e.symbolIndex = buildSymbolIndex(e.markets, refs)
+}
+
+// ResolveSymbol 把 symbol、token id、slug、condition id 或 <slug>:<outcome> 解析成 bbgo symbol。
+// 只在已经加载的 market 中查找，需要先调用 QueryMarkets / DiscoverUpDownMarket。
+func (e *Exchange) ResolveSymbol(ref string) (string, error) {
+	e.mu.RLock()
+	symbols := e.symbolIndex[symbolIndexKey(ref)]
+	e.mu.RUnlock()
+
+	switch len(symbols) {
+	case 0:
+		return "", fmt.Errorf("polymarket: market %s not found", ref)
+	case 1:
+		return symbols[0], nil
+	}
+	return "", fmt.Errorf("polymarket: market %s is ambiguous (%s), use <slug>:<outcome> to pick one",
+		ref, strings.Join(symbols, ", "))
+}
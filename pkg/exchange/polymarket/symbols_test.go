@@ -0,0 +1,98 @@
+package polymarket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestDecodeMarketRefs(t *testing.T) {
+	refs := decodeMarketRefs([]byte(`[{"symbol": "PM_A", "slug": "will-it-rain", "conditionId": "0xabc", "outcome": "Yes"}, {"symbol": "PM_B"}]`))
+	assert.Equal(t, marketRef{Symbol: "PM_A", Slug: "will-it-rain", ConditionID: "0xabc", Outcome: "Yes"}, refs["PM_A"])
+	assert.Equal(t, marketRef{Symbol: "PM_B"}, refs["PM_B"])
+
+	// MarketMap 格式以 key 为 symbol
+	refs = decodeMarketRefs([]byte(`{"PM_A": {"slug": "will-it-rain"}}`))
+	assert.Equal(t, "will-it-rain", refs["PM_A"].Slug)
+	assert.Equal(t, "PM_A", refs["PM_A"].Symbol)
+}
+
+func TestExchange_ResolveSymbol(t *testing.T) {
+	t.Setenv(envMarketsJSON, `[
+		{"symbol": "PM_RAIN_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01",
+		 "slug": "will-it-rain", "conditionId": "0xABC", "outcome": "Yes"},
+		{"symbol": "PM_RAIN_NO", "localSymbol": "222222222222", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01",
+		 "slug": "will-it-rain", "conditionId": "0xABC", "outcome": "No"},
+		{"symbol": "PM_SNOW_YES", "localSymbol": "333333333333", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01",
+		 "slug": "will-it-snow"}
+	]`)
+
+	ex := New("", "", "")
+	_, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	for ref, expected := range map[string]string{
+		"PM_RAIN_YES":       "PM_RAIN_YES",
+		"pm_rain_no":        "PM_RAIN_NO",
+		"222222222222":      "PM_RAIN_NO",
+		"will-it-rain:yes":  "PM_RAIN_YES",
+		"0xabc:No":          "PM_RAIN_NO",
+		" will-it-snow ":    "PM_SNOW_YES",
+		"WILL-IT-SNOW":      "PM_SNOW_YES",
+		"333333333333":      "PM_SNOW_YES",
+		"will-it-rain:Yes ": "PM_RAIN_YES",
+	} {
+		symbol, err := ex.ResolveSymbol(ref)
+		if assert.NoError(t, err, ref) {
+			assert.Equal(t, expected, symbol, ref)
+		}
+	}
+
+	_, err = ex.ResolveSymbol("will-it-rain")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "ambiguous")
+		assert.Contains(t, err.Error(), "PM_RAIN_NO, PM_RAIN_YES")
+	}
+
+	_, err = ex.ResolveSymbol("unknown-slug")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestExchange_ResolveUpDownSymbol(t *testing.T) {
+	ex := New("", "", "")
+	ex.mu.Lock()
+	um, err := newUpDownMarket("btc-updown-15m-1760515200", &GammaMarket{
+		ConditionID:  "0xdef",
+		Outcomes:     `["Up", "Down"]`,
+		ClobTokenIDs: `["111111111111", "222222222222"]`,
+	}, time.Unix(1760515200, 0), time.Unix(1760516100, 0))
+	if assert.NoError(t, err) {
+		ex.addMarketLocked(um.YesMarket)
+		ex.addMarketLocked(um.NoMarket)
+		ex.upDownMarkets[um.Slug] = um
+		ex.rebuildSymbolIndexLocked()
+	}
+	ex.mu.Unlock()
+
+	for ref, expected := range map[string]string{
+		"btc-updown-15m-1760515200:up":   um.YesSymbol,
+		"btc-updown-15m-1760515200:yes":  um.YesSymbol,
+		"btc-updown-15m-1760515200:Down": um.NoSymbol,
+		"0xdef:no":                       um.NoSymbol,
+		"222222222222":                   um.NoSymbol,
+	} {
+		symbol, err := ex.ResolveSymbol(ref)
+		if assert.NoError(t, err, ref) {
+			assert.Equal(t, expected, symbol, ref)
+		}
+	}
+
+	// 替换 market 列表后，不再存在的 symbol 从索引中移除
+	ex.replaceMarkets(types.MarketMap{}, nil, map[string]*UpDownMarket{})
+	_, err = ex.ResolveSymbol("btc-updown-15m-1760515200:up")
+	assert.Error(t, err)
+}
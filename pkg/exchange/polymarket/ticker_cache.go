@@ -0,0 +1,162 @@
+package polymarket
+
+import (
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 行情 websocket 驱动的 ticker 缓存：
+// - POLYMARKET_WS_MARKET=true 时 public-only stream 会连接 CLOB market channel（见 stream.go）
+// - book / price_change 消息更新盘口深度、最优买卖价与中间价，last_trade_price 消息更新最新成交价
+// - QueryTicker 优先读取缓存，超过 POLYMARKET_TICKER_MAX_AGE（默认 5s）没有更新时回退到 REST /book
+// - BestBidAsk 只读取缓存的盘口，不会请求 REST
+// - 每个 token 的盘口有各自的锁，map 本身只在第一次出现新 token 时写锁：不同 market 的行情更新与读取互不阻塞
+
+const (
+	envWsMarket     = "POLYMARKET_WS_MARKET"
+	envTickerMaxAge = "POLYMARKET_TICKER_MAX_AGE"
+
+	defaultTickerMaxAge = 5 * time.Second
+)
+
+type cachedTicker struct {
+	// mu 保护下面所有字段
+	mu sync.Mutex
+
+	buy, sell fixedpoint.Value
+	lastTrade fixedpoint.Value
+	updatedAt time.Time
+	// bookUpdatedAt 为最近一次 book 消息的时间，成交价消息不会刷新盘口
+	bookUpdatedAt time.Time
+	// bids / asks 为盘口深度，dry-run 的 IOC/FOK 订单按深度撮合（见 ioc.go）
+	bids, asks []PriceLevel
+}
+
+// tickerCache 以 CLOB token id 为 key，mu 只保护 tickers map，每个 token 的盘口由 cachedTicker.mu 保护
+type tickerCache struct {
+	mu      sync.RWMutex
+	maxAge  time.Duration
+	tickers map[string]*cachedTicker
+}
+
+func newTickerCacheFromEnv() *tickerCache {
+	return &tickerCache{
+		maxAge:  envDuration(envTickerMaxAge, defaultTickerMaxAge),
+		tickers: make(map[string]*cachedTicker),
+	}
+}
+
+// lookup 返回 token 的缓存，没有时 ok 为 false。
+func (c *tickerCache) lookup(assetID string) (*cachedTicker, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.tickers[assetID]
+	return t, ok
+}
+
+// entry 返回 token 的缓存，没有时创建。
+func (c *tickerCache) entry(assetID string) *cachedTicker {
+	if t, ok := c.lookup(assetID); ok {
+		return t
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.tickers[assetID]
+	if !ok {
+		t = &cachedTicker{}
+		c.tickers[assetID] = t
+	}
+	return t
+}
+
+func (c *tickerCache) updateBook(e BookEvent, at time.Time) {
+	book := OrderBookSummary{Bids: e.Bids, Asks: e.Asks}
+	bid, _ := book.BestBid()
+	ask, _ := book.BestAsk()
+
+	t := c.entry(e.AssetID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buy, t.sell = bid.Price, ask.Price
+	t.bids = append([]PriceLevel(nil), e.Bids...)
+	t.asks = append([]PriceLevel(nil), e.Asks...)
+	t.updatedAt, t.bookUpdatedAt = at, at
+}
+
+// updateBestBidAsk 用 price_change 消息中的最优买卖价更新盘口。
+func (c *tickerCache) updateBestBidAsk(assetID string, bid, ask fixedpoint.Value, at time.Time) {
+	t := c.entry(assetID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buy, t.sell = bid, ask
+	t.updatedAt, t.bookUpdatedAt = at, at
+}
+
+func (c *tickerCache) updateLastTrade(e LastTradePriceEvent, at time.Time) {
+	t := c.entry(e.AssetID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastTrade = e.Price
+	t.updatedAt = at
+}
+
+// get 返回未过期的 ticker。Last 优先使用最新成交价，没有成交时用中间价。
+func (c *tickerCache) get(assetID string, now time.Time) (*types.Ticker, bool) {
+	t, ok := c.lookup(assetID)
+	if !ok {
+		return nil, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.updatedAt) > c.maxAge {
+		return nil, false
+	}
+
+	ticker := &types.Ticker{
+		Time: t.updatedAt,
+		Buy:  t.buy,
+		Sell: t.sell,
+		Last: t.lastTrade,
+	}
+	if ticker.Last.IsZero() && !t.buy.IsZero() && !t.sell.IsZero() {
+		ticker.Last = t.buy.Add(t.sell).Div(fixedpoint.Two)
+	}
+	return ticker, true
+}
+
+// bestBidAsk 返回未过期的最优买卖价，任意一边没有挂单时 ok 为 false。
+func (c *tickerCache) bestBidAsk(assetID string, now time.Time) (bid, ask fixedpoint.Value, ok bool) {
+	t, found := c.lookup(assetID)
+	if !found {
+		return fixedpoint.Zero, fixedpoint.Zero, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.bookUpdatedAt) > c.maxAge || t.buy.Sign() <= 0 || t.sell.Sign() <= 0 {
+		return fixedpoint.Zero, fixedpoint.Zero, false
+	}
+	return t.buy, t.sell, true
+}
+
+// BestBidAsk 返回 symbol 在 market channel 盘口缓存中的最优买卖价（需要 POLYMARKET_WS_MARKET=true 并连接 public-only stream）。
+// 缓存没有该 symbol、盘口超过 POLYMARKET_TICKER_MAX_AGE 没有更新或任意一边没有挂单时 ok 为 false。
+func (e *Exchange) BestBidAsk(symbol string) (bid, ask fixedpoint.Value, ok bool) {
+	token, found := e.tokenOf(symbol)
+	if !found {
+		return fixedpoint.Zero, fixedpoint.Zero, false
+	}
+	return e.tickers.bestBidAsk(token.TokenID, time.Now())
+}
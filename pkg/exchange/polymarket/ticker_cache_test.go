@@ -0,0 +1,232 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestTickerCache(t *testing.T) {
+	cache := &tickerCache{maxAge: time.Second, tickers: make(map[string]*cachedTicker)}
+	now := time.Now()
+
+	_, ok := cache.get("1", now)
+	assert.False(t, ok)
+
+	cache.updateBook(BookEvent{
+		AssetID: "1",
+		Bids:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.4)}, {Price: fixedpoint.NewFromFloat(0.45)}},
+		Asks:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.55)}},
+	}, now)
+
+	ticker, ok := cache.get("1", now)
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.45", ticker.Buy.String())
+		assert.Equal(t, "0.55", ticker.Sell.String())
+		assert.Equal(t, "0.5", ticker.Last.String())
+	}
+
+	cache.updateLastTrade(LastTradePriceEvent{AssetID: "1", Price: fixedpoint.NewFromFloat(0.52)}, now)
+	ticker, ok = cache.get("1", now)
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.52", ticker.Last.String())
+	}
+
+	_, ok = cache.get("1", now.Add(2*time.Second))
+	assert.False(t, ok)
+}
+
+func TestTickerCache_BestBidAsk(t *testing.T) {
+	cache := &tickerCache{maxAge: time.Second, tickers: make(map[string]*cachedTicker)}
+	now := time.Now()
+
+	_, _, ok := cache.bestBidAsk("1", now)
+	assert.False(t, ok)
+
+	cache.updateBook(BookEvent{
+		AssetID: "1",
+		Bids:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.45)}},
+		Asks:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.55)}},
+	}, now)
+
+	bid, ask, ok := cache.bestBidAsk("1", now)
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.45", bid.String())
+		assert.Equal(t, "0.55", ask.String())
+	}
+
+	// 成交价消息不会刷新盘口的时间
+	cache.updateLastTrade(LastTradePriceEvent{AssetID: "1", Price: fixedpoint.NewFromFloat(0.5)}, now.Add(2*time.Second))
+	_, _, ok = cache.bestBidAsk("1", now.Add(2*time.Second))
+	assert.False(t, ok)
+
+	// 单边盘口
+	cache.updateBook(BookEvent{
+		AssetID: "2",
+		Bids:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.45)}},
+	}, now)
+	_, _, ok = cache.bestBidAsk("2", now)
+	assert.False(t, ok)
+}
+
+func TestTickerCache_PerAssetLock(t *testing.T) {
+	cache := &tickerCache{maxAge: time.Minute, tickers: make(map[string]*cachedTicker)}
+	now := time.Now()
+
+	book := func(assetID string, bid, ask float64) BookEvent {
+		return BookEvent{
+			AssetID: assetID,
+			Bids:    []PriceLevel{{Price: fixedpoint.NewFromFloat(bid), Size: fixedpoint.One}},
+			Asks:    []PriceLevel{{Price: fixedpoint.NewFromFloat(ask), Size: fixedpoint.One}},
+		}
+	}
+	cache.updateBook(book("1", 0.4, 0.6), now)
+
+	// 一个 token 的盘口正在更新时，其它 token 的读写不需要等待
+	locked := cache.entry("1")
+	locked.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.updateBook(book("2", 0.3, 0.7), now)
+		_, _, _ = cache.bestBidAsk("2", now)
+		_, _ = cache.get("2", now)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("updating asset 2 is blocked by asset 1")
+	}
+	locked.mu.Unlock()
+	<-done
+
+	// 并发更新与读取不同 token（配合 go test -race 检查）
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		assetID := strconv.Itoa(i + 1)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				cache.updateBook(book(assetID, 0.4, 0.6), now)
+				cache.updateBestBidAsk(assetID, fixedpoint.NewFromFloat(0.45), fixedpoint.NewFromFloat(0.55), now)
+				cache.updateLastTrade(LastTradePriceEvent{AssetID: assetID, Price: fixedpoint.NewFromFloat(0.5)}, now)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				_, _ = cache.get(assetID, now)
+				_, _, _ = cache.bestBidAsk(assetID, now)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 1; i <= 4; i++ {
+		ticker, ok := cache.get(strconv.Itoa(i), now)
+		if assert.True(t, ok) {
+			assert.Equal(t, "0.45", ticker.Buy.String())
+			assert.Equal(t, "0.55", ticker.Sell.String())
+			assert.Equal(t, "0.5", ticker.Last.String())
+		}
+	}
+}
+
+func TestExchange_QueryTickerFromMarketChannel(t *testing.T) {
+	const tokenID = "111111111111"
+
+	t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "`+tokenID+`", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	t.Setenv(envWsMarket, "true")
+	t.Setenv(envTickerMaxAge, "200ms")
+
+	rest := newMockServer(t, map[string]func(w http.ResponseWriter, r *http.Request, body []byte){
+		"GET /book": func(w http.ResponseWriter, r *http.Request, body []byte) {
+			writeJSON(w, map[string]interface{}{
+				"asset_id": r.URL.Query().Get("token_id"),
+				"bids":     []map[string]string{{"price": "0.48", "size": "100"}},
+				"asks":     []map[string]string{{"price": "0.52", "size": "100"}},
+			})
+		},
+	})
+
+	subscribed := make(chan WsSubscribeRequest, 1)
+	upgrader := websocket.Upgrader{}
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req WsSubscribeRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		subscribed <- req
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`[{"event_type": "book", "asset_id": "`+tokenID+`", "bids": [{"price": "0.58", "size": "10"}], "asks": [{"price": "0.62", "size": "10"}], "timestamp": "1672290687"}]`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event_type": "last_trade_price", "asset_id": "`+tokenID+`", "price": "0.6", "side": "BUY", "size": "5", "timestamp": "1672290688"}`))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer ws.Close()
+	t.Setenv(envWsMarketURL, "ws"+strings.TrimPrefix(ws.URL, "http"))
+
+	ctx := context.Background()
+	ex := New("", "", "")
+	_, err := ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+
+	stream := ex.NewStream().(*Stream)
+	stream.SetPublicOnly()
+	assert.NoError(t, stream.Connect(ctx))
+	defer stream.Close()
+
+	select {
+	case req := <-subscribed:
+		assert.Equal(t, "market", req.Type)
+		assert.Equal(t, []string{tokenID}, req.AssetIDs)
+		b, _ := json.Marshal(req)
+		assert.NotContains(t, string(b), "auth")
+	case <-time.After(3 * time.Second):
+		t.Fatal("market channel not subscribed")
+	}
+
+	// 缓存新鲜时不请求 REST
+	assert.Eventually(t, func() bool {
+		ticker, err := ex.QueryTicker(ctx, "PM_YES")
+		return err == nil && ticker.Last.String() == "0.6"
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, rest.count(http.MethodGet, "/book"))
+
+	bid, ask, ok := ex.BestBidAsk("PM_YES")
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.58", bid.String())
+		assert.Equal(t, "0.62", ask.String())
+	}
+	_, _, ok = ex.BestBidAsk("PM_UNKNOWN")
+	assert.False(t, ok)
+
+	// 缓存过期后回退到 REST
+	time.Sleep(250 * time.Millisecond)
+	ticker, err := ex.QueryTicker(ctx, "PM_YES")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.52", ticker.Sell.String())
+	assert.Equal(t, 1, rest.count(http.MethodGet, "/book"))
+}
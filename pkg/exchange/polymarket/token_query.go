@@ -0,0 +1,133 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 按 token id 查询行情：QueryTickerByTokenID / QueryKLinesByTokenID 直接使用 CLOB token id，不需要在 markets 中配置，
+// 便于在写进配置之前先研究一个 market。symbol 版本（QueryTicker / QueryKLines）仍然是主要 API，内部查到 token id 后调用同样的实现。
+// - ticker：优先使用 market channel 的缓存，否则请求 REST /book
+// - K 线：请求 CLOB GET /prices-history（概率价格的历史采样，没有成交量），按 interval 聚合成 K 线（与 stream_kline.go 相同的规则，
+//   没有采样的区间补平盘 K 线），只返回已经结束的 K 线；按 token id 查询时 KLine.Symbol 为 token id
+// - 历史采样的精度为分钟，interval 小于 1m 时报错；没有指定 StartTime 时取 EndTime（默认现在）之前 Limit（默认 500）根 K 线的范围
+
+const defaultKLineQueryLimit = 500
+
+// PricePoint 是 CLOB GET /prices-history 的一个采样点。
+type PricePoint struct {
+	// T 为 unix 秒
+	T int64            `json:"t"`
+	P fixedpoint.Value `json:"p"`
+}
+
+type priceHistoryResponse struct {
+	History []PricePoint `json:"history"`
+}
+
+// queryPriceHistory 查询 token 在 [start, end] 之间的价格采样，fidelity 为采样间隔（分钟）。
+func (c *restClient) queryPriceHistory(ctx context.Context, tokenID string, start, end time.Time, fidelity int) ([]PricePoint, error) {
+	query := url.Values{
+		"market":   {tokenID},
+		"startTs":  {strconv.FormatInt(start.Unix(), 10)},
+		"endTs":    {strconv.FormatInt(end.Unix(), 10)},
+		"fidelity": {strconv.Itoa(fidelity)},
+	}
+
+	var resp priceHistoryResponse
+	if err := c.do(ctx, c.limits.market, http.MethodGet, "/prices-history", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.History, nil
+}
+
+// QueryTickerByTokenID 按 CLOB token id 查询 ticker，不需要配置 market。
+func (e *Exchange) QueryTickerByTokenID(ctx context.Context, tokenID string) (*types.Ticker, error) {
+	if !isTokenID(tokenID) {
+		return nil, fmt.Errorf("polymarket: invalid CLOB token id %q", tokenID)
+	}
+	return e.queryTokenTicker(ctx, tokenID)
+}
+
+// queryTokenTicker 返回 token 的 ticker：market channel 推送的 ticker 还新鲜时直接使用，省掉一次 REST 请求。
+func (e *Exchange) queryTokenTicker(ctx context.Context, tokenID string) (*types.Ticker, error) {
+	if ticker, ok := e.tickers.get(tokenID, time.Now()); ok {
+		return ticker, nil
+	}
+
+	book, err := e.client.queryOrderBook(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return toGlobalTicker(book), nil
+}
+
+// QueryKLinesByTokenID 按 CLOB token id 查询历史 K 线，不需要配置 market，KLine.Symbol 为 token id。
+func (e *Exchange) QueryKLinesByTokenID(ctx context.Context, tokenID string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	if !isTokenID(tokenID) {
+		return nil, fmt.Errorf("polymarket: invalid CLOB token id %q", tokenID)
+	}
+	return e.queryTokenKLines(ctx, tokenID, tokenID, interval, options)
+}
+
+// queryTokenKLines 用 /prices-history 的采样聚合出 symbol 的 K 线。
+func (e *Exchange) queryTokenKLines(ctx context.Context, tokenID, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	d := interval.Duration()
+	if d < time.Minute {
+		return nil, fmt.Errorf("polymarket: unsupported kline interval %s, price history has minute resolution", interval)
+	}
+
+	limit := options.Limit
+	if limit <= 0 {
+		limit = defaultKLineQueryLimit
+	}
+
+	end := time.Now()
+	if options.EndTime != nil {
+		end = *options.EndTime
+	}
+	start := end.Add(-time.Duration(limit) * d)
+	if options.StartTime != nil {
+		start = *options.StartTime
+	}
+	start = start.Truncate(d)
+
+	points, err := e.client.queryPriceHistory(ctx, tokenID, start, end, int(d/time.Minute))
+	if err != nil {
+		return nil, err
+	}
+
+	klines := aggregatePriceHistory(symbol, interval, points, start, end)
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return klines, nil
+}
+
+// aggregatePriceHistory 把 [start, end] 之间的采样按 interval 聚合成 K 线，只返回在 end 之前结束的 K 线。
+func aggregatePriceHistory(symbol string, interval types.Interval, points []PricePoint, start, end time.Time) (klines []types.KLine) {
+	sort.Slice(points, func(i, j int) bool { return points[i].T < points[j].T })
+
+	intervals := []types.Interval{interval}
+	a := newKLineAggregator()
+	for _, p := range points {
+		at := time.Unix(p.T, 0)
+		if at.Before(start) || at.After(end) {
+			continue
+		}
+
+		closed, _ := a.add(symbol, intervals, p.P, fixedpoint.Zero, false, at)
+		klines = append(klines, closed...)
+	}
+
+	klines = append(klines, a.closeUntil(end, func(string, types.Interval) bool { return true })...)
+	return klines
+}
@@ -0,0 +1,84 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_QueryByTokenID(t *testing.T) {
+	const tokenID = "111111111111"
+	t0 := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/book", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, tokenID, req.URL.Query().Get("token_id"))
+		return httptesting.BuildResponseString(http.StatusOK,
+			`{"asset_id": "`+tokenID+`", "bids": [{"price": "0.40", "size": "10"}], "asks": [{"price": "0.44", "size": "8"}]}`), nil
+	})
+	transport.GET("/prices-history", func(req *http.Request) (*http.Response, error) {
+		q := req.URL.Query()
+		assert.Equal(t, tokenID, q.Get("market"))
+		assert.Equal(t, "1", q.Get("fidelity"))
+		assert.Equal(t, "1760515200", q.Get("startTs"))
+		// 08:00 ~ 08:01 两个采样，08:01 ~ 08:02 没有采样，08:02 一个采样，08:03 的 K 线尚未结束
+		return httptesting.BuildResponseString(http.StatusOK, `{"history": [
+			{"t": 1760515230, "p": 0.55}, {"t": 1760515200, "p": 0.5}, {"t": 1760515330, "p": 0.6}, {"t": 1760515390, "p": 0.62}
+		]}`), nil
+	})
+
+	// 没有配置任何 market
+	ex := New("", "", "")
+	defer ex.Close()
+	ex.client = newTestRestClient(transport)
+	ex.markets = types.MarketMap{}
+	ctx := context.Background()
+
+	ticker, err := ex.QueryTickerByTokenID(ctx, tokenID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.42", ticker.Last.String())
+	}
+
+	end := t0.Add(3*time.Minute + 30*time.Second)
+	klines, err := ex.QueryKLinesByTokenID(ctx, tokenID, types.Interval1m, types.KLineQueryOptions{StartTime: &t0, EndTime: &end})
+	if assert.NoError(t, err) && assert.Len(t, klines, 3) {
+		assert.Equal(t, tokenID, klines[0].Symbol)
+		assert.True(t, t0.Equal(klines[0].StartTime.Time()))
+		assert.Equal(t, "0.5", klines[0].Open.String())
+		assert.Equal(t, "0.55", klines[0].Close.String())
+		assert.True(t, klines[0].Closed)
+
+		// 没有采样的区间补平盘 K 线
+		assert.Equal(t, "0.55", klines[1].Open.String())
+		assert.Equal(t, "0.55", klines[1].Close.String())
+		assert.Equal(t, "0.6", klines[2].Close.String())
+	}
+
+	// Limit 只保留最近的 K 线
+	klines, err = ex.QueryKLinesByTokenID(ctx, tokenID, types.Interval1m, types.KLineQueryOptions{StartTime: &t0, EndTime: &end, Limit: 1})
+	if assert.NoError(t, err) && assert.Len(t, klines, 1) {
+		assert.Equal(t, "0.6", klines[0].Close.String())
+	}
+
+	_, err = ex.QueryKLinesByTokenID(ctx, tokenID, types.Interval1s, types.KLineQueryOptions{})
+	assert.ErrorContains(t, err, "unsupported kline interval")
+
+	_, err = ex.QueryTickerByTokenID(ctx, "PM_YES")
+	assert.ErrorContains(t, err, "invalid CLOB token id")
+
+	// symbol 版本查到 token id 后使用同样的实现
+	ex.markets["PM_YES"] = types.Market{Symbol: "PM_YES", LocalSymbol: tokenID}
+	klines, err = ex.QueryKLines(ctx, "PM_YES", types.Interval1m, types.KLineQueryOptions{StartTime: &t0, EndTime: &end})
+	if assert.NoError(t, err) && assert.Len(t, klines, 3) {
+		assert.Equal(t, "PM_YES", klines[0].Symbol)
+	}
+
+	_, err = ex.QueryKLines(ctx, "PM_UNKNOWN", types.Interval1m, types.KLineQueryOptions{})
+	assert.Error(t, err)
+}
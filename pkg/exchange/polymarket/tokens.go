@@ -0,0 +1,58 @@
+package polymarket
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// websocket 推送的事件以 CLOB token id（market 的 LocalSymbol）为 key，
+// 这里维护 token id → symbol 的反向索引，随 market 列表一起更新，避免每个事件都遍历 market。
+
+// buildTokenIndex 用 markets 的 LocalSymbol 构建 token id → symbol 的索引。
+func buildTokenIndex(markets types.MarketMap) map[string]string {
+	index := make(map[string]string, len(markets))
+	for symbol, m := range markets {
+		if m.LocalSymbol != "" {
+			index[m.LocalSymbol] = symbol
+		}
+	}
+	return index
+}
+
+// setMarketsLocked 替换 market 列表并重建 token 索引与 symbol 引用索引，需要持有 e.mu。
+func (e *Exchange) setMarketsLocked(markets types.MarketMap) {
+	e.markets = markets
+	e.marketsUpdatedAt = time.Now()
+	e.tokenSymbols = buildTokenIndex(markets)
+	e.rebuildSymbolIndexLocked()
+}
+
+// addMarketLocked 新增或更新一个 market 并同步 token 索引与 symbol 引用索引，需要持有 e.mu。
+func (e *Exchange) addMarketLocked(m types.Market) {
+	if e.markets == nil {
+		e.markets = make(types.MarketMap)
+	}
+	if e.tokenSymbols == nil {
+		e.tokenSymbols = make(map[string]string)
+	}
+
+	if old, ok := e.markets[m.Symbol]; ok && old.LocalSymbol != m.LocalSymbol {
+		delete(e.tokenSymbols, old.LocalSymbol)
+	}
+	e.markets[m.Symbol] = m
+	e.marketsUpdatedAt = time.Now()
+	if m.LocalSymbol != "" {
+		e.tokenSymbols[m.LocalSymbol] = m.Symbol
+	}
+	e.rebuildSymbolIndexLocked()
+}
+
+// SymbolOfTokenID 返回 CLOB token id 对应的 bbgo symbol。
+func (e *Exchange) SymbolOfTokenID(tokenID string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	symbol, ok := e.tokenSymbols[tokenID]
+	return symbol, ok
+}
@@ -0,0 +1,91 @@
+package polymarket
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_SymbolOfTokenID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.json")
+	write := func(content string) {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write(`[{"symbol": "PM_A", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	t.Setenv(envMarketsFile, path)
+
+	ex := New("", "", "")
+	_, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	symbol, ok := ex.SymbolOfTokenID("111111111111")
+	assert.True(t, ok)
+	assert.Equal(t, "PM_A", symbol)
+
+	// 重新加载后索引随 market 列表重建
+	write(`[{"symbol": "PM_B", "localSymbol": "222222222222", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+	ex.reloadMarketsFile(path)
+
+	_, ok = ex.SymbolOfTokenID("111111111111")
+	assert.False(t, ok)
+	symbol, ok = ex.SymbolOfTokenID("222222222222")
+	assert.True(t, ok)
+	assert.Equal(t, "PM_B", symbol)
+
+	// 新增的 market 同步进索引
+	ex.mu.Lock()
+	ex.addMarketLocked(types.Market{Symbol: "PM_C", LocalSymbol: "333333333333"})
+	ex.mu.Unlock()
+
+	symbol, ok = ex.SymbolOfTokenID("333333333333")
+	assert.True(t, ok)
+	assert.Equal(t, "PM_C", symbol)
+}
+
+func TestStream_MarketChannelEvents(t *testing.T) {
+	stream := NewStream("", "", "", false, func(assetID string) (string, bool) {
+		if assetID == "111111111111" {
+			return "PM_YES", true
+		}
+		return "", false
+	})
+
+	var bookTickers []types.BookTicker
+	stream.OnBookTickerUpdate(func(b types.BookTicker) {
+		bookTickers = append(bookTickers, b)
+	})
+	var trades []types.Trade
+	stream.OnMarketTrade(func(trade types.Trade) {
+		trades = append(trades, trade)
+	})
+
+	stream.dispatchEvent([]interface{}{
+		&BookEvent{
+			AssetID: "111111111111",
+			Bids:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.45), Size: fixedpoint.NewFromFloat(5)}},
+			Asks:    []PriceLevel{{Price: fixedpoint.NewFromFloat(0.5), Size: fixedpoint.NewFromFloat(3)}},
+		},
+		&LastTradePriceEvent{AssetID: "111111111111", Price: fixedpoint.NewFromFloat(0.48), Size: fixedpoint.NewFromFloat(2), Side: "BUY"},
+		// 未知 token id 的事件被丢弃
+		&BookEvent{AssetID: "999999999999"},
+		&LastTradePriceEvent{AssetID: "999999999999", Price: fixedpoint.NewFromFloat(0.1)},
+	})
+
+	if assert.Len(t, bookTickers, 1) {
+		assert.Equal(t, "PM_YES", bookTickers[0].Symbol)
+		assert.Equal(t, "0.45", bookTickers[0].Buy.String())
+		assert.Equal(t, "0.5", bookTickers[0].Sell.String())
+	}
+	if assert.Len(t, trades, 1) {
+		assert.Equal(t, "PM_YES", trades[0].Symbol)
+		assert.Equal(t, types.SideTypeBuy, trades[0].Side)
+		assert.Equal(t, "0.48", trades[0].Price.String())
+	}
+}
@@ -0,0 +1,235 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 成交记录：
+// - 真实交易通过 CLOB GET /data/trades（L2 鉴权）查询，按 next_cursor 翻页
+// - dry-run 使用模拟撮合产生的成交（见 matcher.go），只保存在内存中
+
+// endCursor 表示已经没有下一页
+const endCursor = "LTE="
+
+// TradeRecord 是 CLOB GET /data/trades 返回的成交（只保留用到的字段）。
+// 自己是 taker 时成交信息在外层；是 maker 时在 MakerOrders 中 owner 为自己 API key 的那几笔。
+type TradeRecord struct {
+	ID           string           `json:"id"`
+	TakerOrderID string           `json:"taker_order_id"`
+	Market       string           `json:"market"`
+	AssetID      string           `json:"asset_id"`
+	Side         string           `json:"side"`
+	Size         fixedpoint.Value `json:"size"`
+	FeeRateBps   fixedpoint.Value `json:"fee_rate_bps"`
+	Price        fixedpoint.Value `json:"price"`
+	Status       string           `json:"status"`
+	MatchTime    string           `json:"match_time"`
+	Owner        string           `json:"owner"`
+	TraderSide   string           `json:"trader_side"`
+	MakerOrders  []MakerOrder     `json:"maker_orders"`
+}
+
+type MakerOrder struct {
+	OrderID       string           `json:"order_id"`
+	Owner         string           `json:"owner"`
+	MatchedAmount fixedpoint.Value `json:"matched_amount"`
+	Price         fixedpoint.Value `json:"price"`
+	FeeRateBps    fixedpoint.Value `json:"fee_rate_bps"`
+	AssetID       string           `json:"asset_id"`
+	Side          string           `json:"side"`
+}
+
+type tradesPage struct {
+	Data       []TradeRecord `json:"data"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+func (c *restClient) queryTrades(ctx context.Context, params url.Values) ([]TradeRecord, error) {
+	var records []TradeRecord
+	for cursor := ""; cursor != endCursor; {
+		query := url.Values{}
+		for k, v := range params {
+			query[k] = v
+		}
+		if cursor != "" {
+			query.Set("next_cursor", cursor)
+		}
+
+		var page tradesPage
+		if err := c.do(ctx, c.limits.market, http.MethodGet, "/data/trades", query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		records = append(records, page.Data...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	return records, nil
+}
+
+// tradeFee 按 Polymarket 的费率公式 feeRate * min(price, 1 - price) * size 估算手续费（USDC）。
+func tradeFee(price, size, feeRateBps fixedpoint.Value) fixedpoint.Value {
+	p := fixedpoint.Min(price, fixedpoint.One.Sub(price))
+	return feeRateBps.Div(bpsBase).Mul(p).Mul(size)
+}
+
+// toGlobalTrades 把 tokenID 上属于 apiKey 的成交转换成 types.Trade。
+func toGlobalTrades(r TradeRecord, apiKey, tokenID, symbol string) ([]types.Trade, error) {
+	sec, err := strconv.ParseInt(r.MatchTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("polymarket: invalid match_time %q of trade %s", r.MatchTime, r.ID)
+	}
+	tradeTime := types.Time(time.Unix(sec, 0))
+
+	newTrade := func(orderID, side string, price, size, feeRateBps fixedpoint.Value, isMaker bool) types.Trade {
+		globalSide := toGlobalSide(side)
+		return types.Trade{
+			ID:            hashStringID(r.ID + ":" + orderID),
+			OrderID:       hashStringID(orderID),
+			OrderUUID:     orderID,
+			Exchange:      types.ExchangePolymarket,
+			Price:         price,
+			Quantity:      size,
+			QuoteQuantity: price.Mul(size),
+			Symbol:        symbol,
+			Side:          globalSide,
+			IsBuyer:       globalSide == types.SideTypeBuy,
+			IsMaker:       isMaker,
+			Time:          tradeTime,
+			Fee:           tradeFee(price, size, feeRateBps),
+			FeeCurrency:   collateralCurrency,
+		}
+	}
+
+	if r.TraderSide != "MAKER" {
+		if r.AssetID != tokenID {
+			return nil, nil
+		}
+		return []types.Trade{newTrade(r.TakerOrderID, r.Side, r.Price, r.Size, r.FeeRateBps, false)}, nil
+	}
+
+	var trades []types.Trade
+	for _, mo := range r.MakerOrders {
+		if mo.Owner != apiKey || mo.AssetID != tokenID {
+			continue
+		}
+		trades = append(trades, newTrade(mo.OrderID, mo.Side, mo.Price, mo.MatchedAmount, mo.FeeRateBps, true))
+	}
+	return trades, nil
+}
+
+// QueryTrades 查询 symbol 的成交记录，按时间升序返回。
+// options 的 StartTime/EndTime/Limit 都会生效；CLOB 的成交 id 不是递增的，LastTradeID 只在 dry-run 下生效。
+func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) ([]types.Trade, error) {
+	if options == nil {
+		options = &types.TradeQueryOptions{}
+	}
+
+	if e.IsDryRun() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return e.queryDryRunTrades(symbol, options), nil
+	}
+
+	token, ok := e.tokenOf(symbol)
+	if !ok {
+		return nil, fmt.Errorf("polymarket: market %s has no CLOB token id (localSymbol)", symbol)
+	}
+	if e.client.auth == nil {
+		return nil, fmt.Errorf("polymarket: API key is required to query trades")
+	}
+
+	params := url.Values{"asset_id": {token.TokenID}}
+	if options.StartTime != nil {
+		params.Set("after", strconv.FormatInt(options.StartTime.Unix(), 10))
+	}
+	if options.EndTime != nil {
+		params.Set("before", strconv.FormatInt(options.EndTime.Unix(), 10))
+	}
+
+	records, err := e.client.queryTrades(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []types.Trade
+	for _, r := range records {
+		converted, err := toGlobalTrades(r, e.client.auth.key, token.TokenID, symbol)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, converted...)
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time.Time())
+	})
+	return limitTrades(trades, options.Limit), nil
+}
+
+func (e *Exchange) queryDryRunTrades(symbol string, options *types.TradeQueryOptions) []types.Trade {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	var trades []types.Trade
+	for _, t := range e.trades {
+		if t.Symbol != symbol || t.ID <= options.LastTradeID {
+			continue
+		}
+		if options.StartTime != nil && t.Time.Before(*options.StartTime) {
+			continue
+		}
+		if options.EndTime != nil && t.Time.After(*options.EndTime) {
+			continue
+		}
+		trades = append(trades, t)
+	}
+	return limitTrades(trades, options.Limit)
+}
+
+func limitTrades(trades []types.Trade, limit int64) []types.Trade {
+	if limit > 0 && int64(len(trades)) > limit {
+		return trades[:limit]
+	}
+	return trades
+}
+
+// recordFillLocked 把 dry-run 订单以 price 成交 quantity 记入成交记录（maker 为 false 时是吃单方），
+// 需要持有 e.mu 的读锁与订单所在的 sh.mu。
+func (e *Exchange) recordFillLocked(o *types.Order, quantity, price fixedpoint.Value, at types.Time, maker bool) {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+
+	e.nextTradeID++
+	feeRateBps := fixedpoint.NewFromInt(int64(e.fees.feeRateBps(o.Symbol)))
+	trade := types.Trade{
+		ID:            e.nextTradeID,
+		OrderID:       o.OrderID,
+		Exchange:      types.ExchangePolymarket,
+		Price:         price,
+		Quantity:      quantity,
+		QuoteQuantity: price.Mul(quantity),
+		Symbol:        o.Symbol,
+		Side:          o.Side,
+		IsBuyer:       o.Side == types.SideTypeBuy,
+		IsMaker:       maker,
+		Time:          at,
+		Fee:           tradeFee(price, quantity, feeRateBps),
+		FeeCurrency:   collateralCurrency,
+		Tag:           o.Tag,
+	}
+	e.trades = append(e.trades, trade)
+	recordFillMetrics(trade, e.metricsMode())
+}
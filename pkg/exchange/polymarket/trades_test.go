@@ -0,0 +1,117 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_QueryTrades(t *testing.T) {
+	t.Run("clob", func(t *testing.T) {
+		t.Setenv(envDryRun, "false")
+
+		secret := base64.URLEncoding.EncodeToString([]byte("secret"))
+		transport := &httptesting.MockTransport{}
+		transport.GET("/data/trades", func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "key", req.Header.Get("POLY_API_KEY"))
+			assert.NotEmpty(t, req.Header.Get("POLY_SIGNATURE"))
+
+			query := req.URL.Query()
+			assert.Equal(t, "111111111111", query.Get("asset_id"))
+			assert.Equal(t, "1760515200", query.Get("after"))
+
+			if query.Get("next_cursor") == "" {
+				return httptesting.BuildResponseString(http.StatusOK, `{"next_cursor": "MQ==", "data": [{
+					"id": "t2", "taker_order_id": "0xtaker", "asset_id": "111111111111", "side": "BUY",
+					"size": "10", "price": "0.4", "fee_rate_bps": "100", "match_time": "1760515300", "trader_side": "TAKER"
+				}]}`), nil
+			}
+			return httptesting.BuildResponseString(http.StatusOK, `{"next_cursor": "LTE=", "data": [{
+				"id": "t1", "taker_order_id": "0xother", "asset_id": "222222222222", "side": "BUY",
+				"size": "20", "price": "0.6", "match_time": "1760515250", "trader_side": "MAKER",
+				"maker_orders": [
+					{"order_id": "0xmine", "owner": "key", "matched_amount": "5", "price": "0.4", "fee_rate_bps": "0", "asset_id": "111111111111", "side": "BUY"},
+					{"order_id": "0xtheirs", "owner": "other", "matched_amount": "15", "price": "0.4", "asset_id": "111111111111", "side": "BUY"}
+				]
+			}]}`), nil
+		})
+
+		ex := New("key", secret, "pass")
+		ex.client.baseURL = "https://clob.test"
+		ex.client.httpClient.Transport = transport
+		ex.markets = types.MarketMap{
+			"PM_YES": {Symbol: "PM_YES", LocalSymbol: "111111111111"},
+		}
+
+		start := time.Unix(1760515200, 0)
+		trades, err := ex.QueryTrades(context.Background(), "PM_YES", &types.TradeQueryOptions{StartTime: &start})
+		assert.NoError(t, err)
+		if assert.Len(t, trades, 2) {
+			// 按成交时间升序
+			assert.Equal(t, "0xmine", trades[0].OrderUUID)
+			assert.True(t, trades[0].IsMaker)
+			assert.Equal(t, fixedpoint.NewFromFloat(5), trades[0].Quantity)
+
+			assert.Equal(t, "0xtaker", trades[1].OrderUUID)
+			assert.False(t, trades[1].IsMaker)
+			assert.Equal(t, types.SideTypeBuy, trades[1].Side)
+			assert.Equal(t, fixedpoint.NewFromFloat(4), trades[1].QuoteQuantity)
+			// 1% * min(0.4, 0.6) * 10
+			assert.Equal(t, fixedpoint.NewFromFloat(0.04), trades[1].Fee)
+		}
+
+		trades, err = ex.QueryTrades(context.Background(), "PM_YES", &types.TradeQueryOptions{StartTime: &start, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, trades, 1)
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		t.Setenv(envDryRunAutoFill, "true")
+
+		ex := New("", "", "")
+		for _, price := range []float64{0.4, 0.5} {
+			_, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+				Symbol:   "PM_BTC_15M_UP_YES_USDC",
+				Side:     types.SideTypeBuy,
+				Type:     types.OrderTypeLimit,
+				Price:    fixedpoint.NewFromFloat(price),
+				Quantity: fixedpoint.NewFromFloat(10),
+			})
+			assert.NoError(t, err)
+		}
+		ex.SetReferencePrice("PM_BTC_15M_UP_YES_USDC", fixedpoint.NewFromFloat(0.45))
+
+		trades, err := ex.QueryTrades(context.Background(), "PM_BTC_15M_UP_YES_USDC", nil)
+		assert.NoError(t, err)
+		if assert.Len(t, trades, 1) {
+			assert.Equal(t, fixedpoint.NewFromFloat(0.5), trades[0].Price)
+			assert.Equal(t, fixedpoint.NewFromFloat(10), trades[0].Quantity)
+		}
+
+		trades, err = ex.QueryTrades(context.Background(), "PM_BTC_15M_UP_YES_USDC", &types.TradeQueryOptions{LastTradeID: trades[0].ID})
+		assert.NoError(t, err)
+		assert.Empty(t, trades)
+	})
+}
+
+func TestAPICredentials_Signature(t *testing.T) {
+	creds := newAPICredentials("key", base64.URLEncoding.EncodeToString([]byte("secret")), "pass")
+	sig, err := creds.signature(1000000, http.MethodGet, "/data/trades", nil)
+	assert.NoError(t, err)
+
+	// 签名只取决于 timestamp + method + path + body
+	same, _ := creds.signature(1000000, http.MethodGet, "/data/trades", nil)
+	other, _ := creds.signature(1000001, http.MethodGet, "/data/trades", nil)
+	assert.Equal(t, sig, same)
+	assert.NotEqual(t, sig, other)
+
+	assert.Nil(t, newAPICredentials("", "", ""))
+}
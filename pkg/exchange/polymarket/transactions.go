@@ -0,0 +1,199 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// 钱包发送的链上交易（incrementNonce()、授权）：
+// - legacy 交易，按 EIP-155 签名（chainId 137），RLP 编码后通过 POLYMARKET_RPC_URL 的 eth_sendRawTransaction 发送
+// - 交易 nonce 取 eth_getTransactionCount(pending)，gasPrice 取 eth_gasPrice，gas 为 eth_estimateGas 加 20% 余量
+// - 发送后每隔 POLYMARKET_TX_POLL_INTERVAL（默认 2s）查询一次 receipt，超过 POLYMARKET_TX_TIMEOUT（默认 2m）没有打包返回错误，
+//   receipt 的 status 为 0（交易 revert）同样返回错误
+// - 合约按 msg.sender 记录 nonce 与授权，交易由私钥地址发出，所以要求 POLYMARKET_WALLET_ADDRESS 等于私钥地址；
+//   proxy / Gnosis Safe 钱包需要通过钱包自身（例如 Polymarket 网页）发送
+
+const (
+	envTxTimeout      = "POLYMARKET_TX_TIMEOUT"
+	envTxPollInterval = "POLYMARKET_TX_POLL_INTERVAL"
+)
+
+// legacyTx 是一笔 legacy（type 0）交易。
+type legacyTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       string
+	Value    *big.Int
+	Data     []byte
+}
+
+// encode 返回交易的 RLP 编码：签名前 v / r / s 为 chainId / 0 / 0（EIP-155），签名后为签名的值。
+func (tx *legacyTx) encode(v, r, s *big.Int) ([]byte, error) {
+	to, err := parseAddress(tx.To)
+	if err != nil {
+		return nil, err
+	}
+
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	return rlpList(
+		rlpUint(new(big.Int).SetUint64(tx.Nonce)),
+		rlpUint(tx.GasPrice),
+		rlpUint(new(big.Int).SetUint64(tx.Gas)),
+		rlpBytes(to),
+		rlpUint(value),
+		rlpBytes(tx.Data),
+		rlpUint(v),
+		rlpUint(r),
+		rlpUint(s),
+	), nil
+}
+
+// signTx 按 EIP-155 签名交易，返回可以直接发送的 RLP 编码。
+func (s *signer) signTx(tx *legacyTx, chainID int64) ([]byte, error) {
+	unsigned, err := tx.encode(big.NewInt(chainID), new(big.Int), new(big.Int))
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.sign(keccak256(unsigned))
+	if err != nil {
+		return nil, err
+	}
+
+	v := big.NewInt(chainID*2 + 35 + int64(sig[64]))
+	return tx.encode(v, new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]))
+}
+
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return b
+	}
+	return append(rlpPrefix(0x80, len(b)), b...)
+}
+
+// rlpUint 按最短的大端字节编码整数，0 编码为空字节串。
+func rlpUint(v *big.Int) []byte {
+	return rlpBytes(v.Bytes())
+}
+
+func rlpList(items ...[]byte) []byte {
+	var payload []byte
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(rlpPrefix(0xc0, len(payload)), payload...)
+}
+
+func rlpPrefix(offset byte, n int) []byte {
+	if n < 56 {
+		return []byte{offset + byte(n)}
+	}
+	size := big.NewInt(int64(n)).Bytes()
+	return append([]byte{offset + 55 + byte(len(size))}, size...)
+}
+
+type txReceipt struct {
+	Status      string `json:"status"`
+	BlockNumber string `json:"blockNumber"`
+}
+
+// waitReceipt 等待交易打包，交易 revert 时返回错误。
+func (c *restClient) waitReceipt(ctx context.Context, hash string) error {
+	ctx, cancel := context.WithTimeout(ctx, envDuration(envTxTimeout, 2*time.Minute))
+	defer cancel()
+
+	ticker := time.NewTicker(envDuration(envTxPollInterval, 2*time.Second))
+	defer ticker.Stop()
+
+	for {
+		var receipt *txReceipt
+		if err := c.rpcCall(ctx, "eth_getTransactionReceipt", &receipt, hash); err != nil {
+			return err
+		}
+		if receipt != nil {
+			if receipt.Status != "0x1" {
+				return fmt.Errorf("polymarket: transaction %s reverted in block %s", hash, receipt.BlockNumber)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("polymarket: transaction %s is not mined: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// transactionSender 返回发送交易的私钥地址，要求与持有资金的钱包 POLYMARKET_WALLET_ADDRESS 一致。
+func (e *Exchange) transactionSender() (string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.signer == nil {
+		return "", fmt.Errorf("polymarket: a wallet private key is required to send transactions: set %s or %s",
+			envPrivateKey, envKeystoreFile)
+	}
+
+	if wallet := envString(envWalletAddress, ""); wallet != "" && !strings.EqualFold(wallet, e.signer.address) {
+		return "", fmt.Errorf("polymarket: %s %s is not the private key address %s, send the transaction from the wallet itself",
+			envWalletAddress, wallet, e.signer.address)
+	}
+	return e.signer.address, nil
+}
+
+// sendTransaction 用钱包私钥签名并发送调用合约 to 的交易，等待打包后返回交易 hash。
+func (e *Exchange) sendTransaction(ctx context.Context, to string, data []byte) (string, error) {
+	from, err := e.transactionSender()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := e.rpc.rpcQuantity(ctx, "eth_getTransactionCount", from, "pending")
+	if err != nil {
+		return "", err
+	}
+
+	gasPrice, err := e.rpc.rpcQuantity(ctx, "eth_gasPrice")
+	if err != nil {
+		return "", err
+	}
+
+	call := map[string]string{"from": from, "to": to, "data": "0x" + hex.EncodeToString(data)}
+	gas, err := e.rpc.rpcQuantity(ctx, "eth_estimateGas", call)
+	if err != nil {
+		return "", err
+	}
+
+	tx := &legacyTx{
+		Nonce:    nonce.Uint64(),
+		GasPrice: gasPrice,
+		Gas:      gas.Uint64() * 12 / 10,
+		To:       to,
+		Data:     data,
+	}
+
+	e.mu.RLock()
+	raw, err := e.signer.signTx(tx, polygonChainID)
+	e.mu.RUnlock()
+	if err != nil {
+		return "", err
+	}
+
+	var hash string
+	if err := e.rpc.rpcCall(ctx, "eth_sendRawTransaction", &hash, "0x"+hex.EncodeToString(raw)); err != nil {
+		return "", err
+	}
+
+	log.Infof("polymarket transaction %s sent from %s to %s, waiting for receipt", hash, from, to)
+	return hash, e.rpc.waitReceipt(ctx, hash)
+}
@@ -0,0 +1,96 @@
+package polymarket
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+// mockRPC 模拟 Polygon JSON-RPC，按方法名分发并记录请求。
+type mockRPC struct {
+	mu    sync.Mutex
+	calls []rpcRequest
+}
+
+func newMockRPC(t *testing.T, handlers map[string]func(params []interface{}) interface{}) (*mockRPC, *restClient) {
+	m := &mockRPC{}
+	transport := &httptesting.MockTransport{}
+	transport.POST("", func(req *http.Request) (*http.Response, error) {
+		var rpcReq rpcRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&rpcReq))
+
+		m.mu.Lock()
+		m.calls = append(m.calls, rpcReq)
+		m.mu.Unlock()
+
+		h, ok := handlers[rpcReq.Method]
+		if !ok {
+			t.Errorf("unexpected rpc method %s", rpcReq.Method)
+			return httptesting.BuildResponseString(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`), nil
+		}
+
+		result, _ := json.Marshal(h(rpcReq.Params))
+		return httptesting.BuildResponseString(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":`+string(result)+`}`), nil
+	})
+	return m, newTestRestClient(transport)
+}
+
+// params 返回 method 每次调用的参数。
+func (m *mockRPC) params(method string) [][]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out [][]interface{}
+	for _, c := range m.calls {
+		if c.Method == method {
+			out = append(out, c.Params)
+		}
+	}
+	return out
+}
+
+// 测试向量来自 EIP-155 规范的示例交易
+func TestSigner_SignTx(t *testing.T) {
+	key, err := parsePrivateKey("4646464646464646464646464646464646464646464646464646464646464646")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tx := &legacyTx{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       "0x3535353535353535353535353535353535353535",
+		Value:    new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
+	}
+
+	unsigned, err := tx.encode(big.NewInt(1), new(big.Int), new(big.Int))
+	assert.NoError(t, err)
+	assert.Equal(t, "ec098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080018080", hex.EncodeToString(unsigned))
+
+	raw, err := (&signer{key: key}).signTx(tx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+		hex.EncodeToString(raw))
+
+	tx.To = "0x1234"
+	_, err = (&signer{key: key}).signTx(tx, 1)
+	assert.Error(t, err)
+}
+
+func TestRLP(t *testing.T) {
+	assert.Equal(t, []byte{0x80}, rlpUint(new(big.Int)))
+	assert.Equal(t, []byte{0x7f}, rlpBytes([]byte{0x7f}))
+	assert.Equal(t, []byte{0x81, 0x80}, rlpBytes([]byte{0x80}))
+	assert.Equal(t, []byte{0xc0}, rlpList())
+
+	long := make([]byte, 56)
+	assert.Equal(t, append([]byte{0xb8, 56}, long...), rlpBytes(long))
+}
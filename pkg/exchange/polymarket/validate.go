@@ -0,0 +1,122 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 启动时的配置检查：真实交易缺少私钥、API key 或 token id 映射时，错误原本要到 SubmitOrder 才会出现。
+// ValidateConfig 一次性检查所有配置并汇总成一个 *ConfigError；bbgo session 初始化时通过 types.Initializer 调用。
+
+// ConfigError 汇总所有配置问题，Problems 每一项是一条可以直接照着修改的说明。
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("polymarket: invalid configuration (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Initialize 实现 types.Initializer，session 初始化时检查配置；真实交易还会同步 CLOB 服务器时间（见 clock.go）。
+func (e *Exchange) Initialize(ctx context.Context) error {
+	if err := e.ValidateConfig(); err != nil {
+		return err
+	}
+
+	if !e.IsDryRun() {
+		e.startClockSync(ctx)
+	}
+	return nil
+}
+
+// ValidateConfig 检查 REST 地址与 market 列表；关闭 dry-run 时还要求钱包私钥、API key、钱包地址，
+// 并且每个 market 的 localSymbol 都是 CLOB token id。没有问题时返回 nil，否则返回 *ConfigError。
+func (e *Exchange) ValidateConfig() error {
+	var problems []string
+
+	for _, c := range []struct {
+		env    string
+		client *restClient
+	}{
+		{envClobURL, e.client},
+		{envGammaURL, e.gamma},
+		{envRPCURL, e.rpc},
+	} {
+		if err := validateBaseURL(c.client.baseURL); err != nil {
+			problems = append(problems, fmt.Sprintf("%s %q is invalid: %v", c.env, c.client.baseURL, err))
+		}
+	}
+
+	if _, err := loadCollateral(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := loadOrderCap(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	e.mu.RLock()
+	markets := e.markets
+	e.mu.RUnlock()
+
+	if len(markets) == 0 {
+		loaded, _, _, err := loadStartupMarkets()
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		markets = loaded
+	}
+
+	if !e.IsDryRun() {
+		problems = append(problems, e.validateLiveConfig(markets)...)
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+func (e *Exchange) validateLiveConfig(markets types.MarketMap) (problems []string) {
+	if e.signer == nil {
+		problems = append(problems, fmt.Sprintf("a wallet private key is required for live trading: set %s or %s (or %s=true to use dry-run)",
+			envPrivateKey, envKeystoreFile, envDryRun))
+	}
+	if e.client.auth == nil {
+		problems = append(problems, "CLOB API key/secret/passphrase is required for live trading: set them in the session config or POLYMARKET_API_KEY/POLYMARKET_API_SECRET/POLYMARKET_API_PASSPHRASE")
+	}
+	if envString(envWalletAddress, "") == "" {
+		problems = append(problems, fmt.Sprintf("%s is required to verify allowances before live trading", envWalletAddress))
+	}
+
+	var symbols []string
+	for symbol, m := range markets {
+		if !isTokenID(m.LocalSymbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		problems = append(problems, fmt.Sprintf("market %s has no CLOB token id: set its localSymbol to the outcome token id in %s/%s/%s",
+			symbol, envMarketsFile, envMarketsDir, envMarketsJSON))
+	}
+	return problems
+}
+
+func validateBaseURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	return nil
+}
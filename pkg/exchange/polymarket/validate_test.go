@@ -0,0 +1,50 @@
+package polymarket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchange_ValidateConfig(t *testing.T) {
+	t.Run("dry-run", func(t *testing.T) {
+		ex := New("", "", "")
+		assert.NoError(t, ex.ValidateConfig())
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		t.Setenv(envGammaURL, "gamma-api.polymarket.com")
+
+		err := New("", "", "").ValidateConfig()
+		var configErr *ConfigError
+		if assert.True(t, errors.As(err, &configErr)) && assert.Len(t, configErr.Problems, 1) {
+			assert.Contains(t, configErr.Problems[0], envGammaURL)
+		}
+	})
+
+	t.Run("live without credentials", func(t *testing.T) {
+		t.Setenv(envDryRun, "false")
+
+		err := New("", "", "").ValidateConfig()
+		var configErr *ConfigError
+		if assert.True(t, errors.As(err, &configErr)) {
+			// 私钥、API key、钱包地址，以及两个默认示例 market 没有 token id
+			assert.Len(t, configErr.Problems, 5)
+			assert.Contains(t, configErr.Problems[0], envPrivateKey)
+			assert.Contains(t, configErr.Problems[3], "PM_BTC_15M_UP_NO_USDC")
+			assert.Contains(t, err.Error(), "5 problems")
+		}
+	})
+
+	t.Run("live", func(t *testing.T) {
+		t.Setenv(envDryRun, "false")
+		t.Setenv(envWalletAddress, testAddress)
+		t.Setenv(envMarketsJSON, `[{"symbol": "PM_YES", "localSymbol": "111111111111", "quoteCurrency": "USDC", "pricePrecision": 2, "tickSize": "0.01", "stepSize": "0.01"}]`)
+
+		ex, err := NewWithPrivateKey("key", "c2VjcmV0", "pass", testPrivateKey)
+		if assert.NoError(t, err) {
+			assert.NoError(t, ex.ValidateConfig())
+		}
+	})
+}
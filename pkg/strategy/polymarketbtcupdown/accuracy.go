@@ -0,0 +1,61 @@
+package polymarketbtcupdown
+
+import (
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// predictionTracker 记录每次下注预测的方向，并用下一根收盘的 K 线确认预测是否正确，维护命中率。
+// 下注发生在第 N 根 K 线收盘时，预测的是第 N+1 根 K 线的方向，因此在第 N+1 根收盘时结算。
+type predictionTracker struct {
+	mu sync.Mutex
+
+	// pending 为等待确认的预测（true = up），nil 表示没有待确认的预测
+	pending     *bool
+	pendingTime types.Time
+
+	hits  int
+	total int
+}
+
+// Record 记录一次下注的预测方向。
+func (t *predictionTracker) Record(kline types.KLine, up bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = &up
+	t.pendingTime = kline.EndTime
+}
+
+// Confirm 用新收盘的 K 线结算上一次预测，返回是否有预测被结算以及结算结果。
+// 开盘价等于收盘价的 K 线视为 down，与下注规则一致。
+func (t *predictionTracker) Confirm(kline types.KLine) (confirmed, correct bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending == nil || !kline.StartTime.After(t.pendingTime.Time()) {
+		return false, false
+	}
+
+	up := kline.Close.Compare(kline.Open) > 0
+	correct = up == *t.pending
+
+	t.total++
+	if correct {
+		t.hits++
+	}
+	t.pending = nil
+	return true, correct
+}
+
+// Accuracy 返回命中次数、结算次数与命中率。
+func (t *predictionTracker) Accuracy() (hits, total int, rate float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.total > 0 {
+		rate = float64(t.hits) / float64(t.total)
+	}
+	return t.hits, t.total, rate
+}
@@ -0,0 +1,50 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestKLine(start time.Time, open, close float64) types.KLine {
+	return types.KLine{
+		StartTime: types.Time(start),
+		EndTime:   types.Time(start.Add(15*time.Minute - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(open),
+		Close:     fixedpoint.NewFromFloat(close),
+	}
+}
+
+func TestPredictionTracker(t *testing.T) {
+	var tracker predictionTracker
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	k0 := newTestKLine(t0, 100, 110)
+	tracker.Record(k0, true)
+
+	// 同一根 K 线不结算
+	confirmed, _ := tracker.Confirm(k0)
+	assert.False(t, confirmed)
+
+	confirmed, correct := tracker.Confirm(newTestKLine(t0.Add(15*time.Minute), 110, 120))
+	assert.True(t, confirmed)
+	assert.True(t, correct)
+
+	tracker.Record(newTestKLine(t0.Add(15*time.Minute), 110, 120), true)
+	confirmed, correct = tracker.Confirm(newTestKLine(t0.Add(30*time.Minute), 120, 115))
+	assert.True(t, confirmed)
+	assert.False(t, correct)
+
+	// 没有待确认的预测
+	confirmed, _ = tracker.Confirm(newTestKLine(t0.Add(45*time.Minute), 115, 118))
+	assert.False(t, confirmed)
+
+	hits, total, rate := tracker.Accuracy()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 2, total)
+	assert.InDelta(t, 0.5, rate, 1e-9)
+}
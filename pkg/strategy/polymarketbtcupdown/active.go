@@ -0,0 +1,41 @@
+package polymarketbtcupdown
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 活跃时段：周期性的 up/down 市场在窗口刚开盘时流动性与定价偏差最好，ActiveFrom / ActiveTo 限制只在窗口开盘后的
+// [ActiveFrom, ActiveTo) 内下注，超出时段的信号跳过并记录原因。
+// - 窗口为下注的目标窗口，即紧接着收盘 K 线的那个窗口，边界由 polymarket.UpDownWindow 计算（与 Gamma 的市场 slug 一致）
+// - 偏移按下注时的当前时间计算，ConfirmDelay 的等待也计入偏移
+// - ActiveTo 为 0 表示直到窗口结束；两者都为 0 时不限制
+// - 下注只发生在窗口开盘（K 线收盘）时或 ConfirmDelay 之后，所以 Validate 要求 ActiveFrom <= ConfirmDelay < ActiveTo，
+//   否则配置永远不会下注
+
+// activeWindow 判断当前时间是否在 kline 之后窗口的活跃时段内，不在时返回原因。
+func (s *Strategy) activeWindow(m *MarketConfig, kline types.KLine, now time.Time) (string, bool) {
+	if s.ActiveFrom == 0 && s.ActiveTo == 0 {
+		return "", true
+	}
+
+	start, end, err := polymarket.UpDownWindow(m.Interval, kline.EndTime.Time().Add(time.Millisecond))
+	if err != nil {
+		return err.Error(), false
+	}
+
+	to := end.Sub(start)
+	if s.ActiveTo > 0 {
+		to = s.ActiveTo.Duration()
+	}
+
+	offset := now.Sub(start)
+	if offset < s.ActiveFrom.Duration() || offset >= to {
+		return fmt.Sprintf("%s after window open %s is outside the active window [%s, %s)",
+			offset, start.Format(time.RFC3339), s.ActiveFrom.Duration(), to), false
+	}
+	return "", true
+}
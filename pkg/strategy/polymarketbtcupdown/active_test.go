@@ -0,0 +1,76 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_ActiveWindow(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &MarketConfig{SourceSymbol: "BTCUSDT", Interval: types.Interval15m}
+	// 00:00 ~ 00:15 的 K 线收盘后下注 00:15 开盘的窗口
+	kline := newTestKLine(t0, 100, 110)
+	open := t0.Add(15 * time.Minute)
+
+	s := &Strategy{}
+	_, ok := s.activeWindow(m, kline, open.Add(14*time.Minute))
+	assert.True(t, ok, "no active window configured")
+
+	s.ActiveFrom = types.Duration(5 * time.Second)
+	s.ActiveTo = types.Duration(3 * time.Minute)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   bool
+	}{
+		{name: "before activeFrom", offset: 2 * time.Second, want: false},
+		{name: "at activeFrom", offset: 5 * time.Second, want: true},
+		{name: "within the window", offset: 2 * time.Minute, want: true},
+		{name: "at activeTo", offset: 3 * time.Minute, want: false},
+		{name: "late in the window", offset: 10 * time.Minute, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := s.activeWindow(m, kline, open.Add(tt.offset))
+			assert.Equal(t, tt.want, ok)
+			if !tt.want {
+				assert.Contains(t, reason, "outside the active window")
+			}
+		})
+	}
+
+	// 没有 activeTo 时直到窗口结束
+	s.ActiveTo = 0
+	_, ok = s.activeWindow(m, kline, open.Add(14*time.Minute))
+	assert.True(t, ok)
+	_, ok = s.activeWindow(m, kline, open.Add(15*time.Minute))
+	assert.False(t, ok)
+}
+
+func TestStrategy_ValidateActiveWindow(t *testing.T) {
+	newStrategy := func(from, to, confirm time.Duration) *Strategy {
+		s := &Strategy{
+			SourceSymbol: "BTCUSDT", Interval: types.Interval15m, YesSymbol: "YES", NoSymbol: "NO",
+			ActiveFrom: types.Duration(from), ActiveTo: types.Duration(to), ConfirmDelay: types.Duration(confirm),
+		}
+		assert.NoError(t, s.Defaults())
+		return s
+	}
+
+	assert.NoError(t, newStrategy(0, 3*time.Minute, 10*time.Second).Validate())
+	assert.NoError(t, newStrategy(10*time.Second, 0, 10*time.Second).Validate())
+	// 下注在窗口开盘时或 confirmDelay 之后，activeFrom 更晚时永远不会下注
+	assert.ErrorContains(t, newStrategy(time.Minute, 0, 0).Validate(), "must not be after confirmDelay")
+	assert.ErrorContains(t, newStrategy(time.Minute, 3*time.Minute, 10*time.Second).Validate(), "must not be after confirmDelay")
+	assert.ErrorContains(t, newStrategy(-time.Second, 0, 0).Validate(), "negative")
+	assert.ErrorContains(t, newStrategy(2*time.Minute, time.Minute, 0).Validate(), "must be after activeFrom")
+	assert.ErrorContains(t, newStrategy(0, 10*time.Second, 10*time.Second).Validate(), "shorter than activeTo")
+	assert.ErrorContains(t, newStrategy(15*time.Minute, 0, 0).Validate(), "must not be after confirmDelay")
+	assert.ErrorContains(t, newStrategy(0, 20*time.Minute, 0).Validate(), "within interval")
+}
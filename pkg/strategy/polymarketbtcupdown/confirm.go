@@ -0,0 +1,56 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 信号确认延迟：ConfirmDelay > 0 时 K 线收盘后先等待 ConfirmDelay，再用行情源 session（Binance）的 ticker 读取最新价，
+// 最新价相对 K 线开盘价的方向与收盘方向一致时才下注，等待期间方向反转时取消本次下注。
+// - 等待使用 timer，ctx 取消时立即放弃
+// - 等待期间不阻塞行情 stream：每根收盘 K 线在单独的 goroutine 中处理，BetWindows / Exposures 的读写用 windowMu 串行化（见 window.go、exposure.go）
+// - 查询 ticker 失败时无法确认方向，同样取消下注
+
+// waitConfirmDelay 等待 d，ctx 在此之前取消时返回 false。
+func waitConfirmDelay(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// directionConfirmed 判断最新价相对开盘价的方向是否仍与 K 线的收盘方向一致（与 decide 相同，close > open 为 up）。
+func directionConfirmed(kline types.KLine, latest fixedpoint.Value) bool {
+	up := kline.Close.Compare(kline.Open) > 0
+	return (latest.Compare(kline.Open) > 0) == up
+}
+
+// confirmDirection 等待 ConfirmDelay 后查询行情源的最新价，确认方向没有反转，返回不能下注的原因。
+func (s *Strategy) confirmDirection(ctx context.Context, m *MarketConfig, kline types.KLine) (string, bool) {
+	if !waitConfirmDelay(ctx, s.ConfirmDelay.Duration()) {
+		return "context canceled during confirmDelay", false
+	}
+
+	ticker, err := s.sourceTicker(ctx, m.SourceSymbol)
+	if err != nil {
+		return fmt.Sprintf("query %s ticker failed, cannot confirm direction: %v", m.SourceSymbol, err), false
+	}
+	if ticker.Last.Sign() <= 0 {
+		return fmt.Sprintf("%s has no valid last price, cannot confirm direction", m.SourceSymbol), false
+	}
+
+	if !directionConfirmed(kline, ticker.Last) {
+		return fmt.Sprintf("direction flipped during confirmDelay %s: open %s, close %s, latest %s",
+			s.ConfirmDelay.Duration(), kline.Open.String(), kline.Close.String(), ticker.Last.String()), false
+	}
+	return "", true
+}
@@ -0,0 +1,79 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestDirectionConfirmed(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	up := newTestKLine(t0, 100, 110)
+	down := newTestKLine(t0, 100, 90)
+
+	assert.True(t, directionConfirmed(up, fixedpoint.NewFromFloat(105)))
+	assert.False(t, directionConfirmed(up, fixedpoint.NewFromFloat(99)))
+	assert.False(t, directionConfirmed(up, fixedpoint.NewFromFloat(100)))
+	assert.True(t, directionConfirmed(down, fixedpoint.NewFromFloat(95)))
+	assert.False(t, directionConfirmed(down, fixedpoint.NewFromFloat(101)))
+}
+
+func TestStrategy_ConfirmDirection(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &MarketConfig{SourceSymbol: "BTCUSDT", Interval: types.Interval15m}
+	kline := newTestKLine(t0, 100, 110)
+
+	var latest fixedpoint.Value
+	var queryErr error
+	s := &Strategy{ConfirmDelay: types.Duration(time.Millisecond)}
+	s.sourceTicker = func(ctx context.Context, symbol string) (*types.Ticker, error) {
+		assert.Equal(t, "BTCUSDT", symbol)
+		if queryErr != nil {
+			return nil, queryErr
+		}
+		return &types.Ticker{Last: latest}, nil
+	}
+	ctx := context.Background()
+
+	latest = fixedpoint.NewFromFloat(108)
+	_, ok := s.confirmDirection(ctx, m, kline)
+	assert.True(t, ok)
+
+	// 等待期间跌回开盘价以下，取消下注
+	latest = fixedpoint.NewFromFloat(98)
+	reason, ok := s.confirmDirection(ctx, m, kline)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "direction flipped")
+
+	queryErr = errors.New("network error")
+	reason, ok = s.confirmDirection(ctx, m, kline)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "cannot confirm direction")
+
+	// ctx 取消时不必等到 ConfirmDelay 结束
+	s.ConfirmDelay = types.Duration(time.Hour)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	start := time.Now()
+	reason, ok = s.confirmDirection(canceled, m, kline)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "context canceled")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestStrategy_MarkWindow(t *testing.T) {
+	s := &Strategy{BetWindows: make(betWindows)}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	assert.False(t, s.windowBet("BTCUSDT:15m:1704067200"))
+	assert.True(t, s.markWindow(ctx, "BTCUSDT:15m:1704067200", start))
+	assert.True(t, s.windowBet("BTCUSDT:15m:1704067200"))
+	assert.False(t, s.markWindow(ctx, "BTCUSDT:15m:1704067200", start))
+}
@@ -0,0 +1,82 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// 按优势下注：配置了概率模型（见 model.go）时，下注前读取目标 symbol 在 Polymarket 的实时价格（best ask，没有卖盘时用 last），
+// 与模型给出的该方向概率比较，edge = 模型概率 - 实时价格：
+// - |edge| 不超过 EdgeThreshold 时视为定价合理，不下注
+// - edge 为负时 Polymarket 对下注方向定价偏高（另一方向偏低，但 K 线信号不押另一方向），同样不下注
+// - 优势越大下注越多：金额 = quoteAmount × min(edge / EdgeThreshold, MaxEdgeMultiplier)，EdgeThreshold 为 0 时不放大
+// - UseMarketPrice 时信号的下单价格已经是 best ask，不再重复查询
+
+// marketPrice 返回 symbol 在 Polymarket 的实时买入价格。
+func (s *Strategy) marketPrice(ctx context.Context, session *bbgo.ExchangeSession, sig signal) (fixedpoint.Value, error) {
+	if s.UseMarketPrice {
+		return sig.Price, nil
+	}
+
+	ticker, err := session.Exchange.QueryTicker(ctx, sig.Symbol)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	price := ticker.Sell
+	if price.Sign() <= 0 || price.Compare(fixedpoint.One) >= 0 {
+		price = ticker.Last
+	}
+	if price.Sign() <= 0 || price.Compare(fixedpoint.One) >= 0 {
+		return fixedpoint.Zero, fmt.Errorf("%s has no valid market price", sig.Symbol)
+	}
+	return price, nil
+}
+
+// edgeMultiplier 返回按优势放大下注金额的倍数。
+func (s *Strategy) edgeMultiplier(edge fixedpoint.Value) fixedpoint.Value {
+	if s.EdgeThreshold.Sign() <= 0 {
+		return fixedpoint.One
+	}
+	return fixedpoint.Min(edge.Div(s.EdgeThreshold), s.MaxEdgeMultiplier)
+}
+
+// checkEdge 比较模型概率与 Polymarket 实时价格，返回按优势调整后的下注金额；优势不足时返回不能下注的原因。
+// 没有配置模型时直接返回 m.QuoteAmount。
+func (s *Strategy) checkEdge(ctx context.Context, session *bbgo.ExchangeSession, m *MarketConfig, sig signal) (fixedpoint.Value, string, bool) {
+	model := s.model()
+	if model == nil {
+		return m.QuoteAmount, "", true
+	}
+
+	p, ok := model.Probability(m.history.last(model.Lookback()))
+	if !ok {
+		return fixedpoint.Zero, fmt.Sprintf("not enough klines for probability model, need %d", model.Lookback()), false
+	}
+	if !sig.BetUp {
+		p = fixedpoint.One.Sub(p)
+	}
+
+	price, err := s.marketPrice(ctx, session, sig)
+	if err != nil {
+		return fixedpoint.Zero, fmt.Sprintf("query %s market price failed, cannot compare with model: %v", sig.Symbol, err), false
+	}
+
+	edge := p.Sub(price)
+	if edge.Abs().Compare(s.EdgeThreshold) <= 0 {
+		return fixedpoint.Zero, fmt.Sprintf("edge %s between model probability %s and market price %s of %s does not exceed edgeThreshold %s",
+			edge.String(), p.String(), price.String(), sig.Symbol, s.EdgeThreshold.String()), false
+	}
+	if edge.Sign() < 0 {
+		return fixedpoint.Zero, fmt.Sprintf("market price %s of %s is above model probability %s",
+			price.String(), sig.Symbol, p.String()), false
+	}
+
+	multiplier := s.edgeMultiplier(edge)
+	log.Infof("%s edge %s: model probability %s, market price %s, quoteAmount x%s",
+		sig.Symbol, edge.String(), p.String(), price.String(), multiplier.String())
+	return m.QuoteAmount.Mul(multiplier), "", true
+}
@@ -0,0 +1,88 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_CheckEdge(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := fixedpoint.NewFromFloat
+	ctx := context.Background()
+	session := bbgo.NewExchangeSession("polymarket", polymarket.New("", "", ""))
+
+	m := &MarketConfig{SourceSymbol: "BTCUSDT", Interval: types.Interval15m, QuoteAmount: f(5)}
+	s := &Strategy{UseMarketPrice: true, EdgeThreshold: f(0.05), MaxEdgeMultiplier: f(2)}
+
+	up := signal{Symbol: "YES", BetUp: true, Price: f(0.6)}
+	down := signal{Symbol: "NO", BetUp: false, Price: f(0.2)}
+
+	// 没有配置模型时不检查
+	quote, _, ok := s.checkEdge(ctx, session, m, up)
+	assert.True(t, ok)
+	assert.Equal(t, "5", quote.String())
+
+	s.Model = &LinearModel{Window: 2, MomentumWeight: f(10)}
+	s.Model.Defaults()
+
+	_, reason, ok := s.checkEdge(ctx, session, m, up)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "not enough klines")
+
+	// 上涨 2%：模型概率 0.7
+	for _, k := range newTestKLines(t0, 100, 101, 102) {
+		s.recordKLine(m, k)
+	}
+
+	// edge 0.1 为阈值的 2 倍，金额放大 2 倍
+	quote, _, ok = s.checkEdge(ctx, session, m, up)
+	if assert.True(t, ok) {
+		assert.InDelta(t, 10, quote.Float64(), 1e-6)
+	}
+
+	// edge 0.07：放大 1.4 倍
+	up.Price = f(0.63)
+	quote, _, ok = s.checkEdge(ctx, session, m, up)
+	if assert.True(t, ok) {
+		assert.InDelta(t, 7, quote.Float64(), 1e-6)
+	}
+
+	// 放大倍数不超过 MaxEdgeMultiplier；买 NO 的模型概率为 0.3，edge 0.1
+	s.MaxEdgeMultiplier = f(1.5)
+	quote, _, ok = s.checkEdge(ctx, session, m, down)
+	if assert.True(t, ok) {
+		assert.InDelta(t, 7.5, quote.Float64(), 1e-6)
+	}
+
+	up.Price = f(0.68)
+	_, reason, ok = s.checkEdge(ctx, session, m, up)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "does not exceed edgeThreshold")
+
+	// 价格高于模型概率：下注方向被高估
+	up.Price = f(0.9)
+	_, reason, ok = s.checkEdge(ctx, session, m, up)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "above model probability")
+
+	// 没有阈值时不放大
+	s.EdgeThreshold = fixedpoint.Zero
+	up.Price = f(0.5)
+	quote, _, ok = s.checkEdge(ctx, session, m, up)
+	assert.True(t, ok)
+	assert.Equal(t, "5", quote.String())
+
+	// 读取不到实时价格时不下注
+	s.UseMarketPrice = false
+	_, reason, ok = s.checkEdge(ctx, session, m, up)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "no valid market price")
+}
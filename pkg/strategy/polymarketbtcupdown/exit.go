@@ -0,0 +1,108 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 止盈/止损：
+// - 持仓来自 handleOrderUpdate 统计的成交增量
+// - 每隔 ExitCheckInterval 查询持仓 symbol 的 ticker，用 best bid（能卖出的价格）和 TakeProfitPrice / StopLossPrice 比较
+// - 触发后以 best bid 挂卖单平掉全部持仓；dry-run 下把该价格设为模拟撮合的参考价，让平仓单能成交
+
+func (s *Strategy) runExitLoop(ctx context.Context, router bbgo.OrderExecutionRouter, session *bbgo.ExchangeSession) {
+	ticker := time.NewTicker(s.ExitCheckInterval.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkExits(ctx, router, session)
+		}
+	}
+}
+
+func (s *Strategy) checkExits(ctx context.Context, router bbgo.OrderExecutionRouter, session *bbgo.ExchangeSession) {
+	for symbol, quantity := range s.openPositions() {
+		t, err := session.Exchange.QueryTicker(ctx, symbol)
+		if err != nil {
+			log.WithError(err).Warnf("query %s ticker failed, skip exit check", symbol)
+			continue
+		}
+
+		price := t.Buy
+		if price.IsZero() {
+			price = t.Last
+		}
+		if price.Sign() <= 0 {
+			continue
+		}
+
+		if ex, ok := session.Exchange.(*polymarket.Exchange); ok {
+			ex.SetReferencePrice(symbol, price)
+		}
+
+		var reason string
+		switch {
+		case s.TakeProfitPrice.Sign() > 0 && price.Compare(s.TakeProfitPrice) >= 0:
+			reason = "take profit"
+		case s.StopLossPrice.Sign() > 0 && price.Compare(s.StopLossPrice) <= 0:
+			reason = "stop loss"
+		default:
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			"symbol":          symbol,
+			"price":           price.String(),
+			"quantity":        quantity.String(),
+			"takeProfitPrice": s.TakeProfitPrice.String(),
+			"stopLossPrice":   s.StopLossPrice.String(),
+		}).Infof("%s triggered, submitting exit order", reason)
+
+		s.mu.Lock()
+		s.exitingSymbols[symbol] = true
+		s.mu.Unlock()
+
+		created, err := router.SubmitOrdersTo(ctx, s.PolymarketSession, types.SubmitOrder{
+			Symbol:      symbol,
+			Side:        types.SideTypeSell,
+			Type:        types.OrderTypeLimit,
+			Price:       price,
+			Quantity:    quantity,
+			TimeInForce: types.TimeInForceGTC,
+			Tag:         s.exitTag(),
+		})
+		s.trackOrders(created)
+		if err != nil {
+			log.WithError(err).Errorf("failed to submit %s exit order", symbol)
+
+			s.mu.Lock()
+			delete(s.exitingSymbols, symbol)
+			s.mu.Unlock()
+		}
+	}
+}
+
+// openPositions 返回有持仓且没有挂平仓单的 symbol。
+func (s *Strategy) openPositions() map[string]fixedpoint.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]fixedpoint.Value)
+	for symbol, quantity := range s.positions {
+		if quantity.Sign() > 0 && !s.exitingSymbols[symbol] {
+			out[symbol] = quantity
+		}
+	}
+	return out
+}
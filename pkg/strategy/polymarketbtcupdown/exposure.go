@@ -0,0 +1,121 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 风险敞口：MaxPositionQuote 限制的是本策略挂单的剩余金额 + 已成交但还没有结算的金额。
+// - 已成交金额按 symbol 记录在 Exposures，同时记录该 symbol 最近一次下注的窗口结束时间；窗口结束后市场进入结算
+//   （到期兑付或作废），对应的成交金额不再计入敞口
+// - Exposures 随 bbgo persistence 持久化，策略重启后仍然计入还没有结算的成交；读写与 BetWindows 一样用 windowMu 串行化
+// - 挂单只统计本策略的订单：tag 为 orderTag / exitTag，或者订单 ID 是本策略提交的（实盘的订单推送不带 tag）；
+//   MaxOpenOrders 统计本策略的全部挂单，敞口只统计买单的剩余金额，平仓的卖单不增加敞口
+
+// exposure 为一个 symbol 已成交的买入金额（卖出成交时扣减）与下注窗口的结束时间
+type exposure struct {
+	Quote     fixedpoint.Value `json:"quote"`
+	WindowEnd time.Time        `json:"windowEnd"`
+}
+
+// exposures 为 symbol → 已成交金额
+type exposures map[string]exposure
+
+// open 记录 symbols 在 end 结束的窗口下注，已有的成交金额延续到较晚的窗口结束时间。
+func (e exposures) open(symbols []string, end time.Time) {
+	for _, symbol := range symbols {
+		x := e[symbol]
+		if end.After(x.WindowEnd) {
+			x.WindowEnd = end
+		}
+		e[symbol] = x
+	}
+}
+
+// fill 累加 symbol 的成交金额（卖出时 quote 为负），结果不小于 0。
+func (e exposures) fill(symbol string, quote fixedpoint.Value) {
+	x := e[symbol]
+	x.Quote = fixedpoint.Max(x.Quote.Add(quote), fixedpoint.Zero)
+	if x.Quote.IsZero() && x.WindowEnd.IsZero() {
+		delete(e, symbol)
+		return
+	}
+	e[symbol] = x
+}
+
+// release 清理窗口已经结束的 symbol，有清理时返回 true。
+func (e exposures) release(now time.Time) (released bool) {
+	for symbol, x := range e {
+		if !x.WindowEnd.IsZero() && !now.Before(x.WindowEnd) {
+			delete(e, symbol)
+			released = true
+		}
+	}
+	return released
+}
+
+func (e exposures) total() fixedpoint.Value {
+	total := fixedpoint.Zero
+	for _, x := range e {
+		total = total.Add(x.Quote)
+	}
+	return total
+}
+
+// openExposure 在提交订单前记录 symbols 的下注窗口结束时间并持久化。
+func (s *Strategy) openExposure(ctx context.Context, symbols []string, end time.Time) {
+	s.windowMu.Lock()
+	defer s.windowMu.Unlock()
+
+	s.Exposures.open(symbols, end)
+	bbgo.Sync(ctx, s)
+}
+
+// addFilledQuote 累加 symbol 的成交金额并持久化。
+func (s *Strategy) addFilledQuote(ctx context.Context, symbol string, quote fixedpoint.Value) {
+	s.windowMu.Lock()
+	defer s.windowMu.Unlock()
+
+	s.Exposures.fill(symbol, quote)
+	bbgo.Sync(ctx, s)
+}
+
+// filledQuote 清理窗口已经结束的成交金额，返回剩余的合计。
+func (s *Strategy) filledQuote(ctx context.Context, now time.Time) fixedpoint.Value {
+	s.windowMu.Lock()
+	defer s.windowMu.Unlock()
+
+	if s.Exposures.release(now) {
+		bbgo.Sync(ctx, s)
+	}
+	return s.Exposures.total()
+}
+
+// trackOrders 记录本策略提交的订单 ID，用于识别不带 tag 的订单推送与挂单。
+func (s *Strategy) trackOrders(orders []types.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.orderIDs == nil {
+		s.orderIDs = make(map[uint64]struct{})
+	}
+	for _, o := range orders {
+		s.orderIDs[o.OrderID] = struct{}{}
+	}
+}
+
+// ownOrder 判断订单是否是本策略提交的。
+func (s *Strategy) ownOrder(o types.Order) bool {
+	if o.Tag == s.orderTag() || o.Tag == s.exitTag() {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.orderIDs[o.OrderID]
+	return ok
+}
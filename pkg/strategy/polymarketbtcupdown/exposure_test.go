@@ -0,0 +1,134 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExposures(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	end := time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC)
+
+	e := make(exposures)
+	e.open([]string{"YES", "NO"}, end)
+	e.fill("YES", f(6))
+	e.fill("NO", f(2))
+	e.fill("NO", f(-3))
+	assert.Equal(t, "6", e.total().String())
+
+	// 下一个窗口继续下注 YES，成交金额延续到较晚的窗口结束时间
+	e.open([]string{"YES"}, end.Add(15*time.Minute))
+	e.open([]string{"YES"}, end)
+	assert.Equal(t, end.Add(15*time.Minute), e["YES"].WindowEnd)
+
+	assert.False(t, e.release(end.Add(-time.Second)))
+	assert.True(t, e.release(end))
+	assert.Len(t, e, 1)
+	assert.Equal(t, "6", e.total().String())
+
+	assert.True(t, e.release(end.Add(15*time.Minute)))
+	assert.Empty(t, e)
+
+	// 没有下注窗口的卖出成交不留下记录
+	e.fill("YES", f(-1))
+	assert.Empty(t, e)
+}
+
+func TestStrategy_CheckExposure(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	ctx := context.Background()
+
+	markets := types.MarketMap{
+		"YES": {Symbol: "YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01)},
+		"NO":  {Symbol: "NO", LocalSymbol: "222222222222", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01)},
+	}
+	ex := polymarket.New("", "", "", polymarket.WithMarkets(markets))
+	defer ex.Close()
+	session := bbgo.NewExchangeSession("polymarket", ex)
+	session.SetMarkets(markets)
+
+	s := &Strategy{
+		Markets:            []*MarketConfig{{YesSymbol: "YES", NoSymbol: "NO"}},
+		MaxOpenOrders:      2,
+		MaxPositionQuote:   f(10),
+		Exposures:          make(exposures),
+		executedQuantities: make(map[uint64]fixedpoint.Value),
+		positions:          make(map[string]fixedpoint.Value),
+		exitingSymbols:     make(map[string]bool),
+	}
+
+	submit := func(side types.SideType, price, quantity float64, tag string) types.Order {
+		created, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+			Symbol: "YES", Side: side, Type: types.OrderTypeLimit, Price: f(price), Quantity: f(quantity), Tag: tag,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return *created
+	}
+
+	// 其他策略的挂单不计入
+	submit(types.SideTypeBuy, 0.5, 20, "other")
+	_, ok := s.checkExposure(ctx, session, f(10))
+	assert.True(t, ok)
+
+	// 本策略的买单计入敞口：5 + 6 > 10
+	submit(types.SideTypeBuy, 0.5, 10, ID)
+	_, ok = s.checkExposure(ctx, session, f(5))
+	assert.True(t, ok)
+	reason, ok := s.checkExposure(ctx, session, f(6))
+	assert.False(t, ok)
+	assert.Contains(t, reason, "exceeds maxPositionQuote")
+
+	// 平仓卖单只计入挂单数
+	submit(types.SideTypeSell, 0.9, 10, s.exitTag())
+	reason, ok = s.checkExposure(ctx, session, f(1))
+	assert.False(t, ok)
+	assert.Contains(t, reason, "reached maxOpenOrders 2")
+}
+
+func TestStrategy_FilledExposure(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	ctx := context.Background()
+
+	s := &Strategy{
+		Exposures:          make(exposures),
+		executedQuantities: make(map[uint64]fixedpoint.Value),
+		positions:          make(map[string]fixedpoint.Value),
+		exitingSymbols:     make(map[string]bool),
+	}
+
+	now := time.Now()
+	s.openExposure(ctx, []string{"NO"}, now.Add(time.Minute))
+
+	// 实盘的订单推送不带 tag，按提交时记录的订单 ID 识别
+	s.trackOrders([]types.Order{{OrderID: 99}})
+	order := types.Order{
+		SubmitOrder:      types.SubmitOrder{Symbol: "NO", Side: types.SideTypeBuy, Price: f(0.4), Quantity: f(10)},
+		OrderID:          99,
+		ExecutedQuantity: f(5),
+	}
+	s.handleOrderUpdate(ctx, order)
+	order.ExecutedQuantity = f(10)
+	s.handleOrderUpdate(ctx, order)
+	s.handleOrderUpdate(ctx, types.Order{
+		SubmitOrder:      types.SubmitOrder{Symbol: "NO", Side: types.SideTypeBuy, Price: f(0.4), Quantity: f(10)},
+		OrderID:          100,
+		ExecutedQuantity: f(10),
+	})
+
+	assert.Equal(t, "4", s.filledQuote(ctx, now).String())
+	assert.Equal(t, "10", s.positions["NO"].String())
+
+	// 窗口结束后成交金额不再计入敞口
+	assert.Equal(t, "0", s.filledQuote(ctx, now.Add(time.Minute)).String())
+	assert.Empty(t, s.Exposures)
+}
@@ -0,0 +1,78 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 互补对冲：Hedge 为 true 时，下注买入 YES 的同时按 HedgeRatio 买入 NO（买入 NO 时反过来），限制押错方向时的亏损。
+// - 对冲数量 = 下注订单数量合计 × HedgeRatio，按另一边 market 的精度截断且不超过 MaxOrderQuantity；
+//   HedgeRatio 为 1 时两边数量相同，结算时必定兑付其中一边
+// - 对冲价格为另一边的下单价格（UseMarketPrice 时为 best ask），否则为 1 - 下注价格；
+//   价格超出 [MinEntryPrice, MaxEntryPrice] 或数量低于最小下单量时只下注、不对冲
+// - 对冲单与下注订单在同一批提交，使用相同的 tag：持仓（包括止盈/止损）、MaxPositionQuote 的风险敞口与 dry-run 汇总都包含两边
+// - 只支持 YES/NO 市场，配置了 outcomes 时不能启用
+
+// complementSymbol 返回 symbol 在 yes/no 中的另一边。
+func complementSymbol(symbol, yes, no string) (string, bool) {
+	switch symbol {
+	case yes:
+		return no, no != ""
+	case no:
+		return yes, yes != ""
+	}
+	return "", false
+}
+
+// hedgeOrder 为下注订单生成另一边的对冲单，不能对冲时返回原因。
+func (s *Strategy) hedgeOrder(ctx context.Context, session *bbgo.ExchangeSession, sig signal, yes, no string, orders []types.SubmitOrder) (types.SubmitOrder, string, bool) {
+	symbol, ok := complementSymbol(sig.Symbol, yes, no)
+	if !ok {
+		return types.SubmitOrder{}, fmt.Sprintf("%s has no complement outcome", sig.Symbol), false
+	}
+
+	market, ok := session.Market(symbol)
+	if !ok {
+		return types.SubmitOrder{}, fmt.Sprintf("market %s not found in polymarket session", symbol), false
+	}
+
+	price := s.entryPrice(ctx, session, symbol, fixedpoint.One.Sub(sig.Price))
+	if price.Sign() <= 0 || price.Compare(fixedpoint.One) >= 0 || !s.entryPriceInRange(price) {
+		return types.SubmitOrder{}, fmt.Sprintf("hedge price %s of %s is outside [%s, %s]",
+			price.String(), symbol, s.MinEntryPrice.String(), s.MaxEntryPrice.String()), false
+	}
+
+	quantity := fixedpoint.Zero
+	for _, o := range orders {
+		quantity = quantity.Add(o.Quantity)
+	}
+	quantity = quantity.Mul(s.HedgeRatio)
+	if s.MaxOrderQuantity.Sign() > 0 {
+		quantity = fixedpoint.Min(quantity, s.MaxOrderQuantity)
+	}
+
+	// 价格按 tick 向下取整后数量可能多出一个 step，按取整后的价格重新调整
+	snapped, ok := polymarket.SnapOrder(market, price, price.Mul(quantity))
+	if ok && snapped.Quantity.Compare(quantity) > 0 {
+		snapped, ok = polymarket.SnapOrder(market, snapped.Price, snapped.Price.Mul(quantity))
+	}
+	if !ok {
+		return types.SubmitOrder{}, fmt.Sprintf("hedge quantity %s of %s is below the minimal order size", quantity.String(), symbol), false
+	}
+
+	return types.SubmitOrder{
+		Symbol:      symbol,
+		Market:      market,
+		Side:        types.SideTypeBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       snapped.Price,
+		Quantity:    snapped.Quantity,
+		TimeInForce: types.TimeInForceGTC,
+		Tag:         s.orderTag(),
+	}, "", true
+}
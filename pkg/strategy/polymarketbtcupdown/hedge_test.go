@@ -0,0 +1,154 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestComplementSymbol(t *testing.T) {
+	symbol, ok := complementSymbol("YES", "YES", "NO")
+	assert.True(t, ok)
+	assert.Equal(t, "NO", symbol)
+
+	symbol, ok = complementSymbol("NO", "YES", "NO")
+	assert.True(t, ok)
+	assert.Equal(t, "YES", symbol)
+
+	_, ok = complementSymbol("OTHER", "YES", "NO")
+	assert.False(t, ok)
+
+	_, ok = complementSymbol("YES", "YES", "")
+	assert.False(t, ok)
+}
+
+func TestStrategy_HedgeOrder(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	ctx := context.Background()
+
+	markets := types.MarketMap{
+		"YES": {Symbol: "YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01), MinNotional: f(1)},
+		"NO":  {Symbol: "NO", LocalSymbol: "222222222222", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01), MinNotional: f(1)},
+	}
+	ex := polymarket.New("", "", "", polymarket.WithMarkets(markets))
+	defer ex.Close()
+	session := bbgo.NewExchangeSession("polymarket", ex)
+	session.SetMarkets(markets)
+
+	s := &Strategy{Hedge: true, HedgeRatio: f(0.5), MinEntryPrice: f(0.01), MaxEntryPrice: f(0.99)}
+	orders := s.ladderOrders(markets["YES"], f(0.6), f(6), 0)
+	if !assert.Len(t, orders, 1) || !assert.Equal(t, "10", orders[0].Quantity.String()) {
+		return
+	}
+
+	// 买入 YES 10 份，按 1 - 0.6 的价格买入 NO 5 份
+	hedge, _, ok := s.hedgeOrder(ctx, session, signal{Symbol: "YES", Price: f(0.6)}, "YES", "NO", orders)
+	if assert.True(t, ok) {
+		assert.Equal(t, "NO", hedge.Symbol)
+		assert.Equal(t, types.SideTypeBuy, hedge.Side)
+		assert.Equal(t, "0.4", hedge.Price.String())
+		assert.Equal(t, "5", hedge.Quantity.String())
+		assert.Equal(t, ID, hedge.Tag)
+	}
+
+	// 价格按 tick 向下取整（0.395 -> 0.39）后数量仍不超过 5 份
+	hedge, _, ok = s.hedgeOrder(ctx, session, signal{Symbol: "YES", Price: f(0.605)}, "YES", "NO", orders)
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.39", hedge.Price.String())
+		assert.Equal(t, "5", hedge.Quantity.String())
+	}
+
+	// 买入 NO 时对冲 YES，数量不超过 MaxOrderQuantity
+	s.HedgeRatio = f(1)
+	s.MaxOrderQuantity = f(8)
+	hedge, _, ok = s.hedgeOrder(ctx, session, signal{Symbol: "NO", Price: f(0.3)}, "YES", "NO", orders)
+	if assert.True(t, ok) {
+		assert.Equal(t, "YES", hedge.Symbol)
+		assert.Equal(t, "0.7", hedge.Price.String())
+		assert.Equal(t, "8", hedge.Quantity.String())
+	}
+
+	s.MaxEntryPrice = f(0.65)
+	_, reason, ok := s.hedgeOrder(ctx, session, signal{Symbol: "NO", Price: f(0.3)}, "YES", "NO", orders)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "outside")
+
+	// 对冲金额低于 MinNotional
+	s.MaxEntryPrice = f(0.99)
+	s.HedgeRatio = f(0.1)
+	_, reason, ok = s.hedgeOrder(ctx, session, signal{Symbol: "YES", Price: f(0.6)}, "YES", "NO", orders)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "below the minimal order size")
+
+	_, reason, ok = s.hedgeOrder(ctx, session, signal{Symbol: "OTHER", Price: f(0.6)}, "YES", "NO", orders)
+	assert.False(t, ok)
+	assert.Contains(t, reason, "no complement")
+}
+
+func TestStrategy_DryRunHedgePositions(t *testing.T) {
+	t.Setenv("POLYMARKET_DRYRUN_AUTOFILL", "true")
+	t.Setenv("POLYMARKET_DRYRUN_FILL_INTERVAL", "1h")
+
+	f := fixedpoint.NewFromFloat
+	ctx := context.Background()
+
+	markets := types.MarketMap{
+		"YES": {Symbol: "YES", LocalSymbol: "111111111111", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01)},
+		"NO":  {Symbol: "NO", LocalSymbol: "222222222222", QuoteCurrency: "USDC", TickSize: f(0.01), StepSize: f(0.01)},
+	}
+	ex := polymarket.New("", "", "", polymarket.WithMarkets(markets))
+	defer ex.Close()
+	session := bbgo.NewExchangeSession("polymarket", ex)
+	session.SetMarkets(markets)
+
+	s := &Strategy{Hedge: true, HedgeRatio: f(0.5), MinEntryPrice: f(0.01), MaxEntryPrice: f(0.99)}
+	sig := signal{Symbol: "YES", Price: f(0.6)}
+	orders := s.ladderOrders(markets["YES"], sig.Price, f(6), 0)
+	hedge, _, ok := s.hedgeOrder(ctx, session, sig, "YES", "NO", orders)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	// 下注订单与对冲单一起提交（批量下单，不经过 router）
+	assert.NoError(t, s.submitOrders(ctx, nil, session, append(orders, hedge)))
+
+	ex.SetReferencePrice("YES", f(0.6))
+	ex.SetReferencePrice("NO", f(0.4))
+
+	summary := ex.DryRunSummaryByTag()[ID]
+	assert.Equal(t, 2, summary.FilledOrders)
+	if assert.Len(t, summary.Positions, 2) {
+		assert.Equal(t, "NO", summary.Positions[0].Symbol)
+		assert.Equal(t, "5", summary.Positions[0].Quantity.String())
+		assert.Equal(t, "YES", summary.Positions[1].Symbol)
+		assert.Equal(t, "10", summary.Positions[1].Quantity.String())
+	}
+}
+
+func TestStrategy_ValidateHedge(t *testing.T) {
+	newStrategy := func(hedge bool, ratio float64) *Strategy {
+		s := &Strategy{
+			SourceSymbol: "BTCUSDT", Interval: types.Interval15m, YesSymbol: "YES", NoSymbol: "NO",
+			Hedge: hedge, HedgeRatio: fixedpoint.NewFromFloat(ratio),
+		}
+		assert.NoError(t, s.Defaults())
+		return s
+	}
+
+	assert.NoError(t, newStrategy(false, 0).Validate())
+	assert.NoError(t, newStrategy(true, 0.5).Validate())
+	assert.NoError(t, newStrategy(true, 1).Validate())
+	assert.ErrorContains(t, newStrategy(true, 0).Validate(), "hedgeRatio is required")
+	assert.ErrorContains(t, newStrategy(true, 1.1).Validate(), "between 0 and 1")
+	assert.ErrorContains(t, newStrategy(false, -0.1).Validate(), "between 0 and 1")
+
+	s := newStrategy(true, 0.5)
+	s.Markets[0].Outcomes = []*OutcomeRule{{Symbol: "YES"}}
+	assert.ErrorContains(t, s.Validate(), "hedge only supports yes/no markets")
+}
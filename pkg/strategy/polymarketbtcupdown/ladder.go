@@ -0,0 +1,141 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 阶梯挂单：把 QuoteAmount 拆成 LadderLevels 档限价买单，
+// 第 i 档（从 0 开始）价格为 entryPrice - i*LadderSpacing，金额按 LadderSizing 分配，
+// 在盘口很薄的预测市场上提高成交概率。价格不大于 0 或低于 MinEntryPrice 的档位会被丢弃，
+// 每档数量不超过 MaxOrderQuantity，避免价格接近 0 时数量暴涨。
+
+// LadderSizing 为阶梯各档的金额分配方式
+type LadderSizing string
+
+const (
+	// LadderSizingEqual 每档金额相同
+	LadderSizingEqual LadderSizing = "equal"
+	// LadderSizingLinear 第 i 档的权重为 i+1，越远离入场价的档位金额越大
+	LadderSizingLinear LadderSizing = "linear"
+)
+
+func (m LadderSizing) Validate() error {
+	switch m {
+	case "", LadderSizingEqual, LadderSizingLinear:
+		return nil
+	}
+	return fmt.Errorf("unsupported ladderSizing %q, should be one of %q, %q", m, LadderSizingEqual, LadderSizingLinear)
+}
+
+type ladderLevel struct {
+	Price fixedpoint.Value
+	Quote fixedpoint.Value
+}
+
+// buildLadder 计算每档的价格与金额，levels <= 1 时只有入场价一档。
+func buildLadder(entryPrice, quoteAmount fixedpoint.Value, levels int, spacing fixedpoint.Value, sizing LadderSizing) []ladderLevel {
+	if levels <= 1 {
+		return []ladderLevel{{Price: entryPrice, Quote: quoteAmount}}
+	}
+
+	var prices, weights []fixedpoint.Value
+	totalWeight := fixedpoint.Zero
+	for i := 0; i < levels; i++ {
+		price := entryPrice.Sub(spacing.Mul(fixedpoint.NewFromInt(int64(i))))
+		if price.Sign() <= 0 {
+			break
+		}
+
+		weight := fixedpoint.One
+		if sizing == LadderSizingLinear {
+			weight = fixedpoint.NewFromInt(int64(i + 1))
+		}
+
+		prices = append(prices, price)
+		weights = append(weights, weight)
+		totalWeight = totalWeight.Add(weight)
+	}
+
+	ladder := make([]ladderLevel, len(prices))
+	for i, price := range prices {
+		ladder[i] = ladderLevel{
+			Price: price,
+			Quote: quoteAmount.Mul(weights[i]).Div(totalWeight),
+		}
+	}
+	return ladder
+}
+
+// ladderOrders 把下注金额按阶梯拆成限价买单，每档按 market 的 tick/step 调整成合法的价格与数量，
+// 数量按 feeRateBps 计入手续费，每档的成交金额加手续费不超过该档的金额；
+// 调整后低于 MinQuantity / MinNotional 的档位会被丢弃。
+func (s *Strategy) ladderOrders(market types.Market, entryPrice, quoteAmount fixedpoint.Value, feeRateBps int) []types.SubmitOrder {
+	ladder := buildLadder(entryPrice, quoteAmount, s.LadderLevels, s.LadderSpacing, s.LadderSizing)
+
+	var orders []types.SubmitOrder
+	for _, level := range ladder {
+		if !s.entryPriceInRange(level.Price) {
+			log.Debugf("skip %s ladder level at %s: price is outside [%s, %s]",
+				market.Symbol, level.Price.String(), s.MinEntryPrice.String(), s.MaxEntryPrice.String())
+			continue
+		}
+
+		snapped, ok := polymarket.SnapOrderWithFee(market, level.Price, level.Quote, feeRateBps)
+		if ok && s.MaxOrderQuantity.Sign() > 0 && snapped.Quantity.Compare(s.MaxOrderQuantity) > 0 {
+			// 按比例缩小金额后重新调整，数量按 step 截断后不超过 MaxOrderQuantity
+			quote := level.Quote.Mul(s.MaxOrderQuantity).Div(snapped.Quantity)
+			snapped, ok = polymarket.SnapOrderWithFee(market, level.Price, quote, feeRateBps)
+		}
+		if !ok {
+			log.Debugf("skip %s ladder level at %s: %s USDC is below the minimal order size",
+				market.Symbol, level.Price.String(), level.Quote.String())
+			continue
+		}
+
+		orders = append(orders, types.SubmitOrder{
+			Symbol:      market.Symbol,
+			Market:      market,
+			Side:        types.SideTypeBuy,
+			Type:        types.OrderTypeLimit,
+			Price:       snapped.Price,
+			Quantity:    snapped.Quantity,
+			TimeInForce: types.TimeInForceGTC,
+			Tag:         s.orderTag(),
+		})
+	}
+	return orders
+}
+
+// feeRateBps 返回 symbol 的下单费率，交易端不是 Polymarket 时为 0。
+func feeRateBps(exchange types.Exchange, symbol string) int {
+	if ex, ok := exchange.(*polymarket.Exchange); ok {
+		return ex.FeeRateBps(symbol)
+	}
+	return 0
+}
+
+// submitOrders 提交下注订单：多个订单且交易端是 Polymarket 时走批量下单接口，否则经由 router 逐个提交。
+func (s *Strategy) submitOrders(ctx context.Context, router bbgo.OrderExecutionRouter, session *bbgo.ExchangeSession, orders []types.SubmitOrder) error {
+	ex, ok := session.Exchange.(*polymarket.Exchange)
+	if !ok || len(orders) <= 1 {
+		created, err := router.SubmitOrdersTo(ctx, s.PolymarketSession, orders...)
+		s.trackOrders(created)
+		return err
+	}
+
+	formattedOrders, err := session.FormatOrders(orders)
+	if err != nil {
+		return err
+	}
+
+	// 批量下单部分失败时也记录已经提交的订单
+	created, err := ex.SubmitOrders(ctx, formattedOrders...)
+	s.trackOrders(created)
+	return err
+}
@@ -0,0 +1,174 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestBuildLadder(t *testing.T) {
+	price := fixedpoint.NewFromFloat(0.5)
+	quote := fixedpoint.NewFromFloat(12)
+	spacing := fixedpoint.NewFromFloat(0.02)
+
+	t.Run("single", func(t *testing.T) {
+		ladder := buildLadder(price, quote, 0, spacing, LadderSizingEqual)
+		assert.Equal(t, []ladderLevel{{Price: price, Quote: quote}}, ladder)
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		ladder := buildLadder(price, quote, 3, spacing, LadderSizingEqual)
+		if assert.Len(t, ladder, 3) {
+			assert.Equal(t, "0.5", ladder[0].Price.String())
+			assert.Equal(t, "0.48", ladder[1].Price.String())
+			assert.Equal(t, "0.46", ladder[2].Price.String())
+			for _, level := range ladder {
+				assert.Equal(t, "4", level.Quote.String())
+			}
+		}
+	})
+
+	t.Run("linear", func(t *testing.T) {
+		ladder := buildLadder(price, quote, 3, spacing, LadderSizingLinear)
+		if assert.Len(t, ladder, 3) {
+			assert.Equal(t, "2", ladder[0].Quote.String())
+			assert.Equal(t, "4", ladder[1].Quote.String())
+			assert.Equal(t, "6", ladder[2].Quote.String())
+		}
+	})
+
+	t.Run("drop non-positive prices", func(t *testing.T) {
+		ladder := buildLadder(fixedpoint.NewFromFloat(0.05), quote, 5, spacing, LadderSizingEqual)
+		if assert.Len(t, ladder, 3) {
+			assert.Equal(t, "0.01", ladder[2].Price.String())
+			assert.Equal(t, "4", ladder[2].Quote.String())
+		}
+	})
+}
+
+func TestStrategy_LadderOrders(t *testing.T) {
+	s := &Strategy{
+		LadderLevels:  3,
+		LadderSpacing: fixedpoint.NewFromFloat(0.1),
+		LadderSizing:  LadderSizingEqual,
+	}
+	market := types.Market{
+		Symbol:      "PM_YES",
+		TickSize:    fixedpoint.NewFromFloat(0.01),
+		StepSize:    fixedpoint.NewFromFloat(0.01),
+		MinNotional: fixedpoint.NewFromFloat(1),
+	}
+
+	// 价格按 tick 向下取整（0.503 -> 0.5），数量按 step 截断，每档 1.1 USDC
+	orders := s.ladderOrders(market, fixedpoint.NewFromFloat(0.503), fixedpoint.NewFromFloat(3.3), 0)
+	if assert.Len(t, orders, 3) {
+		assert.Equal(t, "0.5", orders[0].Price.String())
+		assert.Equal(t, "2.2", orders[0].Quantity.String())
+		assert.Equal(t, "0.4", orders[1].Price.String())
+		assert.Equal(t, "2.75", orders[1].Quantity.String())
+		assert.Equal(t, "0.3", orders[2].Price.String())
+		assert.Equal(t, "3.66", orders[2].Quantity.String())
+		for _, o := range orders {
+			assert.Equal(t, "PM_YES", o.Symbol)
+			assert.Equal(t, ID, o.Tag)
+		}
+	}
+
+	// 第三档 0.3 * 3.33 低于 MinNotional 被丢弃
+	orders = s.ladderOrders(market, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(3), 0)
+	assert.Len(t, orders, 2)
+
+	// 每档金额都低于 MinNotional 时全部丢弃
+	orders = s.ladderOrders(market, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(2), 0)
+	assert.Empty(t, orders)
+}
+
+func TestStrategy_LadderOrdersWithFee(t *testing.T) {
+	s := &Strategy{LadderLevels: 2, LadderSpacing: fixedpoint.NewFromFloat(0.1), LadderSizing: LadderSizingEqual}
+	market := types.Market{
+		Symbol:   "PM_YES",
+		TickSize: fixedpoint.NewFromFloat(0.01),
+		StepSize: fixedpoint.NewFromFloat(0.01),
+	}
+
+	feeRate := fixedpoint.NewFromFloat(0.02)
+	quoteAmount := fixedpoint.NewFromFloat(10)
+	orders := s.ladderOrders(market, fixedpoint.NewFromFloat(0.5), quoteAmount, 200)
+	if !assert.Len(t, orders, 2) {
+		return
+	}
+
+	// 每档 5 USDC：0.5 的单位成本 0.51，0.4 的单位成本 0.408
+	assert.Equal(t, "9.8", orders[0].Quantity.String())
+	assert.Equal(t, "12.25", orders[1].Quantity.String())
+
+	total := fixedpoint.Zero
+	for _, o := range orders {
+		fee := feeRate.Mul(fixedpoint.Min(o.Price, fixedpoint.One.Sub(o.Price))).Mul(o.Quantity)
+		cost := o.Price.Mul(o.Quantity).Add(fee)
+		assert.LessOrEqual(t, cost.Compare(fixedpoint.NewFromFloat(5)), 0)
+		// 误差不超过一个 step 的成本
+		assert.Greater(t, cost.Add(o.Price.Mul(market.StepSize).Mul(fixedpoint.NewFromFloat(1.02))).Compare(fixedpoint.NewFromFloat(5)), 0)
+		total = total.Add(cost)
+	}
+	assert.LessOrEqual(t, total.Compare(quoteAmount), 0)
+}
+
+func TestStrategy_LadderOrdersPriceBoundaries(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	market := types.Market{
+		Symbol:   "PM_YES",
+		TickSize: f(0.001),
+		StepSize: f(0.01),
+	}
+
+	s := &Strategy{MinEntryPrice: f(0.01), MaxEntryPrice: f(0.99), MaxOrderQuantity: f(100)}
+
+	// 0.5：不受限制
+	orders := s.ladderOrders(market, f(0.5), f(5), 0)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "10", orders[0].Quantity.String())
+	}
+
+	// 0.001：数量会暴涨到 5000，低于 MinEntryPrice 直接丢弃
+	assert.Empty(t, s.ladderOrders(market, f(0.001), f(5), 0))
+
+	// 0.999：高于 MaxEntryPrice
+	assert.Empty(t, s.ladderOrders(market, f(0.999), f(5), 0))
+
+	// 0.02：250 份超过 MaxOrderQuantity，按 100 份下单
+	orders = s.ladderOrders(market, f(0.02), f(5), 0)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "100", orders[0].Quantity.String())
+		assert.Equal(t, "0.02", orders[0].Price.String())
+	}
+
+	// 阶梯中低于 MinEntryPrice 的档位被丢弃
+	s.LadderLevels, s.LadderSpacing, s.LadderSizing = 3, f(0.01), LadderSizingEqual
+	orders = s.ladderOrders(market, f(0.025), f(3), 0)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, "0.025", orders[0].Price.String())
+		assert.Equal(t, "0.015", orders[1].Price.String())
+		assert.Equal(t, "66.66", orders[1].Quantity.String())
+	}
+}
+
+func TestStrategy_OrderTag(t *testing.T) {
+	s := &Strategy{PolymarketSession: "polymarket"}
+	assert.Equal(t, ID, s.orderTag())
+	assert.Equal(t, ID+":exit", s.exitTag())
+	assert.Equal(t, ID+":polymarket", s.InstanceID())
+
+	s.Tag = "variant-a"
+	assert.Equal(t, "variant-a:exit", s.exitTag())
+	assert.Equal(t, ID+":polymarket:variant-a", s.InstanceID())
+
+	market := types.Market{Symbol: "PM_YES", TickSize: fixedpoint.NewFromFloat(0.01), StepSize: fixedpoint.NewFromFloat(0.01)}
+	orders := s.ladderOrders(market, fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(5), 0)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "variant-a", orders[0].Tag)
+	}
+}
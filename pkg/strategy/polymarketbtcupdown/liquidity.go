@@ -0,0 +1,46 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 按流动性限制下注金额：MaxLiquidityRatio > 0 时下注前查询目标 symbol 的盘口深度（polymarket.Exchange.QueryDepth），
+// 下注金额不超过下单价格以内卖盘总金额的 MaxLiquidityRatio 倍，避免大单把价格推高或长时间挂单不成交。
+// - 卖盘总金额为价格不高于信号下单价格的卖单 price × size 之和（polymarket.NotionalWithinPrice）
+// - 下单价格以内没有卖盘、或查询盘口失败时不下注
+// - 不是 Polymarket 的 session 不限制
+
+// capToLiquidity 按盘口深度限制下注金额，返回限制后的金额；不能下注时返回原因。
+func (s *Strategy) capToLiquidity(ctx context.Context, session *bbgo.ExchangeSession, sig signal, quoteAmount fixedpoint.Value) (fixedpoint.Value, string, bool) {
+	if s.MaxLiquidityRatio.Sign() <= 0 {
+		return quoteAmount, "", true
+	}
+
+	ex, ok := session.Exchange.(*polymarket.Exchange)
+	if !ok {
+		return quoteAmount, "", true
+	}
+
+	book, err := ex.QueryDepth(ctx, sig.Symbol)
+	if err != nil {
+		return fixedpoint.Zero, fmt.Sprintf("query %s depth failed: %v", sig.Symbol, err), false
+	}
+
+	_, notional := polymarket.NotionalWithinPrice(book, types.SideTypeBuy, sig.Price)
+	if notional.Sign() <= 0 {
+		return fixedpoint.Zero, fmt.Sprintf("no %s liquidity at or below price %s", sig.Symbol, sig.Price.String()), false
+	}
+
+	if limit := notional.Mul(s.MaxLiquidityRatio); quoteAmount.Compare(limit) > 0 {
+		log.Infof("cap %s quoteAmount %s to %s (%s of available liquidity %s)",
+			sig.Symbol, quoteAmount.String(), limit.String(), s.MaxLiquidityRatio.String(), notional.String())
+		return limit, "", true
+	}
+	return quoteAmount, "", true
+}
@@ -0,0 +1,68 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_CapToLiquidity(t *testing.T) {
+	f := fixedpoint.NewFromFloat
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token_id") == "222222222222" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"bids": [{"price": "0.4", "size": "10"}], "asks": [{"price": "0.5", "size": "10"}, {"price": "0.55", "size": "20"}, {"price": "0.6", "size": "100"}]}`))
+	}))
+	defer server.Close()
+
+	ex := polymarket.New("", "", "",
+		polymarket.WithBaseURL(server.URL),
+		polymarket.WithMarkets(types.MarketMap{
+			"YES": {LocalSymbol: "111111111111", QuoteCurrency: "USDC"},
+			"NO":  {LocalSymbol: "222222222222", QuoteCurrency: "USDC"},
+		}),
+	)
+	defer ex.Close()
+	session := bbgo.NewExchangeSession("polymarket", ex)
+
+	s := &Strategy{}
+	sig := signal{Symbol: "YES", BetUp: true, Price: f(0.55)}
+
+	// 没有配置时不限制，也不查询盘口
+	quote, _, ok := s.capToLiquidity(ctx, session, sig, f(100))
+	assert.True(t, ok)
+	assert.Equal(t, "100", quote.String())
+
+	// 0.55 以内的卖盘金额：0.5 x 10 + 0.55 x 20 = 16
+	s.MaxLiquidityRatio = f(0.5)
+	quote, _, ok = s.capToLiquidity(ctx, session, sig, f(100))
+	if assert.True(t, ok) {
+		assert.InDelta(t, 8, quote.Float64(), 1e-6)
+	}
+
+	quote, _, ok = s.capToLiquidity(ctx, session, sig, f(5))
+	if assert.True(t, ok) {
+		assert.Equal(t, "5", quote.String())
+	}
+
+	sig.Price = f(0.45)
+	_, reason, ok := s.capToLiquidity(ctx, session, sig, f(5))
+	assert.False(t, ok)
+	assert.Contains(t, reason, "no YES liquidity")
+
+	_, reason, ok = s.capToLiquidity(ctx, session, signal{Symbol: "NO", Price: f(0.5)}, f(5))
+	assert.False(t, ok)
+	assert.Contains(t, reason, "depth failed")
+}
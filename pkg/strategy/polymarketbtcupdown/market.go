@@ -0,0 +1,128 @@
+package polymarketbtcupdown
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// MarketConfig 描述一组“行情源 K 线 → Polymarket YES/NO”的映射，例如 BTCUSDT 15m 或 ETHUSDT 1h。
+type MarketConfig struct {
+	// SourceSymbol 为 Binance 的 symbol
+	SourceSymbol string `json:"sourceSymbol" yaml:"sourceSymbol"`
+
+	// Interval 为 KLine 周期
+	Interval types.Interval `json:"interval" yaml:"interval"`
+
+	// YesSymbol / NoSymbol 为 Polymarket 的交易 symbol
+	YesSymbol string `json:"yesSymbol" yaml:"yesSymbol"`
+	NoSymbol  string `json:"noSymbol" yaml:"noSymbol"`
+
+	// Outcomes 为有序的下注规则，配置后代替 YesSymbol/NoSymbol，见 outcome.go
+	Outcomes []*OutcomeRule `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+
+	// EntryPrice / QuoteAmount 为空时继承策略顶层的配置
+	EntryPrice  fixedpoint.Value `json:"entryPrice" yaml:"entryPrice"`
+	QuoteAmount fixedpoint.Value `json:"quoteAmount" yaml:"quoteAmount"`
+
+	predictions predictionTracker
+
+	// history 为概率模型使用的最近收盘 K 线，见 model.go
+	history klineHistory
+
+	mu sync.Mutex
+	// activeYesSymbol / activeNoSymbol 为 AutoDiscover 发现的当前窗口 symbol，activeWindow 为该窗口的开始时间
+	activeYesSymbol, activeNoSymbol string
+	activeWindow                    time.Time
+}
+
+// targetSymbols 返回当前要下注的 YES/NO symbol：自动发现过的优先，否则用配置的 symbol。
+func (m *MarketConfig) targetSymbols() (yes, no string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.activeYesSymbol != "" && m.activeNoSymbol != "" {
+		return m.activeYesSymbol, m.activeNoSymbol
+	}
+	return m.YesSymbol, m.NoSymbol
+}
+
+// outcomeRules 返回按顺序匹配的下注规则：配置了 Outcomes 时用 Outcomes，否则由当前的 YES/NO symbol 生成默认规则。
+func (m *MarketConfig) outcomeRules(invert bool) []*OutcomeRule {
+	if len(m.Outcomes) > 0 {
+		return m.Outcomes
+	}
+	yes, no := m.targetSymbols()
+	return defaultOutcomeRules(yes, no, invert)
+}
+
+// symbols 返回可能下注的所有 symbol（去重，按规则顺序）。
+func (m *MarketConfig) symbols() (symbols []string) {
+	seen := make(map[string]bool)
+	for _, r := range m.outcomeRules(false) {
+		if !seen[r.Symbol] {
+			seen[r.Symbol] = true
+			symbols = append(symbols, r.Symbol)
+		}
+	}
+	return symbols
+}
+
+// setActive 把当前窗口切换到 windowStart 开始的 yes/no，只会切换到更晚的窗口，
+// 返回切换前的 symbol 以及是否发生了切换。
+func (m *MarketConfig) setActive(yes, no string, windowStart time.Time) (prevYes, prevNo string, changed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prevYes, prevNo = m.activeYesSymbol, m.activeNoSymbol
+	if !windowStart.After(m.activeWindow) {
+		return prevYes, prevNo, false
+	}
+
+	m.activeYesSymbol, m.activeNoSymbol, m.activeWindow = yes, no, windowStart
+	return prevYes, prevNo, true
+}
+
+// asset 从 SourceSymbol 推出 Polymarket slug 使用的资产名，例如 BTCUSDT -> btc。
+func (m *MarketConfig) asset() string {
+	asset := m.SourceSymbol
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if strings.HasSuffix(asset, quote) {
+			asset = strings.TrimSuffix(asset, quote)
+			break
+		}
+	}
+	return strings.ToLower(asset)
+}
+
+func (m *MarketConfig) String() string {
+	return fmt.Sprintf("%s:%s", m.SourceSymbol, m.Interval)
+}
+
+func (m *MarketConfig) Validate() error {
+	if m.SourceSymbol == "" {
+		return fmt.Errorf("sourceSymbol is required")
+	}
+	if m.Interval == "" {
+		return fmt.Errorf("interval is required")
+	}
+	if len(m.Outcomes) == 0 && (m.YesSymbol == "" || m.NoSymbol == "") {
+		return fmt.Errorf("yesSymbol/noSymbol or outcomes is required")
+	}
+	for i, r := range m.Outcomes {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("outcomes[%d]: %w", i, err)
+		}
+	}
+	if m.EntryPrice.Sign() <= 0 {
+		return fmt.Errorf("entryPrice must be positive")
+	}
+	if m.QuoteAmount.Sign() <= 0 {
+		return fmt.Errorf("quoteAmount must be positive")
+	}
+	return nil
+}
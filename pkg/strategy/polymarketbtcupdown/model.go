@@ -0,0 +1,206 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 隐含概率模型：用最近的 Binance 收盘 K 线特征估计下一根 K 线上涨（YES）的概率，与 Polymarket 的实时价格（市场的隐含概率）比较，
+// 只在 Polymarket 相对模型定价偏低时下注（EdgeThreshold 与按优势放大金额见 edge.go）。
+// - ProbabilityModel 为可替换的接口，默认为配置的线性模型 LinearModel，代码中可以用 SetProbabilityModel 替换
+// - 特征：momentum 为窗口内的收益率（第一根开盘到最后一根收盘），volatility 为每根 K 线收益率 close/open-1 的标准差
+// - LinearModel：p = intercept + momentumWeight * momentum + volatilityWeight * volatility，截断到 [0, 1]
+// - 每个市场保留最近 Lookback 根收盘 K 线，启动时通过 QueryKLines 预加载；K 线不足时不下注
+// - 买 NO 时模型概率为 1 - p；outcomes 规则没有 up/down 方向，不支持模型
+
+// KLineFeatures 为模型使用的 K 线特征
+type KLineFeatures struct {
+	Momentum   float64
+	Volatility float64
+}
+
+// computeFeatures 计算按时间升序的 klines 的特征，没有 K 线或开盘价无效时返回 false。
+func computeFeatures(klines []types.KLine) (KLineFeatures, bool) {
+	if len(klines) == 0 {
+		return KLineFeatures{}, false
+	}
+
+	first := klines[0].Open.Float64()
+	if first <= 0 {
+		return KLineFeatures{}, false
+	}
+
+	returns := make([]float64, 0, len(klines))
+	var sum float64
+	for _, k := range klines {
+		open := k.Open.Float64()
+		if open <= 0 {
+			return KLineFeatures{}, false
+		}
+		r := k.Close.Float64()/open - 1
+		returns = append(returns, r)
+		sum += r
+	}
+
+	mean := sum / float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+
+	return KLineFeatures{
+		Momentum:   klines[len(klines)-1].Close.Float64()/first - 1,
+		Volatility: math.Sqrt(variance / float64(len(returns))),
+	}, true
+}
+
+// ProbabilityModel 把最近的收盘 K 线映射为下一根 K 线上涨的隐含概率
+type ProbabilityModel interface {
+	// Lookback 为模型需要的收盘 K 线数量
+	Lookback() int
+
+	// Probability 返回上涨的概率（0~1），klines 按时间升序，数据不足时返回 false
+	Probability(klines []types.KLine) (fixedpoint.Value, bool)
+}
+
+// LinearModel 为默认的线性模型
+type LinearModel struct {
+	// Window 为计算特征的 K 线数量（默认 4）
+	Window int `json:"window" yaml:"window"`
+
+	// Intercept 为没有动量时的概率（默认 0.5）
+	Intercept fixedpoint.Value `json:"intercept" yaml:"intercept"`
+
+	// MomentumWeight / VolatilityWeight 为特征的系数，例如 momentumWeight 10 表示窗口内上涨 1% 时概率 +0.1
+	MomentumWeight   fixedpoint.Value `json:"momentumWeight" yaml:"momentumWeight"`
+	VolatilityWeight fixedpoint.Value `json:"volatilityWeight" yaml:"volatilityWeight"`
+}
+
+var _ ProbabilityModel = (*LinearModel)(nil)
+
+func (l *LinearModel) Defaults() {
+	if l.Window == 0 {
+		l.Window = 4
+	}
+	if l.Intercept.IsZero() {
+		l.Intercept = fixedpoint.NewFromFloat(0.5)
+	}
+}
+
+func (l *LinearModel) Validate() error {
+	if l.Window <= 0 {
+		return fmt.Errorf("window must be positive")
+	}
+	if l.Intercept.Sign() < 0 || l.Intercept.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("intercept must be between 0 and 1")
+	}
+	return nil
+}
+
+func (l *LinearModel) Lookback() int {
+	return l.Window
+}
+
+func (l *LinearModel) Probability(klines []types.KLine) (fixedpoint.Value, bool) {
+	if len(klines) < l.Window {
+		return fixedpoint.Zero, false
+	}
+
+	features, ok := computeFeatures(klines[len(klines)-l.Window:])
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	p := l.Intercept.Float64() +
+		l.MomentumWeight.Float64()*features.Momentum +
+		l.VolatilityWeight.Float64()*features.Volatility
+	return fixedpoint.NewFromFloat(math.Min(math.Max(p, 0), 1)), true
+}
+
+// klineHistory 保存一个市场最近的收盘 K 线
+type klineHistory struct {
+	mu     sync.Mutex
+	klines []types.KLine
+}
+
+// add 追加一根收盘 K 线，只保留最近 limit 根；不晚于最后一根的 K 线（重复推送）会被忽略。
+func (h *klineHistory) add(kline types.KLine, limit int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n := len(h.klines); n > 0 && !kline.StartTime.Time().After(h.klines[n-1].StartTime.Time()) {
+		return
+	}
+
+	h.klines = append(h.klines, kline)
+	if len(h.klines) > limit {
+		h.klines = append([]types.KLine(nil), h.klines[len(h.klines)-limit:]...)
+	}
+}
+
+// last 返回最近 n 根 K 线的副本。
+func (h *klineHistory) last(n int) []types.KLine {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.klines) < n {
+		n = len(h.klines)
+	}
+	return append([]types.KLine(nil), h.klines[len(h.klines)-n:]...)
+}
+
+// SetProbabilityModel 替换隐含概率模型（代替配置的 Model），nil 时恢复使用配置的 Model。
+func (s *Strategy) SetProbabilityModel(model ProbabilityModel) {
+	s.probabilityModel = model
+}
+
+// model 返回使用的概率模型，没有配置时返回 nil。
+func (s *Strategy) model() ProbabilityModel {
+	if s.probabilityModel != nil {
+		return s.probabilityModel
+	}
+	if s.Model != nil {
+		return s.Model
+	}
+	return nil
+}
+
+// recordKLine 在使用模型时保存收盘 K 线。
+func (s *Strategy) recordKLine(m *MarketConfig, kline types.KLine) {
+	if model := s.model(); model != nil {
+		m.history.add(kline, model.Lookback())
+	}
+}
+
+// preloadKLines 启动时查询每个市场最近 Lookback 根已收盘的 K 线，不必等模型的窗口填满才能下注。
+func (s *Strategy) preloadKLines(ctx context.Context, session *bbgo.ExchangeSession) {
+	model := s.model()
+	if model == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, m := range s.Markets {
+		klines, err := session.Exchange.QueryKLines(ctx, m.SourceSymbol, m.Interval, types.KLineQueryOptions{
+			Limit:   model.Lookback() + 1,
+			EndTime: &now,
+		})
+		if err != nil {
+			log.WithError(err).Warnf("failed to preload %s klines for probability model", m.String())
+			continue
+		}
+
+		for _, k := range klines {
+			if k.EndTime.Time().Before(now) {
+				m.history.add(k, model.Lookback())
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestKLines(t0 time.Time, prices ...float64) (klines []types.KLine) {
+	for i := 1; i < len(prices); i++ {
+		klines = append(klines, newTestKLine(t0.Add(time.Duration(i-1)*15*time.Minute), prices[i-1], prices[i]))
+	}
+	return klines
+}
+
+func TestComputeFeatures(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	features, ok := computeFeatures(newTestKLines(t0, 100, 101, 102.01))
+	if assert.True(t, ok) {
+		assert.InDelta(t, 0.0201, features.Momentum, 1e-9)
+		// 每根 K 线的收益率都是 1%，没有波动
+		assert.InDelta(t, 0, features.Volatility, 1e-9)
+	}
+
+	features, ok = computeFeatures(newTestKLines(t0, 100, 102, 102))
+	if assert.True(t, ok) {
+		assert.InDelta(t, 0.02, features.Momentum, 1e-9)
+		assert.InDelta(t, 0.01, features.Volatility, 1e-9)
+	}
+
+	_, ok = computeFeatures(nil)
+	assert.False(t, ok)
+}
+
+func TestLinearModel_Probability(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	model := &LinearModel{Window: 2, MomentumWeight: fixedpoint.NewFromFloat(10)}
+	model.Defaults()
+	assert.NoError(t, model.Validate())
+
+	// 只使用最近 Window 根 K 线：100 → 102 上涨 2%
+	p, ok := model.Probability(newTestKLines(t0, 90, 100, 101, 102))
+	if assert.True(t, ok) {
+		assert.InDelta(t, 0.7, p.Float64(), 1e-6)
+	}
+
+	// 截断到 [0, 1]
+	p, ok = model.Probability(newTestKLines(t0, 100, 90, 80))
+	if assert.True(t, ok) {
+		assert.Equal(t, "0", p.String())
+	}
+
+	_, ok = model.Probability(newTestKLines(t0, 100, 101))
+	assert.False(t, ok)
+
+	assert.Error(t, (&LinearModel{Window: 2, Intercept: fixedpoint.NewFromFloat(1.5)}).Validate())
+}
+
+func TestKLineHistory(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := newTestKLines(t0, 100, 101, 102, 103)
+
+	var h klineHistory
+	for _, k := range klines {
+		h.add(k, 2)
+	}
+	// 重复推送的 K 线被忽略
+	h.add(klines[2], 2)
+
+	last := h.last(5)
+	if assert.Len(t, last, 2) {
+		assert.Equal(t, klines[1].StartTime, last[0].StartTime)
+		assert.Equal(t, klines[2].StartTime, last[1].StartTime)
+	}
+}
@@ -0,0 +1,132 @@
+package polymarketbtcupdown
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 下注目标的选择规则：Outcomes 为有序的“K 线条件 → outcome symbol”规则，收盘 K 线按顺序匹配，第一条命中的规则决定买入的 symbol，
+// 都不命中时不下注。这样同一个策略可以对标量 / 多结果市场下注，例如按收盘价区间买入 “BTC > $X” 的不同档位。
+// 没有配置 Outcomes 时由 YesSymbol/NoSymbol 生成两条默认规则：up 买 YES、down 买 NO（Invert 时反过来）。
+
+// OutcomeDirection 为 K 线方向：up 为收盘 > 开盘，down 为收盘 <= 开盘（十字星视为 down，与默认规则一致）
+type OutcomeDirection string
+
+const (
+	OutcomeDirectionUp   OutcomeDirection = "up"
+	OutcomeDirectionDown OutcomeDirection = "down"
+)
+
+func (d OutcomeDirection) Validate() error {
+	switch d {
+	case "", OutcomeDirectionUp, OutcomeDirectionDown:
+		return nil
+	}
+	return fmt.Errorf("unsupported direction %q, should be one of %q, %q", d, OutcomeDirectionUp, OutcomeDirectionDown)
+}
+
+func (d OutcomeDirection) matches(kline types.KLine) bool {
+	switch d {
+	case OutcomeDirectionUp:
+		return kline.Close.Compare(kline.Open) > 0
+	case OutcomeDirectionDown:
+		return kline.Close.Compare(kline.Open) <= 0
+	}
+	return true
+}
+
+// OutcomeRule 为一条下注规则，所有设置了的条件都满足时命中，没有设置任何条件的规则总是命中（可以作为兜底）。
+type OutcomeRule struct {
+	// Direction 为 K 线方向条件（up/down），为空时不限
+	Direction OutcomeDirection `json:"direction,omitempty" yaml:"direction,omitempty"`
+
+	// CloseAbove / CloseBelow 要求收盘价 > CloseAbove、< CloseBelow
+	CloseAbove *fixedpoint.Value `json:"closeAbove,omitempty" yaml:"closeAbove,omitempty"`
+	CloseBelow *fixedpoint.Value `json:"closeBelow,omitempty" yaml:"closeBelow,omitempty"`
+
+	// ChangeAbove / ChangeBelow 要求涨跌幅 (close - open) / open > ChangeAbove、< ChangeBelow，例如 0.001 表示 0.1%
+	ChangeAbove *fixedpoint.Value `json:"changeAbove,omitempty" yaml:"changeAbove,omitempty"`
+	ChangeBelow *fixedpoint.Value `json:"changeBelow,omitempty" yaml:"changeBelow,omitempty"`
+
+	// Symbol 为命中时买入的 Polymarket symbol
+	Symbol string `json:"symbol" yaml:"symbol"`
+
+	// Predict 为买入该 outcome 所押的下一根 K 线方向（up/down），用于统计预测命中率，为空时不统计
+	Predict OutcomeDirection `json:"predict,omitempty" yaml:"predict,omitempty"`
+}
+
+func (r *OutcomeRule) String() string {
+	return fmt.Sprintf("%s(direction=%s)", r.Symbol, r.Direction)
+}
+
+func (r *OutcomeRule) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if err := r.Direction.Validate(); err != nil {
+		return err
+	}
+	if err := r.Predict.Validate(); err != nil {
+		return fmt.Errorf("predict: %w", err)
+	}
+	if r.CloseAbove != nil && r.CloseBelow != nil && r.CloseAbove.Compare(*r.CloseBelow) >= 0 {
+		return fmt.Errorf("closeAbove must be lower than closeBelow")
+	}
+	if r.ChangeAbove != nil && r.ChangeBelow != nil && r.ChangeAbove.Compare(*r.ChangeBelow) >= 0 {
+		return fmt.Errorf("changeAbove must be lower than changeBelow")
+	}
+	return nil
+}
+
+// Matches 检查收盘 K 线是否满足规则的所有条件。
+func (r *OutcomeRule) Matches(kline types.KLine) bool {
+	if !r.Direction.matches(kline) {
+		return false
+	}
+	if r.CloseAbove != nil && kline.Close.Compare(*r.CloseAbove) <= 0 {
+		return false
+	}
+	if r.CloseBelow != nil && kline.Close.Compare(*r.CloseBelow) >= 0 {
+		return false
+	}
+
+	if r.ChangeAbove != nil || r.ChangeBelow != nil {
+		if kline.Open.Sign() <= 0 {
+			return false
+		}
+		change := kline.Close.Sub(kline.Open).Div(kline.Open)
+		if r.ChangeAbove != nil && change.Compare(*r.ChangeAbove) <= 0 {
+			return false
+		}
+		if r.ChangeBelow != nil && change.Compare(*r.ChangeBelow) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matchOutcome 返回第一条命中的规则，都不命中时返回 nil。
+func matchOutcome(rules []*OutcomeRule, kline types.KLine) *OutcomeRule {
+	for _, r := range rules {
+		if r.Matches(kline) {
+			return r
+		}
+	}
+	return nil
+}
+
+// defaultOutcomeRules 由 YES/NO symbol 生成默认规则，映射关系见 betTarget。
+func defaultOutcomeRules(yesSymbol, noSymbol string, invert bool) []*OutcomeRule {
+	var rules []*OutcomeRule
+	for _, direction := range []OutcomeDirection{OutcomeDirectionUp, OutcomeDirectionDown} {
+		symbol, betUp := betTarget(direction == OutcomeDirectionUp, invert, yesSymbol, noSymbol)
+		predict := OutcomeDirectionDown
+		if betUp {
+			predict = OutcomeDirectionUp
+		}
+		rules = append(rules, &OutcomeRule{Direction: direction, Symbol: symbol, Predict: predict})
+	}
+	return rules
+}
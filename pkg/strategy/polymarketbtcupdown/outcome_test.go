@@ -0,0 +1,145 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestOutcomeRule_Matches(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) *fixedpoint.Value {
+		p := fixedpoint.NewFromFloat(v)
+		return &p
+	}
+
+	tests := []struct {
+		name  string
+		rule  OutcomeRule
+		kline types.KLine
+		want  bool
+	}{
+		{name: "no condition", rule: OutcomeRule{}, kline: newTestKLine(t0, 100, 90), want: true},
+		{name: "up", rule: OutcomeRule{Direction: OutcomeDirectionUp}, kline: newTestKLine(t0, 100, 101), want: true},
+		{name: "doji is not up", rule: OutcomeRule{Direction: OutcomeDirectionUp}, kline: newTestKLine(t0, 100, 100)},
+		{name: "doji is down", rule: OutcomeRule{Direction: OutcomeDirectionDown}, kline: newTestKLine(t0, 100, 100), want: true},
+		{name: "close above", rule: OutcomeRule{CloseAbove: price(100)}, kline: newTestKLine(t0, 90, 100.5), want: true},
+		{name: "close at the lower bound", rule: OutcomeRule{CloseAbove: price(100)}, kline: newTestKLine(t0, 90, 100)},
+		{name: "close in range", rule: OutcomeRule{CloseAbove: price(100), CloseBelow: price(110)}, kline: newTestKLine(t0, 90, 105), want: true},
+		{name: "close above range", rule: OutcomeRule{CloseAbove: price(100), CloseBelow: price(110)}, kline: newTestKLine(t0, 90, 110)},
+		{name: "change above", rule: OutcomeRule{ChangeAbove: price(0.01)}, kline: newTestKLine(t0, 100, 102), want: true},
+		{name: "change not above", rule: OutcomeRule{ChangeAbove: price(0.01)}, kline: newTestKLine(t0, 100, 101)},
+		{name: "negative change below", rule: OutcomeRule{ChangeBelow: price(-0.01)}, kline: newTestKLine(t0, 100, 98), want: true},
+		{name: "direction and close", rule: OutcomeRule{Direction: OutcomeDirectionDown, CloseAbove: price(100)}, kline: newTestKLine(t0, 110, 105), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Matches(tt.kline))
+		})
+	}
+}
+
+func TestOutcomeRule_Validate(t *testing.T) {
+	price := func(v float64) *fixedpoint.Value {
+		p := fixedpoint.NewFromFloat(v)
+		return &p
+	}
+
+	assert.NoError(t, (&OutcomeRule{Symbol: "A", Direction: OutcomeDirectionUp, Predict: OutcomeDirectionDown}).Validate())
+	assert.Error(t, (&OutcomeRule{}).Validate())
+	assert.Error(t, (&OutcomeRule{Symbol: "A", Direction: "sideways"}).Validate())
+	assert.Error(t, (&OutcomeRule{Symbol: "A", Predict: "sideways"}).Validate())
+	assert.Error(t, (&OutcomeRule{Symbol: "A", CloseAbove: price(110), CloseBelow: price(100)}).Validate())
+	assert.Error(t, (&OutcomeRule{Symbol: "A", ChangeAbove: price(0.01), ChangeBelow: price(0.01)}).Validate())
+}
+
+func TestStrategy_DecideOutcomeBuckets(t *testing.T) {
+	var config struct {
+		Outcomes []*OutcomeRule `yaml:"outcomes"`
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+outcomes:
+  - closeAbove: 110000
+    symbol: PM_BTC_ABOVE_110K_YES
+    predict: up
+  - closeAbove: 100000
+    symbol: PM_BTC_ABOVE_100K_YES
+  - symbol: PM_BTC_ABOVE_100K_NO
+`), &config))
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entryPrice := func(string) fixedpoint.Value { return fixedpoint.NewFromFloat(0.5) }
+	s := &Strategy{}
+
+	sig, reason, ok := s.decide(newTestKLine(t0, 109000, 111000), config.Outcomes, entryPrice, fixedpoint.NewFromFloat(5))
+	if assert.True(t, ok, reason) {
+		assert.Equal(t, "PM_BTC_ABOVE_110K_YES", sig.Symbol)
+		assert.True(t, sig.Predicted)
+		assert.True(t, sig.BetUp)
+	}
+
+	sig, reason, ok = s.decide(newTestKLine(t0, 106000, 105000), config.Outcomes, entryPrice, fixedpoint.NewFromFloat(5))
+	if assert.True(t, ok, reason) {
+		assert.Equal(t, "PM_BTC_ABOVE_100K_YES", sig.Symbol)
+		assert.False(t, sig.Predicted)
+	}
+
+	sig, _, ok = s.decide(newTestKLine(t0, 99000, 98000), config.Outcomes, entryPrice, fixedpoint.NewFromFloat(5))
+	assert.True(t, ok)
+	assert.Equal(t, "PM_BTC_ABOVE_100K_NO", sig.Symbol)
+
+	// 没有兜底规则时不下注
+	_, reason, ok = s.decide(newTestKLine(t0, 99000, 98000), config.Outcomes[:2], entryPrice, fixedpoint.NewFromFloat(5))
+	assert.False(t, ok)
+	assert.Contains(t, reason, "no outcome rule")
+}
+
+func TestStrategy_ValidateOutcomes(t *testing.T) {
+	newStrategy := func() *Strategy {
+		s := &Strategy{Outcomes: []*OutcomeRule{
+			{Direction: OutcomeDirectionUp, Symbol: "A"},
+			{Symbol: "B"},
+		}}
+		assert.NoError(t, s.Defaults())
+		return s
+	}
+
+	s := newStrategy()
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, []string{"A", "B"}, s.polymarketSymbols())
+
+	s = newStrategy()
+	s.Invert = true
+	assert.ErrorContains(t, s.Validate(), "invert")
+
+	s = newStrategy()
+	s.AutoDiscover = true
+	assert.ErrorContains(t, s.Validate(), "autoDiscover")
+
+	s = newStrategy()
+	s.Markets = append(s.Markets, &MarketConfig{
+		SourceSymbol: "ETHUSDT", Interval: types.Interval1h, EntryPrice: s.EntryPrice, QuoteAmount: s.QuoteAmount,
+		Outcomes: []*OutcomeRule{{Symbol: "B"}},
+	})
+	assert.ErrorContains(t, s.Validate(), "share symbol B")
+}
+
+func TestDefaultOutcomeRules(t *testing.T) {
+	rules := defaultOutcomeRules("YES", "NO", false)
+	assert.Equal(t, []*OutcomeRule{
+		{Direction: OutcomeDirectionUp, Symbol: "YES", Predict: OutcomeDirectionUp},
+		{Direction: OutcomeDirectionDown, Symbol: "NO", Predict: OutcomeDirectionDown},
+	}, rules)
+
+	rules = defaultOutcomeRules("YES", "NO", true)
+	assert.Equal(t, []*OutcomeRule{
+		{Direction: OutcomeDirectionUp, Symbol: "NO", Predict: OutcomeDirectionDown},
+		{Direction: OutcomeDirectionDown, Symbol: "YES", Predict: OutcomeDirectionUp},
+	}, rules)
+}
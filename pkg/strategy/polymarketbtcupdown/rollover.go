@@ -0,0 +1,101 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 窗口切换（AutoDiscover）：up/down 市场每个周期换一组 YES/NO token。
+// - 每隔 RolloverCheckInterval 通过 Gamma 查询当前时间所在窗口的市场，窗口变化时切换 targetSymbols，
+//   并把 polymarket session 行情 stream 的订阅从上一个窗口换成新窗口（market channel 在当前连接上增量订阅 / 退订，不重连）
+// - K 线收盘时直接使用为该 K 线的下一个窗口查询到的 symbol 下注，不读取切换中的共享状态；
+//   setActive 只会切换到更晚的窗口，收盘处理与定时刷新的先后顺序不影响结果
+
+// runRolloverLoop 定时检查所有市场的窗口切换，ctx 结束时退出。
+func (s *Strategy) runRolloverLoop(ctx context.Context, session *bbgo.ExchangeSession) {
+	ticker := time.NewTicker(s.RolloverCheckInterval.Duration())
+	defer ticker.Stop()
+
+	s.rollover(ctx, session, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.rollover(ctx, session, now)
+		}
+	}
+}
+
+// rollover 查询 now 所在窗口的市场并切换过去。
+func (s *Strategy) rollover(ctx context.Context, session *bbgo.ExchangeSession, now time.Time) {
+	ex, ok := session.Exchange.(*polymarket.Exchange)
+	if !ok {
+		return
+	}
+
+	for _, m := range s.Markets {
+		um, err := ex.DiscoverUpDownMarket(ctx, m.asset(), m.Interval, now)
+		if err != nil {
+			log.WithError(err).Warnf("failed to discover the current up/down market of %s", m.String())
+			continue
+		}
+		s.activateMarket(session, m, um)
+	}
+}
+
+// activateMarket 把 up/down 市场的 YES/NO market 加入 session，窗口变化时切换 m 的 targetSymbols 并更新行情订阅。
+func (s *Strategy) activateMarket(session *bbgo.ExchangeSession, m *MarketConfig, um *polymarket.UpDownMarket) {
+	s.mu.Lock()
+	if _, ok := session.Market(um.YesSymbol); !ok {
+		markets := session.Markets()
+		merged := make(types.MarketMap, len(markets)+2)
+		for symbol, market := range markets {
+			merged[symbol] = market
+		}
+		merged[um.YesSymbol] = um.YesMarket
+		merged[um.NoSymbol] = um.NoMarket
+		session.SetMarkets(merged)
+	}
+	s.mu.Unlock()
+
+	prevYes, prevNo, changed := m.setActive(um.YesSymbol, um.NoSymbol, um.WindowStart)
+	if !changed {
+		return
+	}
+
+	log.Infof("%s rolled over to up/down market %s: yes=%s no=%s", m.String(), um.Slug, um.YesSymbol, um.NoSymbol)
+
+	if session.MarketDataStream == nil {
+		return
+	}
+	err := session.MarketDataStream.Resubscribe(func(old []types.Subscription) ([]types.Subscription, error) {
+		return swapSubscriptions(old, []string{prevYes, prevNo}, []string{um.YesSymbol, um.NoSymbol}), nil
+	})
+	if err != nil {
+		log.WithError(err).Warnf("failed to resubscribe %s", um.Slug)
+	}
+}
+
+// swapSubscriptions 把 old 中 from 这些 symbol 的订阅换成 to 的 book 订阅。
+func swapSubscriptions(old []types.Subscription, from, to []string) []types.Subscription {
+	removed := make(map[string]bool, len(from))
+	for _, symbol := range from {
+		removed[symbol] = true
+	}
+
+	subs := make([]types.Subscription, 0, len(old)+len(to))
+	for _, sub := range old {
+		if !removed[sub.Symbol] {
+			subs = append(subs, sub)
+		}
+	}
+	for _, symbol := range to {
+		subs = append(subs, types.Subscription{Channel: types.BookChannel, Symbol: symbol})
+	}
+	return subs
+}
@@ -0,0 +1,91 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestSwapSubscriptions(t *testing.T) {
+	old := []types.Subscription{
+		{Channel: types.BookChannel, Symbol: "PM_A_YES"},
+		{Channel: types.BookChannel, Symbol: "PM_A_NO"},
+		{Channel: types.BookChannel, Symbol: "PM_OTHER"},
+	}
+
+	subs := swapSubscriptions(old, []string{"PM_A_YES", "PM_A_NO"}, []string{"PM_B_YES", "PM_B_NO"})
+	var symbols []string
+	for _, sub := range subs {
+		symbols = append(symbols, sub.Symbol)
+	}
+	assert.Equal(t, []string{"PM_OTHER", "PM_B_YES", "PM_B_NO"}, symbols)
+}
+
+func TestStrategy_DefaultIntervals(t *testing.T) {
+	s := &Strategy{Intervals: []types.Interval{types.Interval5m, types.Interval15m}, AutoDiscover: true}
+	assert.NoError(t, s.Defaults())
+	assert.NoError(t, s.Validate())
+	if assert.Len(t, s.Markets, 2) {
+		assert.Equal(t, types.Interval5m, s.Markets[0].Interval)
+		assert.Equal(t, types.Interval15m, s.Markets[1].Interval)
+		assert.Equal(t, "BTCUSDT", s.Markets[1].SourceSymbol)
+	}
+
+	// 多个周期共用同一组 YES/NO 时需要 AutoDiscover
+	s = &Strategy{Intervals: []types.Interval{types.Interval5m, types.Interval15m}}
+	assert.NoError(t, s.Defaults())
+	assert.Error(t, s.Validate())
+
+	s = &Strategy{}
+	assert.NoError(t, s.Defaults())
+	assert.Equal(t, []types.Interval{types.Interval15m}, s.Intervals)
+	assert.Len(t, s.Markets, 1)
+}
+
+func TestStrategy_ActivateMarket(t *testing.T) {
+	session := bbgo.NewExchangeSession("polymarket", polymarket.New("", "", ""))
+	s := &Strategy{}
+	assert.NoError(t, s.Defaults())
+	m := s.Markets[0]
+
+	t0 := time.Date(2025, 10, 15, 8, 0, 0, 0, time.UTC)
+	newUpDownMarket := func(start time.Time, name string) *polymarket.UpDownMarket {
+		return &polymarket.UpDownMarket{
+			Slug:        name,
+			WindowStart: start,
+			WindowEnd:   start.Add(15 * time.Minute),
+			YesSymbol:   name + "_YES",
+			NoSymbol:    name + "_NO",
+			YesMarket:   types.Market{Symbol: name + "_YES"},
+			NoMarket:    types.Market{Symbol: name + "_NO"},
+		}
+	}
+
+	first := newUpDownMarket(t0, "PM_W1")
+	second := newUpDownMarket(t0.Add(15*time.Minute), "PM_W2")
+
+	s.activateMarket(session, m, first)
+	s.activateMarket(session, m, second)
+
+	yes, no := m.targetSymbols()
+	assert.Equal(t, "PM_W2_YES", yes)
+	assert.Equal(t, "PM_W2_NO", no)
+	_, ok := session.Market("PM_W2_NO")
+	assert.True(t, ok)
+
+	var symbols []string
+	for _, sub := range session.MarketDataStream.GetSubscriptions() {
+		symbols = append(symbols, sub.Symbol)
+	}
+	assert.Equal(t, []string{"PM_W2_YES", "PM_W2_NO"}, symbols)
+
+	// 较早窗口的收盘处理晚到时不会切换回去
+	s.activateMarket(session, m, first)
+	yes, _ = m.targetSymbols()
+	assert.Equal(t, "PM_W2_YES", yes)
+}
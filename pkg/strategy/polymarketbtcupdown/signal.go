@@ -0,0 +1,81 @@
+package polymarketbtcupdown
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// signal 为一根收盘 K 线产生的下注决策
+type signal struct {
+	// Up 为收盘 K 线的方向，BetUp 为下注所押的方向（Invert 时两者相反）；
+	// Predicted 为 false 时命中的规则没有设置 predict，BetUp 没有意义，不统计命中率
+	Up, BetUp bool
+	Predicted bool
+
+	Symbol string
+	Side   types.SideType
+	Price  fixedpoint.Value
+	// Quantity 为按入场价折算的总数量 quoteAmount / Price，实际下单数量由 ladderOrders 按 market 精度调整
+	Quantity fixedpoint.Value
+}
+
+// decide 根据收盘 K 线决定下注的 symbol、方向与数量，返回不能下注的原因。
+// rules 按顺序匹配，第一条命中的规则决定买入的 symbol（默认规则见 defaultOutcomeRules），都不命中时不下注；
+// 实体比例低于 MinBodyRatio 时不下注。entryPrice 返回目标 symbol 的下单价格，价格不在 (0, 1) 之间
+// 或超出 [MinEntryPrice, MaxEntryPrice]（为 0 时不限制）时不下注。
+func (s *Strategy) decide(kline types.KLine, rules []*OutcomeRule, entryPrice func(symbol string) fixedpoint.Value, quoteAmount fixedpoint.Value) (signal, string, bool) {
+	if !s.hasEnoughBody(kline) {
+		return signal{}, fmt.Sprintf("candle body is below minBodyRatio %s", s.MinBodyRatio.String()), false
+	}
+
+	sig := signal{
+		Up:   kline.Close.Compare(kline.Open) > 0,
+		Side: types.SideTypeBuy,
+	}
+
+	rule := matchOutcome(rules, kline)
+	if rule == nil {
+		return sig, fmt.Sprintf("no outcome rule matches close %s", kline.Close.String()), false
+	}
+	sig.Symbol = rule.Symbol
+	sig.BetUp, sig.Predicted = rule.Predict == OutcomeDirectionUp, rule.Predict != ""
+
+	sig.Price = entryPrice(sig.Symbol)
+	if sig.Price.Sign() <= 0 || sig.Price.Compare(fixedpoint.One) >= 0 {
+		return sig, fmt.Sprintf("invalid entry price %s of %s", sig.Price.String(), sig.Symbol), false
+	}
+	if !s.entryPriceInRange(sig.Price) {
+		return sig, fmt.Sprintf("entry price %s of %s is outside [%s, %s]",
+			sig.Price.String(), sig.Symbol, s.MinEntryPrice.String(), s.MaxEntryPrice.String()), false
+	}
+	if quoteAmount.Sign() <= 0 {
+		return sig, fmt.Sprintf("invalid quoteAmount %s", quoteAmount.String()), false
+	}
+
+	sig.Quantity = quoteAmount.Div(sig.Price)
+	return sig, "", true
+}
+
+// betTarget 把 K 线方向映射到要买入的 token：默认 up 买 YES、down 买 NO（追涨杀跌）；
+// invert 为 true 时反向下注（fade），up 买 NO、down 买 YES。
+// betUp 为下注所押的方向，用于记录预测命中率。
+func betTarget(up, invert bool, yesSymbol, noSymbol string) (symbol string, betUp bool) {
+	betUp = up != invert
+	if betUp {
+		return yesSymbol, true
+	}
+	return noSymbol, false
+}
+
+// entryPriceInRange 判断价格是否在 [MinEntryPrice, MaxEntryPrice] 之内，边界为 0 时不限制。
+func (s *Strategy) entryPriceInRange(price fixedpoint.Value) bool {
+	if s.MinEntryPrice.Sign() > 0 && price.Compare(s.MinEntryPrice) < 0 {
+		return false
+	}
+	if s.MaxEntryPrice.Sign() > 0 && price.Compare(s.MaxEntryPrice) > 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestBetTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		up, invert bool
+		wantSymbol string
+		wantBetUp  bool
+	}{
+		{name: "up", up: true, wantSymbol: "YES", wantBetUp: true},
+		{name: "down", up: false, wantSymbol: "NO", wantBetUp: false},
+		{name: "fade up", up: true, invert: true, wantSymbol: "NO", wantBetUp: false},
+		{name: "fade down", up: false, invert: true, wantSymbol: "YES", wantBetUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbol, betUp := betTarget(tt.up, tt.invert, "YES", "NO")
+			assert.Equal(t, tt.wantSymbol, symbol)
+			assert.Equal(t, tt.wantBetUp, betUp)
+		})
+	}
+}
+
+func TestStrategy_Decide(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newKLine := func(open, high, low, close float64) types.KLine {
+		k := newTestKLine(t0, open, close)
+		k.High, k.Low = fixedpoint.NewFromFloat(high), fixedpoint.NewFromFloat(low)
+		return k
+	}
+	fixedPrice := func(price float64) func(string) fixedpoint.Value {
+		return func(string) fixedpoint.Value { return fixedpoint.NewFromFloat(price) }
+	}
+
+	tests := []struct {
+		name         string
+		strategy     *Strategy
+		kline        types.KLine
+		entryPrice   func(string) fixedpoint.Value
+		quoteAmount  float64
+		wantOK       bool
+		wantSymbol   string
+		wantBetUp    bool
+		wantQuantity string
+	}{
+		{
+			name:         "up buys yes",
+			kline:        newKLine(100, 112, 98, 110),
+			entryPrice:   fixedPrice(0.5),
+			quoteAmount:  5,
+			wantOK:       true,
+			wantSymbol:   "YES",
+			wantBetUp:    true,
+			wantQuantity: "10",
+		},
+		{
+			name:         "down buys no",
+			kline:        newKLine(110, 112, 98, 100),
+			entryPrice:   fixedPrice(0.4),
+			quoteAmount:  2,
+			wantOK:       true,
+			wantSymbol:   "NO",
+			wantQuantity: "5",
+		},
+		{
+			name:         "doji is down",
+			kline:        newKLine(100, 105, 95, 100),
+			entryPrice:   fixedPrice(0.5),
+			quoteAmount:  5,
+			wantOK:       true,
+			wantSymbol:   "NO",
+			wantQuantity: "10",
+		},
+		{
+			name:        "doji is skipped by minBodyRatio",
+			strategy:    &Strategy{MinBodyRatio: fixedpoint.NewFromFloat(0.3)},
+			kline:       newKLine(100, 105, 95, 100),
+			entryPrice:  fixedPrice(0.5),
+			quoteAmount: 5,
+		},
+		{
+			name:         "invert up buys no",
+			strategy:     &Strategy{Invert: true},
+			kline:        newKLine(100, 112, 98, 110),
+			entryPrice:   fixedPrice(0.5),
+			quoteAmount:  5,
+			wantOK:       true,
+			wantSymbol:   "NO",
+			wantQuantity: "10",
+		},
+		{
+			name:         "invert down buys yes",
+			strategy:     &Strategy{Invert: true},
+			kline:        newKLine(110, 112, 98, 100),
+			entryPrice:   fixedPrice(0.5),
+			quoteAmount:  5,
+			wantOK:       true,
+			wantSymbol:   "YES",
+			wantBetUp:    true,
+			wantQuantity: "10",
+		},
+		{
+			name:        "zero entry price",
+			kline:       newKLine(100, 112, 98, 110),
+			entryPrice:  fixedPrice(0),
+			quoteAmount: 5,
+		},
+		{
+			name:        "entry price not below 1",
+			kline:       newKLine(100, 112, 98, 110),
+			entryPrice:  fixedPrice(1),
+			quoteAmount: 5,
+		},
+		{
+			name:        "entry price 0.001 is below minEntryPrice",
+			strategy:    &Strategy{MinEntryPrice: fixedpoint.NewFromFloat(0.01), MaxEntryPrice: fixedpoint.NewFromFloat(0.99)},
+			kline:       newKLine(100, 112, 98, 110),
+			entryPrice:  fixedPrice(0.001),
+			quoteAmount: 5,
+		},
+		{
+			name:         "entry price 0.5 is within bounds",
+			strategy:     &Strategy{MinEntryPrice: fixedpoint.NewFromFloat(0.01), MaxEntryPrice: fixedpoint.NewFromFloat(0.99)},
+			kline:        newKLine(100, 112, 98, 110),
+			entryPrice:   fixedPrice(0.5),
+			quoteAmount:  5,
+			wantOK:       true,
+			wantSymbol:   "YES",
+			wantBetUp:    true,
+			wantQuantity: "10",
+		},
+		{
+			name:        "entry price 0.999 is above maxEntryPrice",
+			strategy:    &Strategy{MinEntryPrice: fixedpoint.NewFromFloat(0.01), MaxEntryPrice: fixedpoint.NewFromFloat(0.99)},
+			kline:       newKLine(100, 112, 98, 110),
+			entryPrice:  fixedPrice(0.999),
+			quoteAmount: 5,
+		},
+		{
+			name:        "zero quote amount",
+			kline:       newKLine(100, 112, 98, 110),
+			entryPrice:  fixedPrice(0.5),
+			quoteAmount: 0,
+		},
+		{
+			name:  "entry price of the target symbol",
+			kline: newKLine(110, 112, 98, 100),
+			entryPrice: func(symbol string) fixedpoint.Value {
+				if symbol == "NO" {
+					return fixedpoint.NewFromFloat(0.25)
+				}
+				return fixedpoint.NewFromFloat(0.75)
+			},
+			quoteAmount:  5,
+			wantOK:       true,
+			wantSymbol:   "NO",
+			wantQuantity: "20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.strategy
+			if s == nil {
+				s = &Strategy{}
+			}
+
+			sig, reason, ok := s.decide(tt.kline, defaultOutcomeRules("YES", "NO", s.Invert), tt.entryPrice, fixedpoint.NewFromFloat(tt.quoteAmount))
+			if !tt.wantOK {
+				assert.False(t, ok)
+				assert.NotEmpty(t, reason)
+				return
+			}
+
+			if assert.True(t, ok, reason) {
+				assert.Equal(t, tt.wantSymbol, sig.Symbol)
+				assert.Equal(t, types.SideTypeBuy, sig.Side)
+				assert.Equal(t, tt.wantBetUp, sig.BetUp)
+				assert.Equal(t, tt.wantQuantity, sig.Quantity.String())
+			}
+		})
+	}
+}
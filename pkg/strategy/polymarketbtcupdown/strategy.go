@@ -3,10 +3,13 @@ package polymarketbtcupdown
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -38,19 +41,154 @@ type Strategy struct {
 	// Interval 为 KLine 周期（默认 15m）
 	Interval types.Interval `json:"interval" yaml:"interval"`
 
+	// Intervals 为单市场简写下同时运行的多个 KLine 周期（例如 [5m, 15m]），每个周期生成一组市场配置，
+	// 默认只有 Interval。多个周期需要配合 AutoDiscover 使用，否则会对同一组 YES/NO 下注。
+	Intervals []types.Interval `json:"intervals" yaml:"intervals"`
+
 	// YesSymbol / NoSymbol 为 Polymarket 的交易 symbol（需要在 Polymarket market 列表里存在）
 	YesSymbol string `json:"yesSymbol" yaml:"yesSymbol"`
 	NoSymbol  string `json:"noSymbol" yaml:"noSymbol"`
 
+	// Outcomes 为单市场简写下有序的“K 线条件 → symbol”下注规则，配置后代替 YesSymbol/NoSymbol，
+	// 用于标量 / 多结果市场（例如按收盘价区间选择 “BTC > $X” 的档位），见 outcome.go
+	Outcomes []*OutcomeRule `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+
 	// EntryPrice 为下单价格（Polymarket 概率价格通常在 0~1；这里只是示例）
 	EntryPrice fixedpoint.Value `json:"entryPrice" yaml:"entryPrice"`
 
+	// UseMarketPrice 为 true 时用 Polymarket 目标 symbol 的最优卖价（best ask）作为下单价格，
+	// 查询失败或没有卖盘时回退到 EntryPrice。
+	UseMarketPrice bool `json:"useMarketPrice" yaml:"useMarketPrice"`
+
 	// QuoteAmount 为每次下注的 USDC 金额（会换算为 quantity = QuoteAmount / EntryPrice）
 	QuoteAmount fixedpoint.Value `json:"quoteAmount" yaml:"quoteAmount"`
+
+	// MinEntryPrice / MaxEntryPrice 为下单价格的边界（默认 0.01 / 0.99）：临近结算时价格接近 0 或 1，
+	// 过低的价格会让 quantity = QuoteAmount / EntryPrice 暴涨，过高的价格几乎没有收益，超出边界时不下注（阶梯中超出的档位被丢弃）
+	MinEntryPrice fixedpoint.Value `json:"minEntryPrice" yaml:"minEntryPrice"`
+	MaxEntryPrice fixedpoint.Value `json:"maxEntryPrice" yaml:"maxEntryPrice"`
+
+	// MaxOrderQuantity 为每个订单的最大数量（份数），超出时按该数量下单。0 表示不限制
+	MaxOrderQuantity fixedpoint.Value `json:"maxOrderQuantity" yaml:"maxOrderQuantity"`
+
+	// LadderLevels 大于 1 时把 QuoteAmount 拆成多档限价买单（阶梯挂单），通过批量下单接口提交。
+	// LadderSpacing 为相邻两档的价差（概率价格），LadderSizing 为金额分配方式（equal/linear，默认 equal）。
+	LadderLevels  int              `json:"ladderLevels" yaml:"ladderLevels"`
+	LadderSpacing fixedpoint.Value `json:"ladderSpacing" yaml:"ladderSpacing"`
+	LadderSizing  LadderSizing     `json:"ladderSizing" yaml:"ladderSizing"`
+
+	// AutoDiscover 为 true 时每根 K 线收盘后通过 Gamma 查询下一个窗口的 “Up or Down” 市场，
+	// 用其 YES(Up)/NO(Down) token 下注，而不是固定的 yesSymbol/noSymbol（目前支持 1 小时以内的周期）。
+	AutoDiscover bool `json:"autoDiscover" yaml:"autoDiscover"`
+
+	// RolloverCheckInterval 为 AutoDiscover 时检查窗口切换的间隔（默认 30s），窗口切换后自动换成新窗口的 YES/NO 并更新行情订阅，见 rollover.go
+	RolloverCheckInterval types.Duration `json:"rolloverCheckInterval" yaml:"rolloverCheckInterval"`
+
+	// Markets 为多组“行情源 K 线 → YES/NO”配置，一个策略实例同时跑多个预测市场。
+	// 为空时用上面的 SourceSymbol/Interval/YesSymbol/NoSymbol 作为唯一的一组（单市场简写）。
+	Markets []*MarketConfig `json:"markets" yaml:"markets"`
+
+	// MinBodyRatio 为 K 线实体 |close-open| 占振幅 high-low 的最小比例（0~1），低于该比例视为噪音（十字星等）不下注。
+	// 默认 0 表示不过滤。
+	MinBodyRatio fixedpoint.Value `json:"minBodyRatio" yaml:"minBodyRatio"`
+
+	// Invert 为 true 时反向下注（fade）：上涨买 NO、下跌买 YES，用于验证动量反转的假设。
+	// 配置了 outcomes 的市场不支持 Invert，反向下注直接写在规则里。
+	Invert bool `json:"invert" yaml:"invert"`
+
+	// MaxOpenOrders 为本策略在 YES/NO 两个 symbol 上同时存在的最大挂单数，达到上限时跳过本次下注。0 表示不限制。
+	MaxOpenOrders int `json:"maxOpenOrders" yaml:"maxOpenOrders"`
+
+	// MaxPositionQuote 为最大风险敞口（USDC）：本策略买单的剩余金额 + 窗口还没有结束的成交金额，见 exposure.go。
+	// 下注后会超过上限时跳过。0 表示不限制。
+	MaxPositionQuote fixedpoint.Value `json:"maxPositionQuote" yaml:"maxPositionQuote"`
+
+	// TakeProfitPrice / StopLossPrice 为止盈/止损的概率价格（0~1），持仓后 best bid 达到该价位时挂卖单平仓。0 表示不启用。
+	TakeProfitPrice fixedpoint.Value `json:"takeProfitPrice" yaml:"takeProfitPrice"`
+	StopLossPrice   fixedpoint.Value `json:"stopLossPrice" yaml:"stopLossPrice"`
+
+	// ExitCheckInterval 为检查止盈/止损的轮询间隔（默认 10s）
+	ExitCheckInterval types.Duration `json:"exitCheckInterval" yaml:"exitCheckInterval"`
+
+	// ConfirmDelay 为收盘后的信号确认延迟：等待该时长后用 Binance 最新价确认方向没有反转再下注，反转时取消下注。
+	// 默认 0 表示收盘后立即下注，需要小于 K 线周期，见 confirm.go
+	ConfirmDelay types.Duration `json:"confirmDelay" yaml:"confirmDelay"`
+
+	// ActiveFrom / ActiveTo 为相对窗口开盘时间的活跃时段，只在 [ActiveFrom, ActiveTo) 内下注，ActiveTo 为 0 表示直到窗口结束。
+	// 默认都为 0 表示不限制，见 active.go
+	ActiveFrom types.Duration `json:"activeFrom" yaml:"activeFrom"`
+	ActiveTo   types.Duration `json:"activeTo" yaml:"activeTo"`
+
+	// Tag 为本策略订单的 tag（默认为策略 ID），平仓单为 "<tag>:exit"。同一个 Polymarket session 上运行多个实例或策略变体时
+	// 配置不同的 tag，dry-run 的成交与盈亏可以按 tag 区分（见 polymarket.Exchange.DryRunSummaryByTag）。
+	Tag string `json:"tag" yaml:"tag"`
+
+	// BetWindows 为已经下注过的 K 线窗口，随 bbgo persistence 持久化，见 window.go
+	BetWindows betWindows `json:"betWindows,omitempty" persistence:"bet_windows"`
+
+	// Exposures 为各 symbol 窗口还没有结束的成交金额，随 bbgo persistence 持久化，见 exposure.go
+	Exposures exposures `json:"exposures,omitempty" persistence:"exposures"`
+
+	// Model 配置后用线性模型估计上涨概率，只有模型概率比 Polymarket 实时价格高出 EdgeThreshold 时才下注，见 model.go / edge.go
+	Model *LinearModel `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// EdgeThreshold 为模型概率与 Polymarket 实时价格之差的阈值（0~1，默认 0 表示模型概率高于价格即可），优势不超过阈值时不下注
+	EdgeThreshold fixedpoint.Value `json:"edgeThreshold" yaml:"edgeThreshold"`
+
+	// MaxEdgeMultiplier 为按优势放大下注金额的上限：金额 = quoteAmount × min(edge / edgeThreshold, maxEdgeMultiplier)，
+	// 默认 2；1 表示不放大。EdgeThreshold 为 0 时不放大
+	MaxEdgeMultiplier fixedpoint.Value `json:"maxEdgeMultiplier" yaml:"maxEdgeMultiplier"`
+
+	// MaxLiquidityRatio 为下注金额占下单价格以内卖盘总金额的最大比例（0~1），超出时按该比例下注，没有卖盘时不下注。
+	// 默认 0 表示不限制，见 liquidity.go
+	MaxLiquidityRatio fixedpoint.Value `json:"maxLiquidityRatio" yaml:"maxLiquidityRatio"`
+
+	// Hedge 为 true 时每次下注同时买入另一边的 outcome 对冲，对冲数量为下注数量的 HedgeRatio 倍（0~1），见 hedge.go
+	Hedge      bool             `json:"hedge" yaml:"hedge"`
+	HedgeRatio fixedpoint.Value `json:"hedgeRatio" yaml:"hedgeRatio"`
+
+	// probabilityModel 为 SetProbabilityModel 替换的模型
+	probabilityModel ProbabilityModel
+
+	// sourceTicker 查询行情源 symbol 的 ticker，用于 ConfirmDelay 确认方向
+	sourceTicker func(ctx context.Context, symbol string) (*types.Ticker, error)
+
+	// windowMu 保护 BetWindows / Exposures：ConfirmDelay 时各根 K 线在各自的 goroutine 中下注
+	windowMu sync.Mutex
+
+	mu sync.Mutex
+	// orderIDs 为本策略提交的订单 ID
+	orderIDs map[uint64]struct{}
+	// executedQuantities 记录每个订单已统计过的成交量，用于计算增量
+	executedQuantities map[uint64]fixedpoint.Value
+	// positions 为各 symbol 的持仓数量（买入成交 - 卖出成交）
+	positions map[string]fixedpoint.Value
+	// exitingSymbols 记录已经挂了平仓单的 symbol，避免重复平仓
+	exitingSymbols map[string]bool
 }
 
 func (s *Strategy) ID() string { return ID }
 
+func (s *Strategy) InstanceID() string {
+	if s.Tag != "" && s.Tag != ID {
+		return fmt.Sprintf("%s:%s:%s", ID, s.PolymarketSession, s.Tag)
+	}
+	return fmt.Sprintf("%s:%s", ID, s.PolymarketSession)
+}
+
+// orderTag 返回本策略开仓单的 tag，没有配置 Tag 时为策略 ID。
+func (s *Strategy) orderTag() string {
+	if s.Tag != "" {
+		return s.Tag
+	}
+	return ID
+}
+
+// exitTag 返回平仓单的 tag，与开仓单区分。
+func (s *Strategy) exitTag() string {
+	return s.orderTag() + ":exit"
+}
+
 func (s *Strategy) Defaults() error {
 	if s.BinanceSession == "" {
 		s.BinanceSession = "binance"
@@ -76,6 +214,50 @@ func (s *Strategy) Defaults() error {
 	if s.QuoteAmount.IsZero() {
 		s.QuoteAmount = fixedpoint.NewFromFloat(5)
 	}
+	if s.MinEntryPrice.IsZero() {
+		s.MinEntryPrice = fixedpoint.NewFromFloat(0.01)
+	}
+	if s.MaxEntryPrice.IsZero() {
+		s.MaxEntryPrice = fixedpoint.NewFromFloat(0.99)
+	}
+	if s.LadderSizing == "" {
+		s.LadderSizing = LadderSizingEqual
+	}
+	if s.ExitCheckInterval == 0 {
+		s.ExitCheckInterval = types.Duration(10 * time.Second)
+	}
+	if s.RolloverCheckInterval == 0 {
+		s.RolloverCheckInterval = types.Duration(30 * time.Second)
+	}
+	if s.Model != nil {
+		s.Model.Defaults()
+	}
+	if s.MaxEdgeMultiplier.IsZero() {
+		s.MaxEdgeMultiplier = fixedpoint.NewFromFloat(2)
+	}
+	if len(s.Intervals) == 0 {
+		s.Intervals = []types.Interval{s.Interval}
+	}
+
+	if len(s.Markets) == 0 {
+		for _, interval := range s.Intervals {
+			s.Markets = append(s.Markets, &MarketConfig{
+				SourceSymbol: s.SourceSymbol,
+				Interval:     interval,
+				YesSymbol:    s.YesSymbol,
+				NoSymbol:     s.NoSymbol,
+				Outcomes:     s.Outcomes,
+			})
+		}
+	}
+	for _, m := range s.Markets {
+		if m.EntryPrice.IsZero() {
+			m.EntryPrice = s.EntryPrice
+		}
+		if m.QuoteAmount.IsZero() {
+			m.QuoteAmount = s.QuoteAmount
+		}
+	}
 	return nil
 }
 
@@ -83,20 +265,120 @@ func (s *Strategy) Validate() error {
 	if s.BinanceSession == "" || s.PolymarketSession == "" {
 		return fmt.Errorf("binanceSession/polymarketSession is required")
 	}
-	if s.SourceSymbol == "" {
-		return fmt.Errorf("sourceSymbol is required")
+	if len(s.Markets) == 0 {
+		return fmt.Errorf("at least one market is required")
 	}
-	if s.Interval == "" {
-		return fmt.Errorf("interval is required")
+	for i, m := range s.Markets {
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("markets[%d]: %w", i, err)
+		}
+		if len(m.Outcomes) > 0 && s.Invert {
+			return fmt.Errorf("markets[%d]: invert is not supported with outcomes, write the faded rules instead", i)
+		}
+		if len(m.Outcomes) > 0 && s.model() != nil {
+			return fmt.Errorf("markets[%d]: probability model is not supported with outcomes", i)
+		}
+		if len(m.Outcomes) > 0 && s.Hedge {
+			return fmt.Errorf("markets[%d]: hedge only supports yes/no markets, remove outcomes", i)
+		}
+		if len(m.Outcomes) > 0 && s.AutoDiscover {
+			return fmt.Errorf("markets[%d]: autoDiscover only supports yes/no up/down markets, remove outcomes", i)
+		}
+	}
+	if len(s.Markets) > 1 && !s.AutoDiscover {
+		seen := make(map[string]bool)
+		for _, m := range s.Markets {
+			symbols := m.symbols()
+			for _, symbol := range symbols {
+				if seen[symbol] {
+					return fmt.Errorf("markets share symbol %s, enable autoDiscover or configure different symbols", symbol)
+				}
+			}
+			for _, symbol := range symbols {
+				seen[symbol] = true
+			}
+		}
+	}
+	if s.RolloverCheckInterval < 0 {
+		return fmt.Errorf("rolloverCheckInterval must not be negative")
+	}
+	if s.Model != nil {
+		if err := s.Model.Validate(); err != nil {
+			return fmt.Errorf("model: %w", err)
+		}
+	}
+	if s.EdgeThreshold.Sign() < 0 || s.EdgeThreshold.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("edgeThreshold must be between 0 and 1")
+	}
+	if s.MaxEdgeMultiplier.Compare(fixedpoint.One) < 0 {
+		return fmt.Errorf("maxEdgeMultiplier must not be less than 1")
+	}
+	if s.MaxLiquidityRatio.Sign() < 0 || s.MaxLiquidityRatio.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("maxLiquidityRatio must be between 0 and 1")
+	}
+	if s.HedgeRatio.Sign() < 0 || s.HedgeRatio.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("hedgeRatio must be between 0 and 1")
+	}
+	if s.Hedge && s.HedgeRatio.IsZero() {
+		return fmt.Errorf("hedgeRatio is required when hedge is enabled")
+	}
+	if s.ConfirmDelay < 0 {
+		return fmt.Errorf("confirmDelay must not be negative")
+	}
+	if s.ActiveFrom < 0 || s.ActiveTo < 0 {
+		return fmt.Errorf("activeFrom/activeTo must not be negative")
+	}
+	if s.ActiveTo > 0 && s.ActiveTo <= s.ActiveFrom {
+		return fmt.Errorf("activeTo %s must be after activeFrom %s", s.ActiveTo.Duration(), s.ActiveFrom.Duration())
+	}
+	// 下注发生在 K 线收盘（目标窗口开盘）时或 confirmDelay 之后，activeFrom 晚于这个时间时永远不会下注
+	if s.ActiveFrom > s.ConfirmDelay {
+		return fmt.Errorf("activeFrom %s must not be after confirmDelay %s, orders are placed at the window open or after confirmDelay",
+			s.ActiveFrom.Duration(), s.ConfirmDelay.Duration())
+	}
+	if s.ActiveTo > 0 && s.ConfirmDelay >= s.ActiveTo {
+		return fmt.Errorf("confirmDelay %s must be shorter than activeTo %s", s.ConfirmDelay.Duration(), s.ActiveTo.Duration())
+	}
+	for i, m := range s.Markets {
+		if s.ConfirmDelay > 0 && s.ConfirmDelay.Duration() >= m.Interval.Duration() {
+			return fmt.Errorf("markets[%d]: confirmDelay %s must be shorter than interval %s", i, s.ConfirmDelay.Duration(), m.Interval)
+		}
+		if s.ActiveFrom.Duration() >= m.Interval.Duration() || s.ActiveTo.Duration() > m.Interval.Duration() {
+			return fmt.Errorf("markets[%d]: activeFrom/activeTo must be within interval %s", i, m.Interval)
+		}
+	}
+	if s.MinBodyRatio.Sign() < 0 || s.MinBodyRatio.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("minBodyRatio must be between 0 and 1")
+	}
+	if s.MinEntryPrice.Sign() < 0 || s.MaxEntryPrice.Compare(fixedpoint.One) >= 0 || s.MinEntryPrice.Compare(s.MaxEntryPrice) >= 0 {
+		return fmt.Errorf("minEntryPrice/maxEntryPrice must satisfy 0 <= minEntryPrice < maxEntryPrice < 1")
+	}
+	if s.MaxOrderQuantity.Sign() < 0 {
+		return fmt.Errorf("maxOrderQuantity must not be negative")
+	}
+	if s.LadderLevels < 0 {
+		return fmt.Errorf("ladderLevels must not be negative")
+	}
+	if s.LadderLevels > 1 && (s.LadderSpacing.Sign() <= 0 || s.LadderSpacing.Compare(fixedpoint.One) >= 0) {
+		return fmt.Errorf("ladderSpacing must be between 0 and 1 when ladderLevels > 1")
+	}
+	if err := s.LadderSizing.Validate(); err != nil {
+		return err
+	}
+	if s.MaxOpenOrders < 0 {
+		return fmt.Errorf("maxOpenOrders must not be negative")
 	}
-	if s.YesSymbol == "" || s.NoSymbol == "" {
-		return fmt.Errorf("yesSymbol/noSymbol is required")
+	if s.MaxPositionQuote.Sign() < 0 {
+		return fmt.Errorf("maxPositionQuote must not be negative")
 	}
-	if s.EntryPrice.Sign() <= 0 {
-		return fmt.Errorf("entryPrice must be positive")
+	if s.TakeProfitPrice.Sign() < 0 || s.TakeProfitPrice.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("takeProfitPrice must be between 0 and 1")
 	}
-	if s.QuoteAmount.Sign() <= 0 {
-		return fmt.Errorf("quoteAmount must be positive")
+	if s.StopLossPrice.Sign() < 0 || s.StopLossPrice.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("stopLossPrice must be between 0 and 1")
+	}
+	if s.TakeProfitPrice.Sign() > 0 && s.StopLossPrice.Sign() > 0 && s.StopLossPrice.Compare(s.TakeProfitPrice) >= 0 {
+		return fmt.Errorf("stopLossPrice must be lower than takeProfitPrice")
 	}
 	return nil
 }
@@ -108,7 +390,9 @@ func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {
 		return
 	}
 
-	binanceSession.Subscribe(types.KLineChannel, s.SourceSymbol, types.SubscribeOptions{Interval: s.Interval})
+	for _, m := range s.Markets {
+		binanceSession.Subscribe(types.KLineChannel, m.SourceSymbol, types.SubscribeOptions{Interval: m.Interval})
+	}
 }
 
 func (s *Strategy) CrossRun(ctx context.Context, router bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession) error {
@@ -123,50 +407,384 @@ func (s *Strategy) CrossRun(ctx context.Context, router bbgo.OrderExecutionRoute
 	if !ok {
 		return fmt.Errorf("binance session %q not found", s.BinanceSession)
 	}
-	_, ok = sessions[s.PolymarketSession]
+	polymarketSession, ok := sessions[s.PolymarketSession]
 	if !ok {
 		return fmt.Errorf("polymarket session %q not found", s.PolymarketSession)
 	}
 
-	binanceSession.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
-		if kline.Symbol != s.SourceSymbol || kline.Interval != s.Interval {
-			return
+	if ex, ok := polymarketSession.Exchange.(*polymarket.Exchange); ok {
+		// dry-run 订单持久化：用 bbgo 的 persistence service（Redis/JSON）创建 store 注入给 exchange
+		if ex.PersistenceEnabled() {
+			persistence := bbgo.GetIsolationFromContext(ctx).GetPersistenceService()
+			if err := ex.SetPersistenceStore(persistence.NewStore(ex.PersistenceNamespace())); err != nil {
+				return fmt.Errorf("restore polymarket dry-run orders failed: %w", err)
+			}
 		}
 
-		// 极简 up/down 规则：收盘 > 开盘 => up，否则 down
-		up := kline.Close.Compare(kline.Open) > 0
-		targetSymbol := s.NoSymbol
-		if up {
-			targetSymbol = s.YesSymbol
-		}
-
-		quantity := s.QuoteAmount.Div(s.EntryPrice)
-
-		log.WithFields(logrus.Fields{
-			"source":        s.SourceSymbol,
-			"interval":      s.Interval,
-			"open":          kline.Open.String(),
-			"close":         kline.Close.String(),
-			"targetSymbol":  targetSymbol,
-			"entryPrice":    s.EntryPrice.String(),
-			"quoteAmount":   s.QuoteAmount.String(),
-			"orderQuantity": quantity.String(),
-		}).Info("signal generated, submitting polymarket order")
-
-		_, err := router.SubmitOrdersTo(ctx, s.PolymarketSession, types.SubmitOrder{
-			Symbol:      targetSymbol,
-			Side:        types.SideTypeBuy,
-			Type:        types.OrderTypeLimit,
-			Price:       s.EntryPrice,
-			Quantity:    quantity,
-			TimeInForce: types.TimeInForceGTC,
-			Tag:         ID,
+		// markets 文件热加载后同步到 session，否则下单时 FormatOrder 找不到新加的 market
+		ex.OnMarketsReloaded(polymarketSession.SetMarkets)
+
+		// 退出时停止 exchange 的后台循环；dry-run 模拟盘同时打印订单、成交与盈亏汇总
+		bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+			defer wg.Done()
+			if ex.IsDryRun() {
+				log.Info(ex.DryRunReport())
+			}
+			if err := ex.Close(); err != nil {
+				log.WithError(err).Warn("failed to close polymarket exchange")
+			}
 		})
-		if err != nil {
-			log.WithError(err).Error("failed to submit polymarket order")
+	}
+
+	if s.BetWindows == nil {
+		s.BetWindows = make(betWindows)
+	}
+	if s.Exposures == nil {
+		s.Exposures = make(exposures)
+	}
+	s.executedQuantities = make(map[uint64]fixedpoint.Value)
+	s.positions = make(map[string]fixedpoint.Value)
+	s.exitingSymbols = make(map[string]bool)
+	if polymarketSession.UserDataStream != nil {
+		polymarketSession.UserDataStream.OnOrderUpdate(func(order types.Order) {
+			s.handleOrderUpdate(ctx, order)
+		})
+	}
+
+	if s.TakeProfitPrice.Sign() > 0 || s.StopLossPrice.Sign() > 0 {
+		go s.runExitLoop(ctx, router, polymarketSession)
+	}
+
+	if s.AutoDiscover {
+		go s.runRolloverLoop(ctx, polymarketSession)
+	}
+
+	s.sourceTicker = binanceSession.Exchange.QueryTicker
+	s.preloadKLines(ctx, binanceSession)
+	binanceSession.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
+		for _, m := range s.Markets {
+			if kline.Symbol != m.SourceSymbol || kline.Interval != m.Interval {
+				continue
+			}
+
+			// 确认延迟期间不阻塞行情 stream
+			if s.ConfirmDelay > 0 {
+				go s.handleKLineClosed(ctx, router, polymarketSession, m, kline)
+			} else {
+				s.handleKLineClosed(ctx, router, polymarketSession, m, kline)
+			}
 		}
 	})
 
 	return nil
 }
 
+// handleKLineClosed 处理单个市场的收盘 K 线：结算上一次预测，然后按 up/down 下注。
+func (s *Strategy) handleKLineClosed(ctx context.Context, router bbgo.OrderExecutionRouter, session *bbgo.ExchangeSession, m *MarketConfig, kline types.KLine) {
+	window := windowKey(m, kline)
+	logger := log.WithFields(logrus.Fields{
+		"market": m.String(),
+		"window": window,
+	})
+
+	if confirmed, correct := m.predictions.Confirm(kline); confirmed {
+		hits, total, rate := m.predictions.Accuracy()
+		logger.WithFields(logrus.Fields{
+			"correct": correct,
+			"hits":    hits,
+			"total":   total,
+			"hitRate": fmt.Sprintf("%.2f%%", rate*100),
+		}).Info("prediction confirmed")
+	}
+
+	s.recordKLine(m, kline)
+
+	if s.windowBet(window) {
+		logger.Warn("window has already been bet, skip duplicated kline")
+		return
+	}
+
+	// 实体不足时不会下注，不必等待确认
+	if s.ConfirmDelay > 0 && s.hasEnoughBody(kline) {
+		if reason, ok := s.confirmDirection(ctx, m, kline); !ok {
+			logger.Infof("skip betting: %s", reason)
+			return
+		}
+	}
+
+	if reason, ok := s.activeWindow(m, kline, time.Now()); !ok {
+		logger.Infof("skip betting: %s", reason)
+		return
+	}
+
+	// 实体不足时不会下注，不必查询市场；
+	// 直接使用为这根 K 线查询到的 symbol，即使定时刷新同时在切换窗口也不会下注到别的窗口
+	rules := m.outcomeRules(s.Invert)
+	yes, no := m.targetSymbols()
+	if s.AutoDiscover && s.hasEnoughBody(kline) {
+		um, err := s.discoverMarket(ctx, session, m, kline)
+		if err != nil {
+			logger.WithError(err).Error("failed to discover up/down market, skip betting")
+			return
+		}
+		rules = defaultOutcomeRules(um.YesSymbol, um.NoSymbol, s.Invert)
+		yes, no = um.YesSymbol, um.NoSymbol
+	}
+
+	sig, reason, ok := s.decide(kline, rules, func(symbol string) fixedpoint.Value {
+		return s.entryPrice(ctx, session, symbol, m.EntryPrice)
+	}, m.QuoteAmount)
+	if !ok {
+		logger.WithFields(logrus.Fields{
+			"open":  kline.Open.String(),
+			"close": kline.Close.String(),
+			"high":  kline.High.String(),
+			"low":   kline.Low.String(),
+		}).Infof("skip betting: %s", reason)
+		return
+	}
+
+	quoteAmount, reason, ok := s.checkEdge(ctx, session, m, sig)
+	if !ok {
+		logger.WithField("targetSymbol", sig.Symbol).Infof("skip betting: %s", reason)
+		return
+	}
+
+	quoteAmount, reason, ok = s.capToLiquidity(ctx, session, sig, quoteAmount)
+	if !ok {
+		logger.WithField("targetSymbol", sig.Symbol).Infof("skip betting: %s", reason)
+		return
+	}
+
+	targetSymbol := sig.Symbol
+	market, ok := session.Market(targetSymbol)
+	if !ok {
+		logger.Errorf("market %s not found in polymarket session, skip betting", targetSymbol)
+		return
+	}
+
+	orders := s.ladderOrders(market, sig.Price, quoteAmount, feeRateBps(session.Exchange, targetSymbol))
+	if len(orders) == 0 {
+		logger.WithField("targetSymbol", targetSymbol).Infof("skip betting: quoteAmount %s is below the minimal order size", quoteAmount.String())
+		return
+	}
+
+	// 对冲单与下注订单一起提交，风险敞口包含对冲单的金额
+	var hedges []types.SubmitOrder
+	exposure := quoteAmount
+	if s.Hedge {
+		if hedge, reason, ok := s.hedgeOrder(ctx, session, sig, yes, no, orders); ok {
+			hedges = append(hedges, hedge)
+			exposure = exposure.Add(hedge.Price.Mul(hedge.Quantity))
+		} else {
+			logger.WithField("targetSymbol", targetSymbol).Infof("skip hedging: %s", reason)
+		}
+	}
+
+	_, windowEnd, err := polymarket.UpDownWindow(m.Interval, kline.EndTime.Time().Add(time.Millisecond))
+	if err != nil {
+		logger.WithError(err).Error("failed to compute the bet window, skip betting")
+		return
+	}
+
+	if reason, ok := s.checkExposure(ctx, session, exposure); !ok {
+		logger.WithField("targetSymbol", targetSymbol).Infof("skip betting: %s", reason)
+		return
+	}
+
+	fields := logrus.Fields{
+		"source":        m.SourceSymbol,
+		"interval":      m.Interval,
+		"open":          kline.Open.String(),
+		"close":         kline.Close.String(),
+		"targetSymbol":  targetSymbol,
+		"invert":        s.Invert,
+		"entryPrice":    sig.Price.String(),
+		"quoteAmount":   quoteAmount.String(),
+		"orderQuantity": orders[0].Quantity.String(),
+		"ladderLevels":  len(orders),
+	}
+	for _, hedge := range hedges {
+		fields["hedgeSymbol"] = hedge.Symbol
+		fields["hedgePrice"] = hedge.Price.String()
+		fields["hedgeQuantity"] = hedge.Quantity.String()
+	}
+	logger.WithFields(fields).Info("signal generated, submitting polymarket order")
+
+	// 提交前先记录窗口：批量下单失败时部分订单可能已经提交，不能再重复下注；
+	// 确认延迟期间重复推送的 K 线可能同时通过了上面的检查，只有先记录的那次下注
+	if !s.markWindow(ctx, window, kline.StartTime.Time()) {
+		logger.Warn("window has already been bet, skip duplicated kline")
+		return
+	}
+
+	// 成交金额计入敞口直到下注窗口结束
+	symbols := []string{targetSymbol}
+	for _, hedge := range hedges {
+		symbols = append(symbols, hedge.Symbol)
+	}
+	s.openExposure(ctx, symbols, windowEnd)
+
+	if err := s.submitOrders(ctx, router, session, append(orders, hedges...)); err != nil {
+		logger.WithError(err).Error("failed to submit polymarket order")
+		return
+	}
+
+	if sig.Predicted {
+		m.predictions.Record(kline, sig.BetUp)
+	}
+}
+
+// discoverMarket 查询紧接着这根 K 线的窗口对应的 up/down 市场，并切换过去（见 activateMarket）。
+func (s *Strategy) discoverMarket(ctx context.Context, session *bbgo.ExchangeSession, m *MarketConfig, kline types.KLine) (*polymarket.UpDownMarket, error) {
+	ex, ok := session.Exchange.(*polymarket.Exchange)
+	if !ok {
+		return nil, fmt.Errorf("session %s is not a polymarket session", s.PolymarketSession)
+	}
+
+	// 下注预测的是下一根 K 线，所以查询下一个窗口
+	next := kline.EndTime.Time().Add(time.Millisecond)
+	um, err := ex.DiscoverUpDownMarket(ctx, m.asset(), m.Interval, next)
+	if err != nil {
+		return nil, err
+	}
+
+	s.activateMarket(session, m, um)
+	return um, nil
+}
+
+// Accuracy 返回所有市场合计的预测命中次数、已结算的预测次数与命中率。
+func (s *Strategy) Accuracy() (hits, total int, rate float64) {
+	for _, m := range s.Markets {
+		h, t, _ := m.predictions.Accuracy()
+		hits += h
+		total += t
+	}
+	if total > 0 {
+		rate = float64(hits) / float64(total)
+	}
+	return hits, total, rate
+}
+
+// hasEnoughBody 检查 K 线实体占振幅的比例是否达到 MinBodyRatio。
+func (s *Strategy) hasEnoughBody(kline types.KLine) bool {
+	if s.MinBodyRatio.IsZero() {
+		return true
+	}
+
+	priceRange := kline.High.Sub(kline.Low)
+	if priceRange.Sign() <= 0 {
+		return false
+	}
+
+	body := kline.Close.Sub(kline.Open).Abs()
+	return body.Div(priceRange).Compare(s.MinBodyRatio) >= 0
+}
+
+// entryPrice 返回下单价格：UseMarketPrice 时取 best ask，否则（或取不到时）用 fallback。
+func (s *Strategy) entryPrice(ctx context.Context, session *bbgo.ExchangeSession, symbol string, fallback fixedpoint.Value) fixedpoint.Value {
+	if !s.UseMarketPrice {
+		return fallback
+	}
+
+	ticker, err := session.Exchange.QueryTicker(ctx, symbol)
+	if err != nil {
+		log.WithError(err).Warnf("query %s ticker failed, fallback to entryPrice %s", symbol, fallback.String())
+		return fallback
+	}
+
+	if ticker.Sell.Sign() <= 0 || ticker.Sell.Compare(fixedpoint.One) >= 0 {
+		log.Warnf("%s has no valid best ask, fallback to entryPrice %s", symbol, fallback.String())
+		return fallback
+	}
+
+	return ticker.Sell
+}
+
+// polymarketSymbols 返回所有市场去重后可能下注的 symbol。
+func (s *Strategy) polymarketSymbols() (symbols []string) {
+	seen := make(map[string]struct{})
+	for _, m := range s.Markets {
+		for _, symbol := range m.symbols() {
+			if _, ok := seen[symbol]; ok {
+				continue
+			}
+			seen[symbol] = struct{}{}
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// handleOrderUpdate 根据本策略订单的成交增量更新成交金额（用于 MaxPositionQuote）与持仓（用于止盈/止损）。
+func (s *Strategy) handleOrderUpdate(ctx context.Context, order types.Order) {
+	if !s.ownOrder(order) {
+		return
+	}
+
+	s.mu.Lock()
+	if order.Tag == s.exitTag() && !order.IsWorking {
+		delete(s.exitingSymbols, order.Symbol)
+	}
+
+	prev := s.executedQuantities[order.OrderID]
+	delta := order.ExecutedQuantity.Sub(prev)
+	if delta.Sign() <= 0 {
+		s.mu.Unlock()
+		return
+	}
+	s.executedQuantities[order.OrderID] = order.ExecutedQuantity
+
+	quote := delta.Mul(order.Price)
+	switch order.Side {
+	case types.SideTypeBuy:
+		s.positions[order.Symbol] = s.positions[order.Symbol].Add(delta)
+
+	case types.SideTypeSell:
+		quote = quote.Neg()
+		s.positions[order.Symbol] = fixedpoint.Max(s.positions[order.Symbol].Sub(delta), fixedpoint.Zero)
+	}
+	s.mu.Unlock()
+
+	s.addFilledQuote(ctx, order.Symbol, quote)
+}
+
+// checkExposure 检查 MaxOpenOrders / MaxPositionQuote（按所有市场合计，只统计本策略的订单），返回不能下注的原因。
+func (s *Strategy) checkExposure(ctx context.Context, session *bbgo.ExchangeSession, quoteAmount fixedpoint.Value) (string, bool) {
+	if s.MaxOpenOrders == 0 && s.MaxPositionQuote.IsZero() {
+		return "", true
+	}
+
+	var openOrders []types.Order
+	for _, symbol := range s.polymarketSymbols() {
+		orders, err := session.Exchange.QueryOpenOrders(ctx, symbol)
+		if err != nil {
+			return fmt.Sprintf("query open orders of %s failed: %v", symbol, err), false
+		}
+		for _, o := range orders {
+			if s.ownOrder(o) {
+				openOrders = append(openOrders, o)
+			}
+		}
+	}
+
+	if s.MaxOpenOrders > 0 && len(openOrders) >= s.MaxOpenOrders {
+		return fmt.Sprintf("open orders %d reached maxOpenOrders %d", len(openOrders), s.MaxOpenOrders), false
+	}
+
+	if s.MaxPositionQuote.Sign() > 0 {
+		exposure := s.filledQuote(ctx, time.Now())
+		for _, o := range openOrders {
+			if o.Side == types.SideTypeBuy {
+				exposure = exposure.Add(o.Quantity.Sub(o.ExecutedQuantity).Mul(o.Price))
+			}
+		}
+
+		if exposure.Add(quoteAmount).Compare(s.MaxPositionQuote) > 0 {
+			return fmt.Sprintf("exposure %s + %s exceeds maxPositionQuote %s",
+				exposure.String(), quoteAmount.String(), s.MaxPositionQuote.String()), false
+		}
+	}
+
+	return "", true
+}
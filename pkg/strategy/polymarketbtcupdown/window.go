@@ -0,0 +1,59 @@
+package polymarketbtcupdown
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 下注窗口去重：每个 (sourceSymbol, interval, K 线开盘时间) 只下注一次。
+// 已下注的窗口保存在 BetWindows 并通过 bbgo persistence 持久化，策略重启或 K 线收盘事件重复推送时不会重复下注。
+
+// betWindowRetention 为已下注窗口的保留时长，更早的记录会被清理，避免无限增长
+const betWindowRetention = 7 * 24 * time.Hour
+
+// betWindows 为窗口 key → K 线开盘时间
+type betWindows map[string]time.Time
+
+// windowKey 返回 K 线对应的下注窗口 key，例如 BTCUSDT:15m:1704067200。
+func windowKey(m *MarketConfig, kline types.KLine) string {
+	return fmt.Sprintf("%s:%s:%d", m.SourceSymbol, m.Interval, kline.StartTime.Time().Unix())
+}
+
+func (w betWindows) has(key string) bool {
+	_, ok := w[key]
+	return ok
+}
+
+// mark 记录窗口已下注，同时清理开盘时间早于 start - betWindowRetention 的记录。
+func (w betWindows) mark(key string, start time.Time) {
+	for k, t := range w {
+		if t.Before(start.Add(-betWindowRetention)) {
+			delete(w, k)
+		}
+	}
+	w[key] = start
+}
+
+// windowBet 判断窗口是否已经下注。
+func (s *Strategy) windowBet(key string) bool {
+	s.windowMu.Lock()
+	defer s.windowMu.Unlock()
+	return s.BetWindows.has(key)
+}
+
+// markWindow 记录窗口已下注并持久化，窗口已经下注过时返回 false。
+func (s *Strategy) markWindow(ctx context.Context, key string, start time.Time) bool {
+	s.windowMu.Lock()
+	defer s.windowMu.Unlock()
+
+	if s.BetWindows.has(key) {
+		return false
+	}
+	s.BetWindows.mark(key, start)
+	bbgo.Sync(ctx, s)
+	return true
+}
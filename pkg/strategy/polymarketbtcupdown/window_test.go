@@ -0,0 +1,32 @@
+package polymarketbtcupdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestBetWindows(t *testing.T) {
+	m := &MarketConfig{SourceSymbol: "BTCUSDT", Interval: types.Interval15m}
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	k0 := newTestKLine(t0, 100, 110)
+	key := windowKey(m, k0)
+	assert.Equal(t, "BTCUSDT:15m:1704067200", key)
+
+	windows := make(betWindows)
+	assert.False(t, windows.has(key))
+
+	windows.mark(key, k0.StartTime.Time())
+	assert.True(t, windows.has(key))
+	assert.False(t, windows.has(windowKey(m, newTestKLine(t0.Add(15*time.Minute), 110, 120))))
+
+	// 超过保留时长的窗口会被清理
+	later := newTestKLine(t0.Add(betWindowRetention+time.Minute), 100, 110)
+	windows.mark(windowKey(m, later), later.StartTime.Time())
+	assert.False(t, windows.has(key))
+	assert.Len(t, windows, 1)
+}
@@ -0,0 +1,61 @@
+package polymarketmirror
+
+import (
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 公允价（fair value）：
+// - 参考价为 Binance book ticker 的中间价 (bid+ask)/2
+// - 每个 Window 周期（按 UTC 对齐，与 up/down 市场的窗口一致）开始时的参考价作为锚点
+// - 隐含概率 p = 1 / (1 + exp(-Sensitivity * (mid/anchor - 1)))：参考价不动时为 0.5，上涨时趋近 1，下跌时趋近 0
+
+// fairValueModel 记录当前窗口的锚点，并根据最新的参考价计算隐含概率。
+type fairValueModel struct {
+	window      time.Duration
+	sensitivity float64
+
+	windowStart time.Time
+	anchor      fixedpoint.Value
+}
+
+// update 用 now 时刻的参考价 mid 计算隐含概率，进入新窗口时以 mid 作为新的锚点。
+func (m *fairValueModel) update(mid fixedpoint.Value, now time.Time) fixedpoint.Value {
+	start := now.Truncate(m.window)
+	if m.anchor.IsZero() || !start.Equal(m.windowStart) {
+		m.windowStart = start
+		m.anchor = mid
+	}
+	return impliedProbability(m.anchor, mid, m.sensitivity)
+}
+
+// impliedProbability 返回参考价相对锚点的涨幅对应的 “上涨” 概率（0~1）。
+func impliedProbability(anchor, price fixedpoint.Value, sensitivity float64) fixedpoint.Value {
+	if anchor.Sign() <= 0 || price.Sign() <= 0 {
+		return fixedpoint.NewFromFloat(0.5)
+	}
+
+	ret := price.Float64()/anchor.Float64() - 1
+	return fixedpoint.NewFromFloat(1 / (1 + math.Exp(-sensitivity*ret)))
+}
+
+// midPrice 返回 book ticker 的中间价，只有单边报价时取该边，都没有时返回 0。
+func midPrice(ticker types.BookTicker) fixedpoint.Value {
+	switch {
+	case ticker.Buy.Sign() > 0 && ticker.Sell.Sign() > 0:
+		return ticker.Buy.Add(ticker.Sell).Div(fixedpoint.Two)
+	case ticker.Buy.Sign() > 0:
+		return ticker.Buy
+	default:
+		return fixedpoint.Max(ticker.Sell, fixedpoint.Zero)
+	}
+}
+
+// quotePrice 返回挂单价格：公允价减去 edge，并限制在 [minPrice, maxPrice] 内。
+func quotePrice(fair, edge, minPrice, maxPrice fixedpoint.Value) fixedpoint.Value {
+	price := fair.Sub(edge)
+	return fixedpoint.Min(fixedpoint.Max(price, minPrice), maxPrice)
+}
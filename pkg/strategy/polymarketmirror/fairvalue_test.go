@@ -0,0 +1,46 @@
+package polymarketmirror
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestImpliedProbability(t *testing.T) {
+	anchor := fixedpoint.NewFromFloat(100000)
+	assert.InDelta(t, 0.5, impliedProbability(anchor, anchor, 200).Float64(), 1e-6)
+	assert.InDelta(t, 0.8808, impliedProbability(anchor, fixedpoint.NewFromFloat(101000), 200).Float64(), 1e-4)
+	assert.InDelta(t, 0.1192, impliedProbability(anchor, fixedpoint.NewFromFloat(99000), 200).Float64(), 1e-4)
+	assert.InDelta(t, 0.5, impliedProbability(fixedpoint.Zero, anchor, 200).Float64(), 1e-6)
+}
+
+func TestFairValueModel_Update(t *testing.T) {
+	m := &fairValueModel{window: 15 * time.Minute, sensitivity: 200}
+	t0 := time.Date(2025, 10, 15, 8, 1, 0, 0, time.UTC)
+
+	// 第一个参考价作为锚点
+	assert.InDelta(t, 0.5, m.update(fixedpoint.NewFromFloat(100000), t0).Float64(), 1e-6)
+	assert.Greater(t, m.update(fixedpoint.NewFromFloat(100500), t0.Add(5*time.Minute)).Float64(), 0.5)
+
+	// 进入下一个窗口（08:15）后以新的参考价为锚点
+	assert.InDelta(t, 0.5, m.update(fixedpoint.NewFromFloat(100500), t0.Add(14*time.Minute)).Float64(), 1e-6)
+	assert.Equal(t, "100500", m.anchor.String())
+}
+
+func TestMidPrice(t *testing.T) {
+	assert.Equal(t, "101", midPrice(types.BookTicker{Buy: fixedpoint.NewFromInt(100), Sell: fixedpoint.NewFromInt(102)}).String())
+	assert.Equal(t, "100", midPrice(types.BookTicker{Buy: fixedpoint.NewFromInt(100)}).String())
+	assert.Equal(t, "102", midPrice(types.BookTicker{Sell: fixedpoint.NewFromInt(102)}).String())
+	assert.True(t, midPrice(types.BookTicker{}).IsZero())
+}
+
+func TestQuotePrice(t *testing.T) {
+	edge, minPrice, maxPrice := fixedpoint.NewFromFloat(0.02), fixedpoint.NewFromFloat(0.05), fixedpoint.NewFromFloat(0.95)
+	assert.Equal(t, "0.6", quotePrice(fixedpoint.NewFromFloat(0.62), edge, minPrice, maxPrice).String())
+	assert.Equal(t, "0.05", quotePrice(fixedpoint.NewFromFloat(0.03), edge, minPrice, maxPrice).String())
+	assert.Equal(t, "0.95", quotePrice(fixedpoint.NewFromFloat(0.99), edge, minPrice, maxPrice).String())
+}
@@ -0,0 +1,227 @@
+package polymarketmirror
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// 单一挂单的维护：
+// - 没有挂单时按目标价格提交限价买单（数量 = QuoteAmount / price，计入手续费后按 tick/step 调整）
+// - 目标价格与挂单价格相差达到 RepriceThreshold 时改单；交易端支持 AmendOrder 时原地改单（真实下单为 cancel-replace），
+//   否则先撤单再重新下单
+// - 挂单成交或被撤掉后（订单更新推送 IsWorking=false）清空，下一次报价重新挂单
+// - 持仓达到 MaxPosition 时撤掉挂单，不再报价
+// 交易所调用都在锁外进行：dry-run 改单/撤单会同步推送订单更新，回调里需要拿锁。
+
+// orderAmender 为支持改单的交易端（polymarket.Exchange）
+type orderAmender interface {
+	AmendOrder(ctx context.Context, order types.Order, newPrice, newQuantity fixedpoint.Value) (*types.Order, error)
+}
+
+var _ orderAmender = &polymarket.Exchange{}
+
+type quoter struct {
+	exchange         types.Exchange
+	market           types.Market
+	tag              string
+	quoteAmount      fixedpoint.Value
+	repriceThreshold fixedpoint.Value
+	maxPosition      fixedpoint.Value
+
+	mu sync.Mutex
+	// order 为当前挂单，没有挂单时为 nil
+	order *types.Order
+	// position 为本策略买入成交的累计数量
+	position fixedpoint.Value
+	// executedQuantities 记录每个订单已统计过的成交量，用于计算增量
+	executedQuantities map[uint64]fixedpoint.Value
+}
+
+// newQuoter 创建 market 的报价器，tag 为挂单的 tag，为空时使用策略 ID。
+func newQuoter(exchange types.Exchange, market types.Market, tag string, quoteAmount, repriceThreshold, maxPosition fixedpoint.Value) *quoter {
+	if tag == "" {
+		tag = ID
+	}
+	return &quoter{
+		exchange:           exchange,
+		market:             market,
+		tag:                tag,
+		quoteAmount:        quoteAmount,
+		repriceThreshold:   repriceThreshold,
+		maxPosition:        maxPosition,
+		executedQuantities: make(map[uint64]fixedpoint.Value),
+	}
+}
+
+// feeRateBps 返回挂单 symbol 的费率，交易端不是 Polymarket 时为 0。
+func (q *quoter) feeRateBps() int {
+	if ex, ok := q.exchange.(*polymarket.Exchange); ok {
+		return ex.FeeRateBps(q.market.Symbol)
+	}
+	return 0
+}
+
+// current 返回当前挂单的快照。
+func (q *quoter) current() (types.Order, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.order == nil {
+		return types.Order{}, false
+	}
+	return *q.order, true
+}
+
+func (q *quoter) setOrder(order *types.Order) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if order != nil && !order.IsWorking {
+		order = nil
+	}
+	q.order = order
+}
+
+// Position 返回累计买入成交的数量。
+func (q *quoter) Position() fixedpoint.Value {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.position
+}
+
+// quote 把挂单维护在目标价格 price 附近。
+func (q *quoter) quote(ctx context.Context, price fixedpoint.Value) error {
+	snapped, ok := polymarket.SnapOrderWithFee(q.market, price, q.quoteAmount, q.feeRateBps())
+	if !ok {
+		return fmt.Errorf("quoteAmount %s at price %s is below the minimal order size of %s",
+			q.quoteAmount.String(), snapped.Price.String(), q.market.Symbol)
+	}
+
+	current, ok := q.current()
+	if q.maxPosition.Sign() > 0 && q.Position().Add(snapped.Quantity).Compare(q.maxPosition) > 0 {
+		if ok {
+			log.Infof("position %s reached maxPosition %s, cancel the resting order", q.Position().String(), q.maxPosition.String())
+			return q.cancel(ctx)
+		}
+		return nil
+	}
+
+	if !ok {
+		return q.submit(ctx, snapped)
+	}
+
+	if current.Price.Sub(snapped.Price).Abs().Compare(q.repriceThreshold) < 0 {
+		return nil
+	}
+	return q.reprice(ctx, current, snapped)
+}
+
+func (q *quoter) submit(ctx context.Context, snapped polymarket.SnappedOrder) error {
+	created, err := q.exchange.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:      q.market.Symbol,
+		Market:      q.market,
+		Side:        types.SideTypeBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       snapped.Price,
+		Quantity:    snapped.Quantity,
+		TimeInForce: types.TimeInForceGTC,
+		Tag:         q.tag,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		"symbol":   created.Symbol,
+		"price":    created.Price.String(),
+		"quantity": created.Quantity.String(),
+	}).Info("mirror order submitted")
+	q.setOrder(created)
+	return nil
+}
+
+// reprice 把挂单改到新价格，剩余数量按新价格重新计算。
+func (q *quoter) reprice(ctx context.Context, current types.Order, snapped polymarket.SnappedOrder) error {
+	logger := log.WithFields(logrus.Fields{
+		"symbol": current.Symbol,
+		"from":   current.Price.String(),
+		"to":     snapped.Price.String(),
+	})
+
+	amender, ok := q.exchange.(orderAmender)
+	if !ok {
+		if err := q.cancel(ctx); err != nil {
+			return err
+		}
+		logger.Info("mirror order cancelled for re-pricing")
+		return q.submit(ctx, snapped)
+	}
+
+	amended, err := amender.AmendOrder(ctx, current, snapped.Price, current.ExecutedQuantity.Add(snapped.Quantity))
+	if err != nil {
+		if amended != nil {
+			// cancel-replace 已经提交了新订单，只是原订单没有撤掉
+			q.setOrder(amended)
+			return err
+		}
+
+		// 原订单可能已经成交或被撤掉，也可能仍然挂着（例如余额不足）：
+		// 尽量撤掉后清空，下一次报价重新挂单，保证只有一个挂单
+		if cancelErr := q.exchange.CancelOrders(ctx, current); cancelErr != nil {
+			logger.WithError(cancelErr).Debug("cancel the mirror order after amend failure")
+		}
+		q.setOrder(nil)
+		return fmt.Errorf("amend mirror order %d failed: %w", current.OrderID, err)
+	}
+
+	logger.Info("mirror order re-priced")
+	q.setOrder(amended)
+	return nil
+}
+
+// cancel 撤掉当前挂单。
+func (q *quoter) cancel(ctx context.Context) error {
+	current, ok := q.current()
+	if !ok {
+		return nil
+	}
+
+	if err := q.exchange.CancelOrders(ctx, current); err != nil {
+		return err
+	}
+	q.setOrder(nil)
+	return nil
+}
+
+// handleOrderUpdate 统计本策略订单的成交增量，挂单不再 working 时清空。
+func (q *quoter) handleOrderUpdate(order types.Order) {
+	if order.Tag != q.tag || order.Symbol != q.market.Symbol {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	prev := q.executedQuantities[order.OrderID]
+	if delta := order.ExecutedQuantity.Sub(prev); delta.Sign() > 0 {
+		q.executedQuantities[order.OrderID] = order.ExecutedQuantity
+		q.position = q.position.Add(delta)
+	}
+
+	if q.order == nil || q.order.OrderID != order.OrderID {
+		return
+	}
+	if !order.IsWorking {
+		q.order = nil
+		return
+	}
+	updated := order
+	q.order = &updated
+}
@@ -0,0 +1,136 @@
+package polymarketmirror
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const testSymbol = "PM_BTC_15M_UP_YES_USDC"
+
+func newTestQuoter(t *testing.T, maxPosition fixedpoint.Value) (*polymarket.Exchange, *quoter) {
+	ex := polymarket.New("", "", "")
+	t.Cleanup(func() { _ = ex.Close() })
+
+	markets, err := ex.QueryMarkets(context.Background())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	q := newQuoter(ex, markets[testSymbol], "", fixedpoint.NewFromFloat(5), fixedpoint.NewFromFloat(0.01), maxPosition)
+	ex.NewStream().OnOrderUpdate(q.handleOrderUpdate)
+	return ex, q
+}
+
+func TestQuoter_Quote(t *testing.T) {
+	ex, q := newTestQuoter(t, fixedpoint.Zero)
+	ctx := context.Background()
+
+	assert.NoError(t, q.quote(ctx, fixedpoint.NewFromFloat(0.5)))
+	first, ok := q.current()
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "0.5", first.Price.String())
+	assert.Equal(t, "10", first.Quantity.String())
+
+	// 偏离小于 RepriceThreshold 时不改单
+	assert.NoError(t, q.quote(ctx, fixedpoint.NewFromFloat(0.505)))
+	current, _ := q.current()
+	assert.Equal(t, "0.5", current.Price.String())
+
+	// 改单保持同一个订单，数量按新价格重新计算
+	assert.NoError(t, q.quote(ctx, fixedpoint.NewFromFloat(0.4)))
+	current, _ = q.current()
+	assert.Equal(t, first.OrderID, current.OrderID)
+	assert.Equal(t, "0.4", current.Price.String())
+	assert.Equal(t, "12.5", current.Quantity.String())
+
+	openOrders, err := ex.QueryOpenOrders(ctx, testSymbol)
+	assert.NoError(t, err)
+	assert.Len(t, openOrders, 1)
+
+	assert.NoError(t, q.cancel(ctx))
+	_, ok = q.current()
+	assert.False(t, ok)
+	openOrders, err = ex.QueryOpenOrders(ctx, testSymbol)
+	assert.NoError(t, err)
+	assert.Empty(t, openOrders)
+}
+
+func TestQuoter_FilledOrder(t *testing.T) {
+	t.Setenv("POLYMARKET_DRYRUN_AUTOFILL", "true")
+	t.Setenv("POLYMARKET_DRYRUN_FILL_INTERVAL", "1h")
+
+	ex, q := newTestQuoter(t, fixedpoint.NewFromFloat(15))
+	ctx := context.Background()
+
+	assert.NoError(t, q.quote(ctx, fixedpoint.NewFromFloat(0.5)))
+	ex.SetReferencePrice(testSymbol, fixedpoint.NewFromFloat(0.45))
+
+	_, ok := q.current()
+	assert.False(t, ok, "filled order should be cleared")
+	assert.Equal(t, "10", q.Position().String())
+
+	// 再挂 10 会超过 MaxPosition
+	assert.NoError(t, q.quote(ctx, fixedpoint.NewFromFloat(0.5)))
+	_, ok = q.current()
+	assert.False(t, ok)
+}
+
+func TestStrategy_Requote(t *testing.T) {
+	ex, q := newTestQuoter(t, fixedpoint.Zero)
+	s := &Strategy{}
+	assert.NoError(t, s.Defaults())
+	assert.NoError(t, s.Validate())
+	s.model = &fairValueModel{window: s.Window.Duration(), sensitivity: s.Sensitivity.Float64()}
+	s.quoter = q
+
+	session := bbgo.NewExchangeSession("polymarket", ex)
+	ctx := context.Background()
+	now := time.Date(2025, 10, 15, 8, 1, 0, 0, time.UTC)
+
+	// 还没有参考价时不报价
+	s.requote(ctx, session, now)
+	_, ok := q.current()
+	assert.False(t, ok)
+
+	s.updateReference(types.BookTicker{Symbol: "BTCUSDT", Buy: fixedpoint.NewFromInt(99999), Sell: fixedpoint.NewFromInt(100001)}, now)
+	s.requote(ctx, session, now)
+	order, ok := q.current()
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.48", order.Price.String())
+	}
+
+	// 参考价上涨 0.5%：公允价约 0.73
+	s.updateReference(types.BookTicker{Symbol: "BTCUSDT", Buy: fixedpoint.NewFromInt(100499), Sell: fixedpoint.NewFromInt(100501)}, now.Add(time.Second))
+	s.requote(ctx, session, now.Add(time.Second))
+	order, _ = q.current()
+	assert.InDelta(t, 0.7106, order.Price.Float64(), 1e-3)
+
+	// 参考价过期后撤单
+	s.requote(ctx, session, now.Add(time.Minute))
+	_, ok = q.current()
+	assert.False(t, ok)
+}
+
+func TestStrategy_Validate(t *testing.T) {
+	s := &Strategy{}
+	assert.NoError(t, s.Defaults())
+	assert.NoError(t, s.Validate())
+
+	s.MinPrice = fixedpoint.NewFromFloat(0.9)
+	s.MaxPrice = fixedpoint.NewFromFloat(0.1)
+	assert.Error(t, s.Validate())
+
+	s = &Strategy{Window: "1x"}
+	assert.NoError(t, s.Defaults())
+	assert.Error(t, s.Validate())
+}
@@ -0,0 +1,286 @@
+package polymarketmirror
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/exchange/polymarket"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// mirror 策略：Polymarket 挂单跟随 Binance 参考价
+// - Binance: 订阅 SourceSymbol 的 book ticker，以中间价作为参考价
+// - 由参考价在当前窗口内的涨跌计算 Polymarket outcome（默认 BTC 15m Up 的 YES）的公允价，见 fairvalue.go
+// - Polymarket: 在公允价 - Edge 处维持一个限价买单，参考价变化导致目标价格偏离达到 RepriceThreshold 时改单，见 quoter.go
+// 参考价超过 MaxReferenceAge 没有更新时撤单，不在过期的行情上报价。
+
+const ID = "polymarket-mirror"
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+type Strategy struct {
+	// BinanceSession 用于行情源（默认 "binance"）
+	BinanceSession string `json:"binanceSession" yaml:"binanceSession"`
+
+	// PolymarketSession 用于交易端（默认 "polymarket"）
+	PolymarketSession string `json:"polymarketSession" yaml:"polymarketSession"`
+
+	// SourceSymbol 为 Binance 的参考价 symbol（默认 BTCUSDT）
+	SourceSymbol string `json:"sourceSymbol" yaml:"sourceSymbol"`
+
+	// Symbol 为挂单的 Polymarket outcome symbol（默认 PM_BTC_15M_UP_YES_USDC），价格代表 “上涨” 的概率
+	Symbol string `json:"symbol" yaml:"symbol"`
+
+	// Window 为计算涨跌的窗口周期（默认 15m），每个窗口开始时的参考价作为锚点
+	Window types.Interval `json:"window" yaml:"window"`
+
+	// Sensitivity 为涨跌幅换算成概率的系数（默认 200：涨 1% 时公允价约 0.88）
+	Sensitivity fixedpoint.Value `json:"sensitivity" yaml:"sensitivity"`
+
+	// Edge 为挂单价格低于公允价的幅度（概率价格，默认 0.02）
+	Edge fixedpoint.Value `json:"edge" yaml:"edge"`
+
+	// MinPrice / MaxPrice 限制挂单价格（默认 0.05 / 0.95）
+	MinPrice fixedpoint.Value `json:"minPrice" yaml:"minPrice"`
+	MaxPrice fixedpoint.Value `json:"maxPrice" yaml:"maxPrice"`
+
+	// QuoteAmount 为挂单的 USDC 金额（quantity = QuoteAmount / price，默认 5）
+	QuoteAmount fixedpoint.Value `json:"quoteAmount" yaml:"quoteAmount"`
+
+	// RepriceThreshold 为目标价格与挂单价格相差多少时改单（默认 0.01）
+	RepriceThreshold fixedpoint.Value `json:"repriceThreshold" yaml:"repriceThreshold"`
+
+	// MaxPosition 为最大持仓数量（outcome token），成交后会超过上限时撤单并停止报价。0 表示不限制。
+	MaxPosition fixedpoint.Value `json:"maxPosition" yaml:"maxPosition"`
+
+	// UpdateInterval 为重新计算公允价与报价的间隔（默认 1s），book ticker 推送很频繁，不逐条处理
+	UpdateInterval types.Duration `json:"updateInterval" yaml:"updateInterval"`
+
+	// MaxReferenceAge 为参考价的最大时效（默认 10s），超过时撤单
+	MaxReferenceAge types.Duration `json:"maxReferenceAge" yaml:"maxReferenceAge"`
+
+	// Tag 为挂单的 tag（默认为策略 ID），同一个 Polymarket session 上运行多个实例或策略变体时配置不同的 tag，
+	// dry-run 的成交与盈亏可以按 tag 区分（见 polymarket.Exchange.DryRunSummaryByTag）
+	Tag string `json:"tag" yaml:"tag"`
+
+	mu sync.Mutex
+	// reference / referenceTime 为最新的参考价与收到的时间
+	reference     fixedpoint.Value
+	referenceTime time.Time
+
+	model  *fairValueModel
+	quoter *quoter
+}
+
+func (s *Strategy) ID() string { return ID }
+
+func (s *Strategy) InstanceID() string {
+	if s.Tag != "" && s.Tag != ID {
+		return fmt.Sprintf("%s:%s:%s:%s", ID, s.PolymarketSession, s.Symbol, s.Tag)
+	}
+	return fmt.Sprintf("%s:%s:%s", ID, s.PolymarketSession, s.Symbol)
+}
+
+func (s *Strategy) Defaults() error {
+	if s.BinanceSession == "" {
+		s.BinanceSession = "binance"
+	}
+	if s.PolymarketSession == "" {
+		s.PolymarketSession = "polymarket"
+	}
+	if s.SourceSymbol == "" {
+		s.SourceSymbol = "BTCUSDT"
+	}
+	if s.Symbol == "" {
+		s.Symbol = "PM_BTC_15M_UP_YES_USDC"
+	}
+	if s.Window == "" {
+		s.Window = types.Interval15m
+	}
+	if s.Sensitivity.IsZero() {
+		s.Sensitivity = fixedpoint.NewFromInt(200)
+	}
+	if s.Edge.IsZero() {
+		s.Edge = fixedpoint.NewFromFloat(0.02)
+	}
+	if s.MinPrice.IsZero() {
+		s.MinPrice = fixedpoint.NewFromFloat(0.05)
+	}
+	if s.MaxPrice.IsZero() {
+		s.MaxPrice = fixedpoint.NewFromFloat(0.95)
+	}
+	if s.QuoteAmount.IsZero() {
+		s.QuoteAmount = fixedpoint.NewFromFloat(5)
+	}
+	if s.RepriceThreshold.IsZero() {
+		s.RepriceThreshold = fixedpoint.NewFromFloat(0.01)
+	}
+	if s.UpdateInterval == 0 {
+		s.UpdateInterval = types.Duration(time.Second)
+	}
+	if s.MaxReferenceAge == 0 {
+		s.MaxReferenceAge = types.Duration(10 * time.Second)
+	}
+	return nil
+}
+
+func (s *Strategy) Validate() error {
+	if s.BinanceSession == "" || s.PolymarketSession == "" {
+		return fmt.Errorf("binanceSession/polymarketSession is required")
+	}
+	if s.SourceSymbol == "" || s.Symbol == "" {
+		return fmt.Errorf("sourceSymbol/symbol is required")
+	}
+	if _, ok := types.SupportedIntervals[s.Window]; !ok {
+		return fmt.Errorf("invalid window %q", s.Window)
+	}
+	if s.Sensitivity.Sign() <= 0 {
+		return fmt.Errorf("sensitivity must be positive")
+	}
+	if s.Edge.Sign() < 0 || s.Edge.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("edge must be between 0 and 1")
+	}
+	if s.MinPrice.Sign() <= 0 || s.MaxPrice.Compare(fixedpoint.One) >= 0 || s.MinPrice.Compare(s.MaxPrice) > 0 {
+		return fmt.Errorf("minPrice/maxPrice must satisfy 0 < minPrice <= maxPrice < 1")
+	}
+	if s.QuoteAmount.Sign() <= 0 {
+		return fmt.Errorf("quoteAmount must be positive")
+	}
+	if s.RepriceThreshold.Sign() <= 0 {
+		return fmt.Errorf("repriceThreshold must be positive")
+	}
+	if s.MaxPosition.Sign() < 0 {
+		return fmt.Errorf("maxPosition must not be negative")
+	}
+	if s.UpdateInterval <= 0 || s.MaxReferenceAge <= 0 {
+		return fmt.Errorf("updateInterval/maxReferenceAge must be positive")
+	}
+	return nil
+}
+
+func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {
+	binanceSession, ok := sessions[s.BinanceSession]
+	if !ok {
+		// 这里不 return error（CrossSubscribe 接口不返回），在 CrossRun 里会再做一次校验。
+		return
+	}
+
+	binanceSession.Subscribe(types.BookTickerChannel, s.SourceSymbol, types.SubscribeOptions{})
+}
+
+func (s *Strategy) CrossRun(ctx context.Context, _ bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession) error {
+	if err := s.Defaults(); err != nil {
+		return err
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	binanceSession, ok := sessions[s.BinanceSession]
+	if !ok {
+		return fmt.Errorf("binance session %q not found", s.BinanceSession)
+	}
+	polymarketSession, ok := sessions[s.PolymarketSession]
+	if !ok {
+		return fmt.Errorf("polymarket session %q not found", s.PolymarketSession)
+	}
+
+	market, ok := polymarketSession.Market(s.Symbol)
+	if !ok {
+		return fmt.Errorf("market %s not found in polymarket session", s.Symbol)
+	}
+
+	s.model = &fairValueModel{window: s.Window.Duration(), sensitivity: s.Sensitivity.Float64()}
+	s.quoter = newQuoter(polymarketSession.Exchange, market, s.Tag, s.QuoteAmount, s.RepriceThreshold, s.MaxPosition)
+	if polymarketSession.UserDataStream != nil {
+		polymarketSession.UserDataStream.OnOrderUpdate(s.quoter.handleOrderUpdate)
+	}
+
+	binanceSession.MarketDataStream.OnBookTickerUpdate(func(ticker types.BookTicker) {
+		if ticker.Symbol == s.SourceSymbol {
+			s.updateReference(ticker, time.Now())
+		}
+	})
+
+	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+		if err := s.quoter.cancel(ctx); err != nil {
+			log.WithError(err).Warn("failed to cancel the mirror order")
+		}
+	})
+
+	go s.runQuoteLoop(ctx, polymarketSession)
+	return nil
+}
+
+func (s *Strategy) updateReference(ticker types.BookTicker, now time.Time) {
+	mid := midPrice(ticker)
+	if mid.Sign() <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.reference, s.referenceTime = mid, now
+	s.mu.Unlock()
+}
+
+func (s *Strategy) runQuoteLoop(ctx context.Context, session *bbgo.ExchangeSession) {
+	ticker := time.NewTicker(s.UpdateInterval.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.requote(ctx, session, now)
+		}
+	}
+}
+
+// requote 用最新的参考价计算目标价格并维护挂单，参考价过期时撤单。
+func (s *Strategy) requote(ctx context.Context, session *bbgo.ExchangeSession, now time.Time) {
+	s.mu.Lock()
+	reference, referenceTime := s.reference, s.referenceTime
+	s.mu.Unlock()
+
+	if reference.IsZero() {
+		return
+	}
+
+	if now.Sub(referenceTime) > s.MaxReferenceAge.Duration() {
+		if _, ok := s.quoter.current(); ok {
+			log.Warnf("%s reference price is older than %s, cancel the mirror order", s.SourceSymbol, s.MaxReferenceAge.Duration())
+			if err := s.quoter.cancel(ctx); err != nil {
+				log.WithError(err).Error("failed to cancel the mirror order")
+			}
+		}
+		return
+	}
+
+	fair := s.model.update(reference, now)
+	price := quotePrice(fair, s.Edge, s.MinPrice, s.MaxPrice)
+
+	// dry-run 以公允价作为模拟撮合的参考价：公允价跌到挂单价以下时挂单才会成交
+	if ex, ok := session.Exchange.(*polymarket.Exchange); ok && ex.IsDryRun() {
+		ex.SetReferencePrice(s.Symbol, fair)
+	}
+
+	if err := s.quoter.quote(ctx, price); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"reference": reference.String(),
+			"fair":      fair.String(),
+			"price":     price.String(),
+		}).Error("failed to quote the mirror order")
+	}
+}
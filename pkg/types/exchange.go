@@ -131,6 +131,13 @@ type ExchangeTradeService interface {
 	CancelOrders(ctx context.Context, orders ...Order) error
 }
 
+// DryRunExchange is an exchange that supports simulated (dry-run) order execution,
+// the dry-run mode can be set per exchange instance.
+type DryRunExchange interface {
+	IsDryRun() bool
+	SetDryRun(dryRun bool)
+}
+
 type ExchangeDefaultFeeRates interface {
 	DefaultFeeRates() ExchangeFee
 }
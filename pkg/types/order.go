@@ -331,6 +331,9 @@ type Order struct {
 	IsFutures  bool `json:"isFutures,omitempty" db:"is_futures"`
 	IsMargin   bool `json:"isMargin,omitempty" db:"is_margin"`
 	IsIsolated bool `json:"isIsolated,omitempty" db:"is_isolated"`
+
+	// IsDryRun marks a simulated order that is never sent to the exchange
+	IsDryRun bool `json:"isDryRun,omitempty" db:"-"`
 }
 
 func (o *Order) LogFields() logrus.Fields {
@@ -347,6 +350,10 @@ func (o *Order) LogFields() logrus.Fields {
 		fields["client_order_id"] = o.ClientOrderID
 	}
 
+	if o.IsDryRun {
+		fields["dry_run"] = true
+	}
+
 	return fields
 }
 
@@ -78,6 +78,9 @@ type StandardStream struct {
 
 	endpointCreator EndpointCreator
 
+	// dialer is the websocket dialer used by Dial, defaultDialer is used if nil
+	dialer *websocket.Dialer
+
 	// Conn is the websocket connection
 	Conn *websocket.Conn
 
@@ -223,6 +226,12 @@ func (s *StandardStream) SetEndpointCreator(creator EndpointCreator) {
 	s.endpointCreator = creator
 }
 
+// SetDialer sets the websocket dialer used by Dial, e.g. to enable permessage-deflate compression.
+// If not set, a default dialer without compression is used.
+func (s *StandardStream) SetDialer(dialer *websocket.Dialer) {
+	s.dialer = dialer
+}
+
 // SetDispatcher sets the dispatcher function that receives parsed events from Read.
 // If set, every successfully parsed message will be passed to this dispatcher.
 // The dispatcher should be non-blocking or handle its own goroutine if it may block.
@@ -431,6 +440,20 @@ func (s *StandardStream) Resubscribe(fn func(old []Subscription) (new []Subscrip
 	return nil
 }
 
+// UpdateSubscriptions replaces the subscriptions like Resubscribe but does not reconnect.
+// It is used by exchange streams that can subscribe / unsubscribe on the live connection.
+func (s *StandardStream) UpdateSubscriptions(fn func(old []Subscription) (new []Subscription, err error)) ([]Subscription, error) {
+	s.subLock.Lock()
+	defer s.subLock.Unlock()
+
+	subs, err := fn(s.Subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	s.Subscriptions = subs
+	return subs, nil
+}
+
 // Subscribe appends a new subscription in a thread-safe manner.
 // This only records the intent locally; concrete exchange streams usually
 // send the broker-specific subscribe command in their OnConnect handler.
@@ -548,7 +571,12 @@ func (s *StandardStream) Dial(ctx context.Context, args ...string) (*websocket.C
 		return nil, errors.New("can not dial, neither url nor endpoint creator is not defined, you should pass an url to Dial() or call SetEndpointCreator()")
 	}
 
-	conn, _, err := defaultDialer.Dial(url, nil)
+	dialer := s.dialer
+	if dialer == nil {
+		dialer = defaultDialer
+	}
+
+	conn, _, err := dialer.Dial(url, nil)
 	if err != nil {
 		return nil, err
 	}